@@ -0,0 +1,133 @@
+package adaptertest
+
+import (
+	"errors"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/click33/sa-token-go/core/adapter"
+)
+
+var _ adapter.Storage = (*MapStorage)(nil)
+
+// MapStorage Legacy storage without atomic or hash operations, exercising the adapter fallback | 不含原子与哈希操作的旧版存储，用于覆盖适配器回退实现
+type MapStorage struct {
+	mu     sync.Mutex
+	values map[string]any
+	expiry map[string]time.Time
+}
+
+// NewMapStorage Creates an empty MapStorage | 创建空的MapStorage
+func NewMapStorage() *MapStorage {
+	return &MapStorage{values: make(map[string]any), expiry: make(map[string]time.Time)}
+}
+
+// errNotFound Returned for missing keys like the memory storage | 与memory存储一致的键不存在错误
+var errNotFound = errors.New("key not found")
+
+func (s *MapStorage) live(key string) bool {
+	if _, ok := s.values[key]; !ok {
+		return false
+	}
+	if deadline, ok := s.expiry[key]; ok && time.Now().After(deadline) {
+		delete(s.values, key)
+		delete(s.expiry, key)
+		return false
+	}
+	return true
+}
+
+func (s *MapStorage) Set(key string, value any, expiration time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.values[key] = value
+	delete(s.expiry, key)
+	if expiration > 0 {
+		s.expiry[key] = time.Now().Add(expiration)
+	}
+	return nil
+}
+
+func (s *MapStorage) SetKeepTTL(key string, value any) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.live(key) {
+		return errNotFound
+	}
+	s.values[key] = value
+	return nil
+}
+
+func (s *MapStorage) Get(key string) (any, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.live(key) {
+		return nil, errNotFound
+	}
+	return s.values[key], nil
+}
+
+func (s *MapStorage) Delete(keys ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, key := range keys {
+		delete(s.values, key)
+		delete(s.expiry, key)
+	}
+	return nil
+}
+
+func (s *MapStorage) Exists(key string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.live(key)
+}
+
+func (s *MapStorage) Keys(pattern string) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var keys []string
+	for key := range s.values {
+		if matched, _ := path.Match(pattern, key); matched && s.live(key) {
+			keys = append(keys, key)
+		}
+	}
+	return keys, nil
+}
+
+func (s *MapStorage) Expire(key string, expiration time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.live(key) {
+		return errNotFound
+	}
+	delete(s.expiry, key)
+	if expiration > 0 {
+		s.expiry[key] = time.Now().Add(expiration)
+	}
+	return nil
+}
+
+func (s *MapStorage) TTL(key string) (time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.live(key) {
+		return -2 * time.Second, errNotFound
+	}
+	deadline, ok := s.expiry[key]
+	if !ok {
+		return -1 * time.Second, nil
+	}
+	return time.Until(deadline), nil
+}
+
+func (s *MapStorage) Clear() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.values = make(map[string]any)
+	s.expiry = make(map[string]time.Time)
+	return nil
+}
+
+func (s *MapStorage) Ping() error { return nil }
@@ -0,0 +1,152 @@
+package adaptertest
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+
+	"github.com/click33/sa-token-go/core/adapter"
+)
+
+var (
+	_ adapter.RequestContext = (*RequestContext)(nil)
+	_ adapter.ContextCarrier = (*RequestContext)(nil)
+)
+
+// RequestContext Framework-free RequestContext over an *http.Request, recording the response | 基于*http.Request的无框架RequestContext，记录响应内容
+//
+// Usage | 用法:
+//
+//	ctx := adaptertest.NewRequestContext(httptest.NewRequest(http.MethodGet, "/api?token=abc", nil))
+//	token := ctx.GetQuery("token")
+type RequestContext struct {
+	Request        *http.Request
+	ResponseHeader http.Header              // Headers set through SetHeader | 通过SetHeader设置的响应头
+	Cookies        []*adapter.CookieOptions // Cookies set in call order | 按调用顺序设置的Cookie
+
+	body    []byte
+	read    bool
+	values  map[string]any
+	aborted bool
+}
+
+// NewRequestContext Wraps request, the body is read once and can be read again afterwards | 包装请求，请求体只读取一次且之后仍可读取
+func NewRequestContext(r *http.Request) *RequestContext {
+	return &RequestContext{
+		Request:        r,
+		ResponseHeader: make(http.Header),
+		values:         make(map[string]any),
+	}
+}
+
+// Context Implements adapter.ContextCarrier | 实现adapter.ContextCarrier
+func (c *RequestContext) Context() context.Context { return c.Request.Context() }
+
+func (c *RequestContext) GetHeader(key string) string { return c.Request.Header.Get(key) }
+
+func (c *RequestContext) GetHeaders() map[string][]string { return c.Request.Header }
+
+func (c *RequestContext) GetQuery(key string) string { return c.Request.URL.Query().Get(key) }
+
+func (c *RequestContext) GetQueryAll() map[string][]string { return c.Request.URL.Query() }
+
+// GetPostForm Reads urlencoded body fields, other bodies have none | 读取urlencoded请求体字段，其他请求体没有表单字段
+func (c *RequestContext) GetPostForm(key string) string {
+	if !adapter.IsFormURLEncoded(c.GetHeader("Content-Type")) {
+		return ""
+	}
+	body, err := c.GetBody()
+	if err != nil {
+		return ""
+	}
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		return ""
+	}
+	return form.Get(key)
+}
+
+func (c *RequestContext) GetCookie(key string) string {
+	cookie, err := c.Request.Cookie(key)
+	if err != nil {
+		return ""
+	}
+	return cookie.Value
+}
+
+// GetBody Reads the body once and restores it for later readers | 读取一次请求体并为后续读取者恢复
+func (c *RequestContext) GetBody() ([]byte, error) {
+	if !c.read {
+		c.read = true
+		if c.Request.Body != nil {
+			data, err := io.ReadAll(c.Request.Body)
+			if err != nil {
+				return nil, err
+			}
+			c.body = data
+		}
+	}
+	c.Request.Body = io.NopCloser(bytes.NewReader(c.body))
+	return c.body, nil
+}
+
+func (c *RequestContext) GetClientIP() string {
+	host, _, err := net.SplitHostPort(c.Request.RemoteAddr)
+	if err != nil {
+		return c.Request.RemoteAddr
+	}
+	return host
+}
+
+func (c *RequestContext) GetMethod() string { return c.Request.Method }
+
+func (c *RequestContext) GetPath() string { return c.Request.URL.Path }
+
+func (c *RequestContext) GetURL() string { return c.Request.URL.String() }
+
+func (c *RequestContext) GetUserAgent() string { return c.Request.UserAgent() }
+
+func (c *RequestContext) SetHeader(key, value string) { c.ResponseHeader.Set(key, value) }
+
+func (c *RequestContext) SetCookie(name, value string, maxAge int, path, domain string, secure, httpOnly bool) {
+	c.SetCookieWithOptions(&adapter.CookieOptions{
+		Name:     name,
+		Value:    value,
+		MaxAge:   maxAge,
+		Path:     path,
+		Domain:   domain,
+		Secure:   secure,
+		HttpOnly: httpOnly,
+	})
+}
+
+func (c *RequestContext) SetCookieWithOptions(options *adapter.CookieOptions) {
+	c.Cookies = append(c.Cookies, options)
+}
+
+func (c *RequestContext) Set(key string, value any) { c.values[key] = value }
+
+func (c *RequestContext) Get(key string) (any, bool) {
+	value, ok := c.values[key]
+	return value, ok
+}
+
+func (c *RequestContext) GetString(key string) string {
+	value, _ := c.values[key].(string)
+	return value
+}
+
+func (c *RequestContext) MustGet(key string) any {
+	value, ok := c.values[key]
+	if !ok {
+		panic("key " + key + " does not exist")
+	}
+	return value
+}
+
+func (c *RequestContext) Abort() { c.aborted = true }
+
+func (c *RequestContext) IsAborted() bool { return c.aborted }
@@ -0,0 +1,185 @@
+// Package adaptertest provides adapter test helpers: an atomic storage suite, a legacy MapStorage
+// and a framework-free RequestContext | 提供适配器测试工具：原子存储测试套件、旧版MapStorage与无框架RequestContext
+//
+// Storage modules call RunAtomicSuite from their own tests so that every backend
+// keeps the one-winner guarantees the manager relies on | 各存储模块在自身测试中调用RunAtomicSuite，确保每种后端满足管理器依赖的唯一胜出语义
+//
+// Usage | 用法:
+//
+//	func TestAtomic(t *testing.T) {
+//	    adaptertest.RunAtomicSuite(t, func() adapter.Storage { return NewStorage() })
+//	}
+package adaptertest
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/click33/sa-token-go/core/adapter"
+)
+
+// racers Goroutines competing for the same key | 竞争同一键的协程数
+const racers = 16
+
+// RunAtomicSuite Runs the atomic and hash checks, newStorage is called once per subtest | 运行原子与哈希检查，每个子测试调用一次newStorage
+// Keys are unique per run, so a shared backend needs no cleanup beyond what the suite deletes | 每次运行的键唯一，共享后端无需额外清理
+func RunAtomicSuite(t *testing.T, newStorage func() adapter.Storage) {
+	t.Helper()
+
+	prefix := fmt.Sprintf("adaptertest:%d:", time.Now().UnixNano())
+	t.Run("SetNX", func(t *testing.T) { testSetNX(t, newStorage(), prefix+"setnx") })
+	t.Run("SetNXRace", func(t *testing.T) { testSetNXRace(t, newStorage(), prefix+"setnx-race") })
+	t.Run("GetDel", func(t *testing.T) { testGetDel(t, newStorage(), prefix+"getdel") })
+	t.Run("GetDelRace", func(t *testing.T) { testGetDelRace(t, newStorage(), prefix+"getdel-race") })
+	t.Run("Hash", func(t *testing.T) { testHash(t, newStorage(), prefix+"hash") })
+	t.Run("HashKeepsTTL", func(t *testing.T) { testHashKeepsTTL(t, newStorage(), prefix+"hash-ttl") })
+}
+
+func testSetNX(t *testing.T, storage adapter.Storage, key string) {
+	defer storage.Delete(key)
+
+	ok, err := adapter.SetNX(storage, key, "first", time.Minute)
+	if err != nil || !ok {
+		t.Fatalf("first SetNX = %v, %v, want true", ok, err)
+	}
+	ok, err = adapter.SetNX(storage, key, "second", time.Minute)
+	if err != nil || ok {
+		t.Fatalf("second SetNX = %v, %v, want false", ok, err)
+	}
+	if value, _ := storage.Get(key); fmt.Sprint(value) != "first" {
+		t.Fatalf("value = %v, want first", value)
+	}
+	if ttl, _ := storage.TTL(key); ttl <= 0 {
+		t.Fatalf("TTL = %v, want expiration kept", ttl)
+	}
+}
+
+func testSetNXRace(t *testing.T, storage adapter.Storage, key string) {
+	defer storage.Delete(key)
+
+	wins := race(func() bool {
+		ok, err := adapter.SetNX(storage, key, "1", time.Minute)
+		return err == nil && ok
+	})
+	if wins != 1 {
+		t.Fatalf("SetNX winners = %d, want 1", wins)
+	}
+}
+
+func testGetDel(t *testing.T, storage adapter.Storage, key string) {
+	if err := storage.Set(key, "value", time.Minute); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+
+	value, err := adapter.GetDel(storage, key)
+	if err != nil || fmt.Sprint(value) != "value" {
+		t.Fatalf("GetDel = %v, %v, want value", value, err)
+	}
+	if storage.Exists(key) {
+		t.Fatal("key still exists after GetDel")
+	}
+	if _, err := adapter.GetDel(storage, key); err == nil {
+		t.Fatal("second GetDel succeeded, want error")
+	}
+}
+
+func testGetDelRace(t *testing.T, storage adapter.Storage, key string) {
+	if err := storage.Set(key, "value", time.Minute); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+
+	wins := race(func() bool {
+		_, err := adapter.GetDel(storage, key)
+		return err == nil
+	})
+	if wins != 1 {
+		t.Fatalf("GetDel winners = %d, want 1", wins)
+	}
+}
+
+func testHash(t *testing.T, storage adapter.Storage, key string) {
+	defer storage.Delete(key)
+
+	fields, err := adapter.HGetAll(storage, key)
+	if err != nil || len(fields) != 0 {
+		t.Fatalf("HGetAll on missing key = %v, %v, want empty", fields, err)
+	}
+
+	for _, field := range []string{"a", "b"} {
+		if err := adapter.HSet(storage, key, field, "value-"+field); err != nil {
+			t.Fatalf("HSet(%s) error = %v", field, err)
+		}
+	}
+	if err := adapter.HSet(storage, key, "a", "updated"); err != nil {
+		t.Fatalf("HSet(a) error = %v", err)
+	}
+	fields, err = adapter.HGetAll(storage, key)
+	if err != nil || len(fields) != 2 || fields["a"] != "updated" || fields["b"] != "value-b" {
+		t.Fatalf("HGetAll = %v, %v", fields, err)
+	}
+
+	if err := adapter.HDel(storage, key, "a"); err != nil {
+		t.Fatalf("HDel(a) error = %v", err)
+	}
+	if fields, _ = adapter.HGetAll(storage, key); len(fields) != 1 {
+		t.Fatalf("HGetAll after HDel = %v, want one field", fields)
+	}
+	if err := adapter.HDel(storage, key, "b", "missing"); err != nil {
+		t.Fatalf("HDel(b) error = %v", err)
+	}
+	if storage.Exists(key) {
+		t.Fatal("hash key still exists after its last field was deleted")
+	}
+}
+
+func testHashKeepsTTL(t *testing.T, storage adapter.Storage, key string) {
+	defer storage.Delete(key)
+
+	if err := adapter.HSet(storage, key, "a", "1"); err != nil {
+		t.Fatalf("HSet() error = %v", err)
+	}
+	if err := storage.Expire(key, time.Minute); err != nil {
+		t.Fatalf("Expire() error = %v", err)
+	}
+	if err := adapter.HSet(storage, key, "b", "2"); err != nil {
+		t.Fatalf("HSet() error = %v", err)
+	}
+	if ttl, _ := storage.TTL(key); ttl <= 0 {
+		t.Fatalf("TTL = %v, want expiration kept by HSet", ttl)
+	}
+
+	// Expire <= 0 makes the key permanent instead of deleting it | Expire <= 0使键永不过期而非删除
+	if err := storage.Expire(key, 0); err != nil {
+		t.Fatalf("Expire(0) error = %v", err)
+	}
+	if ttl, _ := storage.TTL(key); !storage.Exists(key) || ttl != -1*time.Second {
+		t.Fatalf("TTL after Expire(0) = %v, want never expire", ttl)
+	}
+}
+
+// race Runs fn concurrently and counts successes | 并发运行fn并统计成功次数
+func race(fn func() bool) int {
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		wins int
+	)
+	start := make(chan struct{})
+	for i := 0; i < racers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			if fn() {
+				mu.Lock()
+				wins++
+				mu.Unlock()
+			}
+		}()
+	}
+	close(start)
+	wg.Wait()
+	return wins
+}
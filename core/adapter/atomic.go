@@ -0,0 +1,293 @@
+package adapter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"sync"
+	"time"
+)
+
+// Atomic Storage Operations
+// 原子存储操作
+//
+// Storage only offers single-key get/set, so read-modify-write on a shared backend loses updates
+// when several instances race. Storages that share data between instances should implement
+// AtomicStorage and HashStorage (memory and redis do). | Storage只提供单键读写，多实例共享后端时读改写会丢失更新，多实例共享数据的存储应实现AtomicStorage与HashStorage（memory与redis已实现）
+//
+// The package functions below use the native operation when available and otherwise fall back to
+// a process-local lock, which is only correct for a single instance | 下列包函数优先使用原生操作，否则回退到进程内锁，仅在单实例下正确
+//
+// Usage | 用法:
+//   ok, err := adapter.SetNX(storage, key, "1", time.Minute) // first caller wins | 首个调用者胜出
+//   value, err := adapter.GetDel(storage, key)               // one-time read | 一次性读取
+//   err = adapter.HSet(storage, key, field, value)
+
+// AtomicStorage Optional Storage extension with atomic single-key operations | Storage的可选扩展，提供原子单键操作
+type AtomicStorage interface {
+	// SetNX sets key only when absent, reports whether it was set | 仅当键不存在时设置，返回是否设置成功
+	SetNX(key string, value any, expiration time.Duration) (bool, error)
+
+	// GetDel gets and deletes key in one step, errors like Get when missing | 一步获取并删除键，不存在时与Get一样返回错误
+	GetDel(key string) (any, error)
+}
+
+// HashStorage Optional Storage extension with field-level hash operations | Storage的可选扩展，提供字段级哈希操作
+// Hash keys expire as a whole through Expire | 哈希键通过Expire整体过期
+type HashStorage interface {
+	// HSet sets one field of hash | 设置哈希的一个字段
+	HSet(key, field, value string) error
+
+	// HGetAll gets every field of hash, empty map when missing | 获取哈希的全部字段，不存在时返回空map
+	HGetAll(key string) (map[string]string, error)
+
+	// HDel deletes fields, the key is removed with its last field | 删除字段，最后一个字段删除时键随之删除
+	HDel(key string, fields ...string) error
+}
+
+// AtomicStorageV2 Context-aware form of AtomicStorage | AtomicStorage的上下文感知形式
+type AtomicStorageV2 interface {
+	SetNX(ctx context.Context, key string, value any, expiration time.Duration) (bool, error)
+	GetDel(ctx context.Context, key string) (any, error)
+}
+
+// HashStorageV2 Context-aware form of HashStorage | HashStorage的上下文感知形式
+type HashStorageV2 interface {
+	HSet(ctx context.Context, key, field, value string) error
+	HGetAll(ctx context.Context, key string) (map[string]string, error)
+	HDel(ctx context.Context, key string, fields ...string) error
+}
+
+// SetNX Sets key only when absent, reports whether it was set | 仅当键不存在时设置，返回是否设置成功
+func SetNX(storage Storage, key string, value any, expiration time.Duration) (bool, error) {
+	if atomic, ok := storage.(AtomicStorage); ok {
+		return atomic.SetNX(key, value, expiration)
+	}
+	return fallbackSetNX(storage, key, value, expiration)
+}
+
+// GetDel Gets and deletes key in one step | 一步获取并删除键
+func GetDel(storage Storage, key string) (any, error) {
+	if atomic, ok := storage.(AtomicStorage); ok {
+		return atomic.GetDel(key)
+	}
+	return fallbackGetDel(storage, key)
+}
+
+// HSet Sets one field of hash | 设置哈希的一个字段
+func HSet(storage Storage, key, field, value string) error {
+	if hash, ok := storage.(HashStorage); ok {
+		return hash.HSet(key, field, value)
+	}
+	return fallbackHSet(storage, key, field, value)
+}
+
+// HGetAll Gets every field of hash, empty map when missing | 获取哈希的全部字段，不存在时返回空map
+func HGetAll(storage Storage, key string) (map[string]string, error) {
+	if hash, ok := storage.(HashStorage); ok {
+		return hash.HGetAll(key)
+	}
+	return fallbackHGetAll(storage, key)
+}
+
+// HDel Deletes hash fields | 删除哈希字段
+func HDel(storage Storage, key string, fields ...string) error {
+	if hash, ok := storage.(HashStorage); ok {
+		return hash.HDel(key, fields...)
+	}
+	return fallbackHDel(storage, key, fields...)
+}
+
+// CheckExists Reports whether key exists along with backend errors that Storage.Exists hides | 返回键是否存在，以及Storage.Exists隐藏的后端错误
+// Legacy storages cannot report errors and always return nil | 旧版存储无法报告错误，始终返回nil
+func CheckExists(storage Storage, key string) (bool, error) {
+	switch s := storage.(type) {
+	case *boundStorage:
+		return s.storage.Exists(s.ctx, key)
+	case StorageV2Provider:
+		return s.StorageV2().Exists(context.Background(), key)
+	}
+	return storage.Exists(key), nil
+}
+
+// ============ Process-local Fallback | 进程内回退实现 ============
+
+// fallbackLocks Striped locks serializing fallback read-modify-write per key | 按键分段的锁，串行化回退实现的读改写
+var fallbackLocks [64]sync.Mutex
+
+// lockKey Locks the stripe of key and returns its unlock | 锁定键所在分段并返回解锁函数
+func lockKey(key string) func() {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+	mu := &fallbackLocks[h.Sum32()%uint32(len(fallbackLocks))]
+	mu.Lock()
+	return mu.Unlock
+}
+
+func fallbackSetNX(storage Storage, key string, value any, expiration time.Duration) (bool, error) {
+	defer lockKey(key)()
+
+	if storage.Exists(key) {
+		return false, nil
+	}
+	if err := storage.Set(key, value, expiration); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func fallbackGetDel(storage Storage, key string) (any, error) {
+	defer lockKey(key)()
+
+	value, err := storage.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	if err := storage.Delete(key); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+func fallbackHSet(storage Storage, key, field, value string) error {
+	defer lockKey(key)()
+
+	fields, err := loadFallbackHash(storage, key)
+	if err != nil {
+		return err
+	}
+	fields[field] = value
+	return saveFallbackHash(storage, key, fields)
+}
+
+func fallbackHGetAll(storage Storage, key string) (map[string]string, error) {
+	defer lockKey(key)()
+	return loadFallbackHash(storage, key)
+}
+
+func fallbackHDel(storage Storage, key string, fields ...string) error {
+	defer lockKey(key)()
+
+	hash, err := loadFallbackHash(storage, key)
+	if err != nil {
+		return err
+	}
+	for _, field := range fields {
+		delete(hash, field)
+	}
+	if len(hash) == 0 {
+		return storage.Delete(key)
+	}
+	return saveFallbackHash(storage, key, hash)
+}
+
+// loadFallbackHash Reads hash stored as a JSON object | 读取以JSON对象保存的哈希
+func loadFallbackHash(storage Storage, key string) (map[string]string, error) {
+	fields := make(map[string]string)
+	if !storage.Exists(key) {
+		return fields, nil
+	}
+	data, err := storage.Get(key)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw []byte
+	switch v := data.(type) {
+	case string:
+		raw = []byte(v)
+	case []byte:
+		raw = v
+	default:
+		return nil, fmt.Errorf("hash %s holds %T", key, data)
+	}
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, fmt.Errorf("hash %s: %w", key, err)
+	}
+	return fields, nil
+}
+
+// saveFallbackHash Writes hash as a JSON object, keeping the key's TTL | 以JSON对象写入哈希，保持键的TTL
+func saveFallbackHash(storage Storage, key string, fields map[string]string) error {
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return err
+	}
+	if storage.Exists(key) {
+		return storage.SetKeepTTL(key, string(data))
+	}
+	return storage.Set(key, string(data), 0)
+}
+
+// ============ Adapter Forwarding | 适配器转发 ============
+
+func (s *legacyStorage) SetNX(ctx context.Context, key string, value any, expiration time.Duration) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+	return SetNX(s.storage, key, value, expiration)
+}
+
+func (s *legacyStorage) GetDel(ctx context.Context, key string) (any, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return GetDel(s.storage, key)
+}
+
+func (s *legacyStorage) HSet(ctx context.Context, key, field, value string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return HSet(s.storage, key, field, value)
+}
+
+func (s *legacyStorage) HGetAll(ctx context.Context, key string) (map[string]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return HGetAll(s.storage, key)
+}
+
+func (s *legacyStorage) HDel(ctx context.Context, key string, fields ...string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return HDel(s.storage, key, fields...)
+}
+
+func (s *boundStorage) SetNX(key string, value any, expiration time.Duration) (bool, error) {
+	if atomic, ok := s.storage.(AtomicStorageV2); ok {
+		return atomic.SetNX(s.ctx, key, value, expiration)
+	}
+	return fallbackSetNX(s, key, value, expiration)
+}
+
+func (s *boundStorage) GetDel(key string) (any, error) {
+	if atomic, ok := s.storage.(AtomicStorageV2); ok {
+		return atomic.GetDel(s.ctx, key)
+	}
+	return fallbackGetDel(s, key)
+}
+
+func (s *boundStorage) HSet(key, field, value string) error {
+	if hash, ok := s.storage.(HashStorageV2); ok {
+		return hash.HSet(s.ctx, key, field, value)
+	}
+	return fallbackHSet(s, key, field, value)
+}
+
+func (s *boundStorage) HGetAll(key string) (map[string]string, error) {
+	if hash, ok := s.storage.(HashStorageV2); ok {
+		return hash.HGetAll(s.ctx, key)
+	}
+	return fallbackHGetAll(s, key)
+}
+
+func (s *boundStorage) HDel(key string, fields ...string) error {
+	if hash, ok := s.storage.(HashStorageV2); ok {
+		return hash.HDel(s.ctx, key, fields...)
+	}
+	return fallbackHDel(s, key, fields...)
+}
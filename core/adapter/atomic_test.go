@@ -0,0 +1,36 @@
+package adapter_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/click33/sa-token-go/core/adapter"
+	"github.com/click33/sa-token-go/core/adapter/adaptertest"
+)
+
+type ctxKey struct{}
+
+func TestAtomicFallback(t *testing.T) {
+	adaptertest.RunAtomicSuite(t, func() adapter.Storage { return adaptertest.NewMapStorage() })
+}
+
+func TestAtomicFallbackBound(t *testing.T) {
+	ctx := context.WithValue(context.Background(), ctxKey{}, "request")
+	adaptertest.RunAtomicSuite(t, func() adapter.Storage {
+		return adapter.BindContext(adapter.AsStorageV2(adaptertest.NewMapStorage()), ctx)
+	})
+}
+
+func TestAtomicBoundCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	storage := adapter.BindContext(adapter.AsStorageV2(adaptertest.NewMapStorage()), ctx)
+
+	if _, err := adapter.SetNX(storage, "k", "v", 0); !errors.Is(err, context.Canceled) {
+		t.Fatalf("SetNX error = %v, want context.Canceled", err)
+	}
+	if err := adapter.HSet(storage, "h", "f", "v"); !errors.Is(err, context.Canceled) {
+		t.Fatalf("HSet error = %v, want context.Canceled", err)
+	}
+}
@@ -0,0 +1,52 @@
+package adapter
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+)
+
+// MaxBodySize Upper bound of request body bytes buffered by ReadBody | ReadBody缓冲请求体的字节上限
+const MaxBodySize int64 = 10 << 20
+
+// ReadBody Reads up to MaxBodySize bytes of the body and puts them back, later readers still see the whole body
+// 读取至多MaxBodySize字节的请求体并放回，后续读取者仍能读到完整请求体
+func ReadBody(r *http.Request) ([]byte, error) {
+	if r.Body == nil || r.Body == http.NoBody {
+		return nil, nil
+	}
+
+	// Every byte taken from the body goes back in front of the unread rest | 从请求体取出的字节全部放回未读部分之前
+	original := r.Body
+	var consumed bytes.Buffer
+	body, err := io.ReadAll(http.MaxBytesReader(nil, io.NopCloser(io.TeeReader(original, &consumed)), MaxBodySize))
+	r.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(&consumed, original), original}
+	if err != nil {
+		return nil, err
+	}
+	return body, nil
+}
+
+// IsFormURLEncoded Checks if content type is application/x-www-form-urlencoded | 检查Content-Type是否为application/x-www-form-urlencoded
+func IsFormURLEncoded(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && mediaType == "application/x-www-form-urlencoded"
+}
+
+// PostFormValue Reads a field of an urlencoded body through ReadBody, leaving the body readable | 通过ReadBody读取urlencoded请求体字段，请求体保持可读
+func PostFormValue(r *http.Request, key string) string {
+	body, err := ReadBody(r)
+	if err != nil {
+		return ""
+	}
+	values, err := url.ParseQuery(string(body))
+	if err != nil {
+		return ""
+	}
+	return values.Get(key)
+}
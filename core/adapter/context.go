@@ -6,7 +6,7 @@ type CookieOptions struct {
 	Name string
 	// Value Cookie value | Cookie值
 	Value string
-	// MaxAge Cookie expiration time in seconds, 0 means delete cookie, -1 means session cookie | 过期时间（秒），0表示删除cookie，-1表示会话cookie
+	// MaxAge Cookie expiration time in seconds, 0 means session cookie, negative deletes cookie | 过期时间（秒），0表示会话cookie，负数表示删除cookie
 	MaxAge int
 	// Path Cookie path | 路径
 	Path string
@@ -26,7 +26,7 @@ type Storage interface {
 	// Keys gets all keys matching pattern (e.g., "user:*") | 获取匹配模式的所有键（如："user:*"）
 	Keys(pattern string) ([]string, error)
 
-	// Expire sets expiration time for key | 设置键的过期时间
+	// Expire sets expiration time for key, <= 0 removes it (never expire) | 设置键的过期时间，<= 0时移除过期时间（永不过期）
 	Expire(key string, expiration time.Duration) error
 
 	// TTL gets remaining time to live (-1 if no expiration, -2 if key doesn't exist) | 获取键的剩余生存时间（-1表示永不过期，-2表示键不存在）
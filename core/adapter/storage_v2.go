@@ -0,0 +1,216 @@
+package adapter
+
+import (
+	"context"
+	"time"
+)
+
+// StorageV2 Context-aware storage, every call honors ctx cancellation and deadline | 感知上下文的存储，每次调用都遵循ctx的取消与截止时间
+// Method semantics match Storage, Exists also reports backend errors | 方法语义与Storage一致，Exists同时返回后端错误
+type StorageV2 interface {
+	// ============== Basic Operations | 基本操作 ==============
+
+	// Set sets key-value pair with optional expiration time (0 means never expire) | 设置键值对，可选过期时间（0表示永不过期）
+	Set(ctx context.Context, key string, value any, expiration time.Duration) error
+
+	// SetKeepTTL sets key-value pair but keeps the original TTL unchanged | 设置键值但保持原有TTL不变
+	SetKeepTTL(ctx context.Context, key string, value any) error
+
+	// Get gets value by key | 获取键对应的值
+	Get(ctx context.Context, key string) (any, error)
+
+	// Delete deletes one or more keys | 删除一个或多个键
+	Delete(ctx context.Context, keys ...string) error
+
+	// Exists checks if key exists | 检查键是否存在
+	Exists(ctx context.Context, key string) (bool, error)
+
+	// ============== Key Management | 键管理 ==============
+
+	// Keys gets all keys matching pattern (e.g., "user:*") | 获取匹配模式的所有键（如："user:*"）
+	Keys(ctx context.Context, pattern string) ([]string, error)
+
+	// Expire sets expiration time for key, <= 0 removes it (never expire) | 设置键的过期时间，<= 0时移除过期时间（永不过期）
+	Expire(ctx context.Context, key string, expiration time.Duration) error
+
+	// TTL gets remaining time to live (-1 if no expiration, -2 if key doesn't exist) | 获取键的剩余生存时间（-1表示永不过期，-2表示键不存在）
+	TTL(ctx context.Context, key string) (time.Duration, error)
+
+	// ============== Utility Methods | 工具方法 ==============
+
+	// Clear clears all data (use with caution, mainly for testing) | 清空所有数据（谨慎使用，主要用于测试）
+	Clear(ctx context.Context) error
+
+	// Ping checks if storage is accessible | 检查存储是否可访问
+	Ping(ctx context.Context) error
+}
+
+// StorageV2Provider Implemented by a Storage with a native StorageV2 form | 具有原生StorageV2形式的Storage实现该接口
+type StorageV2Provider interface {
+	StorageV2() StorageV2
+}
+
+// ContextCarrier Optional RequestContext extension exposing the request context | RequestContext的可选扩展，暴露请求上下文
+type ContextCarrier interface {
+	Context() context.Context
+}
+
+// AsStorageV2 Adapts Storage to StorageV2, native when it implements StorageV2Provider | 将Storage适配为StorageV2，实现了StorageV2Provider时使用原生实现
+// Legacy storages only check ctx before each call, a running call is not interrupted | 旧版存储仅在调用前检查ctx，不会中断执行中的调用
+func AsStorageV2(storage Storage) StorageV2 {
+	if storage == nil {
+		return nil
+	}
+	if provider, ok := storage.(StorageV2Provider); ok {
+		return provider.StorageV2()
+	}
+	return &legacyStorage{storage: storage}
+}
+
+// AsStorage Adapts StorageV2 to Storage bound to context.Background | 将StorageV2适配为绑定context.Background的Storage
+func AsStorage(storage StorageV2) Storage {
+	return BindContext(storage, context.Background())
+}
+
+// BindContext Binds StorageV2 to ctx, giving a Storage for code without ctx parameters | 将StorageV2绑定到ctx，供没有ctx参数的代码使用
+func BindContext(storage StorageV2, ctx context.Context) Storage {
+	if storage == nil {
+		return nil
+	}
+	if legacy, ok := storage.(*legacyStorage); ok && ctx == context.Background() {
+		return legacy.storage
+	}
+	return &boundStorage{storage: storage, ctx: ctx}
+}
+
+// ============ Legacy Storage | 旧版存储适配 ============
+
+// legacyStorage Storage seen as StorageV2 | 以StorageV2形式使用的Storage
+type legacyStorage struct {
+	storage Storage
+}
+
+func (s *legacyStorage) Set(ctx context.Context, key string, value any, expiration time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return s.storage.Set(key, value, expiration)
+}
+
+func (s *legacyStorage) SetKeepTTL(ctx context.Context, key string, value any) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return s.storage.SetKeepTTL(key, value)
+}
+
+func (s *legacyStorage) Get(ctx context.Context, key string) (any, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return s.storage.Get(key)
+}
+
+func (s *legacyStorage) Delete(ctx context.Context, keys ...string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return s.storage.Delete(keys...)
+}
+
+func (s *legacyStorage) Exists(ctx context.Context, key string) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+	return s.storage.Exists(key), nil
+}
+
+func (s *legacyStorage) Keys(ctx context.Context, pattern string) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return s.storage.Keys(pattern)
+}
+
+func (s *legacyStorage) Expire(ctx context.Context, key string, expiration time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return s.storage.Expire(key, expiration)
+}
+
+func (s *legacyStorage) TTL(ctx context.Context, key string) (time.Duration, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	return s.storage.TTL(key)
+}
+
+func (s *legacyStorage) Clear(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return s.storage.Clear()
+}
+
+func (s *legacyStorage) Ping(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return s.storage.Ping()
+}
+
+// ============ Bound Storage | 绑定上下文的存储 ============
+
+// boundStorage StorageV2 bound to a context, seen as Storage | 绑定上下文、以Storage形式使用的StorageV2
+type boundStorage struct {
+	storage StorageV2
+	ctx     context.Context
+}
+
+// StorageV2 Returns the underlying StorageV2 | 返回底层StorageV2
+func (s *boundStorage) StorageV2() StorageV2 {
+	return s.storage
+}
+
+func (s *boundStorage) Set(key string, value any, expiration time.Duration) error {
+	return s.storage.Set(s.ctx, key, value, expiration)
+}
+
+func (s *boundStorage) SetKeepTTL(key string, value any) error {
+	return s.storage.SetKeepTTL(s.ctx, key, value)
+}
+
+func (s *boundStorage) Get(key string) (any, error) {
+	return s.storage.Get(s.ctx, key)
+}
+
+func (s *boundStorage) Delete(keys ...string) error {
+	return s.storage.Delete(s.ctx, keys...)
+}
+
+// Exists Reports false on error, as Storage.Exists cannot return it | 出错时返回false（Storage.Exists无法返回错误）
+func (s *boundStorage) Exists(key string) bool {
+	exists, err := s.storage.Exists(s.ctx, key)
+	return err == nil && exists
+}
+
+func (s *boundStorage) Keys(pattern string) ([]string, error) {
+	return s.storage.Keys(s.ctx, pattern)
+}
+
+func (s *boundStorage) Expire(key string, expiration time.Duration) error {
+	return s.storage.Expire(s.ctx, key, expiration)
+}
+
+func (s *boundStorage) TTL(key string) (time.Duration, error) {
+	return s.storage.TTL(s.ctx, key)
+}
+
+func (s *boundStorage) Clear() error {
+	return s.storage.Clear(s.ctx)
+}
+
+func (s *boundStorage) Ping() error {
+	return s.storage.Ping(s.ctx)
+}
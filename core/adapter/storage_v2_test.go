@@ -0,0 +1,107 @@
+package adapter_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/click33/sa-token-go/core/adapter"
+	"github.com/click33/sa-token-go/core/adapter/adaptertest"
+)
+
+// providerStorage Legacy storage that also offers a native StorageV2 | 同时提供原生StorageV2的旧版存储
+type providerStorage struct {
+	*adaptertest.MapStorage
+	v2 adapter.StorageV2
+}
+
+func (s *providerStorage) StorageV2() adapter.StorageV2 {
+	return s.v2
+}
+
+func TestAsStorageV2(t *testing.T) {
+	if adapter.AsStorageV2(nil) != nil {
+		t.Fatal("AsStorageV2(nil) != nil")
+	}
+
+	native := adapter.AsStorageV2(adaptertest.NewMapStorage())
+	provider := &providerStorage{MapStorage: adaptertest.NewMapStorage(), v2: native}
+	if adapter.AsStorageV2(provider) != native {
+		t.Fatal("AsStorageV2() must return the StorageV2 of a provider")
+	}
+
+	// Legacy storages are wrapped and share their data | 旧版存储被包装且共享数据
+	legacy := adaptertest.NewMapStorage()
+	v2 := adapter.AsStorageV2(legacy)
+	ctx := context.Background()
+	if err := v2.Set(ctx, "k", "v", time.Minute); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if value, err := legacy.Get("k"); err != nil || value != "v" {
+		t.Fatalf("legacy Get() = %v, %v, want v", value, err)
+	}
+	if exists, err := v2.Exists(ctx, "k"); err != nil || !exists {
+		t.Fatalf("Exists() = %v, %v, want true", exists, err)
+	}
+	if exists, err := v2.Exists(ctx, "missing"); err != nil || exists {
+		t.Fatalf("Exists(missing) = %v, %v, want false", exists, err)
+	}
+}
+
+func TestBindContext(t *testing.T) {
+	if adapter.BindContext(nil, context.Background()) != nil {
+		t.Fatal("BindContext(nil) != nil")
+	}
+
+	// Background binding of a legacy storage unwraps to the storage itself | 旧版存储绑定Background时直接返回原存储
+	legacy := adaptertest.NewMapStorage()
+	if adapter.AsStorage(adapter.AsStorageV2(legacy)) != adapter.Storage(legacy) {
+		t.Fatal("AsStorage(AsStorageV2(legacy)) must unwrap to legacy")
+	}
+
+	ctx := context.WithValue(context.Background(), ctxKey{}, "request")
+	bound := adapter.BindContext(adapter.AsStorageV2(legacy), ctx)
+	if err := bound.Set("k", "v", time.Minute); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if value, err := legacy.Get("k"); err != nil || value != "v" {
+		t.Fatalf("legacy Get() = %v, %v, want v", value, err)
+	}
+	if !bound.Exists("k") {
+		t.Fatal("Exists() = false for a stored key")
+	}
+}
+
+func TestBindContextCancelled(t *testing.T) {
+	legacy := adaptertest.NewMapStorage()
+	_ = legacy.Set("k", "v", 0)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	bound := adapter.BindContext(adapter.AsStorageV2(legacy), ctx)
+
+	if err := bound.Set("other", "v", 0); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Set() error = %v, want context.Canceled", err)
+	}
+	if legacy.Exists("other") {
+		t.Fatal("Set() reached the storage on a cancelled context")
+	}
+	if _, err := bound.Get("k"); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Get() error = %v, want context.Canceled", err)
+	}
+	if err := bound.Delete("k"); !errors.Is(err, context.Canceled) || !legacy.Exists("k") {
+		t.Fatalf("Delete() error = %v, want context.Canceled and the key kept", err)
+	}
+
+	// Exists hides the failure, CheckExists reports it | Exists隐藏故障，CheckExists会报告
+	if bound.Exists("k") {
+		t.Fatal("Exists() = true on a cancelled context")
+	}
+	if _, err := adapter.CheckExists(bound, "k"); !errors.Is(err, context.Canceled) {
+		t.Fatalf("CheckExists() error = %v, want context.Canceled", err)
+	}
+	if exists, err := adapter.CheckExists(legacy, "k"); err != nil || !exists {
+		t.Fatalf("CheckExists(legacy) = %v, %v, want true", exists, err)
+	}
+}
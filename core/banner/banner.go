@@ -79,6 +79,12 @@ func formatCount(count int) string {
 // tokenReadSources returns a compact summary of token read sources | 返回 Token 读取来源的紧凑摘要
 func tokenReadSources(cfg *config.Config) string {
 	var parts []string
+	if len(cfg.TokenSources) > 0 {
+		for _, source := range cfg.TokenSources {
+			parts = append(parts, string(source))
+		}
+		return strings.Join(parts, ", ")
+	}
 	if cfg.IsReadHeader {
 		parts = append(parts, "Header")
 	}
@@ -88,6 +94,9 @@ func tokenReadSources(cfg *config.Config) string {
 	if cfg.IsReadBody {
 		parts = append(parts, "Body")
 	}
+	if !cfg.DisableReadQuery {
+		parts = append(parts, "Query")
+	}
 	if len(parts) == 0 {
 		return "(none)"
 	}
@@ -128,6 +137,12 @@ func PrintWithConfig(cfg *config.Config) {
 	// Token Read Sources (compact) | Token 读取来源（紧凑显示）
 	fmt.Println("├─────────────────────────────────────────────────────────┤")
 	fmt.Print(formatConfigLine("Read From", tokenReadSources(cfg)))
+	if cfg.TokenPrefix != "" {
+		fmt.Print(formatConfigLine("Token Prefix", cfg.TokenPrefix))
+	}
+	if cfg.TokenResponseHeader != "" {
+		fmt.Print(formatConfigLine("Response Header", cfg.TokenResponseHeader))
+	}
 
 	// Security & Storage | 安全与存储
 	fmt.Println("├─────────────────────────────────────────────────────────┤")
@@ -136,6 +151,9 @@ func PrintWithConfig(cfg *config.Config) {
 	} else {
 		fmt.Print(formatConfigLine("JWT Secret Key", "(not used)"))
 	}
+	if cfg.TokenStyle == config.TokenStyleEncrypted {
+		fmt.Print(formatConfigLine("Token Encrypt Key", configured))
+	}
 
 	// Cookie Configuration (only if enabled) | Cookie 配置（仅当启用时显示）
 	fmt.Println("├─────────────────────────────────────────────────────────┤")
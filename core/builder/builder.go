@@ -10,11 +10,13 @@ import (
 	"github.com/click33/sa-token-go/core/banner"
 	"github.com/click33/sa-token-go/core/config"
 	"github.com/click33/sa-token-go/core/manager"
+	"github.com/click33/sa-token-go/core/token"
 )
 
 // Builder Sa-Token builder for fluent configuration | Sa-Token构建器，用于流式配置
 type Builder struct {
 	storage                adapter.Storage
+	loginType              string
 	tokenName              string
 	timeout                int64
 	maxRefresh             int64
@@ -23,24 +25,43 @@ type Builder struct {
 	isConcurrent           bool
 	isShare                bool
 	maxLoginCount          int
+	overflowPolicy         config.OverflowPolicy
 	tokenStyle             config.TokenStyle
 	autoRenew              bool
 	jwtSecretKey           string
+	jwtMode                config.JwtMode
+	jwtClaims              *config.JwtClaimsConfig
+	jwtRevocation          bool
+	jwtClaimsHook          manager.JwtClaimsHook
 	isLog                  bool
 	isPrintBanner          bool
 	isReadBody             bool
+	tokenBodyPath          string
 	isReadHeader           bool
 	isReadCookie           bool
+	isReadQuery            bool
+	tokenSources           []config.TokenSource
+	tokenPrefix            string
+	tokenResponseHeader    string
 	dataRefreshPeriod      int64
 	tokenSessionCheckLogin bool
 	keyPrefix              string
 	cookieConfig           *config.CookieConfig
 	renewPoolConfig        *pool.RenewPoolConfig
+	permissionProvider     manager.PermissionProvider
+	jwtSigningKey          *token.JwtKey
+	jwtVerifyKeys          []*token.JwtKey
+	tokenEncryptKey        string
+	encryptKey             *token.EncryptKey
+	decryptKeys            []*token.EncryptKey
+	tokenGenerator         token.TokenGenerator
+	permissionCacheTTL     int64
 }
 
 // NewBuilder creates a new builder with default configuration | 创建新的构建器（使用默认配置）
 func NewBuilder() *Builder {
 	return &Builder{
+		loginType:              config.DefaultLoginType,
 		tokenName:              config.DefaultTokenName,
 		timeout:                config.DefaultTimeout,
 		maxRefresh:             config.DefaultTimeout / 2,
@@ -49,6 +70,7 @@ func NewBuilder() *Builder {
 		isConcurrent:           true,
 		isShare:                true,
 		maxLoginCount:          config.DefaultMaxLoginCount,
+		overflowPolicy:         config.OverflowReject,
 		tokenStyle:             config.TokenStyleUUID,
 		autoRenew:              true,
 		isLog:                  false,
@@ -56,6 +78,7 @@ func NewBuilder() *Builder {
 		isReadBody:             false,
 		isReadHeader:           true,
 		isReadCookie:           false,
+		isReadQuery:            true,
 		dataRefreshPeriod:      config.NoLimit,
 		tokenSessionCheckLogin: true,
 		keyPrefix:              "satoken:",
@@ -76,6 +99,18 @@ func (b *Builder) Storage(storage adapter.Storage) *Builder {
 	return b
 }
 
+// StorageV2 sets context-aware storage | 设置上下文感知存储
+func (b *Builder) StorageV2(storage adapter.StorageV2) *Builder {
+	b.storage = adapter.AsStorage(storage)
+	return b
+}
+
+// LoginType sets account realm name | 设置账号体系标识
+func (b *Builder) LoginType(loginType string) *Builder {
+	b.loginType = loginType
+	return b
+}
+
 // TokenName sets token name | 设置Token名称
 func (b *Builder) TokenName(name string) *Builder {
 	b.tokenName = name
@@ -130,6 +165,12 @@ func (b *Builder) MaxLoginCount(count int) *Builder {
 	return b
 }
 
+// OverflowPolicy sets behavior when MaxLoginCount is reached | 设置达到最大登录数量时的处理策略
+func (b *Builder) OverflowPolicy(policy config.OverflowPolicy) *Builder {
+	b.overflowPolicy = policy
+	return b
+}
+
 // TokenStyle sets token generation style | 设置Token风格
 func (b *Builder) TokenStyle(style config.TokenStyle) *Builder {
 	b.tokenStyle = style
@@ -148,6 +189,93 @@ func (b *Builder) JwtSecretKey(key string) *Builder {
 	return b
 }
 
+// JwtMode sets storage dependency of JWT tokens | 设置JWT Token的存储依赖模式
+func (b *Builder) JwtMode(mode config.JwtMode) *Builder {
+	b.jwtMode = mode
+	return b
+}
+
+// JwtIssuer sets JWT issuer written and required on parse | 设置签发并在解析时校验的JWT签发者
+func (b *Builder) JwtIssuer(issuer string) *Builder {
+	if b.jwtClaims == nil {
+		b.jwtClaims = &config.JwtClaimsConfig{}
+	}
+	b.jwtClaims.Issuer = issuer
+	return b
+}
+
+// JwtAudience sets JWT audience, parse requires any of them | 设置JWT受众，解析时需匹配其一
+func (b *Builder) JwtAudience(audience ...string) *Builder {
+	if b.jwtClaims == nil {
+		b.jwtClaims = &config.JwtClaimsConfig{}
+	}
+	b.jwtClaims.Audience = audience
+	return b
+}
+
+// JwtLeeway sets clock skew leeway in seconds for JWT time claims | 设置JWT时间声明的时钟偏差（秒）
+func (b *Builder) JwtLeeway(leeway int64) *Builder {
+	if b.jwtClaims == nil {
+		b.jwtClaims = &config.JwtClaimsConfig{}
+	}
+	b.jwtClaims.Leeway = leeway
+	return b
+}
+
+// JwtClaims sets complete JWT claims configuration | 设置完整的JWT声明配置
+func (b *Builder) JwtClaims(cfg *config.JwtClaimsConfig) *Builder {
+	b.jwtClaims = cfg
+	return b
+}
+
+// JwtClaimsHook sets hook that injects custom claims at login | 设置登录时注入自定义声明的钩子
+func (b *Builder) JwtClaimsHook(hook manager.JwtClaimsHook) *Builder {
+	b.jwtClaimsHook = hook
+	return b
+}
+
+// JwtRevocation sets whether to check JWT revocation list and not-before watermark | 设置是否校验JWT吊销列表与not-before水位
+func (b *Builder) JwtRevocation(revocation bool) *Builder {
+	b.jwtRevocation = revocation
+	return b
+}
+
+// JwtSigningKey sets asymmetric or kid-tagged JWT signing key | 设置非对称或带kid的JWT签名密钥
+func (b *Builder) JwtSigningKey(key *token.JwtKey) *Builder {
+	b.jwtSigningKey = key
+	return b
+}
+
+// JwtVerifyKey adds extra JWT verification key (e.g. during rotation) | 添加额外的JWT验签密钥（如轮换期间）
+func (b *Builder) JwtVerifyKey(key *token.JwtKey) *Builder {
+	b.jwtVerifyKeys = append(b.jwtVerifyKeys, key)
+	return b
+}
+
+// TokenEncryptKey sets secret of encrypted tokens | 设置加密Token的密钥
+func (b *Builder) TokenEncryptKey(key string) *Builder {
+	b.tokenEncryptKey = key
+	return b
+}
+
+// EncryptKey sets kid-tagged key encrypting new tokens | 设置用于加密新Token的带kid密钥
+func (b *Builder) EncryptKey(key *token.EncryptKey) *Builder {
+	b.encryptKey = key
+	return b
+}
+
+// DecryptKey adds extra key decrypting tokens (e.g. during rotation) | 添加额外的Token解密密钥（如轮换期间）
+func (b *Builder) DecryptKey(key *token.EncryptKey) *Builder {
+	b.decryptKeys = append(b.decryptKeys, key)
+	return b
+}
+
+// TokenGenerator sets custom token generator overriding TokenStyle | 设置覆盖TokenStyle的自定义Token生成器
+func (b *Builder) TokenGenerator(gen token.TokenGenerator) *Builder {
+	b.tokenGenerator = gen
+	return b
+}
+
 // IsLog sets whether to enable logging | 设置是否输出日志
 func (b *Builder) IsLog(isLog bool) *Builder {
 	b.isLog = isLog
@@ -166,6 +294,12 @@ func (b *Builder) IsReadBody(isRead bool) *Builder {
 	return b
 }
 
+// TokenBodyPath sets dot-separated field path of body token, e.g. "data.token" | 设置请求体Token的点分字段路径，如"data.token"
+func (b *Builder) TokenBodyPath(path string) *Builder {
+	b.tokenBodyPath = path
+	return b
+}
+
 // IsReadHeader sets whether to read token from header | 设置是否从Header读取Token
 func (b *Builder) IsReadHeader(isRead bool) *Builder {
 	b.isReadHeader = isRead
@@ -178,6 +312,30 @@ func (b *Builder) IsReadCookie(isRead bool) *Builder {
 	return b
 }
 
+// IsReadQuery sets whether to read token from query string | 设置是否从查询参数读取Token
+func (b *Builder) IsReadQuery(isRead bool) *Builder {
+	b.isReadQuery = isRead
+	return b
+}
+
+// TokenSources sets ordered token sources, overrides IsReadXxx | 设置有序的Token读取来源（覆盖IsReadXxx）
+func (b *Builder) TokenSources(sources ...config.TokenSource) *Builder {
+	b.tokenSources = sources
+	return b
+}
+
+// TokenPrefix sets required prefix of header tokens, e.g. "Bearer" | 设置请求头Token必须携带的前缀，如"Bearer"
+func (b *Builder) TokenPrefix(prefix string) *Builder {
+	b.tokenPrefix = prefix
+	return b
+}
+
+// TokenResponseHeader sets response header carrying the token after login | 设置登录后携带Token的响应头
+func (b *Builder) TokenResponseHeader(header string) *Builder {
+	b.tokenResponseHeader = header
+	return b
+}
+
 // DataRefreshPeriod sets data refresh period | 设置数据刷新周期
 func (b *Builder) DataRefreshPeriod(seconds int64) *Builder {
 	b.dataRefreshPeriod = seconds
@@ -256,6 +414,18 @@ func (b *Builder) RenewPoolConfig(cfg *pool.RenewPoolConfig) *Builder {
 	return b
 }
 
+// PermissionProvider sets on-demand permission and role source | 设置按需加载的权限与角色数据源
+func (b *Builder) PermissionProvider(provider manager.PermissionProvider) *Builder {
+	b.permissionProvider = provider
+	return b
+}
+
+// PermissionCacheTTL sets provider result cache time in seconds, 0 disables caching | 设置数据源结果缓存时间（秒），0表示不缓存
+func (b *Builder) PermissionCacheTTL(seconds int64) *Builder {
+	b.permissionCacheTTL = seconds
+	return b
+}
+
 // KeyPrefix sets storage key prefix | 设置存储键前缀
 // Automatically adds ":" suffix if not present (except for empty string) | 自动添加 ":" 后缀（空字符串除外）
 // Examples: "satoken" -> "satoken:", "myapp" -> "myapp:", "" -> ""
@@ -298,12 +468,54 @@ func (b *Builder) Validate() error {
 		return fmt.Errorf("tokenName cannot be empty")
 	}
 
-	if b.tokenStyle == config.TokenStyleJWT && b.jwtSecretKey == "" {
-		return fmt.Errorf("jwtSecretKey is required when TokenStyle is JWT")
+	if strings.Contains(b.loginType, ":") {
+		return fmt.Errorf("loginType cannot contain ':', got: %s", b.loginType)
 	}
 
-	if !b.isReadHeader && !b.isReadCookie && !b.isReadBody {
-		return fmt.Errorf("at least one of IsReadHeader, IsReadCookie, or IsReadBody must be true")
+	if !b.tokenStyle.IsValid() {
+		return fmt.Errorf("invalid TokenStyle: %s, custom styles must be registered by token.RegisterStyle", b.tokenStyle)
+	}
+
+	if b.tokenStyle == config.TokenStyleJWT && b.jwtSecretKey == "" && b.jwtSigningKey == nil {
+		return fmt.Errorf("jwtSecretKey or jwtSigningKey is required when TokenStyle is JWT")
+	}
+
+	if b.tokenStyle == config.TokenStyleEncrypted && b.tokenEncryptKey == "" && b.encryptKey == nil {
+		return fmt.Errorf("tokenEncryptKey or encryptKey is required when TokenStyle is Encrypted")
+	}
+
+	if b.tokenGenerator != nil && (b.tokenStyle == config.TokenStyleJWT || b.tokenStyle == config.TokenStyleEncrypted) {
+		return fmt.Errorf("TokenGenerator cannot be combined with TokenStyle %s", b.tokenStyle)
+	}
+
+	if !b.jwtMode.IsValid() {
+		return fmt.Errorf("invalid JwtMode: %s", b.jwtMode)
+	}
+
+	if b.jwtMode != config.JwtModeDefault && b.tokenStyle != config.TokenStyleJWT {
+		return fmt.Errorf("JwtMode %s requires TokenStyle to be JWT", b.jwtMode)
+	}
+
+	if b.jwtClaims != nil && b.jwtClaims.Leeway < 0 {
+		return fmt.Errorf("JwtClaims.Leeway must be >= 0, got: %d", b.jwtClaims.Leeway)
+	}
+
+	if b.jwtSigningKey != nil && !b.jwtSigningKey.CanSign() {
+		return fmt.Errorf("jwtSigningKey must contain a private key")
+	}
+
+	if !b.isReadHeader && !b.isReadCookie && !b.isReadBody && !b.isReadQuery && len(b.tokenSources) == 0 {
+		return fmt.Errorf("at least one of IsReadHeader, IsReadCookie, IsReadBody, IsReadQuery or TokenSources must be set")
+	}
+
+	for _, source := range b.tokenSources {
+		if !source.IsValid() {
+			return fmt.Errorf("invalid TokenSource: %s", source)
+		}
+	}
+
+	if strings.ContainsAny(b.tokenPrefix, " \t") {
+		return fmt.Errorf("TokenPrefix cannot contain whitespace, got: %q", b.tokenPrefix)
 	}
 
 	// Check MaxRefresh
@@ -321,6 +533,11 @@ func (b *Builder) Validate() error {
 		return fmt.Errorf("RenewInterval must be >= -1, got: %d", b.renewInterval)
 	}
 
+	// Check OverflowPolicy
+	if !b.overflowPolicy.IsValid() {
+		return fmt.Errorf("invalid OverflowPolicy: %s", b.overflowPolicy)
+	}
+
 	// Validate RenewPoolConfig if set | 如果设置了续期池配置，进行验证
 	if b.renewPoolConfig != nil {
 		// Check MinSize and MaxSize | 检查最小和最大协程池大小
@@ -366,6 +583,7 @@ func (b *Builder) Build() *manager.Manager {
 	}
 
 	cfg := &config.Config{
+		LoginType:              b.loginType,
 		TokenName:              b.tokenName,
 		Timeout:                b.timeout,
 		MaxRefresh:             b.maxRefresh,
@@ -374,14 +592,24 @@ func (b *Builder) Build() *manager.Manager {
 		IsConcurrent:           b.isConcurrent,
 		IsShare:                b.isShare,
 		MaxLoginCount:          b.maxLoginCount,
+		OverflowPolicy:         b.overflowPolicy,
 		IsReadBody:             b.isReadBody,
+		TokenBodyPath:          b.tokenBodyPath,
 		IsReadHeader:           b.isReadHeader,
 		IsReadCookie:           b.isReadCookie,
+		DisableReadQuery:       !b.isReadQuery,
+		TokenSources:           b.tokenSources,
+		TokenPrefix:            b.tokenPrefix,
+		TokenResponseHeader:    b.tokenResponseHeader,
 		TokenStyle:             b.tokenStyle,
 		DataRefreshPeriod:      b.dataRefreshPeriod,
 		TokenSessionCheckLogin: b.tokenSessionCheckLogin,
 		AutoRenew:              b.autoRenew,
 		JwtSecretKey:           b.jwtSecretKey,
+		JwtMode:                b.jwtMode,
+		JwtClaims:              b.jwtClaims,
+		JwtRevocation:          b.jwtRevocation,
+		TokenEncryptKey:        b.tokenEncryptKey,
 		IsLog:                  b.isLog,
 		IsPrintBanner:          b.isPrintBanner,
 		KeyPrefix:              b.keyPrefix,
@@ -396,6 +624,40 @@ func (b *Builder) Build() *manager.Manager {
 	}
 
 	mgr := manager.NewManager(b.storage, cfg)
+	for _, key := range b.jwtVerifyKeys {
+		if err := mgr.GetJwtKeySet().AddKey(key); err != nil {
+			panic(fmt.Sprintf("invalid jwt verify key: %v", err))
+		}
+	}
+	if b.jwtSigningKey != nil {
+		if err := mgr.GetJwtKeySet().Rotate(b.jwtSigningKey); err != nil {
+			panic(fmt.Sprintf("invalid jwt signing key: %v", err))
+		}
+		// Without a configured secret the legacy key would be the public default one | 未配置密钥时旧版密钥为公开的默认值，需移除
+		if b.jwtSecretKey == "" {
+			_ = mgr.GetJwtKeySet().RemoveKey("")
+		}
+	}
+	for _, key := range b.decryptKeys {
+		if err := mgr.GetEncryptKeySet().AddKey(key); err != nil {
+			panic(fmt.Sprintf("invalid decrypt key: %v", err))
+		}
+	}
+	if b.encryptKey != nil {
+		if err := mgr.GetEncryptKeySet().Rotate(b.encryptKey); err != nil {
+			panic(fmt.Sprintf("invalid encrypt key: %v", err))
+		}
+	}
+	if b.tokenGenerator != nil {
+		mgr.SetTokenGenerator(b.tokenGenerator)
+	}
+	if b.permissionProvider != nil {
+		mgr.SetPermissionProvider(b.permissionProvider)
+		mgr.SetPermissionCacheTTL(time.Duration(b.permissionCacheTTL) * time.Second)
+	}
+	if b.jwtClaimsHook != nil {
+		mgr.SetJwtClaimsHook(b.jwtClaimsHook)
+	}
 
 	// Note: If you use the stputil package, it will automatically set the global Manager | 注意：如果你使用了 stputil 包，它会自动设置全局 Manager
 	// We don't directly call stputil.SetManager here to avoid hard dependencies | 这里不直接调用 stputil.SetManager，避免强依赖
@@ -2,6 +2,9 @@ package config
 
 import (
 	"fmt"
+	"strings"
+	"sync"
+
 	"github.com/click33/sa-token-go/core/pool"
 )
 
@@ -27,6 +30,8 @@ const (
 	TokenStyleTimestamp TokenStyle = "timestamp"
 	// TokenStyleTik Short ID style (like TikTok) | Tik风格短ID（类似抖音）
 	TokenStyleTik TokenStyle = "tik"
+	// TokenStyleEncrypted AES-GCM encrypted opaque token carrying loginID, device and expiry | AES-GCM加密的不透明Token（携带loginID、设备与过期时间）
+	TokenStyleEncrypted TokenStyle = "encrypted"
 )
 
 // SameSiteMode Cookie SameSite attribute values | Cookie的SameSite属性值
@@ -41,29 +46,111 @@ const (
 	SameSiteNone SameSiteMode = "None"
 )
 
+// OverflowPolicy Behavior when MaxLoginCount is reached | 达到最大登录数量时的处理策略
+type OverflowPolicy string
+
+const (
+	// OverflowReject Reject the new login (default) | 拒绝新登录（默认）
+	OverflowReject OverflowPolicy = "reject"
+	// OverflowKickOldest Replace the token with the earliest CreateTime | 顶掉创建时间最早的Token
+	OverflowKickOldest OverflowPolicy = "kick_oldest"
+	// OverflowKickLeastActive Replace the token with the earliest ActiveTime, refreshed by IsLogin/CheckLogin | 顶掉最久未活跃的Token（活跃时间由IsLogin/CheckLogin刷新）
+	OverflowKickLeastActive OverflowPolicy = "kick_least_active"
+	// OverflowKickSameDevice Replace the token of the same device, reject if none | 顶掉同设备的Token，无同设备Token时拒绝
+	OverflowKickSameDevice OverflowPolicy = "kick_same_device"
+)
+
+// IsValid checks if the OverflowPolicy is valid, empty means reject | 检查OverflowPolicy是否有效，空值视为拒绝
+func (p OverflowPolicy) IsValid() bool {
+	switch p {
+	case "", OverflowReject, OverflowKickOldest, OverflowKickLeastActive, OverflowKickSameDevice:
+		return true
+	default:
+		return false
+	}
+}
+
+// JwtMode How much a JWT token relies on storage (only effective when TokenStyle=JWT) | JWT对存储的依赖程度（只有TokenStyle=JWT时生效）
+type JwtMode string
+
+const (
+	// JwtModeDefault JWT is only the token format, all state lives in storage | JWT仅作为Token格式，所有状态保存在存储中
+	JwtModeDefault JwtMode = ""
+	// JwtModeSimple Claims are authoritative, storage is only used for logout/kickout markers and sessions | 以声明为准，存储仅用于注销/踢下线标记与Session
+	JwtModeSimple JwtMode = "simple"
+	// JwtModeMixed Full storage state, claims must also be valid and carry permissions | 完整存储状态，同时要求声明有效并携带权限
+	JwtModeMixed JwtMode = "mixed"
+	// JwtModeStateless Served purely from signed claims, storage is never touched | 完全基于签名声明，不访问存储
+	JwtModeStateless JwtMode = "stateless"
+)
+
+// IsValid checks if the JwtMode is valid, empty means default | 检查JwtMode是否有效，空值视为默认
+func (m JwtMode) IsValid() bool {
+	switch m {
+	case JwtModeDefault, JwtModeSimple, JwtModeMixed, JwtModeStateless:
+		return true
+	default:
+		return false
+	}
+}
+
 // Default configuration constants | 默认配置常量
 const (
 	DefaultTokenName     = "satoken"
 	DefaultTimeout       = 2592000 // 30 days in seconds | 30天（秒）
 	DefaultMaxLoginCount = 12      // Maximum concurrent logins | 最大并发登录数
 	DefaultCookiePath    = "/"
-	NoLimit              = -1 // No limit flag | 不限制标志
+	DefaultLoginType     = "login" // Default account realm | 默认账号体系
+	NoLimit              = -1      // No limit flag | 不限制标志
 )
 
-// IsValid checks if the TokenStyle is valid | 检查TokenStyle是否有效
+// IsValid checks if the TokenStyle is built-in or registered | 检查TokenStyle是否为内置或已注册风格
 func (ts TokenStyle) IsValid() bool {
+	if ts.IsBuiltin() {
+		return true
+	}
+	customStylesMu.RLock()
+	defer customStylesMu.RUnlock()
+	return customStyles[ts]
+}
+
+// IsBuiltin checks if the TokenStyle is one of the built-in styles | 检查TokenStyle是否为内置风格
+func (ts TokenStyle) IsBuiltin() bool {
 	switch ts {
 	case TokenStyleUUID, TokenStyleSimple, TokenStyleRandom32,
 		TokenStyleRandom64, TokenStyleRandom128, TokenStyleJWT,
-		TokenStyleHash, TokenStyleTimestamp, TokenStyleTik:
+		TokenStyleHash, TokenStyleTimestamp, TokenStyleTik, TokenStyleEncrypted:
 		return true
 	default:
 		return false
 	}
 }
 
+var (
+	customStylesMu sync.RWMutex
+	customStyles   = make(map[TokenStyle]bool)
+)
+
+// RegisterTokenStyle Registers custom style name so it passes validation, see token.RegisterStyle | 注册自定义风格名称使其通过校验，参见token.RegisterStyle
+func RegisterTokenStyle(style TokenStyle) error {
+	if style == "" {
+		return fmt.Errorf("token style cannot be empty")
+	}
+	if style.IsBuiltin() {
+		return fmt.Errorf("token style %s is built-in", style)
+	}
+
+	customStylesMu.Lock()
+	defer customStylesMu.Unlock()
+	customStyles[style] = true
+	return nil
+}
+
 // Config Sa-Token configuration | Sa-Token配置
 type Config struct {
+	// LoginType Account realm name, each realm has isolated storage keys (default: "login") | 账号体系标识，不同体系的存储键相互隔离（默认："login"）
+	LoginType string
+
 	// TokenName Token name (also used as Cookie name) | Token名称（同时也是Cookie名称）
 	TokenName string
 
@@ -88,15 +175,33 @@ type Config struct {
 	// MaxLoginCount Maximum number of concurrent logins for the same account, -1 means no limit (only effective when IsConcurrent=true and IsShare=false) | 同一账号最大登录数量，-1代表不限（只有在IsConcurrent=true，IsShare=false时此配置才有效）
 	MaxLoginCount int
 
+	// OverflowPolicy Behavior when MaxLoginCount is reached (default: reject) | 达到最大登录数量时的处理策略（默认：拒绝）
+	OverflowPolicy OverflowPolicy
+
 	// IsReadBody Try to read Token from request body (default: false) | 是否尝试从请求体里读取Token（默认：false）
 	IsReadBody bool
 
+	// TokenBodyPath Dot-separated field path of body Token, e.g. "data.token" (default: TokenName) | 请求体Token的字段路径，以点分隔，如"data.token"（默认：TokenName）
+	TokenBodyPath string
+
 	// IsReadHeader Try to read Token from HTTP Header (default: true, recommended) | 是否尝试从Header里读取Token（默认：true，推荐）
 	IsReadHeader bool
 
 	// IsReadCookie Try to read Token from Cookie (default: false) | 是否尝试从Cookie里读取Token（默认：false）
 	IsReadCookie bool
 
+	// DisableReadQuery Stop reading Token from query string, the zero value keeps it on (default: false) | 不再从查询参数里读取Token，零值保持读取（默认：false）
+	DisableReadQuery bool
+
+	// TokenSources Ordered token sources, overrides IsReadXxx when set | 有序的Token读取来源，设置后覆盖IsReadXxx
+	TokenSources []TokenSource
+
+	// TokenPrefix Required prefix of header tokens, e.g. "Bearer" | 请求头Token必须携带的前缀，如"Bearer"
+	TokenPrefix string
+
+	// TokenResponseHeader Response header carrying the token after login, empty disables it | 登录后携带Token的响应头，为空时不写入
+	TokenResponseHeader string
+
 	// TokenStyle Token generation style | Token风格
 	TokenStyle TokenStyle
 
@@ -112,6 +217,18 @@ type Config struct {
 	// JwtSecretKey JWT secret key (only effective when TokenStyle=JWT) | JWT密钥（只有TokenStyle=JWT时，此配置才生效）
 	JwtSecretKey string
 
+	// JwtMode Storage dependency of JWT tokens (only effective when TokenStyle=JWT) | JWT Token的存储依赖模式（只有TokenStyle=JWT时生效）
+	JwtMode JwtMode
+
+	// JwtRevocation Check jti revocation list and per-account not-before watermark (only effective when TokenStyle=JWT) | 校验jti吊销列表与账号级not-before水位（只有TokenStyle=JWT时生效）
+	JwtRevocation bool
+
+	// JwtClaims Registered claims and validation of JWT tokens (only effective when TokenStyle=JWT) | JWT注册声明与校验配置（只有TokenStyle=JWT时生效）
+	JwtClaims *JwtClaimsConfig
+
+	// TokenEncryptKey Secret deriving the AES-256-GCM key (only effective when TokenStyle=Encrypted) | 派生AES-256-GCM密钥的密钥串（只有TokenStyle=Encrypted时生效）
+	TokenEncryptKey string
+
 	// IsLog Enable operation logging | 是否输出操作日志
 	IsLog bool
 
@@ -150,9 +267,34 @@ type CookieConfig struct {
 	MaxAge int
 }
 
+// JwtClaimsConfig JWT claims configuration | JWT声明配置
+type JwtClaimsConfig struct {
+	// Issuer "iss" written on signing and required on parse | 签发时写入、解析时校验的iss
+	Issuer string
+
+	// Audience "aud" written on signing, parse requires any of them | 签发时写入的aud，解析时需匹配其一
+	Audience []string
+
+	// Subject Write login ID as "sub" | 将登录ID写入sub
+	Subject bool
+
+	// NotBefore Write "nbf" equal to "iat" | 写入与iat相同的nbf
+	NotBefore bool
+
+	// JwtID Write random "jti" | 写入随机jti
+	JwtID bool
+
+	// Leeway Clock skew in seconds tolerated on exp, nbf and iat | 校验exp、nbf与iat时允许的时钟偏差（单位：秒）
+	Leeway int64
+
+	// EmbedPermissions Embed permissions and roles of account as claims at login | 登录时将账号的权限与角色写入声明
+	EmbedPermissions bool
+}
+
 // DefaultConfig Returns default configuration | 返回默认配置
 func DefaultConfig() *Config {
 	return &Config{
+		LoginType:              DefaultLoginType,
 		TokenName:              DefaultTokenName,
 		Timeout:                DefaultTimeout,
 		MaxRefresh:             DefaultTimeout / 2,
@@ -161,6 +303,7 @@ func DefaultConfig() *Config {
 		IsConcurrent:           true,
 		IsShare:                true,
 		MaxLoginCount:          DefaultMaxLoginCount,
+		OverflowPolicy:         OverflowReject,
 		IsReadBody:             false,
 		IsReadHeader:           true,
 		IsReadCookie:           false,
@@ -190,6 +333,11 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("TokenName cannot be empty")
 	}
 
+	// Check LoginType
+	if strings.Contains(c.LoginType, ":") {
+		return fmt.Errorf("LoginType cannot contain ':', got: %s", c.LoginType)
+	}
+
 	// Check TokenStyle
 	if !c.TokenStyle.IsValid() {
 		return fmt.Errorf("invalid TokenStyle: %s", c.TokenStyle)
@@ -200,6 +348,24 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("JwtSecretKey is required when TokenStyle is JWT")
 	}
 
+	// Check encrypt key when using encrypted style
+	if c.TokenStyle == TokenStyleEncrypted && c.TokenEncryptKey == "" {
+		return fmt.Errorf("TokenEncryptKey is required when TokenStyle is Encrypted")
+	}
+
+	// Check JwtMode
+	if !c.JwtMode.IsValid() {
+		return fmt.Errorf("invalid JwtMode: %s", c.JwtMode)
+	}
+	if c.JwtMode != JwtModeDefault && c.TokenStyle != TokenStyleJWT {
+		return fmt.Errorf("JwtMode %s requires TokenStyle to be JWT", c.JwtMode)
+	}
+
+	// Check JwtClaims
+	if c.JwtClaims != nil && c.JwtClaims.Leeway < 0 {
+		return fmt.Errorf("JwtClaims.Leeway must be >= 0, got: %d", c.JwtClaims.Leeway)
+	}
+
 	// Check Timeout
 	if c.Timeout < NoLimit {
 		return fmt.Errorf("Timeout must be >= -1, got: %d", c.Timeout)
@@ -230,9 +396,17 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("MaxLoginCount must be >= -1, got: %d", c.MaxLoginCount)
 	}
 
+	// Check OverflowPolicy
+	if !c.OverflowPolicy.IsValid() {
+		return fmt.Errorf("invalid OverflowPolicy: %s", c.OverflowPolicy)
+	}
+
 	// Check if at least one read source is enabled
-	if !c.IsReadHeader && !c.IsReadCookie && !c.IsReadBody {
-		return fmt.Errorf("at least one of IsReadHeader, IsReadCookie, or IsReadBody must be true")
+	if len(c.GetTokenSources()) == 0 {
+		return fmt.Errorf("at least one of IsReadHeader, IsReadCookie, IsReadBody, query (DisableReadQuery=false) or TokenSources must be set")
+	}
+	if err := validateTokenSources(c.TokenSources, c.TokenPrefix); err != nil {
+		return err
 	}
 
 	// Validate RenewPoolConfig if set | 如果设置了续期池配置，进行验证
@@ -274,9 +448,23 @@ func (c *Config) Clone() *Config {
 		cookieConfig := *c.CookieConfig
 		newConfig.CookieConfig = &cookieConfig
 	}
+	if c.TokenSources != nil {
+		newConfig.TokenSources = append([]TokenSource(nil), c.TokenSources...)
+	}
+	if c.JwtClaims != nil {
+		jwtClaims := *c.JwtClaims
+		jwtClaims.Audience = append([]string(nil), c.JwtClaims.Audience...)
+		newConfig.JwtClaims = &jwtClaims
+	}
 	return &newConfig
 }
 
+// SetLoginType Set account realm name | 设置账号体系标识
+func (c *Config) SetLoginType(loginType string) *Config {
+	c.LoginType = loginType
+	return c
+}
+
 // SetTokenName Set Token name | 设置Token名称
 func (c *Config) SetTokenName(name string) *Config {
 	c.TokenName = name
@@ -325,12 +513,24 @@ func (c *Config) SetMaxLoginCount(count int) *Config {
 	return c
 }
 
+// SetOverflowPolicy Set behavior when MaxLoginCount is reached | 设置达到最大登录数量时的处理策略
+func (c *Config) SetOverflowPolicy(policy OverflowPolicy) *Config {
+	c.OverflowPolicy = policy
+	return c
+}
+
 // SetIsReadBody Set whether to read Token from body | 设置是否从请求体读取Token
 func (c *Config) SetIsReadBody(isReadBody bool) *Config {
 	c.IsReadBody = isReadBody
 	return c
 }
 
+// SetTokenBodyPath Set field path of body Token | 设置请求体Token的字段路径
+func (c *Config) SetTokenBodyPath(path string) *Config {
+	c.TokenBodyPath = path
+	return c
+}
+
 // SetIsReadHeader Set whether to read Token from header | 设置是否从Header读取Token
 func (c *Config) SetIsReadHeader(isReadHeader bool) *Config {
 	c.IsReadHeader = isReadHeader
@@ -343,6 +543,30 @@ func (c *Config) SetIsReadCookie(isReadCookie bool) *Config {
 	return c
 }
 
+// SetIsReadQuery Set whether to read Token from query string | 设置是否从查询参数读取Token
+func (c *Config) SetIsReadQuery(isReadQuery bool) *Config {
+	c.DisableReadQuery = !isReadQuery
+	return c
+}
+
+// SetTokenSources Set ordered token sources | 设置有序的Token读取来源
+func (c *Config) SetTokenSources(sources ...TokenSource) *Config {
+	c.TokenSources = sources
+	return c
+}
+
+// SetTokenPrefix Set required prefix of header tokens | 设置请求头Token必须携带的前缀
+func (c *Config) SetTokenPrefix(prefix string) *Config {
+	c.TokenPrefix = prefix
+	return c
+}
+
+// SetTokenResponseHeader Set response header carrying the token after login | 设置登录后携带Token的响应头
+func (c *Config) SetTokenResponseHeader(header string) *Config {
+	c.TokenResponseHeader = header
+	return c
+}
+
 // SetTokenStyle Set Token generation style | 设置Token风格
 func (c *Config) SetTokenStyle(style TokenStyle) *Config {
 	c.TokenStyle = style
@@ -367,6 +591,30 @@ func (c *Config) SetJwtSecretKey(key string) *Config {
 	return c
 }
 
+// SetTokenEncryptKey Set secret of encrypted tokens | 设置加密Token的密钥
+func (c *Config) SetTokenEncryptKey(key string) *Config {
+	c.TokenEncryptKey = key
+	return c
+}
+
+// SetJwtMode Set storage dependency of JWT tokens | 设置JWT Token的存储依赖模式
+func (c *Config) SetJwtMode(mode JwtMode) *Config {
+	c.JwtMode = mode
+	return c
+}
+
+// SetJwtRevocation Set whether to check JWT revocation | 设置是否校验JWT吊销
+func (c *Config) SetJwtRevocation(revocation bool) *Config {
+	c.JwtRevocation = revocation
+	return c
+}
+
+// SetJwtClaims Set JWT claims configuration | 设置JWT声明配置
+func (c *Config) SetJwtClaims(jwtClaims *JwtClaimsConfig) *Config {
+	c.JwtClaims = jwtClaims
+	return c
+}
+
 // SetAutoRenew Set whether to auto-renew Token | 设置是否自动续期
 func (c *Config) SetAutoRenew(autoRenew bool) *Config {
 	c.AutoRenew = autoRenew
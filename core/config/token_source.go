@@ -0,0 +1,91 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TokenSource Where a token is read from, "kind" or "kind:key" | Token读取来源，格式为"kind"或"kind:key"
+// The key defaults to TokenName, e.g. "header:X-Api-Key", "websocket:access_token" | key默认为TokenName
+type TokenSource string
+
+// Token source kinds | Token来源类型
+const (
+	TokenSourceHeader        TokenSource = "header"        // Request header | 请求头
+	TokenSourceAuthorization TokenSource = "authorization" // Authorization header, key is ignored | Authorization请求头，忽略key
+	TokenSourceCookie        TokenSource = "cookie"        // Cookie | Cookie
+	TokenSourceQuery         TokenSource = "query"         // Query string | 查询参数
+	TokenSourceBody          TokenSource = "body"          // JSON or form-encoded body field, key is a dot path | JSON或表单编码请求体字段，key为点分路径
+	TokenSourceForm          TokenSource = "form"          // Form-encoded body field, multipart is ignored | 表单编码请求体字段，忽略multipart
+	TokenSourceMetadata      TokenSource = "metadata"      // gRPC metadata, key is lower-cased | gRPC元数据，key转为小写
+	TokenSourceWebSocket     TokenSource = "websocket"     // Sec-WebSocket-Protocol entry after the key | Sec-WebSocket-Protocol中key之后的条目
+)
+
+// With Returns source reading the given key | 返回读取指定key的来源
+func (s TokenSource) With(key string) TokenSource {
+	return s.Kind() + TokenSource(":"+key)
+}
+
+// Kind Gets source kind without key | 获取不含key的来源类型
+func (s TokenSource) Kind() TokenSource {
+	kind, _, _ := strings.Cut(string(s), ":")
+	return TokenSource(kind)
+}
+
+// Key Gets source key, or tokenName when absent | 获取来源key，缺省时返回tokenName
+func (s TokenSource) Key(tokenName string) string {
+	if _, key, ok := strings.Cut(string(s), ":"); ok && key != "" {
+		return key
+	}
+	return tokenName
+}
+
+// IsValid Checks if source kind is known | 检查来源类型是否有效
+func (s TokenSource) IsValid() bool {
+	switch s.Kind() {
+	case TokenSourceHeader, TokenSourceAuthorization, TokenSourceCookie, TokenSourceQuery,
+		TokenSourceBody, TokenSourceForm, TokenSourceMetadata, TokenSourceWebSocket:
+		return true
+	default:
+		return false
+	}
+}
+
+// GetTokenSources Gets ordered token sources, derived from IsReadXxx when TokenSources is empty | 获取有序的Token来源，TokenSources为空时由IsReadXxx推导
+func (c *Config) GetTokenSources() []TokenSource {
+	if len(c.TokenSources) > 0 {
+		return c.TokenSources
+	}
+
+	var sources []TokenSource
+	if c.IsReadHeader {
+		sources = append(sources, TokenSourceHeader, TokenSourceAuthorization)
+	}
+	if c.IsReadCookie {
+		sources = append(sources, TokenSourceCookie)
+	}
+	if c.IsReadBody {
+		body := TokenSourceBody
+		if c.TokenBodyPath != "" {
+			body = body.With(c.TokenBodyPath)
+		}
+		sources = append(sources, body, TokenSourceForm)
+	}
+	if !c.DisableReadQuery {
+		sources = append(sources, TokenSourceQuery)
+	}
+	return sources
+}
+
+// validateTokenSources Checks token sources and prefix | 校验Token来源与前缀
+func validateTokenSources(sources []TokenSource, prefix string) error {
+	for _, source := range sources {
+		if !source.IsValid() {
+			return fmt.Errorf("invalid TokenSource: %s", source)
+		}
+	}
+	if strings.ContainsAny(prefix, " \t") {
+		return fmt.Errorf("TokenPrefix cannot contain whitespace, got: %q", prefix)
+	}
+	return nil
+}
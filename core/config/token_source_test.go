@@ -0,0 +1,110 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTokenSourceParts(t *testing.T) {
+	tests := []struct {
+		source TokenSource
+		kind   TokenSource
+		key    string
+		valid  bool
+	}{
+		{TokenSourceHeader, TokenSourceHeader, "satoken", true},
+		{TokenSourceHeader.With("X-Api-Key"), TokenSourceHeader, "X-Api-Key", true},
+		{TokenSourceBody.With("data.items.0.token"), TokenSourceBody, "data.items.0.token", true},
+		{TokenSourceWebSocket.With(""), TokenSourceWebSocket, "satoken", true},
+		{TokenSourceMetadata.With("a:b"), TokenSourceMetadata, "a:b", true},
+		{"session:token", "session", "token", false},
+		{"", "", "satoken", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.source), func(t *testing.T) {
+			if kind := tt.source.Kind(); kind != tt.kind {
+				t.Fatalf("Kind() = %q, want %q", kind, tt.kind)
+			}
+			if key := tt.source.Key("satoken"); key != tt.key {
+				t.Fatalf("Key() = %q, want %q", key, tt.key)
+			}
+			if valid := tt.source.IsValid(); valid != tt.valid {
+				t.Fatalf("IsValid() = %v, want %v", valid, tt.valid)
+			}
+		})
+	}
+
+	// With replaces an existing key | With替换已有的key
+	if got := TokenSourceHeader.With("a").With("b"); got != "header:b" {
+		t.Fatalf("With() = %q, want header:b", got)
+	}
+}
+
+func TestGetTokenSources(t *testing.T) {
+	tests := []struct {
+		name      string
+		configure func(cfg *Config)
+		want      []TokenSource
+	}{
+		{"defaults", func(cfg *Config) {},
+			[]TokenSource{TokenSourceHeader, TokenSourceAuthorization, TokenSourceQuery}},
+		{"query disabled", func(cfg *Config) { cfg.DisableReadQuery = true },
+			[]TokenSource{TokenSourceHeader, TokenSourceAuthorization}},
+		{"config literal keeps query", func(cfg *Config) { *cfg = Config{IsReadHeader: true} },
+			[]TokenSource{TokenSourceHeader, TokenSourceAuthorization, TokenSourceQuery}},
+		{"every flag", func(cfg *Config) {
+			cfg.IsReadCookie = true
+			cfg.IsReadBody = true
+			cfg.TokenBodyPath = "data.token"
+		}, []TokenSource{TokenSourceHeader, TokenSourceAuthorization, TokenSourceCookie, "body:data.token", TokenSourceForm, TokenSourceQuery}},
+		{"explicit order wins", func(cfg *Config) {
+			cfg.IsReadCookie = true
+			cfg.TokenSources = []TokenSource{TokenSourceCookie, TokenSourceHeader.With("X-Token")}
+		}, []TokenSource{TokenSourceCookie, "header:X-Token"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			tt.configure(cfg)
+			if got := cfg.GetTokenSources(); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("GetTokenSources() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateTokenSources(t *testing.T) {
+	tests := []struct {
+		name      string
+		configure func(cfg *Config)
+		wantErr   string
+	}{
+		{"valid", func(cfg *Config) {
+			cfg.TokenSources = []TokenSource{TokenSourceMetadata, TokenSourceWebSocket.With("proto")}
+			cfg.TokenPrefix = "Bearer"
+		}, ""},
+		{"unknown kind", func(cfg *Config) { cfg.TokenSources = []TokenSource{"session"} }, "invalid TokenSource"},
+		{"prefix with space", func(cfg *Config) { cfg.TokenPrefix = "Bearer " }, "TokenPrefix"},
+		{"no source", func(cfg *Config) {
+			cfg.IsReadHeader = false
+			cfg.DisableReadQuery = true
+		}, "at least one"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			tt.configure(cfg)
+			err := cfg.Validate()
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("Validate() error = %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("Validate() = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
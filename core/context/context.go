@@ -1,15 +1,23 @@
 package context
 
 import (
+	"encoding/json"
+	"mime"
+	"net/url"
+	"strconv"
 	"strings"
 
 	"github.com/click33/sa-token-go/core/adapter"
+	"github.com/click33/sa-token-go/core/config"
 	"github.com/click33/sa-token-go/core/manager"
 )
 
 const (
-	bearerPrefix = "Bearer "
-	authHeader   = "Authorization"
+	bearerPrefix    = "Bearer "
+	authHeader      = "Authorization"
+	contentType     = "Content-Type"
+	websocketHeader = "Sec-WebSocket-Protocol"
+	formURLEncoded  = "application/x-www-form-urlencoded"
 )
 
 // SaTokenContext Sa-Token context for current request | Sa-Token上下文，用于当前请求
@@ -19,7 +27,11 @@ type SaTokenContext struct {
 }
 
 // NewContext creates a new Sa-Token context | 创建新的Sa-Token上下文
+// Storage calls use the request context when ctx implements adapter.ContextCarrier | ctx实现adapter.ContextCarrier时，存储调用使用请求上下文
 func NewContext(ctx adapter.RequestContext, mgr *manager.Manager) *SaTokenContext {
+	if carrier, ok := ctx.(adapter.ContextCarrier); ok {
+		mgr = mgr.WithContext(carrier.Context())
+	}
 	return &SaTokenContext{
 		ctx:     ctx,
 		manager: mgr,
@@ -41,38 +53,140 @@ func extractBearerToken(auth string) string {
 	return auth
 }
 
+// trimTokenPrefix 去除必需的前缀（大小写不敏感），缺少前缀时返回空
+func trimTokenPrefix(value, prefix string) string {
+	value = strings.TrimSpace(value)
+	if prefix == "" {
+		return value
+	}
+
+	n := len(prefix)
+	if len(value) > n+1 && strings.EqualFold(value[:n], prefix) && value[n] == ' ' {
+		return strings.TrimSpace(value[n+1:])
+	}
+	return ""
+}
+
+// extractSubprotocolToken 从 Sec-WebSocket-Protocol 中提取 key 之后的条目，如 "satoken, <token>"
+func extractSubprotocolToken(protocols, key string) string {
+	parts := strings.Split(protocols, ",")
+	for i := 0; i < len(parts)-1; i++ {
+		if strings.TrimSpace(parts[i]) == key {
+			return strings.TrimSpace(parts[i+1])
+		}
+	}
+	return ""
+}
+
 // GetTokenValue gets token value from current request | 获取当前请求的Token值
+// Sources are tried in order of Config.GetTokenSources | 按Config.GetTokenSources的顺序依次尝试
 func (c *SaTokenContext) GetTokenValue() string {
 	cfg := c.manager.GetConfig()
 
-	// 1. 尝试从Header获取
-	if cfg.IsReadHeader {
-		// 从自定义 token 名称的 Header 获取
-		if token := strings.TrimSpace(c.ctx.GetHeader(cfg.TokenName)); token != "" {
+	for _, source := range cfg.GetTokenSources() {
+		if token := c.readToken(cfg, source); token != "" {
 			return token
 		}
+	}
+	return ""
+}
 
-		// 从 Authorization 头获取
-		if auth := c.ctx.GetHeader(authHeader); auth != "" {
-			if token := extractBearerToken(auth); token != "" {
-				return token
-			}
+// readToken 从单个来源读取Token
+func (c *SaTokenContext) readToken(cfg *config.Config, source config.TokenSource) string {
+	key := source.Key(cfg.TokenName)
+
+	switch source.Kind() {
+	case config.TokenSourceHeader:
+		return trimTokenPrefix(c.ctx.GetHeader(key), cfg.TokenPrefix)
+	case config.TokenSourceMetadata:
+		// gRPC 元数据的 key 均为小写
+		return trimTokenPrefix(c.ctx.GetHeader(strings.ToLower(key)), cfg.TokenPrefix)
+	case config.TokenSourceAuthorization:
+		if cfg.TokenPrefix == "" {
+			return extractBearerToken(c.ctx.GetHeader(authHeader))
 		}
+		return trimTokenPrefix(c.ctx.GetHeader(authHeader), cfg.TokenPrefix)
+	case config.TokenSourceCookie:
+		return strings.TrimSpace(c.ctx.GetCookie(key))
+	case config.TokenSourceQuery:
+		return strings.TrimSpace(c.ctx.GetQuery(key))
+	case config.TokenSourceForm:
+		return c.readFormToken(key)
+	case config.TokenSourceBody:
+		return c.readBodyToken(key)
+	case config.TokenSourceWebSocket:
+		return extractSubprotocolToken(c.ctx.GetHeader(websocketHeader), key)
+	default:
+		return ""
 	}
+}
 
-	// 2. 尝试从Cookie获取
-	if cfg.IsReadCookie {
-		if token := strings.TrimSpace(c.ctx.GetCookie(cfg.TokenName)); token != "" {
-			return token
+// readBodyToken 按点分路径从 JSON 或表单编码的请求体读取Token
+// 请求体通过 GetBody 读取，各适配器会恢复请求体供后续处理器再次读取
+func (c *SaTokenContext) readBodyToken(path string) string {
+	switch mediaType := c.mediaType(); {
+	case mediaType == formURLEncoded:
+		return c.readFormToken(path)
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		body, err := c.ctx.GetBody()
+		if err != nil || len(body) == 0 {
+			return ""
 		}
+
+		var data any
+		if err := json.Unmarshal(body, &data); err != nil {
+			return ""
+		}
+		token, _ := lookupPath(data, path).(string)
+		return strings.TrimSpace(token)
+	default:
+		return ""
 	}
+}
 
-	// 3. 尝试从Query参数获取
-	if token := strings.TrimSpace(c.ctx.GetQuery(cfg.TokenName)); token != "" {
-		return token
+// readFormToken 读取表单字段，仅自行解析表单编码的请求体，multipart等请求体不交给框架解析，避免耗尽请求体
+func (c *SaTokenContext) readFormToken(key string) string {
+	if c.mediaType() != formURLEncoded {
+		return ""
 	}
 
-	return ""
+	body, err := c.ctx.GetBody()
+	if err != nil {
+		return ""
+	}
+	values, err := url.ParseQuery(string(body))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(values.Get(key))
+}
+
+// mediaType 获取小写的请求体媒体类型（不含参数）
+func (c *SaTokenContext) mediaType() string {
+	mediaType, _, err := mime.ParseMediaType(c.ctx.GetHeader(contentType))
+	if err != nil {
+		return ""
+	}
+	return mediaType
+}
+
+// lookupPath 按点分路径查找 JSON 值，数组使用数字下标，如 "data.items.0.token"
+func lookupPath(data any, path string) any {
+	for _, field := range strings.Split(path, ".") {
+		switch node := data.(type) {
+		case map[string]any:
+			data = node[field]
+		case []any:
+			i, err := strconv.Atoi(field)
+			if err != nil || i < 0 || i >= len(node) {
+				return nil
+			}
+			data = node[i]
+		default:
+			return nil
+		}
+	}
+	return data
 }
 
 // IsLogin 检查当前请求是否已登录
@@ -95,20 +209,22 @@ func (c *SaTokenContext) GetLoginID() (string, error) {
 
 // HasPermission 检查是否有指定权限
 func (c *SaTokenContext) HasPermission(permission string) bool {
-	loginID, err := c.GetLoginID()
-	if err != nil {
-		return false
-	}
-	return c.manager.HasPermission(loginID, permission)
+	return c.manager.HasPermissionByToken(c.GetTokenValue(), permission)
 }
 
 // HasRole 检查是否有指定角色
 func (c *SaTokenContext) HasRole(role string) bool {
-	loginID, err := c.GetLoginID()
-	if err != nil {
-		return false
-	}
-	return c.manager.HasRole(loginID, role)
+	return c.manager.HasRoleByToken(c.GetTokenValue(), role)
+}
+
+// IsSafe 检查当前Token是否处于二级认证有效期内
+func (c *SaTokenContext) IsSafe(service ...string) bool {
+	return c.manager.IsSafe(c.GetTokenValue(), service...)
+}
+
+// CheckSafe 检查二级认证（未通过返回错误）
+func (c *SaTokenContext) CheckSafe(service ...string) error {
+	return c.manager.CheckSafe(c.GetTokenValue(), service...)
 }
 
 // GetRequestContext 获取原始请求上下文
@@ -0,0 +1,157 @@
+package context
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/click33/sa-token-go/core/adapter"
+	"github.com/click33/sa-token-go/core/adapter/adaptertest"
+	"github.com/click33/sa-token-go/core/config"
+	"github.com/click33/sa-token-go/core/manager"
+)
+
+// metadataContext Serves headers by exact key like gRPC metadata | 与gRPC元数据一样按精确key返回请求头
+type metadataContext struct {
+	*adaptertest.RequestContext
+	md map[string]string
+}
+
+func (c *metadataContext) GetHeader(key string) string { return c.md[key] }
+
+func newTestContext(t *testing.T, ctx adapter.RequestContext, configure func(cfg *config.Config)) *SaTokenContext {
+	t.Helper()
+
+	cfg := config.DefaultConfig()
+	cfg.IsPrintBanner = false
+	cfg.IsLog = false
+	if configure != nil {
+		configure(cfg)
+	}
+	mgr := manager.NewManager(adaptertest.NewMapStorage(), cfg)
+	t.Cleanup(mgr.CloseManager)
+	return NewContext(ctx, mgr)
+}
+
+func newRequest(target string, headers map[string]string) *http.Request {
+	r := httptest.NewRequest(http.MethodGet, target, nil)
+	for k, v := range headers {
+		r.Header.Set(k, v)
+	}
+	return r
+}
+
+func TestGetTokenValue(t *testing.T) {
+	tests := []struct {
+		name      string
+		target    string
+		headers   map[string]string
+		configure func(cfg *config.Config)
+		want      string
+	}{
+		{"header before query", "/?satoken=query", map[string]string{"satoken": "header"}, nil, "header"},
+		{"authorization before query", "/?satoken=query", map[string]string{"Authorization": "Bearer auth"}, nil, "auth"},
+		{"query fallback", "/?satoken=query", nil, nil, "query"},
+		{"explicit order", "/?satoken=query", map[string]string{"satoken": "header"}, func(cfg *config.Config) {
+			cfg.TokenSources = []config.TokenSource{config.TokenSourceQuery, config.TokenSourceHeader}
+		}, "query"},
+		{"query disabled", "/?satoken=query", nil, func(cfg *config.Config) { cfg.DisableReadQuery = true }, ""},
+		{"custom header key", "/", map[string]string{"X-Api-Key": "key", "satoken": "header"}, func(cfg *config.Config) {
+			cfg.TokenSources = []config.TokenSource{config.TokenSourceHeader.With("X-Api-Key")}
+		}, "key"},
+		{"cookie", "/", map[string]string{"Cookie": "satoken=cookie"}, func(cfg *config.Config) {
+			cfg.IsReadCookie = true
+		}, "cookie"},
+
+		// Without TokenPrefix only Authorization strips "Bearer" | 未设置TokenPrefix时只有Authorization去除Bearer
+		{"bearer optional", "/", map[string]string{"Authorization": "auth"}, nil, "auth"},
+		{"header keeps bearer", "/", map[string]string{"satoken": "Bearer header"}, nil, "Bearer header"},
+
+		// TokenPrefix is required in both header and Authorization | TokenPrefix在请求头与Authorization中都是必需的
+		{"prefix on header", "/", map[string]string{"satoken": "bearer header"}, withPrefix, "header"},
+		{"prefix on authorization", "/", map[string]string{"Authorization": "Bearer auth"}, withPrefix, "auth"},
+		{"header missing prefix", "/", map[string]string{"satoken": "header"}, withPrefix, ""},
+		{"authorization missing prefix", "/", map[string]string{"Authorization": "auth"}, withPrefix, ""},
+		{"prefix without token", "/", map[string]string{"Authorization": "Bearer "}, withPrefix, ""},
+		{"prefix not applied to query", "/?satoken=query", map[string]string{"satoken": "header"}, withPrefix, "query"},
+
+		{"websocket subprotocol", "/", map[string]string{"Sec-WebSocket-Protocol": "chat, satoken, ws-token"}, withWebSocket, "ws-token"},
+		{"websocket key last", "/", map[string]string{"Sec-WebSocket-Protocol": "chat, satoken"}, withWebSocket, ""},
+		{"websocket custom key", "/", map[string]string{"Sec-WebSocket-Protocol": "access_token, ws-token"}, func(cfg *config.Config) {
+			cfg.TokenSources = []config.TokenSource{config.TokenSourceWebSocket.With("access_token")}
+		}, "ws-token"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := newTestContext(t, adaptertest.NewRequestContext(newRequest(tt.target, tt.headers)), tt.configure)
+			if got := ctx.GetTokenValue(); got != tt.want {
+				t.Fatalf("GetTokenValue() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetTokenValueMetadata(t *testing.T) {
+	md := &metadataContext{
+		RequestContext: adaptertest.NewRequestContext(newRequest("/", nil)),
+		md:             map[string]string{"x-token": "Bearer grpc", "X-Token": "mixed-case"},
+	}
+	ctx := newTestContext(t, md, func(cfg *config.Config) {
+		cfg.TokenSources = []config.TokenSource{config.TokenSourceMetadata.With("X-Token")}
+		cfg.TokenPrefix = "Bearer"
+	})
+
+	if got := ctx.GetTokenValue(); got != "grpc" {
+		t.Fatalf("GetTokenValue() = %q, want the lower-cased metadata key", got)
+	}
+}
+
+func withPrefix(cfg *config.Config) { cfg.TokenPrefix = "Bearer" }
+
+func withWebSocket(cfg *config.Config) {
+	cfg.TokenSources = []config.TokenSource{config.TokenSourceWebSocket}
+}
+
+func TestGetTokenValueBody(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		source      config.TokenSource
+		want        string
+	}{
+		{"json field", "application/json", `{"satoken":" abc "}`, config.TokenSourceBody, "abc"},
+		{"json dot path", "application/json; charset=utf-8", `{"data":{"token":"abc"}}`, config.TokenSourceBody.With("data.token"), "abc"},
+		{"json array index", "application/json", `{"items":[{"t":"a"},{"t":"b"}]}`, config.TokenSourceBody.With("items.1.t"), "b"},
+		{"json top-level array", "application/vnd.api+json", `[{"t":"a"}]`, config.TokenSourceBody.With("0.t"), "a"},
+		{"json index out of range", "application/json", `{"items":[]}`, config.TokenSourceBody.With("items.0"), ""},
+		{"json non-string value", "application/json", `{"satoken":123}`, config.TokenSourceBody, ""},
+		{"json path through scalar", "application/json", `{"data":"x"}`, config.TokenSourceBody.With("data.token"), ""},
+		{"invalid json", "application/json", `{"satoken":`, config.TokenSourceBody, ""},
+		{"form body", "application/x-www-form-urlencoded", "satoken=abc&x=1", config.TokenSourceBody, "abc"},
+		{"form source", "application/x-www-form-urlencoded", "x=1&satoken=abc", config.TokenSourceForm, "abc"},
+		{"form source multipart", "multipart/form-data; boundary=b", "--b\r\nContent-Disposition: form-data; name=\"satoken\"\r\n\r\nabc\r\n--b--\r\n", config.TokenSourceForm, ""},
+		{"other media type", "text/plain", `{"satoken":"abc"}`, config.TokenSourceBody, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+			r.Header.Set("Content-Type", tt.contentType)
+			ctx := newTestContext(t, adaptertest.NewRequestContext(r), func(cfg *config.Config) {
+				cfg.TokenSources = []config.TokenSource{tt.source}
+			})
+
+			if got := ctx.GetTokenValue(); got != tt.want {
+				t.Fatalf("GetTokenValue() = %q, want %q", got, tt.want)
+			}
+			// Downstream handlers still read the whole body | 后续处理器仍能读到完整请求体
+			if rest, _ := io.ReadAll(r.Body); string(rest) != tt.body {
+				t.Fatalf("body after GetTokenValue() = %q, want %q", rest, tt.body)
+			}
+		})
+	}
+}
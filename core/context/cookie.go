@@ -0,0 +1,124 @@
+package context
+
+import (
+	"errors"
+
+	"github.com/click33/sa-token-go/core/adapter"
+	"github.com/click33/sa-token-go/core/config"
+	"github.com/click33/sa-token-go/core/manager"
+)
+
+// Token Cookie Helpers
+// Token Cookie辅助方法
+//
+// The cookie is written only when a cookie source is configured, its name is the source key | 仅在配置了cookie来源时写入Cookie，名称为来源key
+// Attributes come from Config.CookieConfig, MaxAge from Manager.GetCookieMaxAge | 属性取自Config.CookieConfig，MaxAge取自Manager.GetCookieMaxAge
+//
+// Usage | 用法:
+//   token, err := saCtx.LoginAndSetCookie("1001", manager.NewLoginOptions().SetRemember(false))
+//   err = saCtx.LogoutAndClearCookie()
+
+// LoginAndSetCookie Logs in, then writes token cookie and response header | 登录并写入Token Cookie与响应头
+func (c *SaTokenContext) LoginAndSetCookie(loginID string, opts ...*manager.LoginOptions) (string, error) {
+	var options *manager.LoginOptions
+	if len(opts) > 0 && opts[0] != nil {
+		options = opts[0]
+	} else {
+		options = manager.NewLoginOptions()
+	}
+
+	token, err := c.manager.LoginWithOptions(loginID, options)
+	if err != nil {
+		return "", err
+	}
+
+	c.SetTokenCookie(token, c.manager.GetCookieMaxAge(options))
+	if header := c.manager.GetConfig().TokenResponseHeader; header != "" {
+		c.ctx.SetHeader(header, token)
+	}
+	return token, nil
+}
+
+// LogoutAndClearCookie Logs out the account on the device of current token and clears its cookie | 注销当前Token所属账号在该设备上的登录并清除Cookie
+// Anonymous requests get the not-login error and keep their cookie | 未登录的请求返回未登录错误，且保留Cookie
+func (c *SaTokenContext) LogoutAndClearCookie() error {
+	loginID, err := c.GetLoginID()
+	if err != nil {
+		return err
+	}
+	if err := c.Logout(loginID); err != nil {
+		return err
+	}
+	c.ClearTokenCookie()
+	return nil
+}
+
+// Logout Logs out loginID on the device of current token, falling back to what the JWT mode supports | 注销loginID在当前Token设备上的登录，按JWT模式的支持情况依次回退
+// Order: Logout(loginID, device), LogoutByToken, RevokeToken, then nothing as stateless JWT keeps no login state
+// 顺序：Logout(loginID, device)、LogoutByToken、RevokeToken，都不支持时不做处理（stateless JWT不保存登录状态）
+func (c *SaTokenContext) Logout(loginID string) error {
+	device := manager.DefaultDevice
+	if info, err := c.manager.GetTokenInfo(c.GetTokenValue()); err == nil && info != nil && info.LoginID == loginID && info.Device != "" {
+		device = info.Device
+	}
+
+	err := c.manager.Logout(loginID, device)
+	if errors.Is(err, manager.ErrUnsupportedInJwtMode) {
+		err = c.manager.LogoutByToken(c.GetTokenValue())
+	}
+	if errors.Is(err, manager.ErrUnsupportedInJwtMode) {
+		err = c.manager.RevokeToken(c.GetTokenValue())
+	}
+	if errors.Is(err, manager.ErrUnsupportedInJwtMode) {
+		return nil
+	}
+	return err
+}
+
+// SetTokenCookie Writes token cookie, maxAge 0 is a browser-session cookie | 写入Token Cookie，maxAge为0时为浏览器会话Cookie
+func (c *SaTokenContext) SetTokenCookie(token string, maxAge int) {
+	if options := c.tokenCookieOptions(); options != nil {
+		options.Value = token
+		options.MaxAge = maxAge
+		c.ctx.SetCookieWithOptions(options)
+	}
+}
+
+// ClearTokenCookie Deletes token cookie | 删除Token Cookie
+func (c *SaTokenContext) ClearTokenCookie() {
+	if options := c.tokenCookieOptions(); options != nil {
+		options.MaxAge = -1
+		c.ctx.SetCookieWithOptions(options)
+	}
+}
+
+// tokenCookieOptions 根据CookieConfig构建Cookie选项，未配置cookie来源时返回nil
+func (c *SaTokenContext) tokenCookieOptions() *adapter.CookieOptions {
+	cfg := c.manager.GetConfig()
+
+	name := ""
+	for _, source := range cfg.GetTokenSources() {
+		if source.Kind() == config.TokenSourceCookie {
+			name = source.Key(cfg.TokenName)
+			break
+		}
+	}
+	if name == "" {
+		return nil
+	}
+
+	options := &adapter.CookieOptions{
+		Name: name,
+		Path: config.DefaultCookiePath,
+	}
+	if cookie := cfg.CookieConfig; cookie != nil {
+		options.Domain = cookie.Domain
+		options.Secure = cookie.Secure
+		options.HttpOnly = cookie.HttpOnly
+		options.SameSite = string(cookie.SameSite)
+		if cookie.Path != "" {
+			options.Path = cookie.Path
+		}
+	}
+	return options
+}
@@ -3,6 +3,8 @@ package core
 import (
 	"errors"
 	"fmt"
+
+	"github.com/click33/sa-token-go/core/manager"
 )
 
 // Common error definitions for better error handling and internationalization support
@@ -20,6 +22,9 @@ var (
 	// ErrTokenExpired indicates the token has expired | Token已过期
 	ErrTokenExpired = fmt.Errorf("token expired: please login again to get a new token")
 
+	// ErrTokenRevoked indicates the JWT is on the revocation list | JWT已被吊销
+	ErrTokenRevoked = manager.ErrTokenRevoked
+
 	// ErrInvalidLoginID indicates the login ID is invalid | 登录ID无效
 	ErrInvalidLoginID = fmt.Errorf("invalid login ID: the login identifier cannot be empty")
 
@@ -35,6 +40,9 @@ var (
 
 	// ErrRoleDenied indicates insufficient role | 角色权限不足
 	ErrRoleDenied = fmt.Errorf("role denied: you don't have the required role")
+
+	// ErrNotSafe indicates second-level authentication is required | 需要二级认证
+	ErrNotSafe = manager.ErrNotSafe
 )
 
 // ============ Account Errors | 账号错误 ============
@@ -67,7 +75,13 @@ var (
 
 var (
 	// ErrStorageUnavailable indicates the storage backend is unavailable | 存储后端不可用
-	ErrStorageUnavailable = fmt.Errorf("storage unavailable: unable to connect to storage backend")
+	ErrStorageUnavailable = manager.ErrStorageUnavailable
+
+	// ErrUnsupportedInJwtMode indicates the API needs storage the current JWT mode does not use | 当前JWT模式不使用该API所需的存储
+	ErrUnsupportedInJwtMode = manager.ErrUnsupportedInJwtMode
+
+	// ErrUnsupportedTokenStyle indicates the API needs a different token style | 当前Token风格不支持该API
+	ErrUnsupportedTokenStyle = manager.ErrUnsupportedTokenStyle
 )
 
 // ============ Custom Error Type | 自定义错误类型 ============
@@ -165,6 +179,17 @@ func NewAccountDisabledError(loginID string) *SaTokenError {
 		WithContext("loginID", loginID)
 }
 
+// NewNotSafeError Creates a second-level authentication error | 创建二级认证错误
+func NewNotSafeError(service string) *SaTokenError {
+	return NewError(CodeNotSafe, "second-level authentication required", ErrNotSafe).
+		WithContext("service", service)
+}
+
+// NewSignError Creates a request signature error | 创建请求签名错误
+func NewSignError(cause error) *SaTokenError {
+	return NewError(CodeSignInvalid, "request signature verification failed", cause)
+}
+
 // ============ Error Checking Helpers | 错误检查辅助函数 ============
 
 // IsNotLoginError Checks if error is a not login error | 检查是否为未登录错误
@@ -182,9 +207,14 @@ func IsAccountDisabledError(err error) bool {
 	return errors.Is(err, ErrAccountDisabled)
 }
 
+// IsNotSafeError Checks if error is a second-level authentication error | 检查是否为二级认证错误
+func IsNotSafeError(err error) bool {
+	return errors.Is(err, ErrNotSafe)
+}
+
 // IsTokenError Checks if error is a token-related error | 检查是否为Token相关错误
 func IsTokenError(err error) bool {
-	return errors.Is(err, ErrTokenInvalid) || errors.Is(err, ErrTokenExpired)
+	return errors.Is(err, ErrTokenInvalid) || errors.Is(err, ErrTokenExpired) || errors.Is(err, ErrTokenRevoked)
 }
 
 // GetErrorCode Extracts error code from SaTokenError | 从SaTokenError中提取错误码
@@ -217,4 +247,6 @@ const (
 	CodeStorageError     = 10007 // Storage backend error | 存储后端错误
 	CodeInvalidParameter = 10008 // Invalid parameter | 无效参数
 	CodeSessionError     = 10009 // Session operation error | Session操作错误
+	CodeNotSafe          = 10010 // Second-level authentication required | 需要二级认证
+	CodeSignInvalid      = 10011 // Request signature verification failed | 请求签名校验失败
 )
@@ -19,6 +19,9 @@ const (
 	// EventKickout fired when a user is forcibly logged out | 用户被踢下线事件
 	EventKickout Event = "kickout"
 
+	// EventReplaced fired when a token is replaced by a newer login | Token被新登录顶下线事件
+	EventReplaced Event = "replaced"
+
 	// EventDisable fired when an account is disabled | 账号被禁用事件
 	EventDisable Event = "disable"
 
@@ -47,6 +50,7 @@ const (
 // EventData contains information about a triggered event | 事件数据，包含触发事件的相关信息
 type EventData struct {
 	Event     Event          // Event type | 事件类型
+	LoginType string         // Account realm name | 账号体系标识
 	LoginID   string         // User login ID | 用户登录ID
 	Device    string         // Device identifier | 设备标识
 	Token     string         // Authentication token | 认证Token
@@ -56,8 +60,8 @@ type EventData struct {
 
 // String returns a string representation of the event data | 返回事件数据的字符串表示
 func (e *EventData) String() string {
-	return fmt.Sprintf("Event{type=%s, loginID=%s, device=%s, timestamp=%d}",
-		e.Event, e.LoginID, e.Device, e.Timestamp)
+	return fmt.Sprintf("Event{type=%s, loginType=%s, loginID=%s, device=%s, timestamp=%d}",
+		e.Event, e.LoginType, e.LoginID, e.Device, e.Timestamp)
 }
 
 // Listener is the interface for event listeners | 事件监听器接口
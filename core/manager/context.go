@@ -0,0 +1,55 @@
+package manager
+
+import (
+	"context"
+
+	"github.com/click33/sa-token-go/core/adapter"
+)
+
+// Request Context Propagation
+// 请求上下文传递
+//
+// WithContext returns a per-request view of the manager, storage calls of that view and its sub-managers use ctx,
+// so cancellation and deadlines of HTTP/gRPC handlers reach the storage backend.
+// WithContext返回管理器的请求级视图，该视图及其子管理器的存储调用使用ctx，HTTP/gRPC处理器的取消与截止时间因此可传递到存储后端
+//
+// Storages implementing adapter.StorageV2Provider (e.g. redis) receive ctx natively,
+// legacy storages only check ctx before each call | 实现了adapter.StorageV2Provider的存储（如redis）原生接收ctx，旧版存储仅在调用前检查ctx
+//
+// A failing storage is reported as ErrStorageUnavailable by CheckLogin, GetLoginID and CheckLoginWithState,
+// never as not logged in | 存储故障时CheckLogin、GetLoginID与CheckLoginWithState返回ErrStorageUnavailable，而不是未登录
+//
+// Usage | 用法:
+//   loginID, err := manager.WithContext(r.Context()).GetLoginID(token)
+
+// WithContext Returns a view of the manager whose storage calls use ctx | 返回存储调用使用ctx的管理器视图
+// The view shares config, listeners and the settings of sub-managers with m, configure m itself rather than the view | 视图与m共享配置、监听器与子管理器的设置，应在m本身上进行配置
+func (m *Manager) WithContext(ctx context.Context) *Manager {
+	if ctx == nil || m.storageV2 == nil {
+		return m
+	}
+
+	view := *m
+	view.root = m.detached()
+	view.storage = adapter.BindContext(m.storageV2, ctx)
+	view.roleManager = m.roleManager.WithStorage(view.storage)
+	view.tempTokens = m.tempTokens.WithStorage(view.storage)
+	view.nonceManager = m.nonceManager.WithStorage(view.storage)
+	view.signManager = m.signManager.WithStorage(view.storage)
+	view.refreshManager = m.refreshManager.WithStorage(view.storage)
+	view.oauth2Server = m.oauth2Server.WithStorage(view.storage)
+	return &view
+}
+
+// GetStorageV2 Gets context-aware storage | 获取上下文感知存储
+func (m *Manager) GetStorageV2() adapter.StorageV2 {
+	return m.storageV2
+}
+
+// detached Gets the manager WithContext was called on, for work outliving the request | 获取调用WithContext的原始管理器，用于晚于请求结束的任务
+func (m *Manager) detached() *Manager {
+	if m.root != nil {
+		return m.root
+	}
+	return m
+}
@@ -0,0 +1,175 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/click33/sa-token-go/core/adapter/adaptertest"
+	"github.com/click33/sa-token-go/core/config"
+	"github.com/click33/sa-token-go/core/oauth2"
+)
+
+// signedRequest Creates a request signed for app "partner" of m | 创建以m中应用partner签名的请求
+func signedRequest(t *testing.T, m *Manager) *adaptertest.RequestContext {
+	t.Helper()
+	params, err := m.GetSignManager().CreateSignParams("partner", map[string]string{"orderId": "1"})
+	if err != nil {
+		t.Fatalf("CreateSignParams() error = %v", err)
+	}
+	query := url.Values{}
+	for k, v := range params {
+		query.Set(k, v)
+	}
+	return adaptertest.NewRequestContext(httptest.NewRequest(http.MethodGet, "/pay?"+query.Encode(), nil))
+}
+
+// cancelledContext Returns a context that is already cancelled | 返回已取消的上下文
+func cancelledContext() context.Context {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	return ctx
+}
+
+func TestWithContextReportsStorageFailure(t *testing.T) {
+	modes := map[string]func(cfg *config.Config){
+		"default": func(cfg *config.Config) {},
+		"jwt simple": func(cfg *config.Config) {
+			cfg.TokenStyle = config.TokenStyleJWT
+			cfg.JwtSecretKey = "context-test-secret"
+			cfg.JwtMode = config.JwtModeSimple
+		},
+	}
+
+	for name, configure := range modes {
+		t.Run(name, func(t *testing.T) {
+			m, _ := newTestManager(t, configure)
+			token, err := m.Login("1001")
+			if err != nil {
+				t.Fatalf("Login() error = %v", err)
+			}
+
+			view := m.WithContext(cancelledContext())
+			if view.IsLogin(token) {
+				t.Fatal("IsLogin() = true on a cancelled context")
+			}
+			if err := view.CheckLogin(token); !errors.Is(err, ErrStorageUnavailable) || !errors.Is(err, context.Canceled) {
+				t.Fatalf("CheckLogin() = %v, want ErrStorageUnavailable wrapping context.Canceled", err)
+			}
+			if _, err := view.GetLoginID(token); !errors.Is(err, ErrStorageUnavailable) {
+				t.Fatalf("GetLoginID() = %v, want ErrStorageUnavailable", err)
+			}
+			if _, err := view.CheckLoginWithState(token); !errors.Is(err, ErrStorageUnavailable) {
+				t.Fatalf("CheckLoginWithState() = %v, want ErrStorageUnavailable", err)
+			}
+
+			// Requests without a token never reach storage | 未携带Token的请求不访问存储
+			if err := view.CheckLogin(""); !errors.Is(err, ErrNotLogin) {
+				t.Fatalf("CheckLogin(empty) = %v, want ErrNotLogin", err)
+			}
+			if err := m.CheckLogin(token); err != nil {
+				t.Fatalf("CheckLogin() on the manager itself = %v", err)
+			}
+		})
+	}
+}
+
+func TestWithContextMissingTokenIsNotLogin(t *testing.T) {
+	m, _ := newTestManager(t)
+	view := m.WithContext(context.Background())
+
+	if err := view.CheckLogin("missing"); !errors.Is(err, ErrNotLogin) {
+		t.Fatalf("CheckLogin(missing) = %v, want ErrNotLogin", err)
+	}
+	if _, err := view.GetLoginID("missing"); !errors.Is(err, ErrNotLogin) {
+		t.Fatalf("GetLoginID(missing) = %v, want ErrNotLogin", err)
+	}
+	if ok, err := view.CheckLoginWithState("missing"); ok || errors.Is(err, ErrStorageUnavailable) {
+		t.Fatalf("CheckLoginWithState(missing) = %v, %v, want not logged in", ok, err)
+	}
+}
+
+func TestWithContextBindsSubManagers(t *testing.T) {
+	m, _ := newTestManager(t)
+	view := m.WithContext(cancelledContext())
+
+	if _, err := view.CreateTempToken("reset", "1001", 60); !errors.Is(err, context.Canceled) {
+		t.Fatalf("CreateTempToken() = %v, want context.Canceled", err)
+	}
+	if _, err := view.GenerateNonce(); !errors.Is(err, context.Canceled) {
+		t.Fatalf("GenerateNonce() = %v, want context.Canceled", err)
+	}
+	if _, err := view.GetRoleManager().CreateRole("admin", ""); !errors.Is(err, context.Canceled) {
+		t.Fatalf("CreateRole() = %v, want context.Canceled", err)
+	}
+
+	server := m.GetOAuth2Server()
+	if err := server.RegisterClient(&oauth2.Client{
+		ClientID:     "service",
+		ClientSecret: "secret",
+		GrantTypes:   []oauth2.GrantType{oauth2.GrantTypeClientCredentials},
+		Scopes:       []string{"read"},
+	}); err != nil {
+		t.Fatalf("RegisterClient() error = %v", err)
+	}
+	if _, err := view.GetOAuth2Server().ClientCredentialsToken("service", "secret", nil); !errors.Is(err, context.Canceled) {
+		t.Fatalf("ClientCredentialsToken() = %v, want context.Canceled", err)
+	}
+	if _, err := server.ClientCredentialsToken("service", "secret", nil); err != nil {
+		t.Fatalf("ClientCredentialsToken() on the manager itself = %v", err)
+	}
+
+	if err := view.RevokeRefreshToken("refresh-token"); !errors.Is(err, context.Canceled) {
+		t.Fatalf("RevokeRefreshToken() = %v, want context.Canceled", err)
+	}
+
+	m.GetSignManager().AddApp("partner", "partner-secret")
+	req := signedRequest(t, m)
+	if err := view.GetSignManager().VerifyRequest(req); !errors.Is(err, context.Canceled) {
+		t.Fatalf("VerifyRequest() = %v, want context.Canceled", err)
+	}
+	if err := m.GetSignManager().VerifyRequest(req); err != nil {
+		t.Fatalf("VerifyRequest() on the manager itself = %v", err)
+	}
+}
+
+func TestWithContextSharesState(t *testing.T) {
+	m, _ := newTestManager(t)
+	view := m.WithContext(context.Background())
+
+	tempToken, err := view.CreateTempToken("reset", "1001", 60)
+	if err != nil {
+		t.Fatalf("CreateTempToken() error = %v", err)
+	}
+	if _, err := m.ConsumeTempToken("reset", tempToken); err != nil {
+		t.Fatalf("ConsumeTempToken() on the manager = %v", err)
+	}
+
+	nonce, err := view.GenerateNonce()
+	if err != nil {
+		t.Fatalf("GenerateNonce() error = %v", err)
+	}
+	if !m.VerifyNonce(nonce) || view.VerifyNonce(nonce) {
+		t.Fatal("nonce is not shared between the manager and its view")
+	}
+
+	if _, err := view.GetRoleManager().CreateRole("admin", ""); err != nil {
+		t.Fatalf("CreateRole() error = %v", err)
+	}
+	if _, err := m.GetRoleManager().GetRole("admin"); err != nil {
+		t.Fatalf("GetRole() on the manager = %v", err)
+	}
+
+	// Apps and recorded nonces are shared, a request verified by the view cannot be replayed | 应用与已记录的nonce共享，视图校验过的请求无法重放
+	m.GetSignManager().AddApp("partner", "partner-secret")
+	req := signedRequest(t, m)
+	if err := view.GetSignManager().VerifyRequest(req); err != nil {
+		t.Fatalf("VerifyRequest() error = %v", err)
+	}
+	if err := m.GetSignManager().VerifyRequest(req); err == nil {
+		t.Fatal("request verified by the view was replayed on the manager")
+	}
+}
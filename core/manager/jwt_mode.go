@@ -0,0 +1,358 @@
+package manager
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/click33/sa-token-go/core/adapter"
+	"github.com/click33/sa-token-go/core/config"
+	"github.com/click33/sa-token-go/core/listener"
+	"github.com/click33/sa-token-go/core/token"
+)
+
+// JWT Mode Implementation
+// JWT模式实现
+//
+// Modes (only effective when TokenStyle=JWT) | 模式（仅在TokenStyle=JWT时生效）:
+//   default   - JWT is only the token format, every API behaves as with other styles | JWT仅作为Token格式，所有API与其他风格一致
+//   mixed     - Full storage state, signature and exp of the JWT must also be valid | 完整存储状态，同时要求JWT签名与exp有效
+//   simple    - Login writes nothing, storage only keeps logout markers, safe windows and sessions | 登录不写存储，存储仅保存注销标记、二级认证与Session
+//   stateless - Storage is never touched, everything is served from signed claims | 不访问存储，全部基于签名声明
+//
+// Unsupported APIs return ErrUnsupportedInJwtMode | 不支持的API返回ErrUnsupportedInJwtMode:
+//
+//   API                                                    | mixed | simple | stateless
+//   -------------------------------------------------------+-------+--------+----------
+//   LoginByToken, LoginOptions.Token                       |   ✓   |   ✗    |    ✗
+//   Logout / Kickout (by loginID)                          |   ✓   |   ✗    |    ✗
+//   LogoutByToken / KickoutByToken                         |   ✓   |   ✓    |    ✗
+//   GetTokenValue / GetTokenValueListByLoginID             |   ✓   |   ✗    |    ✗
+//   GetTokenSignList / GetSessionCountByLoginID            |   ✓   |   ✗    |    ✗
+//   OpenSafe / CheckSafe / CloseSafe                       |   ✓   |   ✓    |    ✗
+//   Disable / Untie / GetDisableTime                       |   ✓   |   ✓ *  |    ✗
+//   GetSession / GetSessionByToken / DeleteSession         |   ✓   |   ✓    |    ✗
+//   SetPermissions / SetRoles                              |   ✓   |   ✓    |    ✗
+//   LoginWithRefreshToken / RefreshAccessToken             |   ✓   |   ✓    |    ✗
+//
+//   * Disable only blocks new logins, issued tokens stay valid until exp | 封禁仅阻止新登录，已签发Token在过期前仍有效
+//
+// Notes | 说明:
+//   - GetTokenValueListByDevice returns an empty list in simple and stateless | simple与stateless模式下返回空列表
+//   - IsSafe/IsDisable return false and GetSafeTime returns -2 in stateless | stateless模式下IsSafe/IsDisable返回false，GetSafeTime返回-2
+//   - simple and stateless ignore IsConcurrent, IsShare, MaxLoginCount and ActiveTimeout | simple与stateless忽略并发、共享、数量与活跃超时配置
+//   - No JWT mode auto-renews, exp is fixed at signing | 所有JWT模式均不自动续期，exp在签发时固定
+//   - Stateless GetPermissions/GetRoles only ask the PermissionProvider, uncached and without RBAC | stateless下GetPermissions/GetRoles仅查询数据源（不缓存、不含RBAC）
+//   - Nonce, temp token, API sign and OAuth2 helpers always use storage | Nonce、临时Token、API签名与OAuth2始终使用存储
+//   - With JwtRevocation, every mode also reads the revocation list (see jwt_revocation.go) | 启用JwtRevocation时，所有模式都会读取吊销列表（见jwt_revocation.go）
+//
+// Permission claims | 权限声明:
+//   Login embeds GetPermissions/GetRoles as "permissions"/"roles" claims when JwtClaims.EmbedPermissions is set,
+//   or when a provider is configured in mixed/simple/stateless, unless Extra or JwtClaimsHook sets them.
+//   GetPermissionsByToken/GetRolesByToken read the claims, mixed mode adds GetPermissions/GetRoles of the account.
+//   启用JwtClaims.EmbedPermissions，或在mixed/simple/stateless下配置了数据源时，登录将GetPermissions/GetRoles写入"permissions"/"roles"声明（Extra或钩子已指定时除外）。
+//   GetPermissionsByToken/GetRolesByToken读取声明，mixed模式另外合并账号的GetPermissions/GetRoles结果。
+
+// JwtClaimsHook Returns custom claims signed into the JWT at login | 返回登录时写入JWT的自定义声明
+type JwtClaimsHook func(loginID string, opts *LoginOptions) (map[string]any, error)
+
+// SetJwtClaimsHook Sets hook that injects custom claims (tenant, roles...) at login | 设置登录时注入自定义声明（租户、角色等）的钩子
+func (m *Manager) SetJwtClaimsHook(hook JwtClaimsHook) {
+	m.claimsHook = hook
+}
+
+// GetTokenClaims Gets typed claims of a logged-in JWT or encrypted token | 获取已登录JWT或加密Token的类型化声明
+func (m *Manager) GetTokenClaims(tokenValue string) (*token.Claims, error) {
+	if m.config.TokenStyle != config.TokenStyleJWT && m.config.TokenStyle != config.TokenStyleEncrypted {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedTokenStyle, m.config.TokenStyle)
+	}
+	if !m.IsLogin(tokenValue) {
+		return nil, ErrNotLogin
+	}
+	if m.config.TokenStyle == config.TokenStyleEncrypted {
+		return m.generator.DecryptToken(tokenValue)
+	}
+	return m.generator.ParseClaims(tokenValue)
+}
+
+// jwtMode Gets effective JWT mode, default for non-JWT token styles | 获取生效的JWT模式，非JWT风格为默认模式
+func (m *Manager) jwtMode() config.JwtMode {
+	if m.config.TokenStyle != config.TokenStyleJWT {
+		return config.JwtModeDefault
+	}
+	return m.config.JwtMode
+}
+
+// keepsTokenRecord Reports whether login writes TokenInfo and account index | 登录是否写入Token信息与账号索引
+func (m *Manager) keepsTokenRecord() bool {
+	mode := m.jwtMode()
+	return mode == config.JwtModeDefault || mode == config.JwtModeMixed
+}
+
+// checkJwtMode Returns ErrUnsupportedInJwtMode when current mode is one of modes | 当前模式属于modes时返回ErrUnsupportedInJwtMode
+func (m *Manager) checkJwtMode(modes ...config.JwtMode) error {
+	current := m.jwtMode()
+	for _, mode := range modes {
+		if current == mode {
+			return fmt.Errorf("%w: %s", ErrUnsupportedInJwtMode, current)
+		}
+	}
+	return nil
+}
+
+// loginWithClaims Signs a self-contained JWT without writing storage | 签发自包含的JWT，不写入存储
+func (m *Manager) loginWithClaims(loginID string, opts *LoginOptions) (string, error) {
+	if opts.Token != "" {
+		return "", fmt.Errorf("%w: forced token", ErrUnsupportedInJwtMode)
+	}
+	if m.IsDisable(loginID) {
+		return "", ErrAccountDisabled
+	}
+
+	deviceType := getDevice([]string{opts.Device})
+	timeout := m.config.Timeout
+	if opts.Timeout != 0 {
+		timeout = opts.Timeout
+	}
+
+	claims, err := m.buildClaims(loginID, opts)
+	if err != nil {
+		return "", err
+	}
+
+	tokenValue, err := m.generator.GenerateWithExtra(loginID, deviceType, timeout, claims)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate token: %w", err)
+	}
+
+	if m.eventManager != nil {
+		m.eventManager.Trigger(&listener.EventData{
+			Event:     listener.EventLogin,
+			LoginType: m.loginType,
+			LoginID:   loginID,
+			Token:     tokenValue,
+			Device:    deviceType,
+		})
+	}
+
+	return tokenValue, nil
+}
+
+// buildClaims Builds extra JWT claims of a login, opts.Extra when nothing is added | 构建登录的扩展JWT声明，无需追加时返回opts.Extra
+func (m *Manager) buildClaims(loginID string, opts *LoginOptions) (map[string]any, error) {
+	mode := m.jwtMode()
+	embed := m.embedPermissions()
+	if m.config.TokenStyle != config.TokenStyleJWT || (mode == config.JwtModeDefault && m.claimsHook == nil && !embed) {
+		return opts.Extra, nil
+	}
+
+	claims := make(map[string]any, len(opts.Extra)+3)
+	for k, v := range opts.Extra {
+		claims[k] = v
+	}
+
+	if m.claimsHook != nil {
+		custom, err := m.claimsHook(loginID, opts)
+		if err != nil {
+			return nil, fmt.Errorf("jwt claims hook: %w", err)
+		}
+		for k, v := range custom {
+			claims[k] = v
+		}
+	}
+
+	if mode != config.JwtModeDefault && opts.DeviceID != "" {
+		claims[token.ClaimKeyDeviceID] = opts.DeviceID
+	}
+
+	// Claims set by Extra or hook win over looked-up grants | Extra或钩子设置的声明优先于查询结果
+	if embed {
+		if _, ok := claims[token.ClaimKeyPermissions]; !ok {
+			if perms, err := m.GetPermissions(loginID); err == nil {
+				claims[token.ClaimKeyPermissions] = perms
+			}
+		}
+		if _, ok := claims[token.ClaimKeyRoles]; !ok {
+			if roles, err := m.GetRoles(loginID); err == nil {
+				claims[token.ClaimKeyRoles] = roles
+			}
+		}
+	}
+	return claims, nil
+}
+
+// embedPermissions Reports whether grants are signed into the JWT at login | 登录时是否将授权写入JWT
+// Configured explicitly, or implied by a provider in claims-based modes | 显式配置，或在基于声明的模式下配置了数据源
+func (m *Manager) embedPermissions() bool {
+	if cfg := m.config.JwtClaims; cfg != nil && cfg.EmbedPermissions {
+		return true
+	}
+	return m.jwtMode() != config.JwtModeDefault && m.permissionProvider != nil
+}
+
+// getTokenInfoFromClaims Builds TokenInfo from verified JWT claims | 从已验签的JWT声明构建Token信息
+func (m *Manager) getTokenInfoFromClaims(tokenValue string) (*TokenInfo, error) {
+	claims, err := m.generator.ParseClaims(tokenValue)
+	if err != nil {
+		if errors.Is(err, token.ErrTokenExpired) {
+			return nil, ErrTokenExpired
+		}
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTokenData, err)
+	}
+	return newTokenInfoFromClaims(claims)
+}
+
+// newTokenInfoFromClaims Converts typed claims to TokenInfo | 将类型化声明转换为Token信息
+func newTokenInfoFromClaims(claims *token.Claims) (*TokenInfo, error) {
+	if claims.LoginID == "" {
+		return nil, ErrInvalidTokenData
+	}
+
+	info := &TokenInfo{
+		LoginID:    claims.LoginID,
+		Device:     claims.Device,
+		DeviceID:   claims.DeviceID,
+		CreateTime: claims.IssuedAt,
+		ActiveTime: claims.IssuedAt,
+		Extra:      claims.Extra,
+	}
+	if claims.ExpiresAt > 0 {
+		info.Timeout = claims.ExpiresAt - claims.IssuedAt
+	}
+	return info, nil
+}
+
+// checkRevoked Checks logout marker of token in simple mode | simple模式下检查Token的注销标记
+func (m *Manager) checkRevoked(tokenValue string, checkState bool) error {
+	tokenKey := m.getTokenKey(tokenValue)
+	exists, err := adapter.CheckExists(m.storage, tokenKey)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrStorageUnavailable, err)
+	}
+	if !exists {
+		return nil
+	}
+
+	if checkState {
+		data, _ := m.storage.Get(tokenKey)
+		var str string
+		switch v := data.(type) {
+		case []byte:
+			str = string(v)
+		case string:
+			str = v
+		}
+		switch str {
+		case string(TokenStateKickout):
+			return ErrTokenKickout
+		case string(TokenStateReplaced):
+			return ErrTokenReplaced
+		}
+	}
+	return ErrNotLogin
+}
+
+// revokeToken Writes logout marker that lives until token exp | 写入存活至Token过期的注销标记
+func (m *Manager) revokeToken(tokenValue string, info *TokenInfo, event listener.Event) {
+	state := TokenStateLogout
+	switch event {
+	case listener.EventKickout:
+		state = TokenStateKickout
+	case listener.EventReplaced:
+		state = TokenStateReplaced
+	}
+
+	var expiration time.Duration
+	if info.Timeout > 0 {
+		expiration = time.Until(time.Unix(info.CreateTime+info.Timeout, 0))
+		if expiration <= 0 {
+			return
+		}
+	}
+	_ = m.storage.Set(m.getTokenKey(tokenValue), string(state), expiration)
+}
+
+// ============ Token Permission Claims | Token权限声明 ============
+
+// GetPermissionsByToken Gets permissions of token, from claims in JWT modes | 获取Token的权限（JWT模式下读取声明）
+func (m *Manager) GetPermissionsByToken(tokenValue string) ([]string, error) {
+	return m.getListByToken(tokenValue, func(c *token.Claims) []string { return c.Permissions }, m.GetPermissions)
+}
+
+// GetRolesByToken Gets roles of token, from claims in JWT modes | 获取Token的角色（JWT模式下读取声明）
+func (m *Manager) GetRolesByToken(tokenValue string) ([]string, error) {
+	return m.getListByToken(tokenValue, func(c *token.Claims) []string { return c.Roles }, m.GetRoles)
+}
+
+// HasPermissionByToken Checks if token has permission | 检查Token是否拥有指定权限
+func (m *Manager) HasPermissionByToken(tokenValue string, permission string) bool {
+	perms, err := m.GetPermissionsByToken(tokenValue)
+	if err != nil {
+		return false
+	}
+
+	for _, p := range perms {
+		if m.matchPermission(p, permission) {
+			return true
+		}
+	}
+	return false
+}
+
+// HasRoleByToken Checks if token has role | 检查Token是否拥有指定角色
+func (m *Manager) HasRoleByToken(tokenValue string, role string) bool {
+	roles, err := m.GetRolesByToken(tokenValue)
+	if err != nil {
+		return false
+	}
+
+	for _, r := range roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// getListByToken Reads list claim, falls back to or merges with account lookup | 读取列表声明，回退到或合并账号查询结果
+func (m *Manager) getListByToken(tokenValue string, pick func(*token.Claims) []string, byLoginID func(string) ([]string, error)) ([]string, error) {
+	loginID, err := m.GetLoginID(tokenValue)
+	if err != nil {
+		return nil, err
+	}
+
+	mode := m.jwtMode()
+	if mode == config.JwtModeDefault {
+		return byLoginID(loginID)
+	}
+
+	claims, err := m.generator.ParseClaims(tokenValue)
+	if err != nil {
+		return nil, err
+	}
+	list := pick(claims)
+	if list == nil {
+		return byLoginID(loginID)
+	}
+
+	if mode != config.JwtModeMixed {
+		return list, nil
+	}
+
+	// Mixed mode also honors grants made after login | mixed模式同时生效登录后的授权
+	extra, err := byLoginID(loginID)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool, len(list))
+	for _, v := range list {
+		seen[v] = true
+	}
+	for _, v := range extra {
+		if !seen[v] {
+			seen[v] = true
+			list = append(list, v)
+		}
+	}
+	return list, nil
+}
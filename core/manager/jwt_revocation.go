@@ -0,0 +1,186 @@
+package manager
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/click33/sa-token-go/core/adapter"
+	"github.com/click33/sa-token-go/core/config"
+	"github.com/click33/sa-token-go/core/token"
+	"github.com/click33/sa-token-go/core/utils"
+)
+
+// JWT Revocation Implementation
+// JWT吊销实现
+//
+// Flow | 流程:
+// 1. Enable Config.JwtRevocation, every JWT then carries a "jti" | 启用Config.JwtRevocation，所有JWT都携带jti
+// 2. RevokeToken() - Add jti to revocation list until the token's exp | 将jti加入吊销列表直至Token过期
+// 3. RevokeAllTokens()/RevokeTokensBefore() - Reject tokens of account issued before a watermark | 拒绝账号在水位之前签发的Token
+// 4. IsLogin()/CheckLogin() - Reject revoked tokens with ErrTokenRevoked | 拒绝已吊销的Token（ErrTokenRevoked）
+//
+// The revocation list is the only storage read in stateless mode, storage errors reject the token | 吊销列表是stateless模式下唯一的存储读取，存储错误时拒绝Token
+//
+// Watermarks and the private "iatUs" claim keep microseconds, so RevokeAllTokens rejects every token issued so far
+// while a re-login right after it stays valid; tokens without "iatUs" are compared by "iat" and rejected for the whole second
+// 水位与私有声明iatUs均为微秒精度，RevokeAllTokens拒绝目前为止签发的所有Token，而其后立即重新登录的Token仍然有效；
+// 没有iatUs的Token按iat比较，水位所在的整秒均被拒绝
+//
+// A watermark lives for Config.Timeout, tokens logged in with a longer timeout override need RevokeToken once it is gone
+// 水位保留Config.Timeout，使用更长超时覆盖登录的Token在水位过期后需通过RevokeToken吊销
+//
+// Usage | 用法:
+//   manager.RevokeToken(token)       // single token | 单个Token
+//   manager.RevokeAllTokens("1001")  // every token issued so far | 目前为止签发的所有Token
+
+// Revocation key prefixes | 吊销键前缀
+const (
+	RevokedKeyPrefix   = "jwt-revoked:"
+	NotBeforeKeyPrefix = "jwt-not-before:"
+	RevokedValue       = "1"
+)
+
+// revocationEnabled Reports whether JWT revocation is checked | 是否校验JWT吊销
+func (m *Manager) revocationEnabled() bool {
+	return m.config.TokenStyle == config.TokenStyleJWT && m.config.JwtRevocation
+}
+
+// checkRevocation Rejects revoked jti and tokens issued before the account watermark | 拒绝已吊销的jti及账号水位之前签发的Token
+func (m *Manager) checkRevocation(tokenValue string) error {
+	// Unverified read is safe here, this check can only reject | 此处仅可能拒绝Token，读取未验签声明是安全的
+	claims, err := m.generator.ParseClaimsUnverified(tokenValue)
+	if err != nil {
+		return nil
+	}
+
+	if claims.ID != "" {
+		revoked, err := adapter.CheckExists(m.storage, m.getRevokedKey(claims.ID))
+		if err != nil {
+			return fmt.Errorf("%w: failed to check token revocation: %w", ErrStorageUnavailable, err)
+		}
+		if revoked {
+			return ErrTokenRevoked
+		}
+	}
+	if claims.LoginID != "" {
+		watermark, ok, err := m.getNotBefore(claims.LoginID)
+		if err != nil {
+			return fmt.Errorf("%w: failed to check token revocation: %w", ErrStorageUnavailable, err)
+		}
+		if ok && issuedBefore(claims, watermark) {
+			return ErrTokenRevoked
+		}
+	}
+	return nil
+}
+
+// issuedBefore Reports whether token was issued no later than watermark (Unix microseconds) | Token是否在水位（Unix微秒）之前或同时签发
+func issuedBefore(claims *token.Claims, watermark int64) bool {
+	if claims.IssuedAtUs > 0 {
+		return claims.IssuedAtUs <= watermark
+	}
+	return claims.IssuedAt <= watermark/int64(time.Second/time.Microsecond)
+}
+
+// RevokeToken Adds token to revocation list until its exp | 将Token加入吊销列表直至其过期
+func (m *Manager) RevokeToken(tokenValue string) error {
+	if !m.revocationEnabled() {
+		return fmt.Errorf("%w: jwt revocation is disabled", ErrUnsupportedInJwtMode)
+	}
+
+	claims, err := m.generator.ParseClaims(tokenValue)
+	if err != nil {
+		return err
+	}
+	if claims.ID == "" {
+		return fmt.Errorf("%w: token has no jti", ErrInvalidTokenData)
+	}
+
+	var expiresAt time.Time
+	if claims.ExpiresAt > 0 {
+		expiresAt = time.Unix(claims.ExpiresAt, 0)
+	}
+	return m.RevokeTokenByID(claims.ID, expiresAt)
+}
+
+// RevokeTokenByID Adds jti to revocation list, zero expiresAt keeps it forever | 将jti加入吊销列表，expiresAt为零值时永久保留
+func (m *Manager) RevokeTokenByID(jti string, expiresAt time.Time) error {
+	if !m.revocationEnabled() {
+		return fmt.Errorf("%w: jwt revocation is disabled", ErrUnsupportedInJwtMode)
+	}
+	if jti == "" {
+		return fmt.Errorf("jti cannot be empty")
+	}
+
+	var expiration time.Duration
+	if !expiresAt.IsZero() {
+		// Already expired tokens need no entry | 已过期的Token无需记录
+		if expiration = time.Until(expiresAt); expiration <= 0 {
+			return nil
+		}
+	}
+	return m.storage.Set(m.getRevokedKey(jti), RevokedValue, expiration)
+}
+
+// IsTokenRevoked Checks if token is in revocation list or below account watermark, storage errors count as revoked | 检查Token是否已吊销或低于账号水位，存储错误视为已吊销
+func (m *Manager) IsTokenRevoked(tokenValue string) bool {
+	return m.revocationEnabled() && m.checkRevocation(tokenValue) != nil
+}
+
+// RevokeTokensBefore Revokes tokens of account issued up to t, the watermark never moves back | 吊销账号在t及之前签发的Token，水位不会回退
+func (m *Manager) RevokeTokensBefore(loginID string, t time.Time) error {
+	if !m.revocationEnabled() {
+		return fmt.Errorf("%w: jwt revocation is disabled", ErrUnsupportedInJwtMode)
+	}
+
+	watermark := t.UnixMicro()
+	current, ok, err := m.getNotBefore(loginID)
+	if err != nil {
+		return err
+	}
+	if ok && current >= watermark {
+		return nil
+	}
+
+	// Tokens issued up to t expire within Config.Timeout after it | t及之前签发的Token在其后Config.Timeout内过期
+	var expiration time.Duration
+	if m.config.Timeout > 0 {
+		expiration = time.Duration(m.config.Timeout)*time.Second + max(time.Until(t), 0)
+	}
+	return m.storage.Set(m.getNotBeforeKey(loginID), strconv.FormatInt(watermark, 10), expiration)
+}
+
+// RevokeAllTokens Revokes every token of account issued so far | 吊销账号目前为止签发的所有Token
+func (m *Manager) RevokeAllTokens(loginID string) error {
+	return m.RevokeTokensBefore(loginID, time.Now())
+}
+
+// getNotBefore Gets revocation watermark of account in Unix microseconds | 获取账号的吊销水位（Unix微秒）
+func (m *Manager) getNotBefore(loginID string) (int64, bool, error) {
+	key := m.getNotBeforeKey(loginID)
+	exists, err := adapter.CheckExists(m.storage, key)
+	if err != nil || !exists {
+		return 0, false, err
+	}
+
+	data, err := m.storage.Get(key)
+	if err != nil {
+		return 0, false, err
+	}
+	watermark, err := strconv.ParseInt(utils.ToString(data), 10, 64)
+	if err != nil {
+		return 0, false, fmt.Errorf("%w: revocation watermark", ErrInvalidTokenData)
+	}
+	return watermark, true, nil
+}
+
+// getRevokedKey Gets revocation list storage key | 获取吊销列表存储键
+func (m *Manager) getRevokedKey(jti string) string {
+	return m.prefix + RevokedKeyPrefix + jti
+}
+
+// getNotBeforeKey Gets account watermark storage key | 获取账号水位存储键
+func (m *Manager) getNotBeforeKey(loginID string) string {
+	return m.prefix + NotBeforeKeyPrefix + loginID
+}
@@ -0,0 +1,169 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/click33/sa-token-go/core/config"
+	"github.com/click33/sa-token-go/core/token"
+)
+
+func newRevocationManager(t *testing.T) *Manager {
+	t.Helper()
+	m, _ := newTestManager(t, func(cfg *config.Config) {
+		cfg.TokenStyle = config.TokenStyleJWT
+		cfg.JwtSecretKey = "test-secret-key"
+		cfg.JwtMode = config.JwtModeStateless
+		cfg.JwtRevocation = true
+	})
+	return m
+}
+
+func TestRevokeToken(t *testing.T) {
+	m := newRevocationManager(t)
+	token, _ := m.Login("1001")
+	other, _ := m.Login("1001")
+	if !m.IsLogin(token) {
+		t.Fatal("IsLogin() = false before revocation")
+	}
+
+	if err := m.RevokeToken(token); err != nil {
+		t.Fatalf("RevokeToken() error = %v", err)
+	}
+	if m.IsLogin(token) || !m.IsTokenRevoked(token) {
+		t.Fatal("revoked token still accepted")
+	}
+	if _, err := m.CheckLoginWithState(token); !errors.Is(err, ErrTokenRevoked) {
+		t.Fatalf("CheckLoginWithState(revoked) = %v, want ErrTokenRevoked", err)
+	}
+	if !m.IsLogin(other) {
+		t.Fatal("RevokeToken() rejected another token of the account")
+	}
+
+	ttl, _ := m.storage.TTL(m.getRevokedKey(mustClaims(t, m, token).ID))
+	if ttl <= 0 || ttl > time.Duration(m.config.Timeout)*time.Second {
+		t.Fatalf("revocation entry TTL = %v, want the token's remaining lifetime", ttl)
+	}
+
+	// Expired tokens need no entry | 已过期的Token无需记录
+	if err := m.RevokeTokenByID("gone", time.Now().Add(-time.Second)); err != nil || m.storage.Exists(m.getRevokedKey("gone")) {
+		t.Fatalf("RevokeTokenByID(expired) = %v, entry stored", err)
+	}
+}
+
+func TestRevokeTokensBefore(t *testing.T) {
+	m := newRevocationManager(t)
+	token, _ := m.Login("1001")
+	stranger, _ := m.Login("1002")
+
+	// Watermark in the next second covers every token issued so far | 下一秒的水位覆盖目前为止签发的所有Token
+	if err := m.RevokeTokensBefore("1001", time.Now().Add(time.Second)); err != nil {
+		t.Fatalf("RevokeTokensBefore() error = %v", err)
+	}
+	if m.IsLogin(token) {
+		t.Fatal("token issued before the watermark still accepted")
+	}
+	if !m.IsLogin(stranger) {
+		t.Fatal("watermark rejected another account")
+	}
+
+	// The watermark never moves back | 水位不会回退
+	if err := m.RevokeTokensBefore("1001", time.Now().Add(-time.Hour)); err != nil {
+		t.Fatalf("RevokeTokensBefore(past) error = %v", err)
+	}
+	if m.IsLogin(token) {
+		t.Fatal("older watermark re-admitted a revoked token")
+	}
+}
+
+func TestRevokeAllTokens(t *testing.T) {
+	m := newRevocationManager(t)
+	tokens := make([]string, 3)
+	for i := range tokens {
+		tokens[i], _ = m.Login("1001")
+	}
+
+	// Tokens issued within the same second as the revoke are rejected too | 与吊销同一秒内签发的Token同样被拒绝
+	if err := m.RevokeAllTokens("1001"); err != nil {
+		t.Fatalf("RevokeAllTokens() error = %v", err)
+	}
+	for _, tokenValue := range tokens {
+		if m.IsLogin(tokenValue) {
+			t.Fatal("token issued before RevokeAllTokens still accepted")
+		}
+	}
+
+	ttl, _ := m.storage.TTL(m.getNotBeforeKey("1001"))
+	if timeout := time.Duration(m.config.Timeout) * time.Second; ttl <= timeout-time.Minute || ttl > timeout {
+		t.Fatalf("watermark TTL = %v, want the token lifetime %v", ttl, timeout)
+	}
+}
+
+func TestIssuedBefore(t *testing.T) {
+	watermark := time.Unix(1000, 500_000_000).UnixMicro()
+	tests := []struct {
+		name   string
+		claims token.Claims
+		want   bool
+	}{
+		{"earlier microsecond", token.Claims{IssuedAt: 1000, IssuedAtUs: watermark - 1}, true},
+		{"same microsecond", token.Claims{IssuedAt: 1000, IssuedAtUs: watermark}, true},
+		{"later microsecond", token.Claims{IssuedAt: 1000, IssuedAtUs: watermark + 1}, false},
+		{"legacy token in the same second", token.Claims{IssuedAt: 1000}, true},
+		{"legacy token in the next second", token.Claims{IssuedAt: 1001}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := issuedBefore(&tt.claims, watermark); got != tt.want {
+				t.Fatalf("issuedBefore() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRevokeAllTokensAllowsRelogin(t *testing.T) {
+	m := newRevocationManager(t)
+	if _, err := m.Login("1001"); err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+
+	if err := m.RevokeAllTokens("1001"); err != nil {
+		t.Fatalf("RevokeAllTokens() error = %v", err)
+	}
+	token, err := m.Login("1001")
+	if err != nil {
+		t.Fatalf("Login() after RevokeAllTokens error = %v", err)
+	}
+	if !m.IsLogin(token) {
+		t.Fatal("re-login right after RevokeAllTokens rejected")
+	}
+}
+
+func TestRevocationFailsClosed(t *testing.T) {
+	m := newRevocationManager(t)
+	token, _ := m.Login("1001")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	view := m.WithContext(ctx)
+	if view.IsLogin(token) {
+		t.Fatal("IsLogin() accepted a token whose revocation could not be checked")
+	}
+	if _, err := view.CheckLoginWithState(token); !errors.Is(err, context.Canceled) {
+		t.Fatalf("CheckLoginWithState() = %v, want the storage error", err)
+	}
+	if !view.IsTokenRevoked(token) {
+		t.Fatal("IsTokenRevoked() = false on storage error")
+	}
+}
+
+func mustClaims(t *testing.T, m *Manager, tokenValue string) *token.Claims {
+	t.Helper()
+	claims, err := m.generator.ParseClaims(tokenValue)
+	if err != nil {
+		t.Fatalf("ParseClaims() error = %v", err)
+	}
+	return claims
+}
@@ -0,0 +1,82 @@
+package manager
+
+// LoginOptions Login parameters for LoginWithOptions | LoginWithOptions使用的登录参数
+type LoginOptions struct {
+	Device        string         // Device type, empty uses "default" | 设备类型，为空使用"default"
+	DeviceID      string         // Stable device fingerprint | 稳定的设备指纹ID
+	Timeout       int64          // Token timeout in seconds, 0 uses config, -1 never expire | Token超时（秒），0使用全局配置，-1永不过期
+	ActiveTimeout int64          // Active timeout in seconds, 0 uses config, -1 no limit | 活跃超时（秒），0使用全局配置，-1不限制
+	Remember      bool           // Persistent cookie for token timeout, false writes a browser-session cookie | 记住我：持久Cookie，false为浏览器会话Cookie
+	Extra         map[string]any // Extra data written into TokenInfo and JWT claims | 写入TokenInfo与JWT声明的扩展数据
+	Token         string         // Force this token value instead of generating one | 强制使用的Token值（不自动生成）
+}
+
+// NewLoginOptions Creates login options with remember-me enabled | 创建登录参数（默认记住我）
+func NewLoginOptions() *LoginOptions {
+	return &LoginOptions{
+		Remember: true,
+	}
+}
+
+// SetDevice Sets device type | 设置设备类型
+func (o *LoginOptions) SetDevice(device string) *LoginOptions {
+	o.Device = device
+	return o
+}
+
+// SetDeviceID Sets device fingerprint | 设置设备指纹ID
+func (o *LoginOptions) SetDeviceID(deviceID string) *LoginOptions {
+	o.DeviceID = deviceID
+	return o
+}
+
+// SetTimeout Sets token timeout in seconds | 设置Token超时（秒）
+func (o *LoginOptions) SetTimeout(timeout int64) *LoginOptions {
+	o.Timeout = timeout
+	return o
+}
+
+// SetActiveTimeout Sets active timeout in seconds | 设置活跃超时（秒）
+func (o *LoginOptions) SetActiveTimeout(timeout int64) *LoginOptions {
+	o.ActiveTimeout = timeout
+	return o
+}
+
+// SetRemember Sets remember-me | 设置是否记住我
+func (o *LoginOptions) SetRemember(remember bool) *LoginOptions {
+	o.Remember = remember
+	return o
+}
+
+// SetExtra Sets an extra key/value | 设置扩展数据
+func (o *LoginOptions) SetExtra(key string, value any) *LoginOptions {
+	if o.Extra == nil {
+		o.Extra = make(map[string]any)
+	}
+	o.Extra[key] = value
+	return o
+}
+
+// SetToken Forces token value | 强制指定Token值
+func (o *LoginOptions) SetToken(token string) *LoginOptions {
+	o.Token = token
+	return o
+}
+
+// LimitTimeouts Drops timeout overrides longer than config, for options built from client input | 丢弃长于全局配置的超时覆盖（用于由客户端输入构建的参数）
+func (o *LoginOptions) LimitTimeouts(timeout, activeTimeout int64) *LoginOptions {
+	o.Timeout = limitOverride(o.Timeout, timeout)
+	o.ActiveTimeout = limitOverride(o.ActiveTimeout, activeTimeout)
+	return o
+}
+
+// limitOverride Keeps override only if it does not outlive max, non-positive max means unlimited | 仅保留不超过max的覆盖值，max非正数表示不限制
+func limitOverride(value, max int64) int64 {
+	if max <= 0 {
+		return value
+	}
+	if value < 0 || value > max {
+		return 0
+	}
+	return value
+}
@@ -2,7 +2,10 @@ package manager
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -12,6 +15,7 @@ import (
 	"github.com/click33/sa-token-go/core/config"
 	"github.com/click33/sa-token-go/core/listener"
 	"github.com/click33/sa-token-go/core/oauth2"
+	"github.com/click33/sa-token-go/core/rbac"
 	"github.com/click33/sa-token-go/core/security"
 	"github.com/click33/sa-token-go/core/session"
 	"github.com/click33/sa-token-go/core/token"
@@ -30,6 +34,11 @@ const (
 	AccountKeyPrefix = "account:"
 	DisableKeyPrefix = "disable:"
 	RenewKeyPrefix   = "renew:"
+	SafeKeyPrefix    = "safe:"
+	KeySeparator     = ":" // Separates realm and device key segments | 分隔账号体系与设备键段
+
+	// Safe authentication | 二级认证
+	DefaultSafeService = "important"
 
 	// Session keys | Session键
 	SessionKeyLoginID     = "loginId"
@@ -49,6 +58,7 @@ type TokenState string
 const (
 	TokenStateKickout  TokenState = "KICK_OUT"
 	TokenStateReplaced TokenState = "BE_REPLACED"
+	TokenStateLogout   TokenState = "LOGOUT" // Logout marker of storage-less JWT modes | 无存储JWT模式的注销标记
 )
 
 // Error variables | 错误变量
@@ -60,28 +70,60 @@ var (
 	ErrLoginLimitExceeded = fmt.Errorf("login count exceeds the maximum limit")
 	ErrTokenKickout       = fmt.Errorf("token has been kicked out")
 	ErrTokenReplaced      = fmt.Errorf("token has been replaced")
+	ErrNotSafe            = fmt.Errorf("not safe: second-level authentication is required for this operation")
+	ErrTokenFrozen        = fmt.Errorf("token has been frozen due to inactivity")
+	ErrTokenExists        = fmt.Errorf("token value is already bound to another account")
+	ErrTokenExpired       = fmt.Errorf("token has expired")
+	ErrTokenRevoked       = fmt.Errorf("token has been revoked")
+	ErrStorageUnavailable = fmt.Errorf("storage unavailable")
+
+	ErrUnsupportedInJwtMode  = fmt.Errorf("operation is not supported in current jwt mode")
+	ErrUnsupportedTokenStyle = fmt.Errorf("operation is not supported by current token style")
 )
 
 // TokenInfo Token information | Token信息
 type TokenInfo struct {
-	LoginID    string `json:"loginId"`
-	Device     string `json:"device"`
-	CreateTime int64  `json:"createTime"`
-	ActiveTime int64  `json:"activeTime"` // Last active time | 最后活跃时间
-	Tag        string `json:"tag,omitempty"`
+	LoginID       string         `json:"loginId"`
+	Device        string         `json:"device"`
+	DeviceID      string         `json:"deviceId,omitempty"` // Optional physical device identifier | 可选的物理设备标识
+	CreateTime    int64          `json:"createTime"`
+	ActiveTime    int64          `json:"activeTime"`              // Last active time | 最后活跃时间
+	Timeout       int64          `json:"timeout,omitempty"`       // Per-login timeout override | 单次登录的超时覆盖
+	ActiveTimeout int64          `json:"activeTimeout,omitempty"` // Per-login active timeout override | 单次登录的活跃超时覆盖
+	Extra         map[string]any `json:"extra,omitempty"`         // Extra login data | 登录扩展数据
+	Tag           string         `json:"tag,omitempty"`
+}
+
+// TokenSign Entry of the account-to-token index | 账号Token索引条目
+type TokenSign struct {
+	Value      string `json:"value"`              // Token value | Token值
+	Device     string `json:"device"`             // Device type | 设备类型
+	DeviceID   string `json:"deviceId,omitempty"` // Optional physical device identifier | 可选的物理设备标识
+	CreateTime int64  `json:"createTime"`         // Login time | 登录时间
+	Seq        int64  `json:"seq,omitempty"`      // Login order within the same second | 同一秒内的登录顺序
 }
 
 // Manager Authentication manager | 认证管理器
 type Manager struct {
 	storage        adapter.Storage
+	storageV2      adapter.StorageV2 // Context-aware form of storage | 存储的上下文感知形式
+	root           *Manager          // Manager that WithContext was called on | 调用WithContext的原始管理器
 	config         *config.Config
 	generator      *token.Generator
+	loginType      string
 	prefix         string
 	nonceManager   *security.NonceManager
 	refreshManager *security.RefreshTokenManager
+	tempTokens     *security.TempTokenManager
+	signManager    *security.SignManager
 	oauth2Server   *oauth2.OAuth2Server
 	renewPool      *pool.RenewPoolManager
 	eventManager   *listener.Manager
+
+	roleManager        *rbac.RoleManager  // Role hierarchy and assignments | 角色继承与授权
+	permissionProvider PermissionProvider // On-demand permission source | 按需加载的权限数据源
+	permissionCacheTTL time.Duration      // Provider result cache TTL | 数据源结果缓存时间
+	claimsHook         JwtClaimsHook      // Custom JWT claims at login | 登录时的自定义JWT声明
 }
 
 // NewManager Creates a new manager | 创建管理器
@@ -96,6 +138,15 @@ func NewManager(storage adapter.Storage, cfg *config.Config) *Manager {
 		prefix = DefaultPrefix
 	}
 
+	// Isolate non-default realms under their own key segment | 非默认账号体系使用独立的键空间
+	loginType := cfg.LoginType
+	if loginType == "" {
+		loginType = config.DefaultLoginType
+	}
+	if loginType != config.DefaultLoginType {
+		prefix = prefix + loginType + KeySeparator
+	}
+
 	// Initialize renew pool manager if configuration is provided | 如果配置了续期池，初始化续期池管理器
 	var renewPoolManager *pool.RenewPoolManager
 	if cfg.RenewPoolConfig != nil {
@@ -114,11 +165,16 @@ func NewManager(storage adapter.Storage, cfg *config.Config) *Manager {
 
 	return &Manager{
 		storage:        storage,
+		storageV2:      adapter.AsStorageV2(storage),
 		config:         cfg,
 		generator:      token.NewGenerator(cfg),
+		loginType:      loginType,
 		prefix:         prefix,
 		nonceManager:   security.NewNonceManager(storage, prefix, DefaultNonceTTL),
 		refreshManager: security.NewRefreshTokenManager(storage, prefix, TokenKeyPrefix, cfg),
+		tempTokens:     security.NewTempTokenManager(storage, prefix),
+		signManager:    security.NewSignManager(storage, prefix, nil),
+		roleManager:    rbac.NewRoleManager(storage, prefix),
 		oauth2Server:   oauth2.NewOAuth2Server(storage, prefix),
 		eventManager:   listener.NewManager(),
 		renewPool:      renewPoolManager,
@@ -146,12 +202,33 @@ func getDevice(device []string) string {
 
 // getExpiration calculates expiration duration from config | 从配置计算过期时间
 func (m *Manager) getExpiration() time.Duration {
-	if m.config.Timeout > 0 {
-		return time.Duration(m.config.Timeout) * time.Second
+	return timeoutToExpiration(m.config.Timeout)
+}
+
+// getTokenExpiration calculates expiration of token, honoring per-login override | 计算Token过期时间（优先单次登录覆盖）
+func (m *Manager) getTokenExpiration(info *TokenInfo) time.Duration {
+	if info != nil && info.Timeout != 0 {
+		return timeoutToExpiration(info.Timeout)
+	}
+	return m.getExpiration()
+}
+
+// timeoutToExpiration converts timeout seconds to duration, non-positive means never expire | 将超时秒数转换为时长，非正数表示永不过期
+func timeoutToExpiration(timeout int64) time.Duration {
+	if timeout > 0 {
+		return time.Duration(timeout) * time.Second
 	}
 	return 0
 }
 
+// getSafeService extracts safe service name from optional parameter | 从可选参数中提取二级认证业务标识
+func getSafeService(service []string) string {
+	if len(service) > 0 && service[0] != "" {
+		return service[0]
+	}
+	return DefaultSafeService
+}
+
 // assertString safely converts interface to string | 安全地将interface转换为string
 func assertString(v any) (string, bool) {
 	s, ok := v.(string)
@@ -162,23 +239,40 @@ func assertString(v any) (string, bool) {
 
 // Login Performs user login and returns token | 登录，返回Token
 func (m *Manager) Login(loginID string, device ...string) (string, error) {
+	return m.LoginWithOptions(loginID, NewLoginOptions().SetDevice(getDevice(device)))
+}
+
+// LoginWithOptions Performs user login with parameters and returns token | 使用登录参数登录，返回Token
+func (m *Manager) LoginWithOptions(loginID string, opts *LoginOptions) (string, error) {
+	if opts == nil {
+		opts = NewLoginOptions()
+	}
+
+	// Storage-less JWT modes only sign the token | 无存储的JWT模式仅签发Token
+	if !m.keepsTokenRecord() {
+		return m.loginWithClaims(loginID, opts)
+	}
+
 	// Check if account is disabled | 检查账号是否被封禁
 	if m.IsDisable(loginID) {
 		return "", ErrAccountDisabled
 	}
 
-	deviceType := getDevice(device)
-	accountKey := m.getAccountKey(loginID, deviceType)
+	deviceType := getDevice([]string{opts.Device})
 
 	// Handle shared token for concurrent login | 处理多人登录共用 Token 的情况
-	if m.config.IsShare {
-		// Look for existing token of this account + device | 查找账号 + 设备下是否已有登录 Token
-		existingToken, err := m.storage.Get(accountKey)
-		if err == nil && existingToken != nil {
-			if tokenStr, ok := assertString(existingToken); ok && m.IsLogin(tokenStr) {
+	if m.config.IsShare && opts.Token == "" {
+		// Look for latest token of this account + device (+ device ID) | 查找账号 + 设备（+ 设备ID）下最近的登录 Token
+		signs := m.getTokenSignsByDevice(loginID, deviceType)
+		for i := len(signs) - 1; i >= 0; i-- {
+			if opts.DeviceID != "" && signs[i].DeviceID != opts.DeviceID {
+				continue
+			}
+			if tokenStr := signs[i].Value; m.IsLogin(tokenStr) {
 				// If valid token exists, return it directly | 如果已有 Token 且有效，则直接返回
 				return tokenStr, nil
 			}
+			break
 		}
 	}
 
@@ -197,27 +291,48 @@ func (m *Manager) Login(loginID string, device ...string) (string, error) {
 		// This limit applies to all tokens of this account across devices | 该限制针对账号所有设备的登录 Token 数量
 		tokens, _ := m.GetTokenValueListByLoginID(loginID)
 		if len(tokens) >= m.config.MaxLoginCount {
-			// Reached maximum concurrent login count | 已达到最大并发登录数
-			// You may change to "kick out earliest token" if desired | 如需也可改为“踢掉最早 Token”
-			return "", ErrLoginLimitExceeded
+			// Reached maximum concurrent login count, apply overflow policy | 已达到最大并发登录数，执行溢出策略
+			if err := m.handleLoginOverflow(loginID, deviceType, tokens); err != nil {
+				return "", err
+			}
 		}
 	}
 
-	// Generate token | 生成Token
-	tokenValue, err := m.generator.Generate(loginID, deviceType)
-	if err != nil {
-		return "", fmt.Errorf("failed to generate token: %w", err)
+	timeout := m.config.Timeout
+	if opts.Timeout != 0 {
+		timeout = opts.Timeout
+	}
+
+	// Use forced token or generate one | 使用强制指定的Token或生成Token
+	tokenValue := opts.Token
+	if tokenValue != "" {
+		if info, err := m.getTokenInfo(tokenValue, false); err == nil && info != nil && info.LoginID != loginID {
+			return "", ErrTokenExists
+		}
+	} else {
+		claims, err := m.buildClaims(loginID, opts)
+		if err != nil {
+			return "", err
+		}
+		tokenValue, err = m.generator.GenerateWithExtra(loginID, deviceType, timeout, claims)
+		if err != nil {
+			return "", fmt.Errorf("failed to generate token: %w", err)
+		}
 	}
 
 	nowTime := time.Now().Unix()
-	expiration := m.getExpiration()
+	expiration := timeoutToExpiration(timeout)
 
 	// Prepare TokenInfo object and serialize to JSON | 准备Token信息对象并序列化为JSON
 	tokenInfoStr, err := json.Marshal(TokenInfo{
-		LoginID:    loginID,
-		Device:     deviceType,
-		CreateTime: nowTime,
-		ActiveTime: nowTime,
+		LoginID:       loginID,
+		Device:        deviceType,
+		DeviceID:      opts.DeviceID,
+		CreateTime:    nowTime,
+		ActiveTime:    nowTime,
+		Timeout:       opts.Timeout,
+		ActiveTimeout: opts.ActiveTimeout,
+		Extra:         opts.Extra,
 	})
 	if err != nil {
 		return "", fmt.Errorf("failed to marshal tokenInfo: %w", err)
@@ -229,8 +344,13 @@ func (m *Manager) Login(loginID string, device ...string) (string, error) {
 		return "", fmt.Errorf("failed to save token: %w", err)
 	}
 
-	// Save account-token mapping | 保存 AccountKey-Token 映射
-	if err = m.storage.Set(accountKey, tokenValue, expiration); err != nil {
+	// Append to account token index | 追加到账号Token索引
+	if err = m.addTokenSign(loginID, TokenSign{
+		Value:      tokenValue,
+		Device:     deviceType,
+		DeviceID:   opts.DeviceID,
+		CreateTime: nowTime,
+	}, expiration); err != nil {
 		return "", fmt.Errorf("failed to save account mapping: %w", err)
 	}
 
@@ -252,18 +372,72 @@ func (m *Manager) Login(loginID string, device ...string) (string, error) {
 	// Trigger login event | 触发登录事件
 	if m.eventManager != nil {
 		m.eventManager.Trigger(&listener.EventData{
-			Event:   listener.EventLogin,
-			LoginID: loginID,
-			Token:   tokenValue,
-			Device:  deviceType,
+			Event:     listener.EventLogin,
+			LoginType: m.loginType,
+			LoginID:   loginID,
+			Token:     tokenValue,
+			Device:    deviceType,
 		})
 	}
 
 	return tokenValue, nil
 }
 
+// handleLoginOverflow Frees a login slot according to OverflowPolicy | 按溢出策略腾出登录名额
+func (m *Manager) handleLoginOverflow(loginID, device string, tokens []string) error {
+	switch m.config.OverflowPolicy {
+
+	// Replace the oldest token on the same device, reject if the device has none | 顶掉同设备最早的Token，该设备无Token时拒绝
+	case config.OverflowKickSameDevice:
+		signs := m.getTokenSignsByDevice(loginID, device)
+		if len(signs) == 0 {
+			return ErrLoginLimitExceeded
+		}
+		return m.removeTokenChain(signs[0].Value, false, listener.EventReplaced)
+
+	// Replace tokens ordered by CreateTime or ActiveTime until a slot is free | 按创建时间或活跃时间依次顶掉Token直到腾出名额
+	case config.OverflowKickOldest, config.OverflowKickLeastActive:
+		type candidate struct {
+			token string
+			time  int64
+		}
+		candidates := make([]candidate, 0, len(tokens))
+		for _, tokenValue := range tokens {
+			info, err := m.getTokenInfo(tokenValue, false)
+			if err != nil || info == nil {
+				continue
+			}
+			t := info.CreateTime
+			if m.config.OverflowPolicy == config.OverflowKickLeastActive {
+				t = info.ActiveTime
+			}
+			candidates = append(candidates, candidate{token: tokenValue, time: t})
+		}
+		// Stable sort keeps index order (creation order) for ties within a second | 稳定排序，同一秒内按索引顺序（创建顺序）
+		sort.SliceStable(candidates, func(i, j int) bool {
+			return candidates[i].time < candidates[j].time
+		})
+
+		excess := len(tokens) - m.config.MaxLoginCount + 1
+		for i := 0; i < excess && i < len(candidates); i++ {
+			if err := m.removeTokenChain(candidates[i].token, false, listener.EventReplaced); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	// OverflowReject and unset policy | 拒绝策略或未设置
+	default:
+		return ErrLoginLimitExceeded
+	}
+}
+
 // LoginByToken Login with specified token (for seamless token refresh) | 使用指定Token登录（用于token无感刷新）
 func (m *Manager) LoginByToken(loginID string, tokenValue string, device ...string) error {
+	if err := m.checkJwtMode(config.JwtModeSimple, config.JwtModeStateless); err != nil {
+		return err
+	}
+
 	info, err := m.getTokenInfo(tokenValue)
 	if err != nil {
 		return err
@@ -278,7 +452,7 @@ func (m *Manager) LoginByToken(loginID string, tokenValue string, device ...stri
 	}
 
 	now := time.Now().Unix()
-	expiration := m.getExpiration()
+	expiration := m.getTokenExpiration(info)
 
 	// Update last active time only | 更新活跃时间（轻量刷新）
 	info.ActiveTime = now
@@ -291,7 +465,7 @@ func (m *Manager) LoginByToken(loginID string, tokenValue string, device ...stri
 	// Extend TTL for token, account, session | 延长Token、账号、Session的过期时间
 	if expiration > 0 {
 		_ = m.storage.Expire(m.getTokenKey(tokenValue), expiration)
-		_ = m.storage.Expire(m.getAccountKey(info.LoginID, info.Device), expiration)
+		m.extendAccountIndex(info.LoginID, expiration)
 		if sess, err := m.GetSession(info.LoginID); err == nil && sess != nil {
 			_ = sess.Renew(expiration)
 		}
@@ -300,23 +474,20 @@ func (m *Manager) LoginByToken(loginID string, tokenValue string, device ...stri
 	return nil
 }
 
-// Logout Performs user logout | 登出
+// Logout Performs user logout of every token on the device | 登出（该设备下的所有Token）
 func (m *Manager) Logout(loginID string, device ...string) error {
-	deviceType := getDevice(device)
-	accountKey := m.getAccountKey(loginID, deviceType)
-
-	tokenValue, err := m.storage.Get(accountKey)
-	if err != nil || tokenValue == nil {
-		return nil // Already logged out | 已经登出
+	if err := m.checkJwtMode(config.JwtModeSimple, config.JwtModeStateless); err != nil {
+		return err
 	}
 
-	// Assert token value type | 类型断言为字符串
-	tokenStr, ok := assertString(tokenValue)
-	if !ok {
-		return nil
-	}
+	deviceType := getDevice(device)
 
-	return m.removeTokenChain(tokenStr, false, listener.EventLogout)
+	for _, sign := range m.getTokenSignsByDevice(loginID, deviceType) {
+		if err := m.removeTokenChain(sign.Value, false, listener.EventLogout); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // LogoutByToken Logout by token | 根据Token登出
@@ -324,28 +495,28 @@ func (m *Manager) LogoutByToken(tokenValue string) error {
 	if tokenValue == "" {
 		return nil
 	}
+	if err := m.checkJwtMode(config.JwtModeStateless); err != nil {
+		return err
+	}
 
 	return m.removeTokenChain(tokenValue, false, listener.EventLogout)
 }
 
-// kickout Kick user offline (private) | 踢人下线（私有）
+// kickout Kick every token on the device offline (private) | 踢下线该设备下的所有Token（私有）
 func (m *Manager) kickout(loginID string, device string) error {
-	accountKey := m.getAccountKey(loginID, device)
-	tokenValue, err := m.storage.Get(accountKey)
-	if err != nil || tokenValue == nil {
-		return nil
-	}
-
-	tokenStr, ok := assertString(tokenValue)
-	if !ok {
-		return nil
+	for _, sign := range m.getTokenSignsByDevice(loginID, device) {
+		if err := m.removeTokenChain(sign.Value, false, listener.EventKickout); err != nil {
+			return err
+		}
 	}
-
-	return m.removeTokenChain(tokenStr, false, listener.EventKickout)
+	return nil
 }
 
 // Kickout Kick user offline (public method) | 踢人下线（公开方法）
 func (m *Manager) Kickout(loginID string, device ...string) error {
+	if err := m.checkJwtMode(config.JwtModeSimple, config.JwtModeStateless); err != nil {
+		return err
+	}
 	deviceType := getDevice(device)
 	return m.kickout(loginID, deviceType)
 }
@@ -357,6 +528,9 @@ func (m *Manager) kickoutByToken(tokenValue string) error {
 
 // KickoutByToken Kick user offline (public method) | 根据Token踢人下线（公开方法）
 func (m *Manager) KickoutByToken(tokenValue string) error {
+	if err := m.checkJwtMode(config.JwtModeStateless); err != nil {
+		return err
+	}
 	return m.kickoutByToken(tokenValue)
 }
 
@@ -364,23 +538,36 @@ func (m *Manager) KickoutByToken(tokenValue string) error {
 
 // IsLogin Checks if user is logged in | 检查是否登录
 func (m *Manager) IsLogin(tokenValue string) bool {
+	isLogin, _ := m.isLogin(tokenValue)
+	return isLogin
+}
+
+// isLogin Checks login, storage failures are returned instead of read as not logged in | 检查是否登录，存储故障作为错误返回而不视为未登录
+func (m *Manager) isLogin(tokenValue string) (bool, error) {
 	if tokenValue == "" {
-		return false
+		return false, nil
 	}
-	if _, err := m.getTokenInfo(tokenValue, false); err != nil {
-		return false
+	info, err := m.getTokenInfo(tokenValue, false)
+	if errors.Is(err, ErrStorageUnavailable) {
+		return false, err
+	}
+	if err != nil || info == nil {
+		return false, nil
+	}
+	if err := m.checkActiveTimeout(tokenValue, info); err != nil {
+		return false, nil
 	}
 
 	// Async auto-renew for better performance | 异步自动续期（提高性能）
-	// Note: ActiveTimeout feature removed to comply with Java sa-token design
-	if m.config.AutoRenew && m.config.Timeout > 0 {
+	if m.config.AutoRenew && m.config.Timeout > 0 && m.jwtMode() == config.JwtModeDefault {
 		tokenKey := m.getTokenKey(tokenValue)
 		if ttl, err := m.storage.TTL(tokenKey); err == nil {
 			ttlSeconds := int64(ttl.Seconds())
 
 			// Perform renewal if TTL is below MaxRefresh threshold and RenewInterval allows | TTL和RenewInterval同时满足条件才续期
 			if ttlSeconds > 0 && (m.config.MaxRefresh <= 0 || ttlSeconds <= m.config.MaxRefresh) && (m.config.RenewInterval <= 0 || !m.storage.Exists(m.getRenewKey(tokenValue))) {
-				renewFunc := func() { m.renewToken(tokenValue) }
+				// Renewal outlives the request, so it must not use the request context | 续期晚于请求结束，不能使用请求上下文
+				renewFunc := func() { m.detached().renewToken(tokenValue) }
 
 				// Submit to pool if configured, otherwise use goroutine | 使用续期池或协程执行续期
 				if m.renewPool != nil {
@@ -392,12 +579,47 @@ func (m *Manager) IsLogin(tokenValue string) bool {
 		}
 	}
 
-	return true
+	return true, nil
+}
+
+// checkActiveTimeout Rejects frozen token and refreshes its active time | 拒绝已冻结的Token并刷新活跃时间
+// Active time is tracked when active timeout is enabled or OverflowKickLeastActive ranks tokens by it | 启用活跃超时或OverflowKickLeastActive按活跃时间排序时记录活跃时间
+func (m *Manager) checkActiveTimeout(tokenValue string, info *TokenInfo) error {
+	if !m.keepsTokenRecord() {
+		return nil
+	}
+
+	activeTimeout := m.config.ActiveTimeout
+	if info.ActiveTimeout != 0 {
+		activeTimeout = info.ActiveTimeout
+	}
+	if activeTimeout <= 0 && m.config.OverflowPolicy != config.OverflowKickLeastActive {
+		return nil
+	}
+
+	now := time.Now().Unix()
+	if activeTimeout > 0 && now-info.ActiveTime > activeTimeout {
+		return ErrTokenFrozen
+	}
+
+	// One write per second is enough at second resolution | 秒级精度下每秒写一次即可
+	if info.ActiveTime == now {
+		return nil
+	}
+	info.ActiveTime = now
+	if data, err := json.Marshal(info); err == nil {
+		_ = m.storage.SetKeepTTL(m.getTokenKey(tokenValue), string(data))
+	}
+	return nil
 }
 
 // CheckLogin Checks login status (throws error if not logged in) | 检查登录（未登录抛出错误）
 func (m *Manager) CheckLogin(tokenValue string) error {
-	if !m.IsLogin(tokenValue) {
+	isLogin, err := m.isLogin(tokenValue)
+	if err != nil {
+		return err
+	}
+	if !isLogin {
 		return ErrNotLogin
 	}
 	return nil
@@ -410,20 +632,26 @@ func (m *Manager) CheckLoginWithState(tokenValue string) (bool, error) {
 	}
 
 	// Try to get token info with state check | 尝试获取Token信息（包含状态检查）
-	_, err := m.getTokenInfo(tokenValue)
+	info, err := m.getTokenInfo(tokenValue)
 	if err != nil {
 		return false, err
 	}
+	if info == nil {
+		return false, nil
+	}
+	if err := m.checkActiveTimeout(tokenValue, info); err != nil {
+		return false, err
+	}
 
 	// Async auto-renew for better performance | 异步自动续期（提高性能）
-	// Note: ActiveTimeout feature removed to comply with Java sa-token design
-	if m.config.AutoRenew && m.config.Timeout > 0 {
+	if m.config.AutoRenew && m.config.Timeout > 0 && m.jwtMode() == config.JwtModeDefault {
 		if ttl, err := m.storage.TTL(m.getTokenKey(tokenValue)); err == nil {
 			ttlSeconds := int64(ttl.Seconds())
 
 			// Perform renewal if TTL is below MaxRefresh threshold and RenewInterval allows | TTL和RenewInterval同时满足条件才续期
 			if ttlSeconds > 0 && (m.config.MaxRefresh <= 0 || ttlSeconds <= m.config.MaxRefresh) && (m.config.RenewInterval <= 0 || !m.storage.Exists(m.getRenewKey(tokenValue))) {
-				renewFunc := func() { m.renewToken(tokenValue) }
+				// Renewal outlives the request, so it must not use the request context | 续期晚于请求结束，不能使用请求上下文
+				renewFunc := func() { m.detached().renewToken(tokenValue) }
 
 				// Submit to pool if configured, otherwise use goroutine | 使用续期池或协程执行续期
 				if m.renewPool != nil {
@@ -440,7 +668,11 @@ func (m *Manager) CheckLoginWithState(tokenValue string) (bool, error) {
 
 // GetLoginID Gets login ID from token | 根据Token获取登录ID
 func (m *Manager) GetLoginID(tokenValue string) (string, error) {
-	if !m.IsLogin(tokenValue) {
+	isLogin, err := m.isLogin(tokenValue)
+	if err != nil {
+		return "", err
+	}
+	if !isLogin {
 		return "", ErrNotLogin
 	}
 
@@ -469,20 +701,18 @@ func (m *Manager) GetLoginIDNotCheck(tokenValue string) (string, error) {
 
 // GetTokenValue Gets token by login ID | 根据登录ID获取Token
 func (m *Manager) GetTokenValue(loginID string, device ...string) (string, error) {
+	if err := m.checkJwtMode(config.JwtModeSimple, config.JwtModeStateless); err != nil {
+		return "", err
+	}
 	deviceType := getDevice(device)
-	accountKey := m.getAccountKey(loginID, deviceType)
 
-	tokenValue, err := m.storage.Get(accountKey)
-	if err != nil || tokenValue == nil {
+	// Latest login on the device wins | 返回该设备最近一次登录的Token
+	signs := m.getTokenSignsByDevice(loginID, deviceType)
+	if len(signs) == 0 {
 		return "", fmt.Errorf("token not found for login id: %s", loginID)
 	}
 
-	tokenStr, ok := assertString(tokenValue)
-	if !ok {
-		return "", fmt.Errorf("invalid token value type")
-	}
-
-	return tokenStr, nil
+	return signs[len(signs)-1].Value, nil
 }
 
 // GetTokenInfo Gets token information | 获取Token信息
@@ -490,10 +720,154 @@ func (m *Manager) GetTokenInfo(tokenValue string) (*TokenInfo, error) {
 	return m.getTokenInfo(tokenValue)
 }
 
+// GetTokenExtra Gets extra login data of token | 获取Token的登录扩展数据
+func (m *Manager) GetTokenExtra(tokenValue, key string) (any, bool) {
+	info, err := m.getTokenInfo(tokenValue)
+	if err != nil || info == nil || info.Extra == nil {
+		return nil, false
+	}
+	value, ok := info.Extra[key]
+	return value, ok
+}
+
+// GetCookieMaxAge Gets cookie MaxAge for a login, 0 writes a browser-session cookie | 获取登录Cookie的MaxAge，0为浏览器会话Cookie
+// A positive CookieConfig.MaxAge overrides the token timeout for remembered logins | CookieConfig.MaxAge为正数时覆盖记住我登录的Token超时
+func (m *Manager) GetCookieMaxAge(opts *LoginOptions) int {
+	if opts != nil && !opts.Remember {
+		return 0
+	}
+	if m.config.CookieConfig != nil && m.config.CookieConfig.MaxAge > 0 {
+		return m.config.CookieConfig.MaxAge
+	}
+
+	timeout := m.config.Timeout
+	if opts != nil && opts.Timeout != 0 {
+		timeout = opts.Timeout
+	}
+	if timeout < 0 {
+		return 0
+	}
+	return int(timeout)
+}
+
+// ============ Safe Authentication | 二级认证 ============
+
+// OpenSafe Opens a time-boxed safe window for token | 为Token开启限时二级认证
+// Windows of one token share a hash keyed by service, so logout drops them with one delete | 同一Token的认证窗口保存在按业务分字段的哈希中，注销时一次删除
+func (m *Manager) OpenSafe(tokenValue string, safeTime int64, service ...string) error {
+	if err := m.checkJwtMode(config.JwtModeStateless); err != nil {
+		return err
+	}
+	if err := m.CheckLogin(tokenValue); err != nil {
+		return err
+	}
+	if safeTime <= 0 {
+		return fmt.Errorf("safeTime must be > 0, got: %d", safeTime)
+	}
+
+	key := m.getSafeKey(tokenValue)
+	deadline := time.Now().Unix() + safeTime
+	if err := adapter.HSet(m.storage, key, getSafeService(service), strconv.FormatInt(deadline, 10)); err != nil {
+		return err
+	}
+	return m.expireSafe(key)
+}
+
+// IsSafe Checks if token is within safe window | 检查Token是否处于二级认证有效期内
+func (m *Manager) IsSafe(tokenValue string, service ...string) bool {
+	return m.GetSafeTime(tokenValue, service...) > 0
+}
+
+// CheckSafe Checks safe window (returns error if not opened) | 检查二级认证（未通过返回错误）
+func (m *Manager) CheckSafe(tokenValue string, service ...string) error {
+	if err := m.checkJwtMode(config.JwtModeStateless); err != nil {
+		return err
+	}
+	if !m.IsSafe(tokenValue, service...) {
+		return ErrNotSafe
+	}
+	return nil
+}
+
+// GetSafeTime Gets remaining safe time in seconds, -2 if not opened | 获取二级认证剩余有效时间（秒），未开启返回-2
+func (m *Manager) GetSafeTime(tokenValue string, service ...string) int64 {
+	if tokenValue == "" || m.jwtMode() == config.JwtModeStateless {
+		return -2
+	}
+	windows, err := adapter.HGetAll(m.storage, m.getSafeKey(tokenValue))
+	if err != nil {
+		return -2
+	}
+	deadline, err := strconv.ParseInt(windows[getSafeService(service)], 10, 64)
+	if err != nil {
+		return -2
+	}
+	if remaining := deadline - time.Now().Unix(); remaining > 0 {
+		return remaining
+	}
+	return -2
+}
+
+// CloseSafe Closes safe window of token | 关闭Token的二级认证
+func (m *Manager) CloseSafe(tokenValue string, service ...string) error {
+	if tokenValue == "" {
+		return nil
+	}
+	if err := m.checkJwtMode(config.JwtModeStateless); err != nil {
+		return err
+	}
+	return adapter.HDel(m.storage, m.getSafeKey(tokenValue), getSafeService(service))
+}
+
+// closeAllSafe Closes all safe windows of token | 关闭Token的所有二级认证
+func (m *Manager) closeAllSafe(tokenValue string) {
+	_ = m.storage.Delete(m.getSafeKey(tokenValue))
+}
+
+// expireSafe Drops lapsed windows and lets the hash expire with its latest window | 清理已过期窗口，并让哈希随最晚的窗口过期
+func (m *Manager) expireSafe(key string) error {
+	windows, err := adapter.HGetAll(m.storage, key)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().Unix()
+	var latest int64
+	var lapsed []string
+	for service, value := range windows {
+		deadline, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || deadline <= now {
+			lapsed = append(lapsed, service)
+			continue
+		}
+		if deadline > latest {
+			latest = deadline
+		}
+	}
+	if len(lapsed) > 0 {
+		if err := adapter.HDel(m.storage, key, lapsed...); err != nil {
+			return err
+		}
+	}
+	if latest == 0 {
+		return nil
+	}
+	return m.storage.Expire(key, time.Duration(latest-now)*time.Second)
+}
+
+// getSafeKey Gets safe authentication hash key of token | 获取Token的二级认证哈希键
+func (m *Manager) getSafeKey(tokenValue string) string {
+	return m.prefix + SafeKeyPrefix + tokenValue
+}
+
 // ============ Account Disable | 账号封禁 ============
 
 // Disable Disables an account | 封禁账号
 func (m *Manager) Disable(loginID string, duration time.Duration) error {
+	if err := m.checkJwtMode(config.JwtModeStateless); err != nil {
+		return err
+	}
+
 	// Check if the account has active sessions and force logout | 检查账号是否有活跃会话并强制下线
 	tokens, err := m.GetTokenValueListByLoginID(loginID)
 	if err == nil && len(tokens) > 0 {
@@ -510,18 +884,27 @@ func (m *Manager) Disable(loginID string, duration time.Duration) error {
 
 // Untie Re-enables a disabled account | 解封账号
 func (m *Manager) Untie(loginID string) error {
+	if err := m.checkJwtMode(config.JwtModeStateless); err != nil {
+		return err
+	}
 	key := m.getDisableKey(loginID)
 	return m.storage.Delete(key)
 }
 
 // IsDisable Checks if account is disabled | 检查账号是否被封禁
 func (m *Manager) IsDisable(loginID string) bool {
+	if m.jwtMode() == config.JwtModeStateless {
+		return false
+	}
 	key := m.getDisableKey(loginID)
 	return m.storage.Exists(key)
 }
 
 // GetDisableTime Gets remaining disable time in seconds | 获取账号剩余封禁时间（秒）
 func (m *Manager) GetDisableTime(loginID string) (int64, error) {
+	if err := m.checkJwtMode(config.JwtModeStateless); err != nil {
+		return -2, err
+	}
 	key := m.getDisableKey(loginID)
 	ttl, err := m.storage.TTL(key)
 	if err != nil {
@@ -539,6 +922,10 @@ func (m *Manager) getDisableKey(loginID string) string {
 
 // GetSession Gets session by login ID | 获取Session
 func (m *Manager) GetSession(loginID string) (*session.Session, error) {
+	if err := m.checkJwtMode(config.JwtModeStateless); err != nil {
+		return nil, err
+	}
+
 	sess, err := session.Load(loginID, m.storage, m.prefix)
 	if err != nil {
 		sess = session.NewSession(loginID, m.storage, m.prefix)
@@ -566,7 +953,7 @@ func (m *Manager) DeleteSession(loginID string) error {
 
 // ============ Permission Validation | 权限验证 ============
 
-// SetPermissions Sets permissions for user | 设置权限
+// SetPermissions Sets permissions for user (ignored by GetPermissions when a provider is configured) | 设置权限（配置数据源时GetPermissions不读取）
 func (m *Manager) SetPermissions(loginID string, permissions []string) error {
 	sess, err := m.GetSession(loginID)
 	if err != nil {
@@ -575,8 +962,45 @@ func (m *Manager) SetPermissions(loginID string, permissions []string) error {
 	return sess.Set(SessionKeyPermissions, permissions, m.getExpiration())
 }
 
-// GetPermissions Gets permission list | 获取权限列表
+// GetPermissions Gets effective permissions: direct grants plus those inherited through roles | 获取有效权限：直接授予的权限及通过角色继承的权限
 func (m *Manager) GetPermissions(loginID string) ([]string, error) {
+	if m.jwtMode() == config.JwtModeStateless {
+		if m.permissionProvider == nil {
+			return nil, fmt.Errorf("%w: permissions without provider", ErrUnsupportedInJwtMode)
+		}
+		return m.permissionProvider.GetPermissionList(loginID, m.loginType)
+	}
+
+	perms, err := m.getDirectPermissions(loginID)
+	if err != nil {
+		return nil, err
+	}
+	roles, err := m.getAssignedRoles(loginID)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool, len(perms))
+	for _, p := range perms {
+		seen[p] = true
+	}
+	for _, p := range m.roleManager.PermissionsOfRoles(roles) {
+		if !seen[p] {
+			seen[p] = true
+			perms = append(perms, p)
+		}
+	}
+	return perms, nil
+}
+
+// getDirectPermissions Gets permissions granted to account itself, from provider when configured | 获取直接授予账号的权限（配置数据源时从数据源获取）
+func (m *Manager) getDirectPermissions(loginID string) ([]string, error) {
+	if m.permissionProvider != nil {
+		return m.loadFromProvider(m.prefix+PermissionCacheKeyPrefix+loginID, func() ([]string, error) {
+			return m.permissionProvider.GetPermissionList(loginID, m.loginType)
+		})
+	}
+
 	sess, err := m.GetSession(loginID)
 	if err != nil {
 		return nil, err
@@ -596,20 +1020,17 @@ func (m *Manager) HasPermission(loginID string, permission string) bool {
 	if err != nil {
 		return false
 	}
-
-	for _, p := range perms {
-		if m.matchPermission(p, permission) {
-			return true
-		}
-	}
-
-	return false
+	return m.hasPermissionIn(perms, permission)
 }
 
 // HasPermissionsAnd 检查是否拥有所有权限（AND）
 func (m *Manager) HasPermissionsAnd(loginID string, permissions []string) bool {
+	perms, err := m.GetPermissions(loginID)
+	if err != nil {
+		return false
+	}
 	for _, perm := range permissions {
-		if !m.HasPermission(loginID, perm) {
+		if !m.hasPermissionIn(perms, perm) {
 			return false
 		}
 	}
@@ -618,8 +1039,22 @@ func (m *Manager) HasPermissionsAnd(loginID string, permissions []string) bool {
 
 // HasPermissionsOr 检查是否拥有任一权限（OR）
 func (m *Manager) HasPermissionsOr(loginID string, permissions []string) bool {
+	perms, err := m.GetPermissions(loginID)
+	if err != nil {
+		return false
+	}
 	for _, perm := range permissions {
-		if m.HasPermission(loginID, perm) {
+		if m.hasPermissionIn(perms, perm) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasPermissionIn Checks granted list against permission | 检查已授予列表是否包含权限
+func (m *Manager) hasPermissionIn(perms []string, permission string) bool {
+	for _, p := range perms {
+		if m.matchPermission(p, permission) {
 			return true
 		}
 	}
@@ -660,7 +1095,7 @@ func (m *Manager) matchPermission(pattern, permission string) bool {
 
 // ============ Role Validation | 角色验证 ============
 
-// SetRoles Sets roles for user | 设置角色
+// SetRoles Sets roles for user (ignored by GetRoles when a provider is configured) | 设置角色（配置数据源时GetRoles不读取）
 func (m *Manager) SetRoles(loginID string, roles []string) error {
 	sess, err := m.GetSession(loginID)
 	if err != nil {
@@ -669,8 +1104,43 @@ func (m *Manager) SetRoles(loginID string, roles []string) error {
 	return sess.Set(SessionKeyRoles, roles, m.getExpiration())
 }
 
-// GetRoles Gets role list | 获取角色列表
+// GetRoles Gets effective roles including every inherited role | 获取有效角色（含全部继承角色）
 func (m *Manager) GetRoles(loginID string) ([]string, error) {
+	if m.jwtMode() == config.JwtModeStateless {
+		if m.permissionProvider == nil {
+			return nil, fmt.Errorf("%w: roles without provider", ErrUnsupportedInJwtMode)
+		}
+		return m.permissionProvider.GetRoleList(loginID, m.loginType)
+	}
+
+	roles, err := m.getAssignedRoles(loginID)
+	if err != nil {
+		return nil, err
+	}
+	return m.roleManager.ExpandRoles(roles), nil
+}
+
+// getAssignedRoles Gets direct roles plus roles assigned through RBAC | 获取直接角色及通过RBAC分配的角色
+func (m *Manager) getAssignedRoles(loginID string) ([]string, error) {
+	roles, err := m.getDirectRoles(loginID)
+	if err != nil {
+		return nil, err
+	}
+	assigned, err := m.roleManager.GetUserRoles(loginID)
+	if err != nil {
+		return nil, err
+	}
+	return append(roles, assigned...), nil
+}
+
+// getDirectRoles Gets roles set on account itself, from provider when configured | 获取账号自身的角色（配置数据源时从数据源获取）
+func (m *Manager) getDirectRoles(loginID string) ([]string, error) {
+	if m.permissionProvider != nil {
+		return m.loadFromProvider(m.prefix+RoleCacheKeyPrefix+loginID, func() ([]string, error) {
+			return m.permissionProvider.GetRoleList(loginID, m.loginType)
+		})
+	}
+
 	sess, err := m.GetSession(loginID)
 	if err != nil {
 		return nil, err
@@ -738,25 +1208,41 @@ func (m *Manager) GetTokenTag(tokenValue string) (string, error) {
 
 // GetTokenValueListByLoginID Gets all tokens for specified account | 获取指定账号的所有Token
 func (m *Manager) GetTokenValueListByLoginID(loginID string) ([]string, error) {
-	pattern := m.prefix + AccountKeyPrefix + loginID + ":*"
-	keys, err := m.storage.Keys(pattern)
+	signs, err := m.GetTokenSignList(loginID)
 	if err != nil {
 		return nil, err
 	}
 
-	tokens := make([]string, 0, len(keys))
-	for _, key := range keys {
-		value, err := m.storage.Get(key)
-		if err == nil && value != nil {
-			if tokenStr, ok := assertString(value); ok {
-				tokens = append(tokens, tokenStr)
-			}
-		}
+	tokens := make([]string, 0, len(signs))
+	for _, sign := range signs {
+		tokens = append(tokens, sign.Value)
 	}
-
 	return tokens, nil
 }
 
+// GetTokenValueListByDevice Gets all tokens of account on device | 获取指定账号在某设备上的所有Token
+func (m *Manager) GetTokenValueListByDevice(loginID string, device ...string) []string {
+	if !m.keepsTokenRecord() {
+		return []string{}
+	}
+
+	signs := m.getTokenSignsByDevice(loginID, getDevice(device))
+
+	tokens := make([]string, 0, len(signs))
+	for _, sign := range signs {
+		tokens = append(tokens, sign.Value)
+	}
+	return tokens
+}
+
+// GetTokenSignList Gets account token index ordered by login time | 获取账号Token索引（按登录时间排序）
+func (m *Manager) GetTokenSignList(loginID string) ([]TokenSign, error) {
+	if err := m.checkJwtMode(config.JwtModeSimple, config.JwtModeStateless); err != nil {
+		return nil, err
+	}
+	return m.loadTokenSigns(loginID)
+}
+
 // GetSessionCountByLoginID Gets session count for specified account | 获取指定账号的Session数量
 func (m *Manager) GetSessionCountByLoginID(loginID string) (int, error) {
 	tokens, err := m.GetTokenValueListByLoginID(loginID)
@@ -773,9 +1259,178 @@ func (m *Manager) getTokenKey(tokenValue string) string {
 	return m.prefix + TokenKeyPrefix + tokenValue
 }
 
-// getAccountKey Gets account storage key | 获取账号存储键
-func (m *Manager) getAccountKey(loginID, device string) string {
-	return m.prefix + AccountKeyPrefix + loginID + PermissionSeparator + device
+// getAccountKey Gets account token index storage key | 获取账号Token索引存储键
+func (m *Manager) getAccountKey(loginID string) string {
+	return m.prefix + AccountKeyPrefix + loginID
+}
+
+// ============ Account Token Index | 账号Token索引 ============
+//
+// The index of an account is one hash, field = token value, value = TokenSign JSON, so concurrent
+// logins on several instances add and remove their own field without overwriting each other
+// 账号索引为一个哈希，字段为Token值，值为TokenSign JSON，多实例并发登录各自增删字段而不会互相覆盖
+//
+// Before this layout every device had a key "account:<loginID>:<device>" holding one token. Such keys are
+// folded into the hash the first time their device is looked up; MigrateAccountIndex converts all of them at once
+// 旧版本每个设备一个键"account:<loginID>:<device>"（保存单个Token）。该设备首次被查询时旧键并入哈希；MigrateAccountIndex可一次性迁移全部旧键
+
+// loadTokenSigns Loads account token index ordered by login time, pruning entries whose token expired | 加载按登录时间排序的账号Token索引，并清理Token已过期的条目
+func (m *Manager) loadTokenSigns(loginID string) ([]TokenSign, error) {
+	key := m.getAccountKey(loginID)
+	fields, err := adapter.HGetAll(m.storage, key)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTokenData, err)
+	}
+
+	signs := make([]TokenSign, 0, len(fields))
+	var dead []string
+	for tokenValue, value := range fields {
+		var sign TokenSign
+		if err := json.Unmarshal([]byte(value), &sign); err != nil || !m.storage.Exists(m.getTokenKey(tokenValue)) {
+			dead = append(dead, tokenValue)
+			continue
+		}
+		sign.Value = tokenValue
+		signs = append(signs, sign)
+	}
+	if len(dead) > 0 {
+		_ = adapter.HDel(m.storage, key, dead...)
+	}
+
+	sort.Slice(signs, func(i, j int) bool {
+		if signs[i].CreateTime != signs[j].CreateTime {
+			return signs[i].CreateTime < signs[j].CreateTime
+		}
+		return signs[i].Seq < signs[j].Seq
+	})
+	return signs, nil
+}
+
+// accountIndexExpiration Picks the longer of wanted and remaining index TTL | 取期望值与索引剩余TTL中较长者
+func (m *Manager) accountIndexExpiration(key string, expiration time.Duration) time.Duration {
+	if expiration <= 0 || !m.storage.Exists(key) {
+		return expiration
+	}
+	ttl, err := m.storage.TTL(key)
+	if err != nil {
+		return expiration
+	}
+	if ttl < 0 {
+		return 0 // Index is permanent | 索引永不过期
+	}
+	if ttl > expiration {
+		return ttl
+	}
+	return expiration
+}
+
+// extendAccountIndex Extends account index TTL without shortening it | 延长账号索引TTL（不缩短）
+// The index never expires before its longest-living token | 索引的过期时间不早于其中存活最久的Token
+func (m *Manager) extendAccountIndex(loginID string, expiration time.Duration) {
+	key := m.getAccountKey(loginID)
+	if exp := m.accountIndexExpiration(key, expiration); exp > 0 {
+		_ = m.storage.Expire(key, exp)
+	}
+}
+
+// addTokenSign Adds a token to account index, re-login with a forced token replaces its entry | 向账号索引添加Token，强制Token重复登录时替换原条目
+func (m *Manager) addTokenSign(loginID string, sign TokenSign, expiration time.Duration) error {
+	key := m.getAccountKey(loginID)
+	// Read the TTL before writing, a new hash has none yet | 写入前读取TTL，新建的哈希尚无TTL
+	exp := m.accountIndexExpiration(key, expiration)
+
+	sign.Seq = time.Now().UnixNano()
+	data, err := json.Marshal(sign)
+	if err != nil {
+		return err
+	}
+	if err := adapter.HSet(m.storage, key, sign.Value, string(data)); err != nil {
+		return err
+	}
+	// exp <= 0 keeps the index permanent like its token | exp <= 0时索引与Token一样永不过期
+	_ = m.storage.Expire(key, exp)
+	return nil
+}
+
+// removeTokenSign Removes a token from account index | 从账号索引移除Token
+func (m *Manager) removeTokenSign(loginID, tokenValue string) error {
+	return adapter.HDel(m.storage, m.getAccountKey(loginID), tokenValue)
+}
+
+// getTokenSignsByDevice Gets index entries of device ordered by login time | 获取某设备的索引条目（按登录时间排序）
+func (m *Manager) getTokenSignsByDevice(loginID, device string) []TokenSign {
+	m.migrateLegacyAccountKey(m.getLegacyAccountKey(loginID, device))
+
+	signs, err := m.loadTokenSigns(loginID)
+	if err != nil {
+		return nil
+	}
+
+	result := make([]TokenSign, 0, len(signs))
+	for _, sign := range signs {
+		if sign.Device == device {
+			result = append(result, sign)
+		}
+	}
+	return result
+}
+
+// MigrateAccountIndex Folds every legacy "account:<loginID>:<device>" key into the account hashes | 将全部旧版"account:<loginID>:<device>"键并入账号哈希
+// A one-off upgrade step that scans storage keys, returns the number of migrated tokens | 一次性升级步骤（会扫描存储键），返回迁移的Token数量
+func (m *Manager) MigrateAccountIndex() (int, error) {
+	if err := m.checkJwtMode(config.JwtModeSimple, config.JwtModeStateless); err != nil {
+		return 0, err
+	}
+
+	keys, err := m.storage.Keys(m.prefix + AccountKeyPrefix + "*")
+	if err != nil {
+		return 0, err
+	}
+	migrated := 0
+	for _, key := range keys {
+		if m.migrateLegacyAccountKey(key) {
+			migrated++
+		}
+	}
+	return migrated, nil
+}
+
+// migrateLegacyAccountKey Moves the token of a legacy per-device key into the account hash | 将旧版按设备保存的Token移入账号哈希
+// Keys whose value is not a live token of that exact account and device are left untouched | 值不是该账号与设备的有效Token时不做处理
+func (m *Manager) migrateLegacyAccountKey(key string) bool {
+	data, err := m.storage.Get(key)
+	if err != nil {
+		return false
+	}
+	tokenValue, ok := data.(string)
+	if !ok || tokenValue == "" {
+		return false
+	}
+
+	info, err := m.getTokenInfo(tokenValue, false)
+	if err != nil || info == nil || m.getLegacyAccountKey(info.LoginID, info.Device) != key {
+		return false
+	}
+
+	var expiration time.Duration
+	if ttl, err := m.storage.TTL(m.getTokenKey(tokenValue)); err == nil && ttl > 0 {
+		expiration = ttl
+	}
+	if err := m.addTokenSign(info.LoginID, TokenSign{
+		Value:      tokenValue,
+		Device:     info.Device,
+		DeviceID:   info.DeviceID,
+		CreateTime: info.CreateTime,
+	}, expiration); err != nil {
+		return false
+	}
+	_ = m.storage.Delete(key)
+	return true
+}
+
+// getLegacyAccountKey Gets the pre-hash per-device account key | 获取旧版按设备划分的账号键
+func (m *Manager) getLegacyAccountKey(loginID, device string) string {
+	return m.getAccountKey(loginID) + KeySeparator + device
 }
 
 // getRenewKey Gets token renewal tracking key | 获取Token续期追踪键
@@ -794,11 +1449,38 @@ func (m *Manager) getLoginIDByToken(tokenValue string) (string, error) {
 
 // getTokenInfo Gets token information | 获取Token信息
 func (m *Manager) getTokenInfo(tokenValue string, checkState ...bool) (*TokenInfo, error) {
+	if m.revocationEnabled() {
+		if err := m.checkRevocation(tokenValue); err != nil {
+			return nil, err
+		}
+	}
+
+	switch m.jwtMode() {
+	case config.JwtModeStateless:
+		return m.getTokenInfoFromClaims(tokenValue)
+	case config.JwtModeSimple:
+		if err := m.checkRevoked(tokenValue, len(checkState) == 0 || checkState[0]); err != nil {
+			return nil, err
+		}
+		return m.getTokenInfoFromClaims(tokenValue)
+	case config.JwtModeMixed:
+		if _, err := m.getTokenInfoFromClaims(tokenValue); err != nil {
+			return nil, err
+		}
+	}
+
 	tokenKey := m.getTokenKey(tokenValue)
 	data, err := m.storage.Get(tokenKey)
-	if err != nil || data == nil {
+	if err != nil {
+		// Most storages also fail Get on missing keys, tell those from a failing backend | 多数存储对不存在的键也会Get失败，需与后端故障区分
+		if _, existsErr := adapter.CheckExists(m.storage, tokenKey); existsErr != nil {
+			return nil, fmt.Errorf("%w: %w", ErrStorageUnavailable, existsErr)
+		}
 		return nil, err
 	}
+	if data == nil {
+		return nil, nil
+	}
 
 	// Convert storage value to string | 将存储值统一转换为字符串
 	var str string
@@ -849,15 +1531,14 @@ func (m *Manager) renewToken(tokenValue string) {
 		_ = m.storage.SetKeepTTL(tokenKey, tokenInfo)
 	}
 
-	// Extend TTL for token and its accountKey | 为 Token 与对应 accountKey 延长 TTL
-	exp := m.getExpiration()
+	// Extend TTL for token and its account index | 为 Token 与对应账号索引延长 TTL
+	exp := m.getTokenExpiration(info)
 	if exp > 0 {
 		// Renew token TTL | 续期 Token TTL
 		_ = m.storage.Expire(tokenKey, exp)
 
-		// Renew accountKey TTL | 续期账号映射 TTL
-		accountKey := m.getAccountKey(info.LoginID, info.Device)
-		_ = m.storage.Expire(accountKey, exp)
+		// Renew account index TTL | 续期账号索引 TTL
+		m.extendAccountIndex(info.LoginID, exp)
 
 		// Renew session TTL | 续期 Session TTL
 		if sess, err := m.GetSession(info.LoginID); err == nil && sess != nil {
@@ -890,44 +1571,58 @@ func (m *Manager) removeTokenChain(tokenValue string, destroySession bool, event
 		return ErrInvalidTokenData
 	}
 
-	tokenKey := m.getTokenKey(tokenValue)                    // Token存储键 | Token storage key
-	accountKey := m.getAccountKey(info.LoginID, info.Device) // Account映射键 | Account mapping key
-	renewKey := m.getRenewKey(tokenValue)                    // 续期追踪键 | Token renewal tracking key
+	tokenKey := m.getTokenKey(tokenValue) // Token存储键 | Token storage key
+	renewKey := m.getRenewKey(tokenValue) // 续期追踪键 | Token renewal tracking key
 
-	switch event {
+	// Safe windows never outlive the token | 二级认证不应比Token存活更久
+	m.closeAllSafe(tokenValue)
+
+	switch {
+
+	// Simple JWT mode keeps no token record, mark it revoked until exp | simple JWT模式无Token记录，写入注销标记直至过期
+	case m.jwtMode() == config.JwtModeSimple:
+		m.revokeToken(tokenValue, info, event)
 
 	// EventLogout User logout | 用户主动登出
-	case listener.EventLogout:
-		_ = m.storage.Delete(tokenKey)   // Delete token-info mapping | 删除Token信息映射
-		_ = m.storage.Delete(accountKey) // Delete account-token mapping | 删除账号映射
-		_ = m.storage.Delete(renewKey)   // Delete renew key | 删除续期标记
-		if destroySession {              // Optionally destroy session | 可选销毁Session
+	case event == listener.EventLogout:
+		_ = m.storage.Delete(tokenKey) // Delete token-info mapping | 删除Token信息映射
+		_ = m.storage.Delete(renewKey) // Delete renew key | 删除续期标记
+		if destroySession {            // Optionally destroy session | 可选销毁Session
 			_ = m.DeleteSession(info.LoginID)
 		}
 
 	// EventKickout User kicked offline (keep session) | 用户被踢下线（保留Session）
-	case listener.EventKickout:
+	case event == listener.EventKickout:
 		_ = m.storage.SetKeepTTL(tokenKey, string(TokenStateKickout)) // Mark token as kicked out (preserve original TTL for cleanup) | 将Token标记为“被踢下线”（保留原TTL以便自动清理）
-		_ = m.storage.Delete(accountKey)                              // Delete account mapping | 删除账号映射
 		_ = m.storage.Delete(renewKey)                                // Delete renew key | 删除续期标记
 
+	// EventReplaced Token replaced by a newer login (keep session) | Token被新登录顶下线（保留Session）
+	case event == listener.EventReplaced:
+		_ = m.storage.SetKeepTTL(tokenKey, string(TokenStateReplaced)) // Mark token as replaced | 将Token标记为“被顶下线”
+		_ = m.storage.Delete(renewKey)                                 // Delete renew key | 删除续期标记
+
 	// Default Unknown event type | 未知事件类型（默认删除）
 	default:
 		_ = m.storage.Delete(tokenKey)
-		_ = m.storage.Delete(accountKey)
 		_ = m.storage.Delete(renewKey)
 		if destroySession {
 			_ = m.DeleteSession(info.LoginID)
 		}
 	}
 
+	// Drop token from account index | 从账号索引移除Token
+	if m.keepsTokenRecord() {
+		_ = m.removeTokenSign(info.LoginID, tokenValue)
+	}
+
 	// Trigger event notification | 触发事件通知
 	if m.eventManager != nil {
 		m.eventManager.Trigger(&listener.EventData{
-			Event:   event,
-			LoginID: info.LoginID,
-			Token:   tokenValue,
-			Device:  info.Device,
+			Event:     event,
+			LoginType: m.loginType,
+			LoginID:   info.LoginID,
+			Token:     tokenValue,
+			Device:    info.Device,
 		})
 	}
 
@@ -1016,6 +1711,16 @@ func (m *Manager) GetStorage() adapter.Storage {
 	return m.storage
 }
 
+// GetLoginType Gets account realm name | 获取账号体系标识
+func (m *Manager) GetLoginType() string {
+	return m.loginType
+}
+
+// GetKeyPrefix Gets storage key prefix of this realm | 获取当前账号体系的存储键前缀
+func (m *Manager) GetKeyPrefix() string {
+	return m.prefix
+}
+
 // ============ Security Features | 安全特性 ============
 
 // GenerateNonce Generates a one-time nonce | 生成一次性随机数
@@ -1030,6 +1735,10 @@ func (m *Manager) VerifyNonce(nonce string) bool {
 
 // LoginWithRefreshToken Logs in with refresh token | 使用刷新令牌登录
 func (m *Manager) LoginWithRefreshToken(loginID, device string) (*security.RefreshTokenInfo, error) {
+	if err := m.checkJwtMode(config.JwtModeStateless); err != nil {
+		return nil, err
+	}
+
 	deviceType := getDevice([]string{device})
 
 	accessToken, err := m.Login(loginID, deviceType)
@@ -1042,6 +1751,9 @@ func (m *Manager) LoginWithRefreshToken(loginID, device string) (*security.Refre
 
 // RefreshAccessToken Refreshes access token | 刷新访问令牌
 func (m *Manager) RefreshAccessToken(refreshToken string) (*security.RefreshTokenInfo, error) {
+	if err := m.checkJwtMode(config.JwtModeStateless); err != nil {
+		return nil, err
+	}
 	return m.refreshManager.RefreshAccessToken(refreshToken)
 }
 
@@ -1050,6 +1762,85 @@ func (m *Manager) RevokeRefreshToken(refreshToken string) error {
 	return m.refreshManager.RevokeRefreshToken(refreshToken)
 }
 
+// CreateTempToken Creates a one-shot temp token bound to service | 创建绑定业务的临时Token
+// timeout: seconds, 0 uses default, -1 never expire | 有效期（秒），0使用默认值，-1永不过期
+func (m *Manager) CreateTempToken(service string, value any, timeout int64) (string, error) {
+	return m.tempTokens.Create(service, value, time.Duration(timeout)*time.Second)
+}
+
+// ParseTempToken Parses temp token without consuming it | 解析临时Token（不消费）
+func (m *Manager) ParseTempToken(service, tempToken string) (*security.TempTokenInfo, error) {
+	return m.tempTokens.Parse(service, tempToken)
+}
+
+// ConsumeTempToken Parses and deletes temp token (one-time use) | 解析并删除临时Token（一次性使用）
+func (m *Manager) ConsumeTempToken(service, tempToken string) (*security.TempTokenInfo, error) {
+	return m.tempTokens.Consume(service, tempToken)
+}
+
+// GetTempTokenTimeout Gets remaining temp token TTL in seconds | 获取临时Token剩余有效时间（秒）
+func (m *Manager) GetTempTokenTimeout(tempToken string) int64 {
+	return m.tempTokens.GetTimeout(tempToken)
+}
+
+// DeleteTempToken Deletes temp token | 删除临时Token
+func (m *Manager) DeleteTempToken(tempToken string) error {
+	return m.tempTokens.Delete(tempToken)
+}
+
+// GetSignManager Gets API sign manager | 获取API签名管理器
+func (m *Manager) GetSignManager() *security.SignManager {
+	return m.signManager
+}
+
+// SetSignManager Replaces API sign manager (e.g. custom algorithm) | 替换API签名管理器（如自定义算法）
+func (m *Manager) SetSignManager(signManager *security.SignManager) {
+	m.signManager = signManager
+}
+
+// GetJwtKeySet Gets JWT signing and verification keys | 获取JWT签名与验签密钥集
+func (m *Manager) GetJwtKeySet() *token.JwtKeySet {
+	return m.generator.GetKeySet()
+}
+
+// JWKS Gets JWKS document of asymmetric JWT keys | 获取非对称JWT密钥的JWKS文档
+func (m *Manager) JWKS() *token.JWKS {
+	return m.generator.GetKeySet().JWKS()
+}
+
+// SetTokenGenerator Replaces token generation of TokenStyle, nil restores it | 替换按TokenStyle生成Token的逻辑，nil恢复
+// Custom tokens are opaque, so jwt and encrypted styles should not be combined with it | 自定义Token为不透明Token，不应与jwt及加密风格同时使用
+func (m *Manager) SetTokenGenerator(gen token.TokenGenerator) {
+	m.generator.SetTokenGenerator(gen)
+}
+
+// GetEncryptKeySet Gets encrypted token keys | 获取加密Token密钥集
+func (m *Manager) GetEncryptKeySet() *token.EncryptKeySet {
+	return m.generator.GetEncryptKeySet()
+}
+
+// DecryptToken Reads TokenInfo from an encrypted token without storage lookup | 不查询存储，直接从加密Token读取Token信息
+// Only proves the token was issued and not expired, logout/kickout need IsLogin | 仅证明Token由本系统签发且未过期，注销/踢下线需使用IsLogin判断
+func (m *Manager) DecryptToken(tokenValue string) (*TokenInfo, error) {
+	if m.config.TokenStyle != config.TokenStyleEncrypted {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedTokenStyle, m.config.TokenStyle)
+	}
+
+	claims, err := m.generator.DecryptToken(tokenValue)
+	if err != nil {
+		if errors.Is(err, token.ErrTokenExpired) {
+			return nil, ErrTokenExpired
+		}
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTokenData, err)
+	}
+	return newTokenInfoFromClaims(claims)
+}
+
+// GetRoleManager Gets RBAC role manager | 获取RBAC角色管理器
+func (m *Manager) GetRoleManager() *rbac.RoleManager {
+	return m.roleManager
+}
+
 // GetOAuth2Server Gets OAuth2 server instance | 获取OAuth2服务器实例
 func (m *Manager) GetOAuth2Server() *oauth2.OAuth2Server {
 	return m.oauth2Server
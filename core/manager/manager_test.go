@@ -0,0 +1,301 @@
+package manager
+
+import (
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/click33/sa-token-go/core/adapter"
+	"github.com/click33/sa-token-go/core/adapter/adaptertest"
+	"github.com/click33/sa-token-go/core/config"
+)
+
+// newTestManager Creates a manager over a legacy map storage | 基于旧版map存储创建管理器
+func newTestManager(t *testing.T, configure ...func(cfg *config.Config)) (*Manager, *adaptertest.MapStorage) {
+	t.Helper()
+
+	cfg := config.DefaultConfig()
+	cfg.IsPrintBanner = false
+	cfg.IsLog = false
+	for _, fn := range configure {
+		fn(cfg)
+	}
+	storage := adaptertest.NewMapStorage()
+	m := NewManager(storage, cfg)
+	t.Cleanup(m.CloseManager)
+	return m, storage
+}
+
+func TestSafeWindows(t *testing.T) {
+	m, storage := newTestManager(t)
+	token, err := m.Login("1001")
+	if err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+
+	if err := m.CheckSafe(token); !errors.Is(err, ErrNotSafe) {
+		t.Fatalf("CheckSafe() before OpenSafe = %v, want ErrNotSafe", err)
+	}
+	if err := m.OpenSafe(token, 60); err != nil {
+		t.Fatalf("OpenSafe() error = %v", err)
+	}
+	if err := m.OpenSafe(token, 600, "pay"); err != nil {
+		t.Fatalf("OpenSafe(pay) error = %v", err)
+	}
+	if !m.IsSafe(token) || !m.IsSafe(token, "pay") || m.IsSafe(token, "other") {
+		t.Fatal("IsSafe() does not match opened services")
+	}
+	if remaining := m.GetSafeTime(token, "pay"); remaining <= 60 || remaining > 600 {
+		t.Fatalf("GetSafeTime(pay) = %d, want (60, 600]", remaining)
+	}
+	if ttl, _ := storage.TTL(m.getSafeKey(token)); ttl.Seconds() <= 60 {
+		t.Fatalf("safe hash TTL = %v, want the latest window", ttl)
+	}
+
+	if err := m.CloseSafe(token); err != nil {
+		t.Fatalf("CloseSafe() error = %v", err)
+	}
+	if m.IsSafe(token) || !m.IsSafe(token, "pay") {
+		t.Fatal("CloseSafe() must only close its own service")
+	}
+
+	if err := m.Logout("1001"); err != nil {
+		t.Fatalf("Logout() error = %v", err)
+	}
+	if storage.Exists(m.getSafeKey(token)) {
+		t.Fatal("safe hash survived logout")
+	}
+}
+
+func TestSafeLapsedWindow(t *testing.T) {
+	m, _ := newTestManager(t)
+	token, err := m.Login("1001")
+	if err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+
+	key := m.getSafeKey(token)
+	if err := adapter.HSet(m.storage, key, DefaultSafeService, "1"); err != nil {
+		t.Fatalf("HSet() error = %v", err)
+	}
+	if m.IsSafe(token) {
+		t.Fatal("IsSafe() accepted a lapsed deadline")
+	}
+	if err := m.OpenSafe(token, 60, "pay"); err != nil {
+		t.Fatalf("OpenSafe() error = %v", err)
+	}
+	if m.GetSafeTime(token) != -2 {
+		t.Fatal("lapsed window was not pruned")
+	}
+}
+
+// setActiveTime Rewrites the stored active time of token | 改写Token存储的活跃时间
+func setActiveTime(t *testing.T, m *Manager, tokenValue string, activeTime int64) {
+	t.Helper()
+
+	info, err := m.getTokenInfo(tokenValue)
+	if err != nil {
+		t.Fatalf("getTokenInfo() error = %v", err)
+	}
+	info.ActiveTime = activeTime
+	data, _ := json.Marshal(info)
+	if err := m.storage.SetKeepTTL(m.getTokenKey(tokenValue), string(data)); err != nil {
+		t.Fatalf("SetKeepTTL() error = %v", err)
+	}
+}
+
+func TestOverflowKickLeastActive(t *testing.T) {
+	m, _ := newTestManager(t, func(cfg *config.Config) {
+		cfg.IsShare = false
+		cfg.MaxLoginCount = 2
+		cfg.OverflowPolicy = config.OverflowKickLeastActive
+	})
+
+	first, err := m.Login("1001", "web")
+	if err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+	second, err := m.Login("1001", "app")
+	if err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+
+	now := time.Now().Unix()
+	setActiveTime(t, m, first, now-100)
+	setActiveTime(t, m, second, now-50)
+
+	// Activity on the older login moves it ahead of the other one | 较早登录的活跃使其排到另一登录之后
+	if !m.IsLogin(first) {
+		t.Fatal("IsLogin(first) = false")
+	}
+	if info, _ := m.getTokenInfo(first); info.ActiveTime < now {
+		t.Fatalf("ActiveTime = %d, want refreshed by IsLogin", info.ActiveTime)
+	}
+
+	if _, err := m.Login("1001", "pad"); err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+	if !m.IsLogin(first) {
+		t.Fatal("recently active token was replaced")
+	}
+	if err := m.CheckLogin(second); err == nil {
+		t.Fatal("least active token survived the overflow")
+	}
+}
+
+func TestAccountIndexConcurrentLogin(t *testing.T) {
+	m, storage := newTestManager(t, func(cfg *config.Config) {
+		cfg.IsShare = false
+		cfg.MaxLoginCount = 32
+	})
+
+	const logins = 16
+	var wg sync.WaitGroup
+	tokens := make([]string, logins)
+	for i := 0; i < logins; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			token, err := m.Login("1001")
+			if err != nil {
+				t.Errorf("Login() error = %v", err)
+			}
+			tokens[i] = token
+		}(i)
+	}
+	wg.Wait()
+
+	listed, err := m.GetTokenValueListByLoginID("1001")
+	if err != nil || len(listed) != logins {
+		t.Fatalf("GetTokenValueListByLoginID() = %d tokens, %v, want %d", len(listed), err, logins)
+	}
+	if ttl, _ := storage.TTL(m.getAccountKey("1001")); ttl <= 0 {
+		t.Fatalf("account index TTL = %v, want the token timeout", ttl)
+	}
+
+	for _, token := range tokens {
+		if err := m.LogoutByToken(token); err != nil {
+			t.Fatalf("LogoutByToken() error = %v", err)
+		}
+	}
+	if storage.Exists(m.getAccountKey("1001")) {
+		t.Fatal("account index kept after its last token logged out")
+	}
+}
+
+func TestAccountIndexPermanentToken(t *testing.T) {
+	m, storage := newTestManager(t, func(cfg *config.Config) { cfg.IsShare = false })
+
+	if _, err := m.Login("1001"); err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+	m.config.Timeout = -1
+	if _, err := m.Login("1001"); err != nil {
+		t.Fatalf("Login(never expire) error = %v", err)
+	}
+	if ttl, _ := storage.TTL(m.getAccountKey("1001")); ttl != -1*time.Second {
+		t.Fatalf("account index TTL = %v, want never expire", ttl)
+	}
+}
+
+func TestAccountIndexLegacyMigration(t *testing.T) {
+	m, storage := newTestManager(t, func(cfg *config.Config) { cfg.IsShare = false })
+
+	// Rewrite logins into the pre-hash layout | 将登录改写为旧版键布局
+	legacy := func(device string) string {
+		token, err := m.Login("1001", device)
+		if err != nil {
+			t.Fatalf("Login(%s) error = %v", device, err)
+		}
+		if err := m.removeTokenSign("1001", token); err != nil {
+			t.Fatalf("removeTokenSign() error = %v", err)
+		}
+		if err := storage.Set(m.getLegacyAccountKey("1001", device), token, time.Hour); err != nil {
+			t.Fatalf("Set(legacy) error = %v", err)
+		}
+		return token
+	}
+	pcToken := legacy("pc")
+	appToken := legacy("app")
+	webToken := legacy("web")
+
+	// Lazy migration on device lookup | 查询设备时惰性迁移
+	if token, err := m.GetTokenValue("1001", "pc"); err != nil || token != pcToken {
+		t.Fatalf("GetTokenValue(pc) = %q, %v, want legacy token", token, err)
+	}
+	if storage.Exists(m.getLegacyAccountKey("1001", "pc")) {
+		t.Fatal("legacy key kept after migration")
+	}
+	if err := m.Logout("1001", "app"); err != nil {
+		t.Fatalf("Logout(app) error = %v", err)
+	}
+	if m.IsLogin(appToken) {
+		t.Fatal("Logout() missed a legacy token")
+	}
+
+	// Bulk migration skips keys that are not legacy tokens | 批量迁移跳过非旧版Token键
+	if err := storage.Set(m.getLegacyAccountKey("1001", "stale"), "missing-token", time.Hour); err != nil {
+		t.Fatalf("Set(stale) error = %v", err)
+	}
+	migrated, err := m.MigrateAccountIndex()
+	if err != nil || migrated != 1 {
+		t.Fatalf("MigrateAccountIndex() = %d, %v, want 1", migrated, err)
+	}
+	if token, err := m.GetTokenValue("1001", "web"); err != nil || token != webToken {
+		t.Fatalf("GetTokenValue(web) = %q, %v, want migrated token", token, err)
+	}
+	if !storage.Exists(m.getLegacyAccountKey("1001", "stale")) {
+		t.Fatal("MigrateAccountIndex() removed an unrelated key")
+	}
+}
+
+func TestRolePermissions(t *testing.T) {
+	m, _ := newTestManager(t)
+	rm := m.GetRoleManager()
+	if _, err := rm.CreateRole("viewer", ""); err != nil {
+		t.Fatalf("CreateRole() error = %v", err)
+	}
+	if _, err := rm.CreateRole("editor", ""); err != nil {
+		t.Fatalf("CreateRole() error = %v", err)
+	}
+	if err := rm.GrantPermission("viewer", "article:*"); err != nil {
+		t.Fatalf("GrantPermission() error = %v", err)
+	}
+	if err := rm.InheritRole("editor", "viewer"); err != nil {
+		t.Fatalf("InheritRole() error = %v", err)
+	}
+	if err := m.SetRoles("1001", []string{"editor"}); err != nil {
+		t.Fatalf("SetRoles() error = %v", err)
+	}
+	if err := m.SetPermissions("1001", []string{"user:add"}); err != nil {
+		t.Fatalf("SetPermissions() error = %v", err)
+	}
+
+	if !m.HasPermissionsAnd("1001", []string{"article:read", "user:add"}) {
+		t.Fatal("HasPermissionsAnd() = false, want direct and inherited grants")
+	}
+	if m.HasPermissionsOr("1002", []string{"article:read"}) {
+		t.Fatal("HasPermissionsOr() = true for an account without roles")
+	}
+
+	if err := rm.RevokePermission("viewer", "article:*"); err != nil {
+		t.Fatalf("RevokePermission() error = %v", err)
+	}
+	if m.HasPermission("1001", "article:read") {
+		t.Fatal("HasPermission() = true after the inherited grant was revoked")
+	}
+}
+
+func TestUnsupportedTokenStyle(t *testing.T) {
+	m, _ := newTestManager(t)
+	token, _ := m.Login("1001")
+
+	if _, err := m.DecryptToken(token); !errors.Is(err, ErrUnsupportedTokenStyle) || errors.Is(err, ErrUnsupportedInJwtMode) {
+		t.Fatalf("DecryptToken() error = %v, want ErrUnsupportedTokenStyle", err)
+	}
+	if _, err := m.GetTokenClaims(token); !errors.Is(err, ErrUnsupportedTokenStyle) || errors.Is(err, ErrUnsupportedInJwtMode) {
+		t.Fatalf("GetTokenClaims() error = %v, want ErrUnsupportedTokenStyle", err)
+	}
+}
@@ -0,0 +1,132 @@
+package manager
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Permission Provider Implementation
+// 权限数据源实现
+//
+// Flow | 流程:
+// 1. SetPermissionProvider() - Plug in the app's grant lookup | 接入应用的权限查询
+// 2. GetPermissions()/GetRoles() - Read cache, fall back to provider on miss | 先读缓存，未命中时查询数据源
+// 3. ClearPermissionCache() - Invalidate after grants change | 权限变更后使缓存失效
+//
+// Usage | 用法:
+//   manager.SetPermissionProvider(manager.PermissionProviderFunc{
+//       Permissions: func(loginID, loginType string) ([]string, error) { return repo.Perms(loginID) },
+//       Roles:       func(loginID, loginType string) ([]string, error) { return repo.Roles(loginID) },
+//   })
+//   manager.SetPermissionCacheTTL(5 * time.Minute)
+//   manager.ClearPermissionCache("1001") // after granting a role | 授予角色之后
+
+// Cache key prefixes | 缓存键前缀
+const (
+	PermissionCacheKeyPrefix = "permission-cache:"
+	RoleCacheKeyPrefix       = "role-cache:"
+)
+
+// PermissionProvider Loads permissions and roles on demand | 按需加载权限与角色
+type PermissionProvider interface {
+	// GetPermissionList Gets permissions of account in login type | 获取账号在指定账号体系下的权限列表
+	GetPermissionList(loginID string, loginType string) ([]string, error)
+
+	// GetRoleList Gets roles of account in login type | 获取账号在指定账号体系下的角色列表
+	GetRoleList(loginID string, loginType string) ([]string, error)
+}
+
+// PermissionProviderFunc Adapts plain functions to PermissionProvider, nil func returns empty list | 将普通函数适配为PermissionProvider，nil函数返回空列表
+type PermissionProviderFunc struct {
+	Permissions func(loginID string, loginType string) ([]string, error)
+	Roles       func(loginID string, loginType string) ([]string, error)
+}
+
+// GetPermissionList Implements PermissionProvider | 实现PermissionProvider
+func (f PermissionProviderFunc) GetPermissionList(loginID string, loginType string) ([]string, error) {
+	if f.Permissions == nil {
+		return []string{}, nil
+	}
+	return f.Permissions(loginID, loginType)
+}
+
+// GetRoleList Implements PermissionProvider | 实现PermissionProvider
+func (f PermissionProviderFunc) GetRoleList(loginID string, loginType string) ([]string, error) {
+	if f.Roles == nil {
+		return []string{}, nil
+	}
+	return f.Roles(loginID, loginType)
+}
+
+// SetPermissionProvider Sets permission provider, nil falls back to session data | 设置权限数据源，nil时回退到Session数据
+func (m *Manager) SetPermissionProvider(provider PermissionProvider) {
+	m.permissionProvider = provider
+}
+
+// GetPermissionProvider Gets permission provider | 获取权限数据源
+func (m *Manager) GetPermissionProvider() PermissionProvider {
+	return m.permissionProvider
+}
+
+// SetPermissionCacheTTL Sets provider result cache TTL, 0 disables caching | 设置数据源结果缓存时间，0表示不缓存
+func (m *Manager) SetPermissionCacheTTL(ttl time.Duration) {
+	m.permissionCacheTTL = ttl
+}
+
+// ClearPermissionCache Invalidates cached permissions and roles of account | 使账号的权限与角色缓存失效
+func (m *Manager) ClearPermissionCache(loginID string) error {
+	return m.storage.Delete(
+		m.prefix+PermissionCacheKeyPrefix+loginID,
+		m.prefix+RoleCacheKeyPrefix+loginID,
+	)
+}
+
+// ClearAllPermissionCache Invalidates cached permissions and roles of every account | 使所有账号的权限与角色缓存失效
+func (m *Manager) ClearAllPermissionCache() error {
+	for _, pattern := range []string{m.prefix + PermissionCacheKeyPrefix + "*", m.prefix + RoleCacheKeyPrefix + "*"} {
+		keys, err := m.storage.Keys(pattern)
+		if err != nil {
+			return err
+		}
+		if len(keys) > 0 {
+			if err := m.storage.Delete(keys...); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// loadFromProvider Reads cached list or loads it through provider | 读取缓存列表，未命中时通过数据源加载
+func (m *Manager) loadFromProvider(cacheKey string, load func() ([]string, error)) ([]string, error) {
+	if m.permissionCacheTTL > 0 {
+		if data, err := m.storage.Get(cacheKey); err == nil && data != nil {
+			var str string
+			switch v := data.(type) {
+			case []byte:
+				str = string(v)
+			case string:
+				str = v
+			}
+			var list []string
+			if err := json.Unmarshal([]byte(str), &list); err == nil {
+				return list, nil
+			}
+		}
+	}
+
+	list, err := load()
+	if err != nil {
+		return nil, err
+	}
+	if list == nil {
+		list = []string{}
+	}
+
+	if m.permissionCacheTTL > 0 {
+		if data, err := json.Marshal(list); err == nil {
+			_ = m.storage.Set(cacheKey, string(data), m.permissionCacheTTL)
+		}
+	}
+	return list, nil
+}
@@ -0,0 +1,134 @@
+package oauth2
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Client Secret Hashing and Rotation
+// 客户端密钥哈希与轮换
+//
+// RegisterClient stores SecretHash instead of ClientSecret | RegisterClient存储SecretHash而不是ClientSecret
+// RotateClientSecret issues a new secret, the old one stays valid for a grace period | RotateClientSecret签发新密钥，旧密钥在宽限期内仍然有效
+//
+// The default hasher is salted SHA-256, suited to the random secrets generated here.
+// Use SetSecretHasher for bcrypt or argon2 with human-chosen secrets | 默认哈希为加盐SHA-256，适用于此处生成的随机密钥；人为设置的密钥请通过SetSecretHasher使用bcrypt或argon2
+//
+// Usage | 用法:
+//   secret, _ := server.RotateClientSecret("web", 24*time.Hour)
+
+// ClientSecretLength Generated client secret byte length | 生成的客户端密钥字节长度
+const ClientSecretLength = 32
+
+// SecretHasher Hashes and verifies client secrets | 哈希并校验客户端密钥
+// Changing hasher makes stored hashes of the previous one unverifiable | 更换哈希器后，旧哈希器生成的哈希将无法校验
+type SecretHasher interface {
+	// Hash Returns encoded hash of secret | 返回密钥的编码哈希
+	Hash(secret string) (string, error)
+
+	// Verify Checks secret against hash | 校验密钥与哈希是否匹配
+	Verify(hash, secret string) bool
+}
+
+// SHA256SecretHasher Salted SHA-256 hasher, encoded as sha256$salt$digest | 加盐SHA-256哈希器，编码为sha256$salt$digest
+type SHA256SecretHasher struct{}
+
+// Hash Hashes secret with a random salt | 使用随机盐哈希密钥
+func (SHA256SecretHasher) Hash(secret string) (string, error) {
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("failed to generate salt: %w", err)
+	}
+	return "sha256$" + hex.EncodeToString(salt) + "$" + sha256Digest(salt, secret), nil
+}
+
+// Verify Checks secret in constant time | 以恒定时间校验密钥
+func (SHA256SecretHasher) Verify(hash, secret string) bool {
+	parts := strings.Split(hash, "$")
+	if len(parts) != 3 || parts[0] != "sha256" {
+		return false
+	}
+	salt, err := hex.DecodeString(parts[1])
+	if err != nil {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(sha256Digest(salt, secret)), []byte(parts[2])) == 1
+}
+
+// sha256Digest Hex digest of salt followed by secret | 盐与密钥拼接后的十六进制摘要
+func sha256Digest(salt []byte, secret string) string {
+	h := sha256.New()
+	h.Write(salt)
+	h.Write([]byte(secret))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// SetSecretHasher Sets client secret hasher | 设置客户端密钥哈希器
+func (s *OAuth2Server) SetSecretHasher(hasher SecretHasher) {
+	s.secretHasher = hasher
+}
+
+// RotateClientSecret Issues a new secret, the current one stays valid for gracePeriod | 签发新密钥，当前密钥在gracePeriod内仍然有效
+// The returned secret is not stored in plain text and cannot be read again | 返回的密钥不以明文存储，之后无法再次读取
+func (s *OAuth2Server) RotateClientSecret(clientID string, gracePeriod time.Duration) (string, error) {
+	client, err := s.GetClient(clientID)
+	if err != nil {
+		return "", err
+	}
+	if client.Public {
+		return "", ErrPublicClientNotAllowed
+	}
+
+	secret, err := generateClientSecret()
+	if err != nil {
+		return "", err
+	}
+	hash, err := s.secretHasher.Hash(secret)
+	if err != nil {
+		return "", err
+	}
+
+	client.PreviousSecretHash, client.PreviousSecretExpiresAt = "", 0
+	if gracePeriod > 0 && client.SecretHash != "" {
+		client.PreviousSecretHash = client.SecretHash
+		client.PreviousSecretExpiresAt = time.Now().Add(gracePeriod).Unix()
+	}
+	client.SecretHash = hash
+	client.ClientSecret = ""
+
+	if err := s.clients.SaveClient(client); err != nil {
+		return "", err
+	}
+	return secret, nil
+}
+
+// verifyClientSecret Checks secret against current, in-grace previous or legacy plain secret | 校验当前密钥、宽限期内的旧密钥或旧版明文密钥
+func (s *OAuth2Server) verifyClientSecret(client *Client, secret string) bool {
+	if secret == "" {
+		return false
+	}
+	if client.SecretHash != "" && s.secretHasher.Verify(client.SecretHash, secret) {
+		return true
+	}
+	if client.PreviousSecretHash != "" && time.Now().Unix() < client.PreviousSecretExpiresAt &&
+		s.secretHasher.Verify(client.PreviousSecretHash, secret) {
+		return true
+	}
+	// Custom stores may still hold plain secrets | 自定义存储可能仍保存明文密钥
+	return client.SecretHash == "" && client.ClientSecret != "" &&
+		subtle.ConstantTimeCompare([]byte(client.ClientSecret), []byte(secret)) == 1
+}
+
+// generateClientSecret Generates random client secret | 生成随机客户端密钥
+func generateClientSecret() (string, error) {
+	secretBytes := make([]byte, ClientSecretLength)
+	if _, err := rand.Read(secretBytes); err != nil {
+		return "", fmt.Errorf("failed to generate client secret: %w", err)
+	}
+	return hex.EncodeToString(secretBytes), nil
+}
@@ -0,0 +1,264 @@
+package oauth2
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/click33/sa-token-go/core/adapter"
+	"github.com/click33/sa-token-go/core/utils"
+)
+
+// OAuth2 Client Registry
+// OAuth2客户端注册表
+//
+// Implementations | 实现:
+// - MemoryClientStore  - Per-process map, the default | 进程内map（默认）
+// - StorageClientStore - adapter.Storage (e.g. redis), shared by every instance | 基于adapter.Storage（如redis），所有实例共享
+// - SQLClientStore     - database/sql table of client_id and JSON data | 由client_id与JSON数据组成的database/sql表
+//
+// Stores only see hashed secrets, OAuth2Server.RegisterClient hashes ClientSecret before saving | 存储只接触哈希后的密钥，OAuth2Server.RegisterClient在保存前对ClientSecret做哈希
+//
+// Usage | 用法:
+//   server.SetClientStore(oauth2.NewStorageClientStore(redisStorage, "satoken:"))
+//   server.RegisterClient(&oauth2.Client{ClientID: "web", ClientSecret: "secret", ...})
+
+// ClientKeySuffix Client key suffix after prefix | 客户端键后缀
+const ClientKeySuffix = "oauth2:client:"
+
+// ClientStore Persists OAuth2 clients | 持久化OAuth2客户端
+type ClientStore interface {
+	// GetClient Gets client, ErrClientNotFound when missing | 获取客户端，不存在时返回ErrClientNotFound
+	GetClient(clientID string) (*Client, error)
+
+	// SaveClient Creates or replaces client | 创建或替换客户端
+	SaveClient(client *Client) error
+
+	// DeleteClient Deletes client, missing clients are not an error | 删除客户端，不存在时不报错
+	DeleteClient(clientID string) error
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler for Redis storage | 实现encoding.BinaryMarshaler接口用于Redis存储
+func (c *Client) MarshalBinary() ([]byte, error) {
+	return json.Marshal(c)
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler for Redis storage | 实现encoding.BinaryUnmarshaler接口用于Redis存储
+func (c *Client) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, c)
+}
+
+// clone Copies client so callers cannot mutate stored state | 复制客户端，避免调用方修改已存储的状态
+func (c *Client) clone() *Client {
+	copied := *c
+	copied.RedirectURIs = append([]string(nil), c.RedirectURIs...)
+	copied.GrantTypes = append([]GrantType(nil), c.GrantTypes...)
+	copied.Scopes = append([]string(nil), c.Scopes...)
+	copied.Contacts = append([]string(nil), c.Contacts...)
+	return &copied
+}
+
+// ============ Memory Client Store | 内存客户端存储 ============
+
+// MemoryClientStore Per-process client store | 进程内客户端存储
+type MemoryClientStore struct {
+	clients map[string]*Client
+	mu      sync.RWMutex
+}
+
+// NewMemoryClientStore Creates per-process client store | 创建进程内客户端存储
+func NewMemoryClientStore() *MemoryClientStore {
+	return &MemoryClientStore{
+		clients: make(map[string]*Client),
+	}
+}
+
+// GetClient Gets a copy of the client | 获取客户端副本
+func (s *MemoryClientStore) GetClient(clientID string) (*Client, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	client, exists := s.clients[clientID]
+	if !exists {
+		return nil, ErrClientNotFound
+	}
+	return client.clone(), nil
+}
+
+// SaveClient Stores a copy of the client | 存储客户端副本
+func (s *MemoryClientStore) SaveClient(client *Client) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.clients[client.ClientID] = client.clone()
+	return nil
+}
+
+// DeleteClient Deletes client | 删除客户端
+func (s *MemoryClientStore) DeleteClient(clientID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.clients, clientID)
+	return nil
+}
+
+// ============ Storage Client Store | 基于Storage的客户端存储 ============
+
+// StorageClientStore Client store on adapter.Storage, clients never expire | 基于adapter.Storage的客户端存储，客户端永不过期
+type StorageClientStore struct {
+	storage   adapter.Storage
+	keyPrefix string
+}
+
+// NewStorageClientStore Creates client store on storage | 创建基于存储的客户端存储
+// prefix: key prefix (e.g., "satoken:") | 键前缀（如："satoken:"）
+func NewStorageClientStore(storage adapter.Storage, prefix string) *StorageClientStore {
+	return &StorageClientStore{
+		storage:   storage,
+		keyPrefix: prefix,
+	}
+}
+
+// GetClient Gets client | 获取客户端
+func (s *StorageClientStore) GetClient(clientID string) (*Client, error) {
+	key := s.getClientKey(clientID)
+	if !s.storage.Exists(key) {
+		return nil, ErrClientNotFound
+	}
+	data, err := s.storage.Get(key)
+	if err != nil {
+		return nil, err
+	}
+
+	dataBytes, err := utils.ToBytes(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid client data: %w", err)
+	}
+	client := &Client{}
+	if err := client.UnmarshalBinary(dataBytes); err != nil {
+		return nil, fmt.Errorf("invalid client data: %w", err)
+	}
+	return client, nil
+}
+
+// SaveClient Saves client | 保存客户端
+func (s *StorageClientStore) SaveClient(client *Client) error {
+	data, err := client.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	return s.storage.Set(s.getClientKey(client.ClientID), string(data), 0)
+}
+
+// DeleteClient Deletes client | 删除客户端
+func (s *StorageClientStore) DeleteClient(clientID string) error {
+	return s.storage.Delete(s.getClientKey(clientID))
+}
+
+// getClientKey Gets storage key for client | 获取客户端的存储键
+func (s *StorageClientStore) getClientKey(clientID string) string {
+	return s.keyPrefix + ClientKeySuffix + clientID
+}
+
+// ============ SQL Client Store | SQL客户端存储 ============
+
+// SQLPlaceholder Bind parameter style of the SQL driver | SQL驱动的参数占位符风格
+type SQLPlaceholder int
+
+const (
+	PlaceholderQuestion SQLPlaceholder = iota // ? (MySQL, SQLite) | ?（MySQL、SQLite）
+	PlaceholderDollar                         // $1 (PostgreSQL) | $1（PostgreSQL）
+)
+
+// DefaultClientTable Default SQL table name | 默认SQL表名
+const DefaultClientTable = "oauth2_clients"
+
+// SQLClientStore Client store on a database/sql table with client_id and data columns | 基于database/sql表（client_id与data两列）的客户端存储
+// The table name is trusted configuration and is not escaped | 表名为可信配置，不做转义
+type SQLClientStore struct {
+	db          *sql.DB
+	table       string
+	placeholder SQLPlaceholder
+}
+
+// NewSQLClientStore Creates SQL client store, empty table means DefaultClientTable | 创建SQL客户端存储，表名为空时使用DefaultClientTable
+func NewSQLClientStore(db *sql.DB, table string) *SQLClientStore {
+	if table == "" {
+		table = DefaultClientTable
+	}
+	return &SQLClientStore{
+		db:    db,
+		table: table,
+	}
+}
+
+// SetPlaceholder Sets bind parameter style | 设置参数占位符风格
+func (s *SQLClientStore) SetPlaceholder(placeholder SQLPlaceholder) *SQLClientStore {
+	s.placeholder = placeholder
+	return s
+}
+
+// CreateTableSQL Returns portable DDL of the client table | 返回客户端表的通用建表语句
+func (s *SQLClientStore) CreateTableSQL() string {
+	return "CREATE TABLE IF NOT EXISTS " + s.table + " (client_id VARCHAR(255) NOT NULL PRIMARY KEY, data TEXT NOT NULL)"
+}
+
+// GetClient Gets client | 获取客户端
+func (s *SQLClientStore) GetClient(clientID string) (*Client, error) {
+	var data string
+	query := "SELECT data FROM " + s.table + " WHERE client_id = " + s.bind(1)
+	if err := s.db.QueryRow(query, clientID).Scan(&data); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrClientNotFound
+		}
+		return nil, err
+	}
+
+	client := &Client{}
+	if err := client.UnmarshalBinary([]byte(data)); err != nil {
+		return nil, fmt.Errorf("invalid client data: %w", err)
+	}
+	return client, nil
+}
+
+// SaveClient Replaces client row in a transaction | 在事务中替换客户端记录
+func (s *SQLClientStore) SaveClient(client *Client) error {
+	data, err := client.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	// Delete then insert works on every dialect, unlike upsert syntax | 先删后插适用于所有方言，upsert语法各不相同
+	tx, err := s.db.BeginTx(context.Background(), nil)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec("DELETE FROM "+s.table+" WHERE client_id = "+s.bind(1), client.ClientID); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	if _, err := tx.Exec("INSERT INTO "+s.table+" (client_id, data) VALUES ("+s.bind(1)+", "+s.bind(2)+")", client.ClientID, string(data)); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// DeleteClient Deletes client row | 删除客户端记录
+func (s *SQLClientStore) DeleteClient(clientID string) error {
+	_, err := s.db.Exec("DELETE FROM "+s.table+" WHERE client_id = "+s.bind(1), clientID)
+	return err
+}
+
+// bind Returns the n-th bind parameter | 返回第n个绑定参数
+func (s *SQLClientStore) bind(n int) string {
+	if s.placeholder == PlaceholderDollar {
+		return "$" + strconv.Itoa(n)
+	}
+	return "?"
+}
@@ -0,0 +1,350 @@
+package oauth2_test
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/click33/sa-token-go/core/adapter"
+	"github.com/click33/sa-token-go/core/oauth2"
+)
+
+func TestClientStores(t *testing.T) {
+	stores := map[string]func(t *testing.T) oauth2.ClientStore{
+		"Memory": func(t *testing.T) oauth2.ClientStore { return oauth2.NewMemoryClientStore() },
+		"ValueStorage": func(t *testing.T) oauth2.ClientStore {
+			return oauth2.NewStorageClientStore(newFakeStorage(keepValue), "test:")
+		},
+		"StringStorage": func(t *testing.T) oauth2.ClientStore {
+			return oauth2.NewStorageClientStore(newFakeStorage(redisString), "test:")
+		},
+		"SQLQuestion": func(t *testing.T) oauth2.ClientStore { return newSQLStore(t, oauth2.PlaceholderQuestion) },
+		"SQLDollar":   func(t *testing.T) oauth2.ClientStore { return newSQLStore(t, oauth2.PlaceholderDollar) },
+	}
+	for name, newStore := range stores {
+		t.Run(name, func(t *testing.T) { testClientStore(t, newStore(t)) })
+	}
+}
+
+func testClientStore(t *testing.T, store oauth2.ClientStore) {
+	server := oauth2.NewOAuth2Server(newFakeStorage(keepValue), "test:")
+	server.SetClientStore(store)
+
+	if err := server.RegisterClient(&oauth2.Client{
+		ClientID:     "web",
+		ClientSecret: "web-secret",
+		GrantTypes:   []oauth2.GrantType{oauth2.GrantTypeClientCredentials},
+		Scopes:       []string{"read"},
+		Name:         "Web App",
+		Contacts:     []string{"ops@example.com"},
+	}); err != nil {
+		t.Fatalf("RegisterClient() error = %v", err)
+	}
+
+	client, err := store.GetClient("web")
+	if err != nil {
+		t.Fatalf("GetClient() error = %v", err)
+	}
+	if client.ClientSecret != "" || client.SecretHash == "" || strings.Contains(client.SecretHash, "web-secret") {
+		t.Errorf("stored secret not hashed: %+v", client)
+	}
+	if client.Name != "Web App" || len(client.Contacts) != 1 || client.CreateTime == 0 {
+		t.Errorf("stored metadata lost: %+v", client)
+	}
+
+	client.Scopes[0] = "mutated"
+	if again, _ := store.GetClient("web"); again.Scopes[0] != "read" {
+		t.Error("mutating returned client changed stored client")
+	}
+
+	// A second server sharing the store sees the client | 共享存储的另一个服务器可见该客户端
+	other := oauth2.NewOAuth2Server(newFakeStorage(keepValue), "test:")
+	other.SetClientStore(store)
+	if _, err := other.ClientCredentialsToken("web", "web-secret", nil); err != nil {
+		t.Errorf("ClientCredentialsToken() error = %v", err)
+	}
+	if _, err := other.ClientCredentialsToken("web", "wrong", nil); !errors.Is(err, oauth2.ErrInvalidClientCredentials) {
+		t.Errorf("ClientCredentialsToken(wrong secret) error = %v, want %v", err, oauth2.ErrInvalidClientCredentials)
+	}
+
+	server.UnregisterClient("web")
+	if _, err := other.GetClient("web"); !errors.Is(err, oauth2.ErrClientNotFound) {
+		t.Errorf("GetClient() after unregister error = %v, want %v", err, oauth2.ErrClientNotFound)
+	}
+}
+
+func TestRotateClientSecret(t *testing.T) {
+	server := oauth2.NewOAuth2Server(newFakeStorage(keepValue), "test:")
+	server.RegisterClient(&oauth2.Client{
+		ClientID:     "web",
+		ClientSecret: "old-secret",
+		GrantTypes:   []oauth2.GrantType{oauth2.GrantTypeClientCredentials},
+	})
+	server.RegisterClient(&oauth2.Client{ClientID: "spa", Public: true})
+
+	secret, err := server.RotateClientSecret("web", time.Hour)
+	if err != nil {
+		t.Fatalf("RotateClientSecret() error = %v", err)
+	}
+	for _, s := range []string{secret, "old-secret"} {
+		if _, err := server.ClientCredentialsToken("web", s, nil); err != nil {
+			t.Errorf("ClientCredentialsToken(%q) within grace period error = %v", s, err)
+		}
+	}
+
+	newest, err := server.RotateClientSecret("web", 0)
+	if err != nil {
+		t.Fatalf("RotateClientSecret() error = %v", err)
+	}
+	for _, s := range []string{secret, "old-secret"} {
+		if _, err := server.ClientCredentialsToken("web", s, nil); !errors.Is(err, oauth2.ErrInvalidClientCredentials) {
+			t.Errorf("ClientCredentialsToken(%q) after rotation error = %v, want %v", s, err, oauth2.ErrInvalidClientCredentials)
+		}
+	}
+	if _, err := server.ClientCredentialsToken("web", newest, nil); err != nil {
+		t.Errorf("ClientCredentialsToken(newest) error = %v", err)
+	}
+
+	if _, err := server.RotateClientSecret("spa", time.Hour); !errors.Is(err, oauth2.ErrPublicClientNotAllowed) {
+		t.Errorf("RotateClientSecret(public) error = %v, want %v", err, oauth2.ErrPublicClientNotAllowed)
+	}
+}
+
+func TestRegisterClientMetadata(t *testing.T) {
+	server := oauth2.NewOAuth2Server(newFakeStorage(keepValue), "test:")
+	server.SetRegistrationPolicy(&oauth2.RegistrationPolicy{
+		GrantTypes: []oauth2.GrantType{oauth2.GrantTypeAuthorizationCode, oauth2.GrantTypeRefreshToken, oauth2.GrantTypeClientCredentials},
+		Scopes:     []string{"read", "write"},
+	})
+
+	tests := []struct {
+		name     string
+		metadata oauth2.ClientMetadata
+		wantErr  error
+	}{
+		{"MissingRedirectURI", oauth2.ClientMetadata{}, oauth2.ErrInvalidRedirectURI},
+		{"RelativeRedirectURI", oauth2.ClientMetadata{RedirectURIs: []string{"/callback"}}, oauth2.ErrInvalidRedirectURI},
+		{"FragmentRedirectURI", oauth2.ClientMetadata{RedirectURIs: []string{"https://a.example.com/cb#x"}}, oauth2.ErrInvalidRedirectURI},
+		{"UnknownAuthMethod", oauth2.ClientMetadata{RedirectURIs: []string{"https://a.example.com/cb"}, TokenEndpointAuthMethod: "private_key_jwt"}, oauth2.ErrInvalidClientMetadata},
+		{"GrantNotAllowed", oauth2.ClientMetadata{GrantTypes: []string{"password"}}, oauth2.ErrInvalidClientMetadata},
+		{"PublicClientCredentials", oauth2.ClientMetadata{GrantTypes: []string{"client_credentials"}, TokenEndpointAuthMethod: "none"}, oauth2.ErrInvalidClientMetadata},
+		{"ImplicitResponseType", oauth2.ClientMetadata{RedirectURIs: []string{"https://a.example.com/cb"}, ResponseTypes: []string{"token"}}, oauth2.ErrInvalidClientMetadata},
+		{"ScopeOutsidePolicy", oauth2.ClientMetadata{RedirectURIs: []string{"https://a.example.com/cb"}, Scope: "admin"}, oauth2.ErrInvalidScope},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := server.RegisterClientMetadata(&tt.metadata); !errors.Is(err, tt.wantErr) {
+				t.Errorf("RegisterClientMetadata() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	t.Run("Confidential", func(t *testing.T) {
+		info, err := server.RegisterClientMetadata(&oauth2.ClientMetadata{
+			RedirectURIs: []string{"https://a.example.com/cb"},
+			GrantTypes:   []string{"authorization_code", "refresh_token"},
+			ClientName:   "Partner",
+		})
+		if err != nil {
+			t.Fatalf("RegisterClientMetadata() error = %v", err)
+		}
+		if info.ClientSecret == "" || info.ClientSecretExpiresAt == nil || *info.ClientSecretExpiresAt != 0 {
+			t.Errorf("confidential client issued no secret: %+v", info)
+		}
+		if info.TokenEndpointAuthMethod != oauth2.TokenEndpointAuthClientSecretBasic || info.Scope != "read write" {
+			t.Errorf("registered metadata not normalized: %+v", info.ClientMetadata)
+		}
+
+		code, err := server.GenerateAuthorizationCode(info.ClientID, "https://a.example.com/cb", "user-1", nil)
+		if err != nil {
+			t.Fatalf("GenerateAuthorizationCode() error = %v", err)
+		}
+		if _, err := server.ExchangeCodeForToken(code.Code, info.ClientID, info.ClientSecret, "https://a.example.com/cb"); err != nil {
+			t.Errorf("ExchangeCodeForToken() error = %v", err)
+		}
+	})
+
+	t.Run("Public", func(t *testing.T) {
+		info, err := server.RegisterClientMetadata(&oauth2.ClientMetadata{
+			RedirectURIs:            []string{"com.example.app://callback"},
+			TokenEndpointAuthMethod: oauth2.TokenEndpointAuthNone,
+			Scope:                   "read",
+		})
+		if err != nil {
+			t.Fatalf("RegisterClientMetadata() error = %v", err)
+		}
+		if info.ClientSecret != "" || info.ClientSecretExpiresAt != nil {
+			t.Errorf("public client issued a secret: %+v", info)
+		}
+		client, err := server.GetClient(info.ClientID)
+		if err != nil || !client.Public || len(client.Scopes) != 1 {
+			t.Errorf("GetClient() = %+v, %v", client, err)
+		}
+	})
+}
+
+func TestHandlerRegister(t *testing.T) {
+	server := oauth2.NewOAuth2Server(newFakeStorage(keepValue), "test:")
+	handler := oauth2.NewHandler(server, nil)
+	body := `{"redirect_uris":["https://a.example.com/cb"],"client_name":"Partner"}`
+
+	if resp := handler.Register(newRegisterContext(body, "")); resp.Status != http.StatusForbidden {
+		t.Errorf("Register() without authorizer status = %d, want %d", resp.Status, http.StatusForbidden)
+	}
+
+	handler.SetRegistrationAuthorizer(func(ctx adapter.RequestContext) bool {
+		return ctx.GetHeader("Authorization") == "Bearer initial-token"
+	})
+	if resp := handler.Register(newRegisterContext(body, "Bearer nope")); resp.Status != http.StatusUnauthorized {
+		t.Errorf("Register() unauthorized status = %d, want %d", resp.Status, http.StatusUnauthorized)
+	}
+	if resp := handler.Register(newRegisterContext(`{"redirect_uris":["/cb"]}`, "Bearer initial-token")); resp.Status != http.StatusBadRequest ||
+		!strings.Contains(string(resp.Body), oauth2.ErrorInvalidRedirectURI) {
+		t.Errorf("Register() bad redirect = %d %s", resp.Status, resp.Body)
+	}
+
+	resp := handler.Register(newRegisterContext(body, "Bearer initial-token"))
+	if resp.Status != http.StatusCreated {
+		t.Fatalf("Register() status = %d, body %s", resp.Status, resp.Body)
+	}
+	info := &oauth2.ClientInformation{}
+	if err := json.Unmarshal(resp.Body, info); err != nil {
+		t.Fatalf("Register() body %s: %v", resp.Body, err)
+	}
+	if client, err := server.GetClient(info.ClientID); err != nil || client.Name != "Partner" {
+		t.Errorf("GetClient() = %+v, %v", client, err)
+	}
+}
+
+// registerContext Request context of a JSON POST, unused methods panic | JSON POST请求上下文，未使用的方法会panic
+type registerContext struct {
+	adapter.RequestContext
+	body          string
+	authorization string
+}
+
+func newRegisterContext(body, authorization string) *registerContext {
+	return &registerContext{body: body, authorization: authorization}
+}
+
+func (c *registerContext) GetMethod() string { return http.MethodPost }
+
+func (c *registerContext) GetBody() ([]byte, error) { return []byte(c.body), nil }
+
+func (c *registerContext) GetHeader(key string) string {
+	if key == "Authorization" {
+		return c.authorization
+	}
+	return ""
+}
+
+// ============ In-memory SQL driver | 内存SQL驱动 ============
+
+// fakeDrivers Numbers registered drivers, sql.Register panics on a reused name under -count | 为注册的驱动编号，-count下重复名称会导致sql.Register panic
+var fakeDrivers atomic.Int64
+
+// newSQLStore Opens a SQL client store on a fresh in-memory table | 在新的内存表上打开SQL客户端存储
+func newSQLStore(t *testing.T, placeholder oauth2.SQLPlaceholder) *oauth2.SQLClientStore {
+	name := fmt.Sprintf("fakesql-%d", fakeDrivers.Add(1))
+	sql.Register(name, &fakeDriver{placeholder: placeholder, rows: make(map[string]string)})
+	db, err := sql.Open(name, "")
+	if err != nil {
+		t.Fatalf("sql.Open() error = %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	store := oauth2.NewSQLClientStore(db, "").SetPlaceholder(placeholder)
+	if _, err := db.Exec(store.CreateTableSQL()); err != nil {
+		t.Fatalf("create table error = %v", err)
+	}
+	return store
+}
+
+// fakeDriver Understands exactly the statements SQLClientStore issues | 仅理解SQLClientStore发出的语句
+type fakeDriver struct {
+	placeholder oauth2.SQLPlaceholder
+	mu          sync.Mutex
+	rows        map[string]string
+}
+
+func (d *fakeDriver) Open(string) (driver.Conn, error) { return &fakeConn{d: d}, nil }
+
+type fakeConn struct{ d *fakeDriver }
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	bind := "?"
+	if c.d.placeholder == oauth2.PlaceholderDollar {
+		bind = "$1"
+	}
+	if !strings.HasPrefix(query, "CREATE") && !strings.Contains(query, bind) {
+		return nil, fmt.Errorf("unexpected placeholder in %q", query)
+	}
+	return &fakeStmt{d: c.d, query: query}, nil
+}
+
+func (c *fakeConn) Close() error              { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error) { return c, nil }
+func (c *fakeConn) Commit() error             { return nil }
+func (c *fakeConn) Rollback() error           { return nil }
+
+type fakeStmt struct {
+	d     *fakeDriver
+	query string
+}
+
+func (s *fakeStmt) Close() error  { return nil }
+func (s *fakeStmt) NumInput() int { return -1 }
+
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.d.mu.Lock()
+	defer s.d.mu.Unlock()
+	switch {
+	case strings.HasPrefix(s.query, "CREATE TABLE IF NOT EXISTS oauth2_clients "):
+	case strings.HasPrefix(s.query, "DELETE FROM oauth2_clients "):
+		delete(s.d.rows, args[0].(string))
+	case strings.HasPrefix(s.query, "INSERT INTO oauth2_clients "):
+		if _, exists := s.d.rows[args[0].(string)]; exists {
+			return nil, errors.New("duplicate primary key")
+		}
+		s.d.rows[args[0].(string)] = args[1].(string)
+	default:
+		return nil, fmt.Errorf("unexpected exec %q", s.query)
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (s *fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	if !strings.HasPrefix(s.query, "SELECT data FROM oauth2_clients ") {
+		return nil, fmt.Errorf("unexpected query %q", s.query)
+	}
+	s.d.mu.Lock()
+	defer s.d.mu.Unlock()
+	data, ok := s.d.rows[args[0].(string)]
+	return &fakeRows{data: data, done: !ok}, nil
+}
+
+type fakeRows struct {
+	data string
+	done bool
+}
+
+func (r *fakeRows) Columns() []string { return []string{"data"} }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	r.done = true
+	dest[0] = r.data
+	return nil
+}
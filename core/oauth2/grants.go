@@ -0,0 +1,162 @@
+package oauth2
+
+import (
+	"fmt"
+)
+
+// Client Credentials and Password Grants Implementation
+// 客户端凭证模式与密码模式实现
+//
+// Rules | 规则:
+// - Client.GrantTypes lists allowed grants, empty means authorization_code and refresh_token | Client.GrantTypes列出允许的授权类型，为空时为authorization_code与refresh_token
+// - Client.Scopes lists allowed scopes, empty means any scope | Client.Scopes列出允许的权限范围，为空时不限制
+// - No requested scope grants every client scope, otherwise each one must be allowed | 未请求权限范围时授予客户端全部范围，否则每个范围都必须被允许
+// - Refresh may only narrow the original scopes (RFC 6749 section 6) | 刷新时只能缩小原有权限范围（RFC 6749第6节）
+// - client_credentials needs a confidential client and issues no refresh token | client_credentials要求机密客户端，且不签发刷新令牌
+//
+// Usage | 用法:
+//   server.SetUserAuthenticator(oauth2.UserAuthenticatorFunc(func(username, password string) (string, error) {
+//       return userService.Verify(username, password)
+//   }))
+//   token, _ := server.PasswordToken("alice", "pwd", "cli", "cli-secret", []string{"read"})
+//   token, _ = server.ClientCredentialsToken("svc", "svc-secret", nil)
+
+// Grant error variables | 授权错误变量
+var (
+	ErrUnauthorizedGrantType   = fmt.Errorf("grant type not allowed for client")
+	ErrInvalidScope            = fmt.Errorf("invalid scope")
+	ErrInvalidUserCredentials  = fmt.Errorf("invalid username or password")
+	ErrUserAuthenticatorNotSet = fmt.Errorf("user authenticator not set")
+	ErrPublicClientNotAllowed  = fmt.Errorf("public client cannot use this grant type")
+)
+
+// defaultGrantTypes Grants of clients registered without GrantTypes | 未配置GrantTypes的客户端允许的授权类型
+var defaultGrantTypes = []GrantType{GrantTypeAuthorizationCode, GrantTypeRefreshToken}
+
+// UserAuthenticator Checks resource owner credentials for the password grant | 为密码模式校验资源所有者凭证
+type UserAuthenticator interface {
+	// Authenticate Returns user ID of valid credentials | 凭证有效时返回用户ID
+	Authenticate(username, password string) (userID string, err error)
+}
+
+// UserAuthenticatorFunc Adapts a function to UserAuthenticator | 将函数适配为UserAuthenticator
+type UserAuthenticatorFunc func(username, password string) (string, error)
+
+// Authenticate Calls f | 调用f
+func (f UserAuthenticatorFunc) Authenticate(username, password string) (string, error) {
+	return f(username, password)
+}
+
+// SetUserAuthenticator Sets password grant authenticator | 设置密码模式的用户认证器
+func (s *OAuth2Server) SetUserAuthenticator(authenticator UserAuthenticator) {
+	s.userAuthenticator = authenticator
+}
+
+// ClientCredentialsToken Issues token to a confidential client acting on its own behalf | 为代表自身的机密客户端签发令牌
+func (s *OAuth2Server) ClientCredentialsToken(clientID, clientSecret string, scopes []string) (*AccessToken, error) {
+	client, err := s.authenticateClient(clientID, clientSecret)
+	if err != nil {
+		return nil, err
+	}
+	if client.Public {
+		return nil, ErrPublicClientNotAllowed
+	}
+	if err := checkGrantType(client, GrantTypeClientCredentials); err != nil {
+		return nil, err
+	}
+
+	granted, err := resolveScopes(client, scopes)
+	if err != nil {
+		return nil, err
+	}
+	return s.generateAccessToken("", client.ClientID, granted, false)
+}
+
+// PasswordToken Issues token for resource owner credentials checked by UserAuthenticator | 使用UserAuthenticator校验资源所有者凭证后签发令牌
+func (s *OAuth2Server) PasswordToken(username, password, clientID, clientSecret string, scopes []string) (*AccessToken, error) {
+	client, err := s.authenticateClient(clientID, clientSecret)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkGrantType(client, GrantTypePassword); err != nil {
+		return nil, err
+	}
+
+	granted, err := resolveScopes(client, scopes)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.userAuthenticator == nil {
+		return nil, ErrUserAuthenticatorNotSet
+	}
+	userID, err := s.userAuthenticator.Authenticate(username, password)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidUserCredentials, err)
+	}
+	if userID == "" {
+		return nil, ErrInvalidUserCredentials
+	}
+
+	return s.generateAccessToken(userID, client.ClientID, granted, true)
+}
+
+// ============ Grant and Scope Rules | 授权类型与权限范围规则 ============
+
+// AllowsGrantType Checks if client may use grant type | 检查客户端是否允许使用该授权类型
+func (c *Client) AllowsGrantType(grantType GrantType) bool {
+	allowed := c.GrantTypes
+	if len(allowed) == 0 {
+		allowed = defaultGrantTypes
+	}
+	for _, g := range allowed {
+		if g == grantType {
+			return true
+		}
+	}
+	return false
+}
+
+// checkGrantType Rejects grant types the client is not registered for | 拒绝客户端未注册的授权类型
+func checkGrantType(client *Client, grantType GrantType) error {
+	if !client.AllowsGrantType(grantType) {
+		return fmt.Errorf("%w: %s", ErrUnauthorizedGrantType, grantType)
+	}
+	return nil
+}
+
+// resolveScopes Down-selects requested scopes against client scopes | 按客户端权限范围筛选请求的权限范围
+func resolveScopes(client *Client, requested []string) ([]string, error) {
+	if len(requested) == 0 {
+		return client.Scopes, nil
+	}
+	return narrowScopes(client.Scopes, requested)
+}
+
+// narrowScopes Returns requested scopes deduplicated, each must be in allowed unless allowed is empty | 返回去重后的请求范围，allowed非空时每个范围都必须在其中
+func narrowScopes(allowed, requested []string) ([]string, error) {
+	allowedSet := make(map[string]struct{}, len(allowed))
+	for _, scope := range allowed {
+		allowedSet[scope] = struct{}{}
+	}
+
+	granted := make([]string, 0, len(requested))
+	seen := make(map[string]struct{}, len(requested))
+	for _, scope := range requested {
+		if scope == "" {
+			continue
+		}
+		if _, dup := seen[scope]; dup {
+			continue
+		}
+		if _, ok := allowedSet[scope]; len(allowed) > 0 && !ok {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidScope, scope)
+		}
+		seen[scope] = struct{}{}
+		granted = append(granted, scope)
+	}
+	if len(granted) == 0 {
+		return allowed, nil
+	}
+	return granted, nil
+}
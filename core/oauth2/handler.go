@@ -0,0 +1,557 @@
+package oauth2
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/click33/sa-token-go/core/adapter"
+)
+
+// OAuth2 HTTP Endpoints
+// OAuth2 HTTP端点
+//
+// Endpoints | 端点:
+// - Authorize()  GET/POST /oauth2/authorize  - Authorization code request with consent hook (RFC 6749 4.1) | 授权码请求，由同意钩子决定
+// - Token()      POST     /oauth2/token      - Form-encoded token request, HTTP Basic or form client auth (RFC 6749 3.2) | 表单令牌请求，支持HTTP Basic或表单客户端认证
+// - Revoke()     POST     /oauth2/revoke     - Token revocation (RFC 7009) | 令牌撤销
+// - Introspect() POST     /oauth2/introspect - Token introspection for confidential clients (RFC 7662), see AllowIntrospection | 供机密客户端使用的令牌内省，见AllowIntrospection
+// - Register()   POST     /oauth2/register   - Dynamic client registration (RFC 7591), see registration.go | 动态客户端注册，见registration.go
+//
+// Handlers only read the request through adapter.RequestContext and return a Response,
+// framework integrations write it with their RegisterOAuth2Routes | 处理器仅通过adapter.RequestContext读取请求并返回Response，由各框架集成的RegisterOAuth2Routes写出
+//
+// Usage | 用法:
+//   handler := oauth2.NewHandler(server, func(ctx adapter.RequestContext, req *oauth2.AuthorizeRequest) *oauth2.ConsentResult {
+//       loginID, err := core.NewContext(ctx, manager).GetLoginID()
+//       if err != nil {
+//           return &oauth2.ConsentResult{Response: oauth2.NewRedirectResponse("/login")}
+//       }
+//       return &oauth2.ConsentResult{UserID: loginID, Approved: true}
+//   })
+//   resp := handler.Token(ctx)
+//   resp.Write(w)
+
+// Default endpoint paths | 默认端点路径
+const (
+	AuthorizePath  = "/oauth2/authorize"
+	TokenPath      = "/oauth2/token"
+	RevokePath     = "/oauth2/revoke"
+	IntrospectPath = "/oauth2/introspect"
+	RegisterPath   = "/oauth2/register"
+)
+
+// Error codes of RFC 6749 section 4.1.2.1 and 5.2, and registration errors | RFC 6749第4.1.2.1节与5.2节的错误码，以及注册错误码
+const (
+	ErrorInvalidRequest          = "invalid_request"
+	ErrorInvalidClient           = "invalid_client"
+	ErrorInvalidGrant            = "invalid_grant"
+	ErrorUnauthorizedClient      = "unauthorized_client"
+	ErrorUnsupportedGrantType    = "unsupported_grant_type"
+	ErrorUnsupportedResponseType = "unsupported_response_type"
+	ErrorInvalidScope            = "invalid_scope"
+	ErrorAccessDenied            = "access_denied"
+	ErrorServerError             = "server_error"
+
+	ErrorInvalidRedirectURI    = "invalid_redirect_uri"    // RFC 7591 section 3.2.2
+	ErrorInvalidClientMetadata = "invalid_client_metadata" // RFC 7591 section 3.2.2
+	ErrorInvalidToken          = "invalid_token"           // RFC 6750 section 3.1
+)
+
+// ResponseTypeCode The only supported response_type | 唯一支持的response_type
+const ResponseTypeCode = "code"
+
+// Response Endpoint result, written by framework integrations | 端点结果，由框架集成写出
+type Response struct {
+	Status int               // HTTP status code | HTTP状态码
+	Header map[string]string // Response headers | 响应头
+	Body   []byte            // Response body, may be empty | 响应体（可为空）
+}
+
+// NewJSONResponse Creates a non-cacheable JSON response | 创建不可缓存的JSON响应
+func NewJSONResponse(status int, body any) *Response {
+	data, err := json.Marshal(body)
+	if err != nil {
+		status = http.StatusInternalServerError
+		data = []byte(`{"error":"server_error"}`)
+	}
+	return &Response{
+		Status: status,
+		Header: map[string]string{
+			"Content-Type":  "application/json;charset=UTF-8",
+			"Cache-Control": "no-store",
+			"Pragma":        "no-cache",
+		},
+		Body: data,
+	}
+}
+
+// NewRedirectResponse Creates a 302 redirect response | 创建302重定向响应
+func NewRedirectResponse(location string) *Response {
+	return &Response{
+		Status: http.StatusFound,
+		Header: map[string]string{
+			"Location":      location,
+			"Cache-Control": "no-store",
+		},
+	}
+}
+
+// Write Writes response to w | 将响应写入w
+func (r *Response) Write(w http.ResponseWriter) {
+	for key, value := range r.Header {
+		w.Header().Set(key, value)
+	}
+	w.WriteHeader(r.Status)
+	if len(r.Body) > 0 {
+		_, _ = w.Write(r.Body)
+	}
+}
+
+// AuthorizeRequest Validated authorization request passed to the consent hook | 传给同意钩子的已校验授权请求
+type AuthorizeRequest struct {
+	Client              *Client  // Requesting client | 请求的客户端
+	RedirectURI         string   // Registered redirect URI | 已注册的回调URI
+	Scopes              []string // Resolved scopes | 解析后的权限范围
+	State               string   // Opaque client state | 客户端不透明状态值
+	CodeChallenge       string   // PKCE code_challenge | PKCE挑战值
+	CodeChallengeMethod string   // PKCE code_challenge_method | PKCE挑战值计算方式
+}
+
+// ConsentResult Decision of the consent hook | 同意钩子的决定
+type ConsentResult struct {
+	UserID   string    // Resource owner, required when approved | 资源所有者（同意时必填）
+	Approved bool      // Whether the resource owner approved | 资源所有者是否同意
+	Scopes   []string  // Optionally narrows request scopes | 可选，缩小请求的权限范围
+	Response *Response // Rendered instead, e.g. login or consent page | 直接返回的响应（如登录页或授权确认页）
+}
+
+// ConsentHook Resolves resource owner and consent, nil result denies | 解析资源所有者与授权同意，返回nil表示拒绝
+type ConsentHook func(ctx adapter.RequestContext, req *AuthorizeRequest) *ConsentResult
+
+// Handler Framework-neutral OAuth2 HTTP endpoints | 框架无关的OAuth2 HTTP端点
+type Handler struct {
+	server  *OAuth2Server
+	consent ConsentHook
+
+	registrationAuthorizer RegistrationAuthorizer // nil disables Register | 为nil时禁用Register
+	introspectors          map[string]bool        // Clients allowed to introspect tokens of every client | 可内省所有客户端令牌的客户端
+}
+
+// NewHandler Creates OAuth2 HTTP endpoints, consent is required by Authorize | 创建OAuth2 HTTP端点，Authorize需要consent
+func NewHandler(server *OAuth2Server, consent ConsentHook) *Handler {
+	return &Handler{
+		server:  server,
+		consent: consent,
+	}
+}
+
+// ============ Authorization Endpoint | 授权端点 ============
+
+// Authorize Handles authorization request, redirecting code or error to redirect_uri | 处理授权请求，将授权码或错误重定向到redirect_uri
+// Unknown client or redirect_uri are answered directly, never redirected (RFC 6749 4.1.2.1) | 未知客户端或回调URI直接响应，不做重定向
+func (h *Handler) Authorize(ctx adapter.RequestContext) *Response {
+	param := func(key string) string {
+		if value := ctx.GetQuery(key); value != "" {
+			return value
+		}
+		if ctx.GetMethod() == http.MethodPost {
+			return ctx.GetPostForm(key)
+		}
+		return ""
+	}
+
+	client, err := h.server.GetClient(param("client_id"))
+	if err != nil {
+		return errorResponse(http.StatusBadRequest, ErrorInvalidRequest, "unknown client_id")
+	}
+	redirectURI := param("redirect_uri")
+	if !h.server.isValidRedirectURI(client, redirectURI) {
+		return errorResponse(http.StatusBadRequest, ErrorInvalidRequest, ErrInvalidRedirectURI.Error())
+	}
+
+	state := param("state")
+	if param("response_type") != ResponseTypeCode {
+		return authorizeError(redirectURI, state, ErrorUnsupportedResponseType, "response_type must be code")
+	}
+	if err := checkGrantType(client, GrantTypeAuthorizationCode); err != nil {
+		return authorizeError(redirectURI, state, ErrorUnauthorizedClient, err.Error())
+	}
+	scopes, err := resolveScopes(client, strings.Fields(param("scope")))
+	if err != nil {
+		return authorizeError(redirectURI, state, ErrorInvalidScope, err.Error())
+	}
+	challenge, method := param("code_challenge"), param("code_challenge_method")
+	if _, err := normalizeChallenge(client, challenge, method); err != nil {
+		return authorizeError(redirectURI, state, ErrorInvalidRequest, err.Error())
+	}
+	if h.consent == nil {
+		return authorizeError(redirectURI, state, ErrorServerError, "consent hook not set")
+	}
+
+	result := h.consent(ctx, &AuthorizeRequest{
+		Client:              client,
+		RedirectURI:         redirectURI,
+		Scopes:              scopes,
+		State:               state,
+		CodeChallenge:       challenge,
+		CodeChallengeMethod: method,
+	})
+	if result != nil && result.Response != nil {
+		return result.Response
+	}
+	if result == nil || !result.Approved || result.UserID == "" {
+		return authorizeError(redirectURI, state, ErrorAccessDenied, "resource owner denied the request")
+	}
+	if len(result.Scopes) > 0 {
+		if scopes, err = narrowScopes(scopes, result.Scopes); err != nil {
+			return authorizeError(redirectURI, state, ErrorInvalidScope, err.Error())
+		}
+	}
+
+	authCode, err := h.server.GenerateAuthorizationCodeWithPKCE(client.ClientID, redirectURI, result.UserID, scopes, challenge, method)
+	if err != nil {
+		code := errorCode(err)
+		return authorizeError(redirectURI, state, code, errorDescription(code, err))
+	}
+
+	params := url.Values{"code": {authCode.Code}}
+	if state != "" {
+		params.Set("state", state)
+	}
+	return redirectWithParams(redirectURI, params)
+}
+
+// ============ Token Endpoint | 令牌端点 ============
+
+// tokenResponse Successful token response (RFC 6749 5.1) | 令牌成功响应
+type tokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	Scope        string `json:"scope,omitempty"`
+}
+
+// Token Handles form-encoded token request for every supported grant type | 处理所有支持授权类型的表单令牌请求
+func (h *Handler) Token(ctx adapter.RequestContext) *Response {
+	if resp := requirePost(ctx); resp != nil {
+		return resp
+	}
+	clientID, clientSecret, err := clientCredentials(ctx)
+	if err != nil {
+		return errorResponse(http.StatusBadRequest, ErrorInvalidRequest, err.Error())
+	}
+	scopes := strings.Fields(ctx.GetPostForm("scope"))
+
+	var token *AccessToken
+	switch grantType := GrantType(ctx.GetPostForm("grant_type")); grantType {
+	case GrantTypeAuthorizationCode:
+		code := ctx.GetPostForm("code")
+		if code == "" {
+			return errorResponse(http.StatusBadRequest, ErrorInvalidRequest, "code required")
+		}
+		token, err = h.server.ExchangeCodeForTokenWithPKCE(code, clientID, clientSecret, ctx.GetPostForm("redirect_uri"), ctx.GetPostForm("code_verifier"))
+		// A code issued without challenge to a PKCE client is a bad grant here | 此处为PKCE客户端签发的无挑战值授权码属于无效授权
+		if errors.Is(err, ErrPKCERequired) {
+			return errorResponse(http.StatusBadRequest, ErrorInvalidGrant, err.Error())
+		}
+	case GrantTypeRefreshToken:
+		refreshToken := ctx.GetPostForm("refresh_token")
+		if refreshToken == "" {
+			return errorResponse(http.StatusBadRequest, ErrorInvalidRequest, "refresh_token required")
+		}
+		token, err = h.server.RefreshAccessTokenWithScopes(refreshToken, clientID, clientSecret, scopes)
+	case GrantTypeClientCredentials:
+		token, err = h.server.ClientCredentialsToken(clientID, clientSecret, scopes)
+	case GrantTypePassword:
+		username := ctx.GetPostForm("username")
+		if username == "" {
+			return errorResponse(http.StatusBadRequest, ErrorInvalidRequest, "username required")
+		}
+		token, err = h.server.PasswordToken(username, ctx.GetPostForm("password"), clientID, clientSecret, scopes)
+	case "":
+		return errorResponse(http.StatusBadRequest, ErrorInvalidRequest, "grant_type required")
+	default:
+		return errorResponse(http.StatusBadRequest, ErrorUnsupportedGrantType, string(grantType))
+	}
+	if err != nil {
+		return tokenError(err)
+	}
+
+	return NewJSONResponse(http.StatusOK, &tokenResponse{
+		AccessToken:  token.Token,
+		TokenType:    token.TokenType,
+		ExpiresIn:    token.ExpiresIn,
+		RefreshToken: token.RefreshToken,
+		Scope:        strings.Join(token.Scopes, " "),
+	})
+}
+
+// ============ Revocation Endpoint | 撤销端点 ============
+
+// Revoke Handles RFC 7009 revocation, unknown tokens and tokens of other clients are ignored | 处理RFC 7009撤销，忽略未知令牌及其他客户端的令牌
+// Revoking a refresh token also revokes its access token | 撤销刷新令牌时同时撤销其访问令牌
+func (h *Handler) Revoke(ctx adapter.RequestContext) *Response {
+	if resp := requirePost(ctx); resp != nil {
+		return resp
+	}
+	client, resp := h.authenticate(ctx)
+	if resp != nil {
+		return resp
+	}
+	tokenString := ctx.GetPostForm("token")
+	if tokenString == "" {
+		return errorResponse(http.StatusBadRequest, ErrorInvalidRequest, "token required")
+	}
+
+	token, refresh := h.lookupToken(tokenString, ctx.GetPostForm("token_type_hint"))
+	if token != nil && token.ClientID == client.ClientID {
+		var err error
+		if refresh {
+			err = h.server.RevokeRefreshToken(tokenString)
+		} else {
+			err = h.server.RevokeToken(tokenString)
+		}
+		if err != nil {
+			return errorResponse(http.StatusServiceUnavailable, ErrorServerError, "")
+		}
+	}
+	return &Response{Status: http.StatusOK, Header: map[string]string{"Cache-Control": "no-store"}}
+}
+
+// ============ Introspection Endpoint | 内省端点 ============
+
+// introspectionResponse Token introspection response (RFC 7662 2.2) | 令牌内省响应
+type introspectionResponse struct {
+	Active    bool   `json:"active"`
+	Scope     string `json:"scope,omitempty"`
+	ClientID  string `json:"client_id,omitempty"`
+	Subject   string `json:"sub,omitempty"`
+	TokenType string `json:"token_type,omitempty"`
+	ExpiresAt int64  `json:"exp,omitempty"`
+	IssuedAt  int64  `json:"iat,omitempty"`
+}
+
+// AllowIntrospection Lets confidential clients, e.g. resource servers, introspect tokens of every client | 允许机密客户端（如资源服务器）内省所有客户端的令牌
+// Other clients only see their own tokens as active | 其他客户端只能看到自己的令牌为有效
+func (h *Handler) AllowIntrospection(clientIDs ...string) *Handler {
+	if h.introspectors == nil {
+		h.introspectors = make(map[string]bool, len(clientIDs))
+	}
+	for _, clientID := range clientIDs {
+		h.introspectors[clientID] = true
+	}
+	return h
+}
+
+// Introspect Handles RFC 7662 introspection, only confidential clients may call it | 处理RFC 7662内省，仅机密客户端可调用
+// Tokens of other clients are reported inactive unless allowed by AllowIntrospection | 除非经AllowIntrospection允许，其他客户端的令牌均返回无效
+func (h *Handler) Introspect(ctx adapter.RequestContext) *Response {
+	if resp := requirePost(ctx); resp != nil {
+		return resp
+	}
+	client, resp := h.authenticate(ctx)
+	if resp != nil {
+		return resp
+	}
+	if client.Public {
+		return invalidClient("public client cannot introspect tokens")
+	}
+	tokenString := ctx.GetPostForm("token")
+	if tokenString == "" {
+		return errorResponse(http.StatusBadRequest, ErrorInvalidRequest, "token required")
+	}
+
+	token, refresh := h.lookupToken(tokenString, ctx.GetPostForm("token_type_hint"))
+	if token == nil || (token.ClientID != client.ClientID && !h.introspectors[client.ClientID]) {
+		return NewJSONResponse(http.StatusOK, &introspectionResponse{Active: false})
+	}
+
+	lifetime := time.Duration(token.ExpiresIn) * time.Second
+	if refresh {
+		lifetime = DefaultRefreshTTL
+	}
+	info := &introspectionResponse{
+		Active:    true,
+		Scope:     strings.Join(token.Scopes, " "),
+		ClientID:  token.ClientID,
+		Subject:   token.UserID,
+		TokenType: token.TokenType,
+	}
+	// Tokens stored before CreateTime existed report no exp and iat | CreateTime出现前存储的令牌不返回exp与iat
+	if token.CreateTime > 0 {
+		info.IssuedAt = token.CreateTime
+		info.ExpiresAt = token.CreateTime + int64(lifetime.Seconds())
+		if info.ExpiresAt <= time.Now().Unix() {
+			return NewJSONResponse(http.StatusOK, &introspectionResponse{Active: false})
+		}
+	}
+	return NewJSONResponse(http.StatusOK, info)
+}
+
+// ============ Helper Methods | 辅助方法 ============
+
+// authenticate Authenticates the calling client, answering invalid_client on failure | 认证调用方客户端，失败时返回invalid_client
+func (h *Handler) authenticate(ctx adapter.RequestContext) (*Client, *Response) {
+	clientID, clientSecret, err := clientCredentials(ctx)
+	if err != nil {
+		return nil, errorResponse(http.StatusBadRequest, ErrorInvalidRequest, err.Error())
+	}
+	client, err := h.server.authenticateClient(clientID, clientSecret)
+	if err != nil {
+		return nil, invalidClient(err.Error())
+	}
+	return client, nil
+}
+
+// lookupToken Finds access or refresh token, trying the hinted type first | 查找访问令牌或刷新令牌，优先尝试提示的类型
+func (h *Handler) lookupToken(tokenString, hint string) (token *AccessToken, refresh bool) {
+	if hint != "refresh_token" {
+		if token, err := h.server.ValidateAccessToken(tokenString); err == nil {
+			return token, false
+		}
+	}
+	if token, err := h.server.getRefreshToken(tokenString); err == nil {
+		return token, true
+	}
+	if hint == "refresh_token" {
+		if token, err := h.server.ValidateAccessToken(tokenString); err == nil {
+			return token, false
+		}
+	}
+	return nil, false
+}
+
+// clientCredentials Reads client credentials from HTTP Basic or form, using both is rejected | 从HTTP Basic或表单读取客户端凭证，两者同时使用时拒绝
+func clientCredentials(ctx adapter.RequestContext) (clientID, clientSecret string, err error) {
+	formID, formSecret := ctx.GetPostForm("client_id"), ctx.GetPostForm("client_secret")
+
+	auth := ctx.GetHeader("Authorization")
+	if len(auth) < 6 || !strings.EqualFold(auth[:6], "Basic ") {
+		return formID, formSecret, nil
+	}
+	if formSecret != "" {
+		return "", "", errors.New("multiple client authentication methods")
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(auth[6:]))
+	if err != nil {
+		return "", "", errors.New("malformed basic credentials")
+	}
+	rawID, rawSecret, ok := strings.Cut(string(decoded), ":")
+	if !ok {
+		return "", "", errors.New("malformed basic credentials")
+	}
+	// Basic credentials are form-urlencoded first (RFC 6749 2.3.1) | Basic凭证先经过表单URL编码
+	if clientID, err = url.QueryUnescape(rawID); err != nil {
+		return "", "", errors.New("malformed basic credentials")
+	}
+	if clientSecret, err = url.QueryUnescape(rawSecret); err != nil {
+		return "", "", errors.New("malformed basic credentials")
+	}
+	if formID != "" && formID != clientID {
+		return "", "", errors.New("client_id does not match basic credentials")
+	}
+	return clientID, clientSecret, nil
+}
+
+// requirePost Rejects non-POST requests | 拒绝非POST请求
+func requirePost(ctx adapter.RequestContext) *Response {
+	if ctx.GetMethod() == http.MethodPost {
+		return nil
+	}
+	resp := errorResponse(http.StatusMethodNotAllowed, ErrorInvalidRequest, "POST required")
+	resp.Header["Allow"] = http.MethodPost
+	return resp
+}
+
+// tokenError Converts server error to token endpoint error response | 将服务器错误转换为令牌端点错误响应
+func tokenError(err error) *Response {
+	code := errorCode(err)
+	switch code {
+	case ErrorInvalidClient:
+		return invalidClient(err.Error())
+	case ErrorServerError:
+		return errorResponse(http.StatusInternalServerError, code, "")
+	}
+	return errorResponse(http.StatusBadRequest, code, err.Error())
+}
+
+// invalidClient Creates 401 invalid_client response with Basic challenge | 创建带Basic质询的401 invalid_client响应
+func invalidClient(description string) *Response {
+	resp := errorResponse(http.StatusUnauthorized, ErrorInvalidClient, description)
+	resp.Header["WWW-Authenticate"] = `Basic realm="oauth2"`
+	return resp
+}
+
+// errorBody Error response body (RFC 6749 5.2) | 错误响应体
+type errorBody struct {
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description,omitempty"`
+}
+
+// errorResponse Creates JSON error response | 创建JSON错误响应
+func errorResponse(status int, code, description string) *Response {
+	return NewJSONResponse(status, &errorBody{Error: code, ErrorDescription: description})
+}
+
+// authorizeError Redirects error to the client (RFC 6749 4.1.2.1) | 将错误重定向给客户端
+func authorizeError(redirectURI, state, code, description string) *Response {
+	params := url.Values{"error": {code}}
+	if description != "" {
+		params.Set("error_description", description)
+	}
+	if state != "" {
+		params.Set("state", state)
+	}
+	return redirectWithParams(redirectURI, params)
+}
+
+// redirectWithParams Redirects to uri with params merged into its query | 合并查询参数后重定向到uri
+func redirectWithParams(uri string, params url.Values) *Response {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return errorResponse(http.StatusBadRequest, ErrorInvalidRequest, ErrInvalidRedirectURI.Error())
+	}
+	query := u.Query()
+	for key, values := range params {
+		query[key] = values
+	}
+	u.RawQuery = query.Encode()
+	return NewRedirectResponse(u.String())
+}
+
+// errorCode Maps server error to RFC 6749 error code | 将服务器错误映射为RFC 6749错误码
+func errorCode(err error) string {
+	switch {
+	case errors.Is(err, ErrClientNotFound), errors.Is(err, ErrInvalidClientCredentials):
+		return ErrorInvalidClient
+	case errors.Is(err, ErrUnauthorizedGrantType), errors.Is(err, ErrPublicClientNotAllowed):
+		return ErrorUnauthorizedClient
+	case errors.Is(err, ErrInvalidScope):
+		return ErrorInvalidScope
+	case errors.Is(err, ErrInvalidRedirectURI), errors.Is(err, ErrPKCERequired),
+		errors.Is(err, ErrInvalidCodeChallenge), errors.Is(err, ErrUnsupportedChallengeMethod):
+		return ErrorInvalidRequest
+	case errors.Is(err, ErrInvalidAuthCode), errors.Is(err, ErrAuthCodeUsed), errors.Is(err, ErrAuthCodeExpired),
+		errors.Is(err, ErrClientMismatch), errors.Is(err, ErrRedirectURIMismatch), errors.Is(err, ErrInvalidCodeVerifier),
+		errors.Is(err, ErrInvalidRefreshToken), errors.Is(err, ErrInvalidUserCredentials):
+		return ErrorInvalidGrant
+	case errors.Is(err, ErrUserAuthenticatorNotSet):
+		return ErrorUnsupportedGrantType
+	}
+	return ErrorServerError
+}
+
+// errorDescription Hides internal details of server_error | 隐藏server_error的内部细节
+func errorDescription(code string, err error) string {
+	if code == ErrorServerError {
+		return ""
+	}
+	return err.Error()
+}
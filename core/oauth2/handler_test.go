@@ -0,0 +1,473 @@
+package oauth2_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/click33/sa-token-go/core/adapter"
+	"github.com/click33/sa-token-go/core/adapter/adaptertest"
+	"github.com/click33/sa-token-go/core/oauth2"
+)
+
+const (
+	handlerPrefix   = "test:"
+	handlerRedirect = "https://web.example.com/cb"
+)
+
+// newHandlerServer Creates a server with a web app, a SPA and two services | 创建包含Web应用、SPA与两个服务的服务器
+func newHandlerServer(t *testing.T) (*oauth2.OAuth2Server, *fakeStorage) {
+	t.Helper()
+
+	storage := newFakeStorage(redisString)
+	server := oauth2.NewOAuth2Server(storage, handlerPrefix)
+	clients := []*oauth2.Client{
+		{
+			ClientID:     "web",
+			ClientSecret: "web-secret",
+			RedirectURIs: []string{handlerRedirect},
+			Scopes:       []string{"read", "write"},
+			GrantTypes: []oauth2.GrantType{
+				oauth2.GrantTypeAuthorizationCode,
+				oauth2.GrantTypeRefreshToken,
+				oauth2.GrantTypeClientCredentials,
+			},
+		},
+		{ClientID: "spa", RedirectURIs: []string{handlerRedirect}, Public: true},
+		{ClientID: "api", ClientSecret: "api-secret", GrantTypes: []oauth2.GrantType{oauth2.GrantTypeClientCredentials}},
+		{ClientID: "other", ClientSecret: "other-secret", GrantTypes: []oauth2.GrantType{oauth2.GrantTypeClientCredentials}},
+	}
+	for _, client := range clients {
+		if err := server.RegisterClient(client); err != nil {
+			t.Fatalf("RegisterClient(%s) error = %v", client.ClientID, err)
+		}
+	}
+	return server, storage
+}
+
+// newHandler Creates endpoints whose consent hook is driven by the user query param | 创建由user查询参数驱动同意钩子的端点
+// user="" denies, user="login" renders the login page, any other value approves | 为空拒绝，为login时返回登录页，其他值同意
+func newHandler(server *oauth2.OAuth2Server) *oauth2.Handler {
+	return oauth2.NewHandler(server, func(ctx adapter.RequestContext, req *oauth2.AuthorizeRequest) *oauth2.ConsentResult {
+		switch user := ctx.GetQuery("user"); user {
+		case "":
+			return nil
+		case "login":
+			return &oauth2.ConsentResult{Response: oauth2.NewRedirectResponse("/login")}
+		default:
+			return &oauth2.ConsentResult{UserID: user, Approved: true}
+		}
+	})
+}
+
+// formContext Creates a form-encoded request, basic is "id:secret" or empty | 创建表单请求，basic为"id:secret"或为空
+func formContext(method string, form url.Values, basic string) adapter.RequestContext {
+	r := httptest.NewRequest(method, "/oauth2", strings.NewReader(form.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if basic != "" {
+		id, secret, _ := strings.Cut(basic, ":")
+		r.SetBasicAuth(id, secret)
+	}
+	return adaptertest.NewRequestContext(r)
+}
+
+// decodeBody Decodes JSON response body | 解析JSON响应体
+func decodeBody(t *testing.T, resp *oauth2.Response) map[string]any {
+	t.Helper()
+	body := map[string]any{}
+	if err := json.Unmarshal(resp.Body, &body); err != nil {
+		t.Fatalf("response body %q: %v", resp.Body, err)
+	}
+	return body
+}
+
+// checkError Checks status and RFC 6749 error code of a JSON error response | 校验JSON错误响应的状态码与错误码
+func checkError(t *testing.T, resp *oauth2.Response, status int, code string) {
+	t.Helper()
+	if resp.Status != status {
+		t.Fatalf("status = %d, want %d (body %s)", resp.Status, status, resp.Body)
+	}
+	if got := decodeBody(t, resp)["error"]; got != code {
+		t.Fatalf("error = %v, want %s", got, code)
+	}
+	if resp.Header["Content-Type"] != "application/json;charset=UTF-8" || resp.Header["Cache-Control"] != "no-store" {
+		t.Fatalf("error headers = %v", resp.Header)
+	}
+}
+
+func TestHandlerAuthorize(t *testing.T) {
+	server, _ := newHandlerServer(t)
+	handler := newHandler(server)
+	challenge := oauth2.S256Challenge(strings.Repeat("v", oauth2.CodeVerifierMinLength))
+
+	tests := []struct {
+		name     string
+		query    url.Values
+		status   int
+		error    string // Error code of a direct JSON answer | 直接JSON响应的错误码
+		redirect string // Error code redirected to the client, "code" on success | 重定向给客户端的错误码，成功时为code
+		location string // Exact Location of a rendered response | 渲染响应的Location
+	}{
+		{
+			name:   "unknown client is answered directly",
+			query:  url.Values{"client_id": {"nope"}, "redirect_uri": {handlerRedirect}, "response_type": {"code"}},
+			status: http.StatusBadRequest, error: oauth2.ErrorInvalidRequest,
+		},
+		{
+			name:   "unregistered redirect_uri is answered directly",
+			query:  url.Values{"client_id": {"web"}, "redirect_uri": {"https://evil.example.com/cb"}, "response_type": {"code"}},
+			status: http.StatusBadRequest, error: oauth2.ErrorInvalidRequest,
+		},
+		{
+			name:     "unsupported response_type",
+			query:    url.Values{"client_id": {"web"}, "redirect_uri": {handlerRedirect}, "response_type": {"token"}, "user": {"u1"}},
+			status:   http.StatusFound,
+			redirect: oauth2.ErrorUnsupportedResponseType,
+		},
+		{
+			name:     "scope outside the client scopes",
+			query:    url.Values{"client_id": {"web"}, "redirect_uri": {handlerRedirect}, "response_type": {"code"}, "scope": {"admin"}, "user": {"u1"}},
+			status:   http.StatusFound,
+			redirect: oauth2.ErrorInvalidScope,
+		},
+		{
+			name:     "public client without PKCE",
+			query:    url.Values{"client_id": {"spa"}, "redirect_uri": {handlerRedirect}, "response_type": {"code"}, "user": {"u1"}},
+			status:   http.StatusFound,
+			redirect: oauth2.ErrorInvalidRequest,
+		},
+		{
+			name:     "resource owner denies",
+			query:    url.Values{"client_id": {"web"}, "redirect_uri": {handlerRedirect}, "response_type": {"code"}},
+			status:   http.StatusFound,
+			redirect: oauth2.ErrorAccessDenied,
+		},
+		{
+			name:     "consent hook renders login page",
+			query:    url.Values{"client_id": {"web"}, "redirect_uri": {handlerRedirect}, "response_type": {"code"}, "user": {"login"}},
+			status:   http.StatusFound,
+			location: "/login",
+		},
+		{
+			name:     "approved",
+			query:    url.Values{"client_id": {"web"}, "redirect_uri": {handlerRedirect}, "response_type": {"code"}, "scope": {"read"}, "user": {"u1"}},
+			status:   http.StatusFound,
+			redirect: "code",
+		},
+		{
+			name: "approved public client with PKCE",
+			query: url.Values{"client_id": {"spa"}, "redirect_uri": {handlerRedirect}, "response_type": {"code"}, "user": {"u1"},
+				"code_challenge": {challenge}, "code_challenge_method": {oauth2.CodeChallengeMethodS256}},
+			status:   http.StatusFound,
+			redirect: "code",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.query.Set("state", "xyz")
+			r := httptest.NewRequest(http.MethodGet, oauth2.AuthorizePath+"?"+tt.query.Encode(), nil)
+			resp := handler.Authorize(adaptertest.NewRequestContext(r))
+
+			if tt.error != "" {
+				checkError(t, resp, tt.status, tt.error)
+				if resp.Header["Location"] != "" {
+					t.Fatalf("direct error must not redirect, Location = %s", resp.Header["Location"])
+				}
+				return
+			}
+			if resp.Status != tt.status {
+				t.Fatalf("status = %d, want %d (body %s)", resp.Status, tt.status, resp.Body)
+			}
+			if tt.location != "" {
+				if resp.Header["Location"] != tt.location {
+					t.Fatalf("Location = %s, want %s", resp.Header["Location"], tt.location)
+				}
+				return
+			}
+
+			location, err := url.Parse(resp.Header["Location"])
+			if err != nil || !strings.HasPrefix(location.String(), handlerRedirect+"?") {
+				t.Fatalf("Location = %s, want a redirect to %s", resp.Header["Location"], handlerRedirect)
+			}
+			params := location.Query()
+			if params.Get("state") != "xyz" {
+				t.Fatalf("state = %q, want xyz", params.Get("state"))
+			}
+			if tt.redirect == "code" {
+				if params.Get("code") == "" || params.Get("error") != "" {
+					t.Fatalf("redirect params = %v, want a code", params)
+				}
+			} else if params.Get("error") != tt.redirect {
+				t.Fatalf("error = %q, want %s", params.Get("error"), tt.redirect)
+			}
+		})
+	}
+}
+
+func TestHandlerAuthorizePost(t *testing.T) {
+	server, _ := newHandlerServer(t)
+	handler := newHandler(server)
+
+	form := url.Values{"client_id": {"web"}, "redirect_uri": {handlerRedirect}, "response_type": {"code"}}
+	r := httptest.NewRequest(http.MethodPost, oauth2.AuthorizePath+"?user=u1", strings.NewReader(form.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp := handler.Authorize(adaptertest.NewRequestContext(r))
+
+	if resp.Status != http.StatusFound || !strings.Contains(resp.Header["Location"], "code=") {
+		t.Fatalf("Authorize(POST) = %d %v", resp.Status, resp.Header)
+	}
+}
+
+func TestHandlerToken(t *testing.T) {
+	server, _ := newHandlerServer(t)
+	handler := newHandler(server)
+
+	clientCredentials := url.Values{"grant_type": {"client_credentials"}}
+	withForm := func(values url.Values, extra ...string) url.Values {
+		form := url.Values{}
+		for k, v := range values {
+			form[k] = v
+		}
+		for i := 0; i+1 < len(extra); i += 2 {
+			form.Set(extra[i], extra[i+1])
+		}
+		return form
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		form   url.Values
+		basic  string
+		status int
+		error  string
+	}{
+		{"form auth", http.MethodPost, withForm(clientCredentials, "client_id", "web", "client_secret", "web-secret"), "", http.StatusOK, ""},
+		{"basic auth", http.MethodPost, clientCredentials, "web:web-secret", http.StatusOK, ""},
+		{"basic auth with matching form client_id", http.MethodPost, withForm(clientCredentials, "client_id", "web"), "web:web-secret", http.StatusOK, ""},
+		{"basic and form secret", http.MethodPost, withForm(clientCredentials, "client_secret", "web-secret"), "web:web-secret", http.StatusBadRequest, oauth2.ErrorInvalidRequest},
+		{"basic and other form client_id", http.MethodPost, withForm(clientCredentials, "client_id", "api"), "web:web-secret", http.StatusBadRequest, oauth2.ErrorInvalidRequest},
+		{"wrong secret", http.MethodPost, clientCredentials, "web:nope", http.StatusUnauthorized, oauth2.ErrorInvalidClient},
+		{"unknown client", http.MethodPost, withForm(clientCredentials, "client_id", "nope"), "", http.StatusUnauthorized, oauth2.ErrorInvalidClient},
+		{"public client", http.MethodPost, withForm(clientCredentials, "client_id", "spa"), "", http.StatusBadRequest, oauth2.ErrorUnauthorizedClient},
+		{"scope outside the client scopes", http.MethodPost, withForm(clientCredentials, "scope", "admin"), "web:web-secret", http.StatusBadRequest, oauth2.ErrorInvalidScope},
+		{"grant not allowed", http.MethodPost, url.Values{"grant_type": {"refresh_token"}, "refresh_token": {"r"}}, "api:api-secret", http.StatusBadRequest, oauth2.ErrorUnauthorizedClient},
+		{"missing grant_type", http.MethodPost, url.Values{}, "web:web-secret", http.StatusBadRequest, oauth2.ErrorInvalidRequest},
+		{"unsupported grant_type", http.MethodPost, url.Values{"grant_type": {"implicit"}}, "web:web-secret", http.StatusBadRequest, oauth2.ErrorUnsupportedGrantType},
+		{"missing code", http.MethodPost, url.Values{"grant_type": {"authorization_code"}}, "web:web-secret", http.StatusBadRequest, oauth2.ErrorInvalidRequest},
+		{"unknown code", http.MethodPost, url.Values{"grant_type": {"authorization_code"}, "code": {"nope"}}, "web:web-secret", http.StatusBadRequest, oauth2.ErrorInvalidGrant},
+		{"missing refresh_token", http.MethodPost, url.Values{"grant_type": {"refresh_token"}}, "web:web-secret", http.StatusBadRequest, oauth2.ErrorInvalidRequest},
+		{"unknown refresh_token", http.MethodPost, url.Values{"grant_type": {"refresh_token"}, "refresh_token": {"nope"}}, "web:web-secret", http.StatusBadRequest, oauth2.ErrorInvalidGrant},
+		{"GET", http.MethodGet, clientCredentials, "web:web-secret", http.StatusMethodNotAllowed, oauth2.ErrorInvalidRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := handler.Token(formContext(tt.method, tt.form, tt.basic))
+			if tt.error != "" {
+				checkError(t, resp, tt.status, tt.error)
+				if tt.status == http.StatusUnauthorized && resp.Header["WWW-Authenticate"] == "" {
+					t.Fatal("invalid_client must carry a Basic challenge")
+				}
+				if tt.status == http.StatusMethodNotAllowed && resp.Header["Allow"] != http.MethodPost {
+					t.Fatalf("Allow = %q, want POST", resp.Header["Allow"])
+				}
+				return
+			}
+			if resp.Status != tt.status {
+				t.Fatalf("status = %d, want %d (body %s)", resp.Status, tt.status, resp.Body)
+			}
+			body := decodeBody(t, resp)
+			if body["access_token"] == "" || body["token_type"] != oauth2.TokenTypeBearer || body["refresh_token"] != nil {
+				t.Fatalf("token response = %v", body)
+			}
+		})
+	}
+}
+
+func TestHandlerTokenCodeAndRefresh(t *testing.T) {
+	server, _ := newHandlerServer(t)
+	handler := newHandler(server)
+
+	code, err := server.GenerateAuthorizationCode("web", handlerRedirect, "u1", []string{"read", "write"})
+	if err != nil {
+		t.Fatalf("GenerateAuthorizationCode() error = %v", err)
+	}
+	exchange := url.Values{"grant_type": {"authorization_code"}, "code": {code.Code}, "redirect_uri": {handlerRedirect}}
+
+	if resp := handler.Token(formContext(http.MethodPost, exchange, "api:api-secret")); resp.Status != http.StatusBadRequest {
+		t.Fatalf("exchange by another client status = %d, body %s", resp.Status, resp.Body)
+	}
+	resp := handler.Token(formContext(http.MethodPost, exchange, "web:web-secret"))
+	if resp.Status != http.StatusOK {
+		t.Fatalf("exchange status = %d, body %s", resp.Status, resp.Body)
+	}
+	body := decodeBody(t, resp)
+	refreshToken, _ := body["refresh_token"].(string)
+	if refreshToken == "" || body["scope"] != "read write" {
+		t.Fatalf("exchange response = %v", body)
+	}
+	checkError(t, handler.Token(formContext(http.MethodPost, exchange, "web:web-secret")), http.StatusBadRequest, oauth2.ErrorInvalidGrant)
+
+	refresh := url.Values{"grant_type": {"refresh_token"}, "refresh_token": {refreshToken}, "scope": {"read"}}
+	resp = handler.Token(formContext(http.MethodPost, refresh, "web:web-secret"))
+	if resp.Status != http.StatusOK || decodeBody(t, resp)["scope"] != "read" {
+		t.Fatalf("refresh = %d %s", resp.Status, resp.Body)
+	}
+	checkError(t, handler.Token(formContext(http.MethodPost, refresh, "web:web-secret")), http.StatusBadRequest, oauth2.ErrorInvalidGrant)
+}
+
+func TestHandlerRevoke(t *testing.T) {
+	server, _ := newHandlerServer(t)
+	handler := newHandler(server)
+
+	issue := func() *oauth2.AccessToken {
+		code, err := server.GenerateAuthorizationCode("web", handlerRedirect, "u1", nil)
+		if err != nil {
+			t.Fatalf("GenerateAuthorizationCode() error = %v", err)
+		}
+		token, err := server.ExchangeCodeForToken(code.Code, "web", "web-secret", handlerRedirect)
+		if err != nil {
+			t.Fatalf("ExchangeCodeForToken() error = %v", err)
+		}
+		return token
+	}
+	revoke := func(basic string, form url.Values) *oauth2.Response {
+		return handler.Revoke(formContext(http.MethodPost, form, basic))
+	}
+	active := func(token string) bool {
+		_, err := server.ValidateAccessToken(token)
+		return err == nil
+	}
+
+	token := issue()
+	checkError(t, revoke("web:nope", url.Values{"token": {token.Token}}), http.StatusUnauthorized, oauth2.ErrorInvalidClient)
+	checkError(t, revoke("web:web-secret", url.Values{}), http.StatusBadRequest, oauth2.ErrorInvalidRequest)
+	checkError(t, handler.Revoke(formContext(http.MethodGet, url.Values{"token": {token.Token}}, "web:web-secret")), http.StatusMethodNotAllowed, oauth2.ErrorInvalidRequest)
+
+	// Tokens of other clients and unknown tokens answer 200 and stay untouched | 其他客户端的令牌与未知令牌返回200且保持不变
+	for _, form := range []url.Values{{"token": {token.Token}}, {"token": {token.RefreshToken}, "token_type_hint": {"refresh_token"}}} {
+		if resp := revoke("other:other-secret", form); resp.Status != http.StatusOK {
+			t.Fatalf("revoke by another client status = %d", resp.Status)
+		}
+	}
+	if !active(token.Token) {
+		t.Fatal("another client revoked the token")
+	}
+	if resp := revoke("web:web-secret", url.Values{"token": {"unknown"}}); resp.Status != http.StatusOK {
+		t.Fatalf("revoke unknown token status = %d", resp.Status)
+	}
+
+	if resp := revoke("web:web-secret", url.Values{"token": {token.Token}}); resp.Status != http.StatusOK {
+		t.Fatalf("revoke status = %d", resp.Status)
+	}
+	if active(token.Token) {
+		t.Fatal("access token still valid after revoke")
+	}
+	if _, err := server.RefreshAccessToken(token.RefreshToken, "web", "web-secret"); err == nil {
+		t.Fatal("refresh token still valid after revoking its access token")
+	}
+
+	// Revoking a refresh token also revokes its access token | 撤销刷新令牌时同时撤销其访问令牌
+	token = issue()
+	if resp := revoke("web:web-secret", url.Values{"token": {token.RefreshToken}}); resp.Status != http.StatusOK {
+		t.Fatalf("revoke refresh token status = %d", resp.Status)
+	}
+	if active(token.Token) {
+		t.Fatal("access token still valid after revoking its refresh token")
+	}
+}
+
+func TestHandlerIntrospect(t *testing.T) {
+	server, storage := newHandlerServer(t)
+	handler := newHandler(server)
+
+	code, err := server.GenerateAuthorizationCode("web", handlerRedirect, "u1", []string{"read"})
+	if err != nil {
+		t.Fatalf("GenerateAuthorizationCode() error = %v", err)
+	}
+	token, err := server.ExchangeCodeForToken(code.Code, "web", "web-secret", handlerRedirect)
+	if err != nil {
+		t.Fatalf("ExchangeCodeForToken() error = %v", err)
+	}
+	service, err := server.ClientCredentialsToken("other", "other-secret", nil)
+	if err != nil {
+		t.Fatalf("ClientCredentialsToken() error = %v", err)
+	}
+
+	// An access token issued a day ago has expired | 一天前签发的访问令牌已过期
+	expired, err := server.ClientCredentialsToken("web", "web-secret", nil)
+	if err != nil {
+		t.Fatalf("ClientCredentialsToken() error = %v", err)
+	}
+	expired.CreateTime = time.Now().Add(-24 * time.Hour).Unix()
+	data, _ := json.Marshal(expired)
+	_ = storage.Set(handlerPrefix+oauth2.TokenKeySuffix+expired.Token, string(data), 0)
+
+	introspect := func(basic string, form url.Values) *oauth2.Response {
+		return handler.Introspect(formContext(http.MethodPost, form, basic))
+	}
+
+	checkError(t, introspect("web:nope", url.Values{"token": {token.Token}}), http.StatusUnauthorized, oauth2.ErrorInvalidClient)
+	checkError(t, introspect("", url.Values{"client_id": {"spa"}, "token": {token.Token}}), http.StatusUnauthorized, oauth2.ErrorInvalidClient)
+	checkError(t, introspect("web:web-secret", url.Values{}), http.StatusBadRequest, oauth2.ErrorInvalidRequest)
+
+	tests := []struct {
+		name   string
+		basic  string
+		form   url.Values
+		active bool
+		exp    time.Duration // Expected exp - iat of an active token | 有效令牌的exp与iat之差
+	}{
+		{"own access token", "web:web-secret", url.Values{"token": {token.Token}}, true, oauth2.DefaultTokenExpiration},
+		{"own refresh token", "web:web-secret", url.Values{"token": {token.RefreshToken}}, true, oauth2.DefaultRefreshTTL},
+		{"own refresh token with hint", "web:web-secret", url.Values{"token": {token.RefreshToken}, "token_type_hint": {"refresh_token"}}, true, oauth2.DefaultRefreshTTL},
+		{"access token with refresh hint", "web:web-secret", url.Values{"token": {token.Token}, "token_type_hint": {"refresh_token"}}, true, oauth2.DefaultTokenExpiration},
+		{"expired access token", "web:web-secret", url.Values{"token": {expired.Token}}, false, 0},
+		{"unknown token", "web:web-secret", url.Values{"token": {"unknown"}}, false, 0},
+		{"token of another client", "other:other-secret", url.Values{"token": {token.Token}}, false, 0},
+		{"refresh token of another client", "other:other-secret", url.Values{"token": {token.RefreshToken}}, false, 0},
+		{"web cannot see other tokens", "web:web-secret", url.Values{"token": {service.Token}}, false, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := introspect(tt.basic, tt.form)
+			if resp.Status != http.StatusOK {
+				t.Fatalf("status = %d, body %s", resp.Status, resp.Body)
+			}
+			body := decodeBody(t, resp)
+			if body["active"] != tt.active {
+				t.Fatalf("active = %v, want %v (body %s)", body["active"], tt.active, resp.Body)
+			}
+			if !tt.active {
+				if len(body) != 1 {
+					t.Fatalf("inactive response leaks fields: %s", resp.Body)
+				}
+				return
+			}
+			if body["client_id"] != "web" || body["sub"] != "u1" || body["scope"] != "read" || body["token_type"] != oauth2.TokenTypeBearer {
+				t.Fatalf("introspection = %s", resp.Body)
+			}
+			exp, _ := body["exp"].(float64)
+			iat, _ := body["iat"].(float64)
+			if int64(exp-iat) != int64(tt.exp.Seconds()) {
+				t.Fatalf("exp - iat = %v, want %v", exp-iat, tt.exp.Seconds())
+			}
+		})
+	}
+
+	// Resource servers may be allowed to introspect every client | 可允许资源服务器内省所有客户端的令牌
+	handler.AllowIntrospection("other")
+	if body := decodeBody(t, introspect("other:other-secret", url.Values{"token": {token.Token}})); body["active"] != true {
+		t.Fatalf("allowed introspection = %v", body)
+	}
+	if body := decodeBody(t, introspect("web:web-secret", url.Values{"token": {service.Token}})); body["active"] != false {
+		t.Fatalf("introspection by a client that was not allowed = %v", body)
+	}
+}
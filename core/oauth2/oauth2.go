@@ -2,12 +2,14 @@ package oauth2
 
 import (
 	"crypto/rand"
+	"encoding"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
-	"sync"
 	"time"
 
 	"github.com/click33/sa-token-go/core/adapter"
+	"github.com/click33/sa-token-go/core/utils"
 )
 
 // OAuth2 Authorization Code Flow Implementation
@@ -17,9 +19,13 @@ import (
 // 1. RegisterClient() - Register OAuth2 client | 注册OAuth2客户端
 // 2. GenerateAuthorizationCode() - User authorizes, get code | 用户授权，获取授权码
 // 3. ExchangeCodeForToken() - Exchange code for access token | 用授权码换取访问令牌
+//    Public clients use the WithPKCE variants, see pkce.go | 公开客户端使用WithPKCE系列方法，见pkce.go
+//    ClientCredentialsToken()/PasswordToken() - Other grants, see grants.go | 其他授权类型，见grants.go
 // 4. ValidateAccessToken() - Validate access token | 验证访问令牌
 // 5. RefreshAccessToken() - Use refresh token to get new token | 用刷新令牌获取新令牌
 //
+// HTTP endpoints for these steps are in handler.go | 这些步骤对应的HTTP端点见handler.go
+//
 // Usage | 用法:
 //   server := oauth2.NewOAuth2Server(storage)
 //   server.RegisterClient(&oauth2.Client{...})
@@ -55,6 +61,7 @@ var (
 	ErrRedirectURIMismatch      = fmt.Errorf("redirect_uri mismatch")
 	ErrInvalidAccessToken       = fmt.Errorf("invalid access token")
 	ErrInvalidTokenData         = fmt.Errorf("invalid token data")
+	ErrInvalidRefreshToken      = fmt.Errorf("invalid refresh token")
 )
 
 // GrantType OAuth2 grant type | OAuth2授权类型
@@ -69,44 +76,84 @@ const (
 
 // Client OAuth2 client configuration | OAuth2客户端配置
 type Client struct {
-	ClientID     string      // Client ID | 客户端ID
-	ClientSecret string      // Client secret | 客户端密钥
-	RedirectURIs []string    // Allowed redirect URIs | 允许的回调URI
-	GrantTypes   []GrantType // Allowed grant types | 允许的授权类型
-	Scopes       []string    // Allowed scopes | 允许的权限范围
+	ClientID     string      `json:"clientID"`               // Client ID | 客户端ID
+	ClientSecret string      `json:"clientSecret,omitempty"` // Plain secret, hashed into SecretHash by RegisterClient | 明文密钥，RegisterClient会将其哈希为SecretHash
+	RedirectURIs []string    `json:"redirectURIs"`           // Allowed redirect URIs | 允许的回调URI
+	GrantTypes   []GrantType `json:"grantTypes"`             // Allowed grant types | 允许的授权类型
+	Scopes       []string    `json:"scopes"`                 // Allowed scopes | 允许的权限范围
+	Public       bool        `json:"public"`                 // Public client (SPA, mobile) without secret, implies RequirePKCE | 无密钥的公开客户端（SPA、移动端），隐含RequirePKCE
+	RequirePKCE  bool        `json:"requirePKCE"`            // Reject authorization requests without code_challenge | 拒绝不带code_challenge的授权请求
+
+	// Metadata | 元数据
+	Name       string   `json:"name,omitempty"`      // Display name | 显示名称
+	LogoURI    string   `json:"logoURI,omitempty"`   // Logo URL | 图标URL
+	ClientURI  string   `json:"clientURI,omitempty"` // Home page URL | 主页URL
+	Contacts   []string `json:"contacts,omitempty"`  // Contact emails | 联系人邮箱
+	CreateTime int64    `json:"createTime"`          // Registration time | 注册时间
+
+	// Secret state, see client_secret.go | 密钥状态，见client_secret.go
+	SecretHash              string `json:"secretHash,omitempty"`              // Current secret hash | 当前密钥哈希
+	PreviousSecretHash      string `json:"previousSecretHash,omitempty"`      // Rotated-out secret hash | 轮换前的密钥哈希
+	PreviousSecretExpiresAt int64  `json:"previousSecretExpiresAt,omitempty"` // End of previous secret grace period | 旧密钥宽限期结束时间
 }
 
 // AuthorizationCode authorization code information | 授权码信息
 type AuthorizationCode struct {
-	Code        string   // Authorization code | 授权码
-	ClientID    string   // Client ID | 客户端ID
-	RedirectURI string   // Redirect URI | 回调URI
-	UserID      string   // User ID | 用户ID
-	Scopes      []string // Requested scopes | 请求的权限范围
-	CreateTime  int64    // Creation time | 创建时间
-	ExpiresIn   int64    // Expiration time in seconds | 过期时间（秒）
-	Used        bool     // Whether used | 是否已使用
+	Code                string   `json:"code"`                          // Authorization code | 授权码
+	ClientID            string   `json:"clientID"`                      // Client ID | 客户端ID
+	RedirectURI         string   `json:"redirectURI"`                   // Redirect URI | 回调URI
+	UserID              string   `json:"userID"`                        // User ID | 用户ID
+	Scopes              []string `json:"scopes"`                        // Requested scopes | 请求的权限范围
+	CreateTime          int64    `json:"createTime"`                    // Creation time | 创建时间
+	ExpiresIn           int64    `json:"expiresIn"`                     // Expiration time in seconds | 过期时间（秒）
+	Used                bool     `json:"used"`                          // Whether used | 是否已使用
+	CodeChallenge       string   `json:"codeChallenge,omitempty"`       // PKCE code_challenge | PKCE挑战值
+	CodeChallengeMethod string   `json:"codeChallengeMethod,omitempty"` // PKCE method, S256 or plain | PKCE挑战值计算方式（S256或plain）
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler for Redis storage | 实现encoding.BinaryMarshaler接口用于Redis存储
+func (c *AuthorizationCode) MarshalBinary() ([]byte, error) {
+	return json.Marshal(c)
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler for Redis storage | 实现encoding.BinaryUnmarshaler接口用于Redis存储
+func (c *AuthorizationCode) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, c)
 }
 
 // AccessToken access token information | 访问令牌信息
 type AccessToken struct {
-	Token        string   // Access token | 访问令牌
-	TokenType    string   // Token type (Bearer) | 令牌类型（Bearer）
-	ExpiresIn    int64    // Expiration time in seconds | 过期时间（秒）
-	RefreshToken string   // Refresh token | 刷新令牌
-	Scopes       []string // Granted scopes | 授予的权限范围
-	UserID       string   // User ID | 用户ID
-	ClientID     string   // Client ID | 客户端ID
+	Token        string   `json:"token"`                  // Access token | 访问令牌
+	TokenType    string   `json:"tokenType"`              // Token type (Bearer) | 令牌类型（Bearer）
+	ExpiresIn    int64    `json:"expiresIn"`              // Expiration time in seconds | 过期时间（秒）
+	RefreshToken string   `json:"refreshToken,omitempty"` // Refresh token | 刷新令牌
+	Scopes       []string `json:"scopes"`                 // Granted scopes | 授予的权限范围
+	UserID       string   `json:"userID,omitempty"`       // User ID, empty for client_credentials | 用户ID（client_credentials为空）
+	ClientID     string   `json:"clientID"`               // Client ID | 客户端ID
+	CreateTime   int64    `json:"createTime"`             // Creation time | 创建时间
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler for Redis storage | 实现encoding.BinaryMarshaler接口用于Redis存储
+func (t *AccessToken) MarshalBinary() ([]byte, error) {
+	return json.Marshal(t)
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler for Redis storage | 实现encoding.BinaryUnmarshaler接口用于Redis存储
+func (t *AccessToken) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, t)
 }
 
 // OAuth2Server OAuth2 authorization server | OAuth2授权服务器
 type OAuth2Server struct {
 	storage         adapter.Storage
-	keyPrefix       string // Configurable prefix | 可配置的前缀
-	clients         map[string]*Client
-	clientsMu       sync.RWMutex  // Clients map lock | 客户端映射锁
+	keyPrefix       string        // Configurable prefix | 可配置的前缀
+	clients         ClientStore   // Client registry | 客户端注册表
+	secretHasher    SecretHasher  // Client secret hasher | 客户端密钥哈希器
 	codeExpiration  time.Duration // Authorization code expiration (10min) | 授权码过期时间（10分钟）
 	tokenExpiration time.Duration // Access token expiration (2h) | 访问令牌过期时间（2小时）
+
+	userAuthenticator  UserAuthenticator   // Password grant credential check | 密码模式的凭证校验
+	registrationPolicy *RegistrationPolicy // Dynamic client registration rules | 动态客户端注册规则
 }
 
 // NewOAuth2Server Creates a new OAuth2 server | 创建新的OAuth2服务器
@@ -115,47 +162,69 @@ func NewOAuth2Server(storage adapter.Storage, prefix string) *OAuth2Server {
 	return &OAuth2Server{
 		storage:         storage,
 		keyPrefix:       prefix,
-		clients:         make(map[string]*Client),
+		clients:         NewMemoryClientStore(),
+		secretHasher:    SHA256SecretHasher{},
 		codeExpiration:  DefaultCodeExpiration,
 		tokenExpiration: DefaultTokenExpiration,
 	}
 }
 
-// RegisterClient Registers an OAuth2 client | 注册OAuth2客户端
+// WithStorage Returns a copy using storage, e.g. one bound to a request context | 返回使用storage的副本，如绑定请求上下文的存储
+// The copy shares client store and settings with s, configure s itself | 副本与s共享客户端注册表与设置，应在s本身上进行配置
+func (s *OAuth2Server) WithStorage(storage adapter.Storage) *OAuth2Server {
+	copied := *s
+	copied.storage = storage
+	return &copied
+}
+
+// SetClientStore Sets client registry, share one store to share clients between instances | 设置客户端注册表，多实例共享同一存储即可共享客户端
+func (s *OAuth2Server) SetClientStore(store ClientStore) {
+	s.clients = store
+}
+
+// RegisterClient Registers or replaces an OAuth2 client, ClientSecret is stored hashed | 注册或替换OAuth2客户端，ClientSecret以哈希形式存储
+// client itself is not modified | 不会修改传入的client
 func (s *OAuth2Server) RegisterClient(client *Client) error {
 	if client == nil || client.ClientID == "" {
 		return fmt.Errorf("invalid client: clientID is required")
 	}
 
-	s.clientsMu.Lock()
-	defer s.clientsMu.Unlock()
-
-	s.clients[client.ClientID] = client
-	return nil
+	stored := client.clone()
+	if stored.ClientSecret != "" {
+		hash, err := s.secretHasher.Hash(stored.ClientSecret)
+		if err != nil {
+			return fmt.Errorf("failed to hash client secret: %w", err)
+		}
+		stored.SecretHash = hash
+		stored.ClientSecret = ""
+	}
+	if stored.CreateTime == 0 {
+		stored.CreateTime = time.Now().Unix()
+	}
+	return s.clients.SaveClient(stored)
 }
 
 // UnregisterClient Unregisters an OAuth2 client | 注销OAuth2客户端
 func (s *OAuth2Server) UnregisterClient(clientID string) {
-	s.clientsMu.Lock()
-	defer s.clientsMu.Unlock()
-
-	delete(s.clients, clientID)
+	_ = s.clients.DeleteClient(clientID)
 }
 
 // GetClient Gets client by ID | 根据ID获取客户端
 func (s *OAuth2Server) GetClient(clientID string) (*Client, error) {
-	s.clientsMu.RLock()
-	defer s.clientsMu.RUnlock()
-
-	client, exists := s.clients[clientID]
-	if !exists {
+	if clientID == "" {
 		return nil, ErrClientNotFound
 	}
-	return client, nil
+	return s.clients.GetClient(clientID)
 }
 
 // GenerateAuthorizationCode Generates authorization code | 生成授权码
 func (s *OAuth2Server) GenerateAuthorizationCode(clientID, redirectURI, userID string, scopes []string) (*AuthorizationCode, error) {
+	return s.GenerateAuthorizationCodeWithPKCE(clientID, redirectURI, userID, scopes, "", "")
+}
+
+// GenerateAuthorizationCodeWithPKCE Generates authorization code bound to a PKCE challenge | 生成绑定PKCE挑战值的授权码
+// Empty method means plain as in RFC 7636 | 方式为空时按RFC 7636视为plain
+func (s *OAuth2Server) GenerateAuthorizationCodeWithPKCE(clientID, redirectURI, userID string, scopes []string, codeChallenge, codeChallengeMethod string) (*AuthorizationCode, error) {
 	if userID == "" {
 		return nil, fmt.Errorf("userID cannot be empty")
 	}
@@ -169,6 +238,18 @@ func (s *OAuth2Server) GenerateAuthorizationCode(clientID, redirectURI, userID s
 	if !s.isValidRedirectURI(client, redirectURI) {
 		return nil, ErrInvalidRedirectURI
 	}
+	if err := checkGrantType(client, GrantTypeAuthorizationCode); err != nil {
+		return nil, err
+	}
+	granted, err := resolveScopes(client, scopes)
+	if err != nil {
+		return nil, err
+	}
+
+	method, err := normalizeChallenge(client, codeChallenge, codeChallengeMethod)
+	if err != nil {
+		return nil, err
+	}
 
 	// Generate code | 生成授权码
 	codeBytes := make([]byte, CodeLength)
@@ -178,18 +259,20 @@ func (s *OAuth2Server) GenerateAuthorizationCode(clientID, redirectURI, userID s
 	code := hex.EncodeToString(codeBytes)
 
 	authCode := &AuthorizationCode{
-		Code:        code,
-		ClientID:    clientID,
-		RedirectURI: redirectURI,
-		UserID:      userID,
-		Scopes:      scopes,
-		CreateTime:  time.Now().Unix(),
-		ExpiresIn:   int64(s.codeExpiration.Seconds()),
-		Used:        false,
+		Code:                code,
+		ClientID:            clientID,
+		RedirectURI:         redirectURI,
+		UserID:              userID,
+		Scopes:              granted,
+		CreateTime:          time.Now().Unix(),
+		ExpiresIn:           int64(s.codeExpiration.Seconds()),
+		Used:                false,
+		CodeChallenge:       codeChallenge,
+		CodeChallengeMethod: method,
 	}
 
 	key := s.getCodeKey(code)
-	if err := s.storage.Set(key, authCode, s.codeExpiration); err != nil {
+	if err := s.setValue(key, authCode, s.codeExpiration); err != nil {
 		return nil, fmt.Errorf("failed to store authorization code: %w", err)
 	}
 
@@ -208,14 +291,19 @@ func (s *OAuth2Server) isValidRedirectURI(client *Client, redirectURI string) bo
 
 // ExchangeCodeForToken Exchanges authorization code for access token | 用授权码换取访问令牌
 func (s *OAuth2Server) ExchangeCodeForToken(code, clientID, clientSecret, redirectURI string) (*AccessToken, error) {
+	return s.ExchangeCodeForTokenWithPKCE(code, clientID, clientSecret, redirectURI, "")
+}
+
+// ExchangeCodeForTokenWithPKCE Exchanges authorization code checking PKCE code_verifier | 用授权码换取访问令牌并校验PKCE校验码
+// Public clients pass an empty clientSecret | 公开客户端传入空的clientSecret
+func (s *OAuth2Server) ExchangeCodeForTokenWithPKCE(code, clientID, clientSecret, redirectURI, codeVerifier string) (*AccessToken, error) {
 	// Verify client credentials | 验证客户端凭证
-	client, err := s.GetClient(clientID)
+	client, err := s.authenticateClient(clientID, clientSecret)
 	if err != nil {
 		return nil, err
 	}
-
-	if client.ClientSecret != clientSecret {
-		return nil, ErrInvalidClientCredentials
+	if err := checkGrantType(client, GrantTypeAuthorizationCode); err != nil {
+		return nil, err
 	}
 
 	// Get authorization code | 获取授权码
@@ -225,8 +313,8 @@ func (s *OAuth2Server) ExchangeCodeForToken(code, clientID, clientSecret, redire
 		return nil, ErrInvalidAuthCode
 	}
 
-	authCode, ok := data.(*AuthorizationCode)
-	if !ok {
+	authCode := &AuthorizationCode{}
+	if err := decodeValue(data, authCode); err != nil {
 		return nil, fmt.Errorf("invalid code data")
 	}
 
@@ -248,15 +336,32 @@ func (s *OAuth2Server) ExchangeCodeForToken(code, clientID, clientSecret, redire
 		return nil, ErrAuthCodeExpired
 	}
 
-	// Mark code as used | 标记为已使用
+	if err := verifyCodeVerifier(authCode, codeVerifier); err != nil {
+		return nil, err
+	}
+	// Codes issued before PKCE became required are rejected too | PKCE启用前签发的授权码同样拒绝
+	if authCode.CodeChallenge == "" && client.requiresPKCE() {
+		return nil, ErrPKCERequired
+	}
+
+	// One-time use: only the caller that deletes the unused code wins | 一次性使用：只有删除未使用授权码的调用者有效
+	consumed, err := adapter.GetDel(s.storage, key)
+	if err != nil || consumed == nil {
+		return nil, ErrAuthCodeUsed
+	}
+	if current := (&AuthorizationCode{}); decodeValue(consumed, current) != nil || current.Used {
+		return nil, ErrAuthCodeUsed
+	}
+
+	// Keep a used marker so replays report ErrAuthCodeUsed | 保留已使用标记，重放时返回ErrAuthCodeUsed
 	authCode.Used = true
-	s.storage.Set(key, authCode, time.Minute)
+	s.setValue(key, authCode, time.Minute)
 
-	return s.generateAccessToken(authCode.UserID, authCode.ClientID, authCode.Scopes)
+	return s.generateAccessToken(authCode.UserID, authCode.ClientID, authCode.Scopes, true)
 }
 
-// generateAccessToken Generates access token and refresh token | 生成访问令牌和刷新令牌
-func (s *OAuth2Server) generateAccessToken(userID, clientID string, scopes []string) (*AccessToken, error) {
+// generateAccessToken Generates access token and optionally refresh token | 生成访问令牌及可选的刷新令牌
+func (s *OAuth2Server) generateAccessToken(userID, clientID string, scopes []string, withRefresh bool) (*AccessToken, error) {
 	// Generate access token | 生成访问令牌
 	tokenBytes := make([]byte, AccessTokenLength)
 	if _, err := rand.Read(tokenBytes); err != nil {
@@ -265,11 +370,14 @@ func (s *OAuth2Server) generateAccessToken(userID, clientID string, scopes []str
 	accessToken := hex.EncodeToString(tokenBytes)
 
 	// Generate refresh token | 生成刷新令牌
-	refreshBytes := make([]byte, RefreshTokenLength)
-	if _, err := rand.Read(refreshBytes); err != nil {
-		return nil, fmt.Errorf("failed to generate refresh token: %w", err)
+	var refreshToken string
+	if withRefresh {
+		refreshBytes := make([]byte, RefreshTokenLength)
+		if _, err := rand.Read(refreshBytes); err != nil {
+			return nil, fmt.Errorf("failed to generate refresh token: %w", err)
+		}
+		refreshToken = hex.EncodeToString(refreshBytes)
 	}
-	refreshToken := hex.EncodeToString(refreshBytes)
 
 	token := &AccessToken{
 		Token:        accessToken,
@@ -279,19 +387,19 @@ func (s *OAuth2Server) generateAccessToken(userID, clientID string, scopes []str
 		Scopes:       scopes,
 		UserID:       userID,
 		ClientID:     clientID,
+		CreateTime:   time.Now().Unix(),
 	}
 
-	tokenKey := s.getTokenKey(accessToken)
-	refreshKey := s.getRefreshKey(refreshToken)
-
 	// Store access token | 存储访问令牌
-	if err := s.storage.Set(tokenKey, token, s.tokenExpiration); err != nil {
+	if err := s.setValue(s.getTokenKey(accessToken), token, s.tokenExpiration); err != nil {
 		return nil, fmt.Errorf("failed to store access token: %w", err)
 	}
 
 	// Store refresh token | 存储刷新令牌
-	if err := s.storage.Set(refreshKey, token, DefaultRefreshTTL); err != nil {
-		return nil, fmt.Errorf("failed to store refresh token: %w", err)
+	if withRefresh {
+		if err := s.setValue(s.getRefreshKey(refreshToken), token, DefaultRefreshTTL); err != nil {
+			return nil, fmt.Errorf("failed to store refresh token: %w", err)
+		}
 	}
 
 	return token, nil
@@ -309,8 +417,8 @@ func (s *OAuth2Server) ValidateAccessToken(tokenString string) (*AccessToken, er
 		return nil, ErrInvalidAccessToken
 	}
 
-	token, ok := data.(*AccessToken)
-	if !ok {
+	token := &AccessToken{}
+	if err := decodeValue(data, token); err != nil {
 		return nil, ErrInvalidTokenData
 	}
 
@@ -319,37 +427,53 @@ func (s *OAuth2Server) ValidateAccessToken(tokenString string) (*AccessToken, er
 
 // RefreshAccessToken Refreshes access token using refresh token | 使用刷新令牌刷新访问令牌
 func (s *OAuth2Server) RefreshAccessToken(refreshToken, clientID, clientSecret string) (*AccessToken, error) {
+	return s.RefreshAccessTokenWithScopes(refreshToken, clientID, clientSecret, nil)
+}
+
+// RefreshAccessTokenWithScopes Refreshes access token narrowing its scopes, nil keeps them | 刷新访问令牌并缩小权限范围，nil保持原范围
+// Refresh tokens rotate: each one is accepted once and replaced by the returned one | 刷新令牌会轮换：每个只能使用一次，并由返回的新令牌替代
+func (s *OAuth2Server) RefreshAccessTokenWithScopes(refreshToken, clientID, clientSecret string, scopes []string) (*AccessToken, error) {
 	// Verify client credentials | 验证客户端凭证
-	client, err := s.GetClient(clientID)
+	client, err := s.authenticateClient(clientID, clientSecret)
 	if err != nil {
 		return nil, err
 	}
-
-	if client.ClientSecret != clientSecret {
-		return nil, ErrInvalidClientCredentials
+	if err := checkGrantType(client, GrantTypeRefreshToken); err != nil {
+		return nil, err
 	}
 
 	// Get refresh token | 获取刷新令牌
-	key := s.getRefreshKey(refreshToken)
-	data, err := s.storage.Get(key)
-	if err != nil || data == nil {
-		return nil, fmt.Errorf("invalid refresh token")
-	}
-
-	oldToken, ok := data.(*AccessToken)
-	if !ok {
-		return nil, fmt.Errorf("invalid refresh token data")
+	oldToken, err := s.getRefreshToken(refreshToken)
+	if err != nil {
+		return nil, err
 	}
 
 	if oldToken.ClientID != clientID {
 		return nil, ErrClientMismatch
 	}
 
+	// Refresh may only narrow the original grant | 刷新只能缩小原有授权范围
+	granted := oldToken.Scopes
+	if len(scopes) > 0 {
+		if len(oldToken.Scopes) == 0 {
+			return nil, ErrInvalidScope
+		}
+		if granted, err = narrowScopes(oldToken.Scopes, scopes); err != nil {
+			return nil, err
+		}
+	}
+
+	// Consume the old refresh token so a replayed or stolen copy fails, public clients have no secret to stop it
+	// 消费旧的刷新令牌，使重放或被盗的副本失效（公开客户端没有密钥可拦截）
+	if _, err := adapter.GetDel(s.storage, s.getRefreshKey(refreshToken)); err != nil {
+		return nil, ErrInvalidRefreshToken
+	}
+
 	// Delete old access token | 删除旧的访问令牌
 	oldTokenKey := s.getTokenKey(oldToken.Token)
 	s.storage.Delete(oldTokenKey)
 
-	return s.generateAccessToken(oldToken.UserID, oldToken.ClientID, oldToken.Scopes)
+	return s.generateAccessToken(oldToken.UserID, oldToken.ClientID, granted, true)
 }
 
 // RevokeToken Revokes access token and its refresh token | 撤销访问令牌及其刷新令牌
@@ -365,7 +489,8 @@ func (s *OAuth2Server) RevokeToken(tokenString string) error {
 	}
 
 	// Revoke refresh token if exists | 如果存在则撤销刷新令牌
-	if token, ok := data.(*AccessToken); ok && token.RefreshToken != "" {
+	token := &AccessToken{}
+	if decodeValue(data, token) == nil && token.RefreshToken != "" {
 		refreshKey := s.getRefreshKey(token.RefreshToken)
 		s.storage.Delete(refreshKey)
 	}
@@ -373,8 +498,73 @@ func (s *OAuth2Server) RevokeToken(tokenString string) error {
 	return s.storage.Delete(key)
 }
 
+// RevokeRefreshToken Revokes refresh token and its access token | 撤销刷新令牌及其访问令牌
+func (s *OAuth2Server) RevokeRefreshToken(refreshToken string) error {
+	if refreshToken == "" {
+		return nil
+	}
+
+	token, err := s.getRefreshToken(refreshToken)
+	if err == nil {
+		s.storage.Delete(s.getTokenKey(token.Token))
+	}
+
+	return s.storage.Delete(s.getRefreshKey(refreshToken))
+}
+
 // ============ Helper Methods | 辅助方法 ============
 
+// getRefreshToken Gets token data stored under refresh token | 获取刷新令牌下存储的令牌数据
+func (s *OAuth2Server) getRefreshToken(refreshToken string) (*AccessToken, error) {
+	if refreshToken == "" {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	data, err := s.storage.Get(s.getRefreshKey(refreshToken))
+	if err != nil || data == nil {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	token := &AccessToken{}
+	if err := decodeValue(data, token); err != nil {
+		return nil, ErrInvalidTokenData
+	}
+	return token, nil
+}
+
+// setValue Stores serialized form so that every storage backend round-trips it | 存储序列化结果，保证各存储后端均可还原
+func (s *OAuth2Server) setValue(key string, value encoding.BinaryMarshaler, expiration time.Duration) error {
+	data, err := value.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	return s.storage.Set(key, string(data), expiration)
+}
+
+// decodeValue Decodes a value stored by setValue | 解码setValue存储的值
+func decodeValue(data any, out encoding.BinaryUnmarshaler) error {
+	dataBytes, err := utils.ToBytes(data)
+	if err != nil {
+		return err
+	}
+	return out.UnmarshalBinary(dataBytes)
+}
+
+// authenticateClient Gets client and checks its secret, public clients have none | 获取客户端并校验密钥（公开客户端无密钥）
+func (s *OAuth2Server) authenticateClient(clientID, clientSecret string) (*Client, error) {
+	client, err := s.GetClient(clientID)
+	if err != nil {
+		return nil, err
+	}
+	if client.Public {
+		return client, nil
+	}
+	if !s.verifyClientSecret(client, clientSecret) {
+		return nil, ErrInvalidClientCredentials
+	}
+	return client, nil
+}
+
 // getCodeKey Gets storage key for authorization code | 获取授权码的存储键
 func (s *OAuth2Server) getCodeKey(code string) string {
 	return s.keyPrefix + CodeKeySuffix + code
@@ -0,0 +1,115 @@
+package oauth2_test
+
+import (
+	"encoding"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/click33/sa-token-go/core/adapter"
+	"github.com/click33/sa-token-go/core/oauth2/oauth2test"
+)
+
+// TestStorageSuite runs the OAuth2 flow against a storage keeping Go values (like memory)
+// and one keeping strings only (like redis) | 分别针对保存Go值（如memory）与仅保存字符串（如redis）的存储运行OAuth2流程
+func TestStorageSuite(t *testing.T) {
+	t.Run("ValueStorage", func(t *testing.T) {
+		oauth2test.RunStorageSuite(t, func() adapter.Storage { return newFakeStorage(keepValue) })
+	})
+	t.Run("StringStorage", func(t *testing.T) {
+		oauth2test.RunStorageSuite(t, func() adapter.Storage { return newFakeStorage(redisString) })
+	})
+}
+
+// keepValue Stores values as they are | 原样保存值
+func keepValue(value any) (any, error) {
+	return value, nil
+}
+
+// redisString Converts values the way go-redis does before sending them | 按go-redis发送前的方式转换值
+func redisString(value any) (any, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case []byte:
+		return string(v), nil
+	case encoding.BinaryMarshaler:
+		data, err := v.MarshalBinary()
+		return string(data), err
+	}
+	return nil, fmt.Errorf("can't marshal %T (implement encoding.BinaryMarshaler)", value)
+}
+
+// fakeStorage Map-backed storage without expiration | 基于map、不处理过期的存储
+type fakeStorage struct {
+	mu     sync.Mutex
+	data   map[string]any
+	encode func(any) (any, error)
+}
+
+func newFakeStorage(encode func(any) (any, error)) *fakeStorage {
+	return &fakeStorage{data: make(map[string]any), encode: encode}
+}
+
+func (s *fakeStorage) Set(key string, value any, expiration time.Duration) error {
+	encoded, err := s.encode(value)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data[key] = encoded
+	return nil
+}
+
+func (s *fakeStorage) SetKeepTTL(key string, value any) error {
+	return s.Set(key, value, 0)
+}
+
+func (s *fakeStorage) Get(key string) (any, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	value, ok := s.data[key]
+	if !ok {
+		return nil, fmt.Errorf("key not found: %s", key)
+	}
+	return value, nil
+}
+
+func (s *fakeStorage) Delete(keys ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, key := range keys {
+		delete(s.data, key)
+	}
+	return nil
+}
+
+func (s *fakeStorage) Exists(key string) bool {
+	_, err := s.Get(key)
+	return err == nil
+}
+
+func (s *fakeStorage) Keys(pattern string) ([]string, error) {
+	return nil, nil
+}
+
+func (s *fakeStorage) Expire(key string, expiration time.Duration) error {
+	return nil
+}
+
+func (s *fakeStorage) TTL(key string) (time.Duration, error) {
+	return -1, nil
+}
+
+func (s *fakeStorage) Clear() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data = make(map[string]any)
+	return nil
+}
+
+func (s *fakeStorage) Ping() error {
+	return nil
+}
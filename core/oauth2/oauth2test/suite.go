@@ -0,0 +1,357 @@
+// Package oauth2test runs the OAuth2 server flow against a storage backend | 针对存储后端运行OAuth2服务器流程
+//
+// Storage modules call RunStorageSuite from their own tests so that every backend
+// round-trips authorization codes and tokens | 各存储模块在自身测试中调用RunStorageSuite，确保每种后端都能还原授权码与令牌
+//
+// Usage | 用法:
+//
+//	func TestOAuth2(t *testing.T) {
+//	    oauth2test.RunStorageSuite(t, func() adapter.Storage { return NewStorage() })
+//	}
+package oauth2test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/click33/sa-token-go/core/adapter"
+	"github.com/click33/sa-token-go/core/oauth2"
+)
+
+const (
+	confidentialID     = "suite-web"
+	confidentialSecret = "suite-secret"
+	publicID           = "suite-spa"
+	redirectURI        = "https://client.example.com/callback"
+	keyPrefix          = "oauth2test:"
+)
+
+// RunStorageSuite Runs the full OAuth2 flow, newStorage is called once per subtest | 运行完整OAuth2流程，每个子测试调用一次newStorage
+// Codes and tokens are random, so a shared backend needs no cleanup | 授权码与令牌均随机生成，共享后端无需清理
+func RunStorageSuite(t *testing.T, newStorage func() adapter.Storage) {
+	t.Helper()
+
+	t.Run("AuthorizationCode", func(t *testing.T) { testAuthorizationCode(t, newServer(t, newStorage())) })
+	t.Run("AuthorizationCodeRace", func(t *testing.T) { testAuthorizationCodeRace(t, newServer(t, newStorage())) })
+	t.Run("PKCE", func(t *testing.T) { testPKCE(t, newServer(t, newStorage())) })
+	t.Run("RefreshToken", func(t *testing.T) { testRefreshToken(t, newServer(t, newStorage())) })
+	t.Run("PublicRefreshToken", func(t *testing.T) { testPublicRefreshToken(t, newServer(t, newStorage())) })
+	t.Run("RefreshTokenRace", func(t *testing.T) { testRefreshTokenRace(t, newServer(t, newStorage())) })
+	t.Run("RevokeToken", func(t *testing.T) { testRevokeToken(t, newServer(t, newStorage())) })
+	t.Run("ClientCredentials", func(t *testing.T) { testClientCredentials(t, newServer(t, newStorage())) })
+	t.Run("Password", func(t *testing.T) { testPassword(t, newServer(t, newStorage())) })
+}
+
+func newServer(t *testing.T, storage adapter.Storage) *oauth2.OAuth2Server {
+	t.Helper()
+
+	server := oauth2.NewOAuth2Server(storage, keyPrefix)
+	clients := []*oauth2.Client{
+		{
+			ClientID:     confidentialID,
+			ClientSecret: confidentialSecret,
+			RedirectURIs: []string{redirectURI},
+			Scopes:       []string{"read", "write"},
+			GrantTypes: []oauth2.GrantType{
+				oauth2.GrantTypeAuthorizationCode,
+				oauth2.GrantTypeRefreshToken,
+				oauth2.GrantTypeClientCredentials,
+				oauth2.GrantTypePassword,
+			},
+		},
+		{
+			ClientID:     publicID,
+			RedirectURIs: []string{redirectURI},
+			Public:       true,
+		},
+	}
+	for _, client := range clients {
+		if err := server.RegisterClient(client); err != nil {
+			t.Fatalf("RegisterClient(%s) error = %v", client.ClientID, err)
+		}
+	}
+	server.SetUserAuthenticator(oauth2.UserAuthenticatorFunc(func(username, password string) (string, error) {
+		if username == "alice" && password == "alice-pwd" {
+			return "user-alice", nil
+		}
+		return "", errors.New("bad credentials")
+	}))
+	return server
+}
+
+func testAuthorizationCode(t *testing.T, server *oauth2.OAuth2Server) {
+	code, err := server.GenerateAuthorizationCode(confidentialID, redirectURI, "user-1", []string{"read"})
+	if err != nil {
+		t.Fatalf("GenerateAuthorizationCode() error = %v", err)
+	}
+
+	token, err := server.ExchangeCodeForToken(code.Code, confidentialID, confidentialSecret, redirectURI)
+	if err != nil {
+		t.Fatalf("ExchangeCodeForToken() error = %v", err)
+	}
+	if token.RefreshToken == "" {
+		t.Error("ExchangeCodeForToken() issued no refresh token")
+	}
+
+	if _, err := server.ExchangeCodeForToken(code.Code, confidentialID, confidentialSecret, redirectURI); !errors.Is(err, oauth2.ErrAuthCodeUsed) {
+		t.Errorf("second exchange error = %v, want %v", err, oauth2.ErrAuthCodeUsed)
+	}
+
+	stored, err := server.ValidateAccessToken(token.Token)
+	if err != nil {
+		t.Fatalf("ValidateAccessToken() error = %v", err)
+	}
+	if !reflect.DeepEqual(stored, token) {
+		t.Errorf("ValidateAccessToken() = %+v, want %+v", stored, token)
+	}
+	if stored.UserID != "user-1" || stored.ClientID != confidentialID || stored.CreateTime == 0 {
+		t.Errorf("ValidateAccessToken() lost fields: %+v", stored)
+	}
+}
+
+func testPKCE(t *testing.T, server *oauth2.OAuth2Server) {
+	verifier := strings.Repeat("v", oauth2.CodeVerifierMinLength)
+	challenge := oauth2.S256Challenge(verifier)
+
+	// Challenge rules | 挑战值规则
+	if _, err := server.GenerateAuthorizationCode(publicID, redirectURI, "user-2", nil); !errors.Is(err, oauth2.ErrPKCERequired) {
+		t.Errorf("public client without challenge error = %v, want %v", err, oauth2.ErrPKCERequired)
+	}
+	if _, err := server.GenerateAuthorizationCodeWithPKCE(publicID, redirectURI, "user-2", nil, challenge, "md5"); !errors.Is(err, oauth2.ErrUnsupportedChallengeMethod) {
+		t.Errorf("unknown method error = %v, want %v", err, oauth2.ErrUnsupportedChallengeMethod)
+	}
+	if _, err := server.GenerateAuthorizationCodeWithPKCE(publicID, redirectURI, "user-2", nil, "short", oauth2.CodeChallengeMethodS256); !errors.Is(err, oauth2.ErrInvalidCodeChallenge) {
+		t.Errorf("short challenge error = %v, want %v", err, oauth2.ErrInvalidCodeChallenge)
+	}
+
+	code, err := server.GenerateAuthorizationCodeWithPKCE(publicID, redirectURI, "user-2", nil, challenge, oauth2.CodeChallengeMethodS256)
+	if err != nil {
+		t.Fatalf("GenerateAuthorizationCodeWithPKCE() error = %v", err)
+	}
+
+	// Failed verifications must not burn the code | 校验失败不能使授权码失效
+	wrong := strings.Repeat("w", oauth2.CodeVerifierMinLength)
+	for _, bad := range []string{wrong, "", challenge} {
+		if _, err := server.ExchangeCodeForTokenWithPKCE(code.Code, publicID, "", redirectURI, bad); !errors.Is(err, oauth2.ErrInvalidCodeVerifier) {
+			t.Errorf("exchange with verifier %q error = %v, want %v", bad, err, oauth2.ErrInvalidCodeVerifier)
+		}
+	}
+	if _, err := server.ExchangeCodeForTokenWithPKCE(code.Code, publicID, "", redirectURI, verifier); err != nil {
+		t.Errorf("exchange with verifier error = %v", err)
+	}
+	if _, err := server.ExchangeCodeForTokenWithPKCE(code.Code, publicID, "", redirectURI, verifier); !errors.Is(err, oauth2.ErrAuthCodeUsed) {
+		t.Errorf("second exchange error = %v, want %v", err, oauth2.ErrAuthCodeUsed)
+	}
+
+	// Plain method compares the verifier itself | plain方式直接比较校验码
+	code, err = server.GenerateAuthorizationCodeWithPKCE(publicID, redirectURI, "user-2", nil, verifier, "")
+	if err != nil {
+		t.Fatalf("GenerateAuthorizationCodeWithPKCE(plain) error = %v", err)
+	}
+	if code.CodeChallengeMethod != oauth2.CodeChallengeMethodPlain {
+		t.Errorf("empty method stored as %q, want plain", code.CodeChallengeMethod)
+	}
+	if _, err := server.ExchangeCodeForTokenWithPKCE(code.Code, publicID, "", redirectURI, verifier); err != nil {
+		t.Errorf("plain exchange error = %v", err)
+	}
+
+	// A verifier for a code without challenge is a downgrade attempt | 为无挑战值的授权码提供校验码视为降级攻击
+	code, err = server.GenerateAuthorizationCode(confidentialID, redirectURI, "user-2", nil)
+	if err != nil {
+		t.Fatalf("GenerateAuthorizationCode() error = %v", err)
+	}
+	if _, err := server.ExchangeCodeForTokenWithPKCE(code.Code, confidentialID, confidentialSecret, redirectURI, verifier); !errors.Is(err, oauth2.ErrInvalidCodeVerifier) {
+		t.Errorf("downgrade exchange error = %v, want %v", err, oauth2.ErrInvalidCodeVerifier)
+	}
+}
+
+func testRefreshToken(t *testing.T, server *oauth2.OAuth2Server) {
+	token, err := server.PasswordToken("alice", "alice-pwd", confidentialID, confidentialSecret, nil)
+	if err != nil {
+		t.Fatalf("PasswordToken() error = %v", err)
+	}
+
+	refreshed, err := server.RefreshAccessTokenWithScopes(token.RefreshToken, confidentialID, confidentialSecret, []string{"read"})
+	if err != nil {
+		t.Fatalf("RefreshAccessTokenWithScopes() error = %v", err)
+	}
+	if !reflect.DeepEqual(refreshed.Scopes, []string{"read"}) || refreshed.UserID != "user-alice" {
+		t.Errorf("RefreshAccessTokenWithScopes() = %+v", refreshed)
+	}
+	if _, err := server.ValidateAccessToken(token.Token); err == nil {
+		t.Error("old access token still valid after refresh")
+	}
+	if _, err := server.ValidateAccessToken(refreshed.Token); err != nil {
+		t.Errorf("ValidateAccessToken(refreshed) error = %v", err)
+	}
+
+	// Refresh tokens rotate | 刷新令牌轮换
+	if refreshed.RefreshToken == "" || refreshed.RefreshToken == token.RefreshToken {
+		t.Fatalf("refresh issued refresh token %q, want a new one", refreshed.RefreshToken)
+	}
+	if _, err := server.RefreshAccessToken(token.RefreshToken, confidentialID, confidentialSecret); !errors.Is(err, oauth2.ErrInvalidRefreshToken) {
+		t.Errorf("reused refresh token error = %v, want %v", err, oauth2.ErrInvalidRefreshToken)
+	}
+
+	// Another client can neither use nor burn the token | 其他客户端既不能使用也不能作废该令牌
+	if _, err := server.RefreshAccessToken(refreshed.RefreshToken, publicID, ""); !errors.Is(err, oauth2.ErrClientMismatch) {
+		t.Errorf("refresh by another client error = %v, want %v", err, oauth2.ErrClientMismatch)
+	}
+	if _, err := server.RefreshAccessToken(refreshed.RefreshToken, confidentialID, confidentialSecret); err != nil {
+		t.Errorf("refresh with rotated token error = %v", err)
+	}
+}
+
+func testPublicRefreshToken(t *testing.T, server *oauth2.OAuth2Server) {
+	verifier := strings.Repeat("v", oauth2.CodeVerifierMinLength)
+	code, err := server.GenerateAuthorizationCodeWithPKCE(publicID, redirectURI, "user-3", nil,
+		oauth2.S256Challenge(verifier), oauth2.CodeChallengeMethodS256)
+	if err != nil {
+		t.Fatalf("GenerateAuthorizationCodeWithPKCE() error = %v", err)
+	}
+	token, err := server.ExchangeCodeForTokenWithPKCE(code.Code, publicID, "", redirectURI, verifier)
+	if err != nil {
+		t.Fatalf("ExchangeCodeForTokenWithPKCE() error = %v", err)
+	}
+
+	// Public clients have no secret, a leaked refresh token works only once | 公开客户端没有密钥，泄露的刷新令牌只能使用一次
+	refreshed, err := server.RefreshAccessToken(token.RefreshToken, publicID, "")
+	if err != nil {
+		t.Fatalf("RefreshAccessToken() error = %v", err)
+	}
+	if _, err := server.RefreshAccessToken(token.RefreshToken, publicID, ""); !errors.Is(err, oauth2.ErrInvalidRefreshToken) {
+		t.Errorf("replayed refresh token error = %v, want %v", err, oauth2.ErrInvalidRefreshToken)
+	}
+	if _, err := server.RefreshAccessToken(refreshed.RefreshToken, publicID, ""); err != nil {
+		t.Errorf("refresh with rotated token error = %v", err)
+	}
+}
+
+func testAuthorizationCodeRace(t *testing.T, server *oauth2.OAuth2Server) {
+	code, err := server.GenerateAuthorizationCode(confidentialID, redirectURI, "alice", nil)
+	if err != nil {
+		t.Fatalf("GenerateAuthorizationCode() error = %v", err)
+	}
+
+	var (
+		wg    sync.WaitGroup
+		mu    sync.Mutex
+		won   int
+		start = make(chan struct{})
+	)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			_, err := server.ExchangeCodeForToken(code.Code, confidentialID, confidentialSecret, redirectURI)
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				won++
+			case !errors.Is(err, oauth2.ErrAuthCodeUsed) && !errors.Is(err, oauth2.ErrInvalidAuthCode):
+				t.Errorf("losing exchange error = %v, want %v or %v", err, oauth2.ErrAuthCodeUsed, oauth2.ErrInvalidAuthCode)
+			}
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	if won != 1 {
+		t.Errorf("%d concurrent exchanges succeeded, want 1", won)
+	}
+}
+
+func testRefreshTokenRace(t *testing.T, server *oauth2.OAuth2Server) {
+	token, err := server.PasswordToken("alice", "alice-pwd", confidentialID, confidentialSecret, nil)
+	if err != nil {
+		t.Fatalf("PasswordToken() error = %v", err)
+	}
+
+	var (
+		wg  sync.WaitGroup
+		mu  sync.Mutex
+		won int
+	)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := server.RefreshAccessToken(token.RefreshToken, confidentialID, confidentialSecret); err == nil {
+				mu.Lock()
+				won++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if won != 1 {
+		t.Errorf("%d concurrent refreshes succeeded, want 1", won)
+	}
+}
+
+func testRevokeToken(t *testing.T, server *oauth2.OAuth2Server) {
+	token, err := server.PasswordToken("alice", "alice-pwd", confidentialID, confidentialSecret, []string{"write"})
+	if err != nil {
+		t.Fatalf("PasswordToken() error = %v", err)
+	}
+
+	if err := server.RevokeToken(token.Token); err != nil {
+		t.Fatalf("RevokeToken() error = %v", err)
+	}
+	if _, err := server.ValidateAccessToken(token.Token); err == nil {
+		t.Error("access token still valid after RevokeToken")
+	}
+	if _, err := server.RefreshAccessToken(token.RefreshToken, confidentialID, confidentialSecret); !errors.Is(err, oauth2.ErrInvalidRefreshToken) {
+		t.Errorf("refresh after revoke error = %v, want %v", err, oauth2.ErrInvalidRefreshToken)
+	}
+
+	token, err = server.PasswordToken("alice", "alice-pwd", confidentialID, confidentialSecret, nil)
+	if err != nil {
+		t.Fatalf("PasswordToken() error = %v", err)
+	}
+	if err := server.RevokeRefreshToken(token.RefreshToken); err != nil {
+		t.Fatalf("RevokeRefreshToken() error = %v", err)
+	}
+	if _, err := server.ValidateAccessToken(token.Token); err == nil {
+		t.Error("access token still valid after RevokeRefreshToken")
+	}
+}
+
+func testClientCredentials(t *testing.T, server *oauth2.OAuth2Server) {
+	token, err := server.ClientCredentialsToken(confidentialID, confidentialSecret, []string{"write"})
+	if err != nil {
+		t.Fatalf("ClientCredentialsToken() error = %v", err)
+	}
+
+	stored, err := server.ValidateAccessToken(token.Token)
+	if err != nil {
+		t.Fatalf("ValidateAccessToken() error = %v", err)
+	}
+	if stored.UserID != "" || stored.RefreshToken != "" || !reflect.DeepEqual(stored.Scopes, []string{"write"}) {
+		t.Errorf("ValidateAccessToken() = %+v", stored)
+	}
+}
+
+func testPassword(t *testing.T, server *oauth2.OAuth2Server) {
+	if _, err := server.PasswordToken("alice", "nope", confidentialID, confidentialSecret, nil); !errors.Is(err, oauth2.ErrInvalidUserCredentials) {
+		t.Errorf("PasswordToken(bad password) error = %v, want %v", err, oauth2.ErrInvalidUserCredentials)
+	}
+
+	token, err := server.PasswordToken("alice", "alice-pwd", confidentialID, confidentialSecret, nil)
+	if err != nil {
+		t.Fatalf("PasswordToken() error = %v", err)
+	}
+	stored, err := server.ValidateAccessToken(token.Token)
+	if err != nil {
+		t.Fatalf("ValidateAccessToken() error = %v", err)
+	}
+	if !reflect.DeepEqual(stored.Scopes, []string{"read", "write"}) {
+		t.Errorf("PasswordToken() scopes = %v, want client scopes", stored.Scopes)
+	}
+}
@@ -0,0 +1,115 @@
+package oauth2
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+)
+
+// PKCE (RFC 7636) Implementation
+// PKCE（RFC 7636）实现
+//
+// Flow | 流程:
+// 1. Client keeps a random code_verifier, sends code_challenge = BASE64URL(SHA256(verifier)) | 客户端保存随机code_verifier，发送其S256摘要code_challenge
+// 2. GenerateAuthorizationCodeWithPKCE() - Challenge is stored on the code | 挑战值随授权码存储
+// 3. ExchangeCodeForTokenWithPKCE() - Verifier must match the stored challenge | 校验code_verifier与存储的挑战值匹配
+//
+// Public clients (Client.Public) have no secret and always require PKCE | 公开客户端（Client.Public）无密钥，且始终要求PKCE
+//
+// Usage | 用法:
+//   challenge := oauth2.S256Challenge(verifier)
+//   code, _ := server.GenerateAuthorizationCodeWithPKCE(clientID, redirectURI, userID, scopes, challenge, oauth2.CodeChallengeMethodS256)
+//   token, _ := server.ExchangeCodeForTokenWithPKCE(code.Code, clientID, "", redirectURI, verifier)
+
+// Code challenge methods | 挑战值计算方式
+const (
+	CodeChallengeMethodS256  = "S256"  // SHA-256 of verifier, recommended | 校验码的SHA-256摘要（推荐）
+	CodeChallengeMethodPlain = "plain" // Verifier itself | 校验码本身
+
+	CodeVerifierMinLength = 43  // Minimum verifier and challenge length | 校验码与挑战值最小长度
+	CodeVerifierMaxLength = 128 // Maximum verifier and challenge length | 校验码与挑战值最大长度
+)
+
+// PKCE error variables | PKCE错误变量
+var (
+	ErrPKCERequired               = fmt.Errorf("code_challenge required for this client")
+	ErrInvalidCodeChallenge       = fmt.Errorf("invalid code_challenge")
+	ErrUnsupportedChallengeMethod = fmt.Errorf("unsupported code_challenge_method")
+	ErrInvalidCodeVerifier        = fmt.Errorf("invalid code_verifier")
+)
+
+// S256Challenge Computes S256 code_challenge of verifier | 计算校验码的S256挑战值
+func S256Challenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// requiresPKCE Reports whether client must send code_challenge | 客户端是否必须发送code_challenge
+func (c *Client) requiresPKCE() bool {
+	return c.Public || c.RequirePKCE
+}
+
+// normalizeChallenge Validates challenge and method, empty method means plain | 校验挑战值与方式，方式为空时视为plain
+func normalizeChallenge(client *Client, challenge, method string) (string, error) {
+	if challenge == "" {
+		if method != "" {
+			return "", ErrInvalidCodeChallenge
+		}
+		if client.requiresPKCE() {
+			return "", ErrPKCERequired
+		}
+		return "", nil
+	}
+
+	if method == "" {
+		method = CodeChallengeMethodPlain
+	}
+	if method != CodeChallengeMethodS256 && method != CodeChallengeMethodPlain {
+		return "", fmt.Errorf("%w: %s", ErrUnsupportedChallengeMethod, method)
+	}
+	if !isPKCEValue(challenge) {
+		return "", ErrInvalidCodeChallenge
+	}
+	return method, nil
+}
+
+// verifyCodeVerifier Checks verifier against challenge stored on code | 校验code_verifier与授权码上的挑战值
+func verifyCodeVerifier(authCode *AuthorizationCode, verifier string) error {
+	if authCode.CodeChallenge == "" {
+		// A verifier without challenge signals a downgrade attempt | 没有挑战值却提供校验码视为降级攻击
+		if verifier != "" {
+			return ErrInvalidCodeVerifier
+		}
+		return nil
+	}
+	if !isPKCEValue(verifier) {
+		return ErrInvalidCodeVerifier
+	}
+
+	expected := verifier
+	if authCode.CodeChallengeMethod == CodeChallengeMethodS256 {
+		expected = S256Challenge(verifier)
+	}
+	if subtle.ConstantTimeCompare([]byte(expected), []byte(authCode.CodeChallenge)) != 1 {
+		return ErrInvalidCodeVerifier
+	}
+	return nil
+}
+
+// isPKCEValue Checks length and unreserved charset of verifier or challenge | 校验校验码或挑战值的长度与字符集
+func isPKCEValue(value string) bool {
+	if len(value) < CodeVerifierMinLength || len(value) > CodeVerifierMaxLength {
+		return false
+	}
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case c >= 'A' && c <= 'Z', c >= 'a' && c <= 'z', c >= '0' && c <= '9':
+		case c == '-' || c == '.' || c == '_' || c == '~':
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,260 @@
+package oauth2
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/click33/sa-token-go/core/adapter"
+)
+
+// Dynamic Client Registration (RFC 7591)
+// 动态客户端注册（RFC 7591）
+//
+// Rules | 规则:
+// - token_endpoint_auth_method none registers a public client, client_secret_basic (default) and client_secret_post a confidential one | none注册公开客户端，client_secret_basic（默认）与client_secret_post注册机密客户端
+// - grant_types default to authorization_code and must be allowed by RegistrationPolicy | grant_types默认为authorization_code，且必须被RegistrationPolicy允许
+// - redirect_uris are required for authorization_code, absolute and without fragment | authorization_code需要redirect_uris，且必须为不含片段的绝对URI
+// - No requested scope grants RegistrationPolicy.Scopes | 未请求权限范围时授予RegistrationPolicy.Scopes
+//
+// The HTTP endpoint is disabled until Handler.SetRegistrationAuthorizer is called | 调用Handler.SetRegistrationAuthorizer前HTTP端点处于禁用状态
+//
+// Usage | 用法:
+//   server.SetRegistrationPolicy(&oauth2.RegistrationPolicy{Scopes: []string{"read"}})
+//   handler.SetRegistrationAuthorizer(func(ctx adapter.RequestContext) bool {
+//       return ctx.GetHeader("Authorization") == "Bearer "+initialAccessToken
+//   })
+
+// ClientIDLength Generated client ID byte length | 生成的客户端ID字节长度
+const ClientIDLength = 16
+
+// Token endpoint authentication methods | 令牌端点认证方式
+const (
+	TokenEndpointAuthNone              = "none"
+	TokenEndpointAuthClientSecretBasic = "client_secret_basic"
+	TokenEndpointAuthClientSecretPost  = "client_secret_post"
+)
+
+// ErrInvalidClientMetadata Registration metadata rejected | 注册元数据被拒绝
+var ErrInvalidClientMetadata = fmt.Errorf("invalid client metadata")
+
+// defaultRegistrationGrantTypes Grants dynamic clients may request without policy | 未设置策略时动态客户端可请求的授权类型
+var defaultRegistrationGrantTypes = []GrantType{GrantTypeAuthorizationCode, GrantTypeRefreshToken}
+
+// RegistrationPolicy Limits what dynamic clients may register | 限制动态客户端可注册的内容
+type RegistrationPolicy struct {
+	GrantTypes  []GrantType // Allowed grant types, empty means authorization_code and refresh_token | 允许的授权类型，为空时为authorization_code与refresh_token
+	Scopes      []string    // Allowed and default scopes, empty means unrestricted | 允许且默认授予的权限范围，为空时不限制
+	RequirePKCE bool        // Require PKCE from confidential dynamic clients too | 机密动态客户端同样要求PKCE
+}
+
+// ClientMetadata Client metadata of RFC 7591 section 2 | RFC 7591第2节的客户端元数据
+type ClientMetadata struct {
+	RedirectURIs            []string `json:"redirect_uris,omitempty"`
+	TokenEndpointAuthMethod string   `json:"token_endpoint_auth_method,omitempty"`
+	GrantTypes              []string `json:"grant_types,omitempty"`
+	ResponseTypes           []string `json:"response_types,omitempty"`
+	ClientName              string   `json:"client_name,omitempty"`
+	ClientURI               string   `json:"client_uri,omitempty"`
+	LogoURI                 string   `json:"logo_uri,omitempty"`
+	Scope                   string   `json:"scope,omitempty"`
+	Contacts                []string `json:"contacts,omitempty"`
+}
+
+// ClientInformation Registration response of RFC 7591 section 3.2.1 | RFC 7591第3.2.1节的注册响应
+type ClientInformation struct {
+	ClientID              string `json:"client_id"`
+	ClientSecret          string `json:"client_secret,omitempty"` // Returned once, only its hash is stored | 仅返回一次，只存储其哈希
+	ClientIDIssuedAt      int64  `json:"client_id_issued_at"`
+	ClientSecretExpiresAt *int64 `json:"client_secret_expires_at,omitempty"` // 0 means never, set when a secret is issued | 0表示永不过期，签发密钥时设置
+	ClientMetadata
+}
+
+// SetRegistrationPolicy Sets dynamic client registration rules | 设置动态客户端注册规则
+func (s *OAuth2Server) SetRegistrationPolicy(policy *RegistrationPolicy) {
+	s.registrationPolicy = policy
+}
+
+// RegisterClientMetadata Registers a client from RFC 7591 metadata | 根据RFC 7591元数据注册客户端
+func (s *OAuth2Server) RegisterClientMetadata(metadata *ClientMetadata) (*ClientInformation, error) {
+	if metadata == nil {
+		return nil, ErrInvalidClientMetadata
+	}
+	policy := s.registrationPolicy
+	if policy == nil {
+		policy = &RegistrationPolicy{}
+	}
+
+	registered := *metadata
+	client := &Client{
+		Name:        metadata.ClientName,
+		ClientURI:   metadata.ClientURI,
+		LogoURI:     metadata.LogoURI,
+		Contacts:    metadata.Contacts,
+		RequirePKCE: policy.RequirePKCE,
+	}
+
+	switch metadata.TokenEndpointAuthMethod {
+	case "":
+		registered.TokenEndpointAuthMethod = TokenEndpointAuthClientSecretBasic
+	case TokenEndpointAuthNone:
+		client.Public = true
+	case TokenEndpointAuthClientSecretBasic, TokenEndpointAuthClientSecretPost:
+	default:
+		return nil, fmt.Errorf("%w: unsupported token_endpoint_auth_method %s", ErrInvalidClientMetadata, metadata.TokenEndpointAuthMethod)
+	}
+
+	grantTypes, err := registrationGrantTypes(policy, metadata.GrantTypes, client.Public)
+	if err != nil {
+		return nil, err
+	}
+	client.GrantTypes = grantTypes
+	registered.GrantTypes = make([]string, len(grantTypes))
+	for i, grantType := range grantTypes {
+		registered.GrantTypes[i] = string(grantType)
+	}
+
+	for _, responseType := range metadata.ResponseTypes {
+		if responseType != ResponseTypeCode {
+			return nil, fmt.Errorf("%w: unsupported response_type %s", ErrInvalidClientMetadata, responseType)
+		}
+	}
+	if client.AllowsGrantType(GrantTypeAuthorizationCode) {
+		registered.ResponseTypes = []string{ResponseTypeCode}
+		if len(metadata.RedirectURIs) == 0 {
+			return nil, fmt.Errorf("%w: redirect_uris required for authorization_code", ErrInvalidRedirectURI)
+		}
+	}
+	for _, uri := range metadata.RedirectURIs {
+		if !isAbsoluteURI(uri) {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidRedirectURI, uri)
+		}
+	}
+	client.RedirectURIs = metadata.RedirectURIs
+
+	for _, uri := range []string{metadata.ClientURI, metadata.LogoURI} {
+		if uri != "" && !isAbsoluteURI(uri) {
+			return nil, fmt.Errorf("%w: invalid URI %s", ErrInvalidClientMetadata, uri)
+		}
+	}
+
+	if client.Scopes, err = narrowScopes(policy.Scopes, strings.Fields(metadata.Scope)); err != nil {
+		return nil, err
+	}
+	registered.Scope = strings.Join(client.Scopes, " ")
+
+	idBytes := make([]byte, ClientIDLength)
+	if _, err := rand.Read(idBytes); err != nil {
+		return nil, fmt.Errorf("failed to generate client ID: %w", err)
+	}
+	client.ClientID = hex.EncodeToString(idBytes)
+	client.CreateTime = time.Now().Unix()
+
+	info := &ClientInformation{
+		ClientID:         client.ClientID,
+		ClientIDIssuedAt: client.CreateTime,
+		ClientMetadata:   registered,
+	}
+	if !client.Public {
+		if client.ClientSecret, err = generateClientSecret(); err != nil {
+			return nil, err
+		}
+		neverExpires := int64(0)
+		info.ClientSecret = client.ClientSecret
+		info.ClientSecretExpiresAt = &neverExpires
+	}
+
+	if err := s.RegisterClient(client); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+// registrationGrantTypes Validates requested grant types against policy | 按策略校验请求的授权类型
+func registrationGrantTypes(policy *RegistrationPolicy, requested []string, public bool) ([]GrantType, error) {
+	if len(requested) == 0 {
+		requested = []string{string(GrantTypeAuthorizationCode)}
+	}
+	allowed := policy.GrantTypes
+	if len(allowed) == 0 {
+		allowed = defaultRegistrationGrantTypes
+	}
+
+	grantTypes := make([]GrantType, 0, len(requested))
+	for _, name := range requested {
+		grantType := GrantType(name)
+		permitted := false
+		for _, g := range allowed {
+			if g == grantType {
+				permitted = true
+				break
+			}
+		}
+		if !permitted {
+			return nil, fmt.Errorf("%w: grant_type %s not allowed", ErrInvalidClientMetadata, name)
+		}
+		if public && grantType == GrantTypeClientCredentials {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidClientMetadata, ErrPublicClientNotAllowed)
+		}
+		grantTypes = append(grantTypes, grantType)
+	}
+	return grantTypes, nil
+}
+
+// isAbsoluteURI Checks URI has scheme and host and no fragment | 检查URI包含协议与主机且不含片段
+func isAbsoluteURI(uri string) bool {
+	u, err := url.Parse(uri)
+	return err == nil && u.Scheme != "" && u.Host != "" && u.Fragment == ""
+}
+
+// ============ Registration Endpoint | 注册端点 ============
+
+// RegistrationAuthorizer Decides whether a registration request is allowed, e.g. by initial access token | 决定是否允许注册请求（如校验初始访问令牌）
+type RegistrationAuthorizer func(ctx adapter.RequestContext) bool
+
+// SetRegistrationAuthorizer Enables the registration endpoint | 启用注册端点
+func (h *Handler) SetRegistrationAuthorizer(authorizer RegistrationAuthorizer) *Handler {
+	h.registrationAuthorizer = authorizer
+	return h
+}
+
+// Register Handles RFC 7591 registration of a JSON metadata body | 处理RFC 7591的JSON元数据注册请求
+func (h *Handler) Register(ctx adapter.RequestContext) *Response {
+	if resp := requirePost(ctx); resp != nil {
+		return resp
+	}
+	if h.registrationAuthorizer == nil {
+		return errorResponse(http.StatusForbidden, ErrorAccessDenied, "dynamic client registration disabled")
+	}
+	if !h.registrationAuthorizer(ctx) {
+		resp := errorResponse(http.StatusUnauthorized, ErrorInvalidToken, "registration not authorized")
+		resp.Header["WWW-Authenticate"] = `Bearer error="invalid_token"`
+		return resp
+	}
+
+	body, err := ctx.GetBody()
+	if err != nil {
+		return errorResponse(http.StatusBadRequest, ErrorInvalidRequest, "unreadable body")
+	}
+	metadata := &ClientMetadata{}
+	if err := json.Unmarshal(body, metadata); err != nil {
+		return errorResponse(http.StatusBadRequest, ErrorInvalidClientMetadata, "malformed JSON")
+	}
+
+	info, err := h.server.RegisterClientMetadata(metadata)
+	switch {
+	case err == nil:
+		return NewJSONResponse(http.StatusCreated, info)
+	case errors.Is(err, ErrInvalidRedirectURI):
+		return errorResponse(http.StatusBadRequest, ErrorInvalidRedirectURI, err.Error())
+	case errors.Is(err, ErrInvalidClientMetadata), errors.Is(err, ErrInvalidScope):
+		return errorResponse(http.StatusBadRequest, ErrorInvalidClientMetadata, err.Error())
+	}
+	return errorResponse(http.StatusInternalServerError, ErrorServerError, "")
+}
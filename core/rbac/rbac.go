@@ -0,0 +1,625 @@
+package rbac
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/click33/sa-token-go/core/adapter"
+	"github.com/click33/sa-token-go/core/utils"
+)
+
+// Role-Based Access Control Implementation
+// 基于角色的访问控制实现
+//
+// Model | 模型:
+// - Role grants permissions and inherits every permission of its parent roles | 角色授予权限，并继承父角色的全部权限
+// - Account is assigned roles, effective permissions are the union along the hierarchy | 账号被分配角色，有效权限为继承链上的并集
+//
+// Usage | 用法:
+//   rm := manager.GetRoleManager()
+//   rm.CreateRole("viewer", "read only")
+//   rm.GrantPermission("viewer", "article:read")
+//   rm.CreateRole("editor", "")
+//   rm.InheritRole("editor", "viewer")       // editor inherits viewer | editor继承viewer
+//   rm.AssignRole("1001", "editor")
+//   perms, _ := rm.GetEffectivePermissions("1001") // [article:read]
+//
+// Storage | 存储:
+// - Writes are serialized across instances by a storage lock | 写操作通过存储锁在多实例间串行化
+// - Roles and their accounts are indexed in hashes, nothing scans keys | 角色及其账号通过哈希索引，不扫描键
+// - Expanded permissions are cached per role set under a generation bumped on every role change | 展开后的权限按角色集合缓存，角色变更时更新代次使缓存失效
+
+// Constants for rbac | RBAC常量
+const (
+	RoleKeySuffix            = "rbac:role:"       // Role definition key suffix | 角色定义键后缀
+	UserRoleKeySuffix        = "rbac:user-role:"  // Account role assignment key suffix | 账号角色分配键后缀
+	RoleIndexKeySuffix       = "rbac:roles"       // Hash of role names | 角色名哈希
+	RoleMemberKeySuffix      = "rbac:role-user:"  // Hash of accounts assigned a role | 分配了某角色的账号哈希
+	GenerationKeySuffix      = "rbac:generation"  // Role definition generation | 角色定义代次
+	PermissionCacheKeySuffix = "rbac:perm-cache:" // Expanded permission cache key suffix | 展开权限缓存键后缀
+	LockKeySuffix            = "rbac:lock"        // Write lock key | 写锁键
+
+	DefaultCacheTTL  = 30 * time.Minute      // Default expanded permission cache TTL | 默认展开权限缓存时间
+	LockTTL          = 10 * time.Second      // Write lock lease | 写锁租期
+	LockWait         = 5 * time.Second       // Max wait for write lock | 写锁最长等待时间
+	lockRetry        = 10 * time.Millisecond // Write lock poll interval | 写锁轮询间隔
+	invalidRoleChars = ":*"                  // Key separator and wildcard | 键分隔符与通配符
+)
+
+// Error variables | 错误变量
+var (
+	ErrRoleNotFound     = fmt.Errorf("role not found")
+	ErrRoleExists       = fmt.Errorf("role already exists")
+	ErrInvalidRoleName  = fmt.Errorf("role name cannot be empty or contain ':' or '*'")
+	ErrRoleCycle        = fmt.Errorf("role inheritance would create a cycle")
+	ErrInvalidRoleData  = fmt.Errorf("invalid role data")
+	ErrInvalidLoginID   = fmt.Errorf("loginID cannot be empty")
+	ErrInvalidGrantData = fmt.Errorf("invalid role assignment data")
+	ErrLockTimeout      = fmt.Errorf("timed out waiting for rbac write lock")
+)
+
+// Role role definition | 角色定义
+type Role struct {
+	Name        string   `json:"name"`                  // Role name | 角色名
+	Description string   `json:"description,omitempty"` // Description | 描述
+	Permissions []string `json:"permissions"`           // Directly granted permissions | 直接授予的权限
+	Parents     []string `json:"parents,omitempty"`     // Inherited roles | 继承的父角色
+	CreateTime  int64    `json:"createTime"`            // Creation timestamp | 创建时间戳
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler for Redis storage | 实现encoding.BinaryMarshaler接口用于Redis存储
+func (r *Role) MarshalBinary() ([]byte, error) {
+	return json.Marshal(r)
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler for Redis storage | 实现encoding.BinaryUnmarshaler接口用于Redis存储
+func (r *Role) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, r)
+}
+
+// RoleManager Role and assignment manager | 角色与授权管理器
+type RoleManager struct {
+	storage   adapter.Storage
+	keyPrefix string        // Configurable prefix | 可配置的前缀
+	cacheTTL  time.Duration // Expanded permission cache TTL | 展开权限缓存时间
+}
+
+// NewRoleManager Creates a new role manager | 创建新的角色管理器
+// prefix: key prefix (e.g., "satoken:" or "" for Java compatibility) | 键前缀（如："satoken:" 或 "" 兼容Java）
+func NewRoleManager(storage adapter.Storage, prefix string) *RoleManager {
+	return &RoleManager{
+		storage:   storage,
+		keyPrefix: prefix,
+		cacheTTL:  DefaultCacheTTL,
+	}
+}
+
+// WithStorage Returns a copy using storage, e.g. one bound to a request context | 返回使用storage的副本，如绑定请求上下文的存储
+// The copy shares keys, lock and cache generation with rm | 副本与rm共享键、锁与缓存版本
+func (rm *RoleManager) WithStorage(storage adapter.Storage) *RoleManager {
+	copied := *rm
+	copied.storage = storage
+	return &copied
+}
+
+// SetCacheTTL Sets expanded permission cache TTL, 0 disables caching | 设置展开权限缓存时间，0表示不缓存
+func (rm *RoleManager) SetCacheTTL(ttl time.Duration) *RoleManager {
+	rm.cacheTTL = ttl
+	return rm
+}
+
+// ============ Role Definition | 角色定义 ============
+
+// CreateRole Creates a role | 创建角色
+func (rm *RoleManager) CreateRole(name, description string) (*Role, error) {
+	if err := ValidateRoleName(name); err != nil {
+		return nil, err
+	}
+
+	role := &Role{
+		Name:        name,
+		Description: description,
+		Permissions: []string{},
+		CreateTime:  time.Now().Unix(),
+	}
+	err := rm.withLock(func() error {
+		exists, err := adapter.CheckExists(rm.storage, rm.getRoleKey(name))
+		if err != nil {
+			return err
+		}
+		if exists {
+			return ErrRoleExists
+		}
+		if err := rm.saveRole(role); err != nil {
+			return err
+		}
+		return adapter.HSet(rm.storage, rm.getRoleIndexKey(), name, strconv.FormatInt(role.CreateTime, 10))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return role, nil
+}
+
+// GetRole Gets role definition | 获取角色定义
+func (rm *RoleManager) GetRole(name string) (*Role, error) {
+	return rm.loadRole(name)
+}
+
+// ListRoles Lists all roles ordered by name | 列出全部角色（按名称排序）
+func (rm *RoleManager) ListRoles() ([]*Role, error) {
+	names, err := rm.listRoleNames()
+	if err != nil {
+		return nil, err
+	}
+
+	roles := make([]*Role, 0, len(names))
+	for _, name := range names {
+		role, err := rm.loadRole(name)
+		if err != nil {
+			continue
+		}
+		roles = append(roles, role)
+	}
+	return roles, nil
+}
+
+// DeleteRole Deletes a role and detaches it from children and accounts | 删除角色，并从子角色与账号上解除
+func (rm *RoleManager) DeleteRole(name string) error {
+	return rm.withLock(func() error {
+		exists, err := adapter.CheckExists(rm.storage, rm.getRoleKey(name))
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return ErrRoleNotFound
+		}
+		if err := rm.storage.Delete(rm.getRoleKey(name)); err != nil {
+			return err
+		}
+		if err := adapter.HDel(rm.storage, rm.getRoleIndexKey(), name); err != nil {
+			return err
+		}
+		defer rm.bumpGeneration()
+
+		// Detach from child roles | 从子角色中移除
+		names, err := rm.listRoleNames()
+		if err != nil {
+			return err
+		}
+		for _, child := range names {
+			role, err := rm.loadRole(child)
+			if err != nil {
+				continue
+			}
+			if parents, changed := without(role.Parents, name); changed {
+				role.Parents = parents
+				if err := rm.saveRole(role); err != nil {
+					return err
+				}
+			}
+		}
+
+		// Detach from accounts | 从账号中移除
+		members, err := adapter.HGetAll(rm.storage, rm.getRoleMemberKey(name))
+		if err != nil {
+			return err
+		}
+		for loginID := range members {
+			roles, err := rm.loadUserRoles(loginID)
+			if err != nil {
+				continue
+			}
+			if kept, changed := without(roles, name); changed {
+				if err := rm.saveUserRoles(loginID, kept); err != nil {
+					return err
+				}
+			}
+		}
+		return rm.storage.Delete(rm.getRoleMemberKey(name))
+	})
+}
+
+// GrantPermission Grants permissions to role | 为角色授予权限
+func (rm *RoleManager) GrantPermission(name string, permissions ...string) error {
+	return rm.updateRole(name, func(role *Role) error {
+		role.Permissions = union(role.Permissions, permissions)
+		return nil
+	})
+}
+
+// RevokePermission Revokes permissions from role | 撤销角色的权限
+func (rm *RoleManager) RevokePermission(name string, permissions ...string) error {
+	return rm.updateRole(name, func(role *Role) error {
+		for _, perm := range permissions {
+			role.Permissions, _ = without(role.Permissions, perm)
+		}
+		return nil
+	})
+}
+
+// InheritRole Makes role inherit every permission of parent | 使角色继承父角色的全部权限
+func (rm *RoleManager) InheritRole(name, parent string) error {
+	if name == parent {
+		return ErrRoleCycle
+	}
+
+	return rm.updateRole(name, func(role *Role) error {
+		if _, err := rm.loadRole(parent); err != nil {
+			return err
+		}
+		// Parent must not already inherit from role | 父角色不能已继承当前角色
+		for _, ancestor := range rm.expandRoles([]string{parent}) {
+			if ancestor == name {
+				return ErrRoleCycle
+			}
+		}
+		role.Parents = union(role.Parents, []string{parent})
+		return nil
+	})
+}
+
+// DisinheritRole Removes parent from role | 移除角色的父角色
+func (rm *RoleManager) DisinheritRole(name, parent string) error {
+	return rm.updateRole(name, func(role *Role) error {
+		role.Parents, _ = without(role.Parents, parent)
+		return nil
+	})
+}
+
+// ============ Account Assignment | 账号授权 ============
+
+// AssignRole Assigns existing roles to account | 为账号分配已存在的角色
+func (rm *RoleManager) AssignRole(loginID string, roles ...string) error {
+	if loginID == "" {
+		return ErrInvalidLoginID
+	}
+
+	return rm.withLock(func() error {
+		for _, name := range roles {
+			exists, err := adapter.CheckExists(rm.storage, rm.getRoleKey(name))
+			if err != nil {
+				return err
+			}
+			if !exists {
+				return fmt.Errorf("%w: %s", ErrRoleNotFound, name)
+			}
+		}
+
+		current, err := rm.loadUserRoles(loginID)
+		if err != nil {
+			return err
+		}
+		if err := rm.saveUserRoles(loginID, union(current, roles)); err != nil {
+			return err
+		}
+		for _, name := range roles {
+			if err := adapter.HSet(rm.storage, rm.getRoleMemberKey(name), loginID, strconv.FormatInt(time.Now().Unix(), 10)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// UnassignRole Removes roles from account | 移除账号的角色
+func (rm *RoleManager) UnassignRole(loginID string, roles ...string) error {
+	return rm.withLock(func() error {
+		current, err := rm.loadUserRoles(loginID)
+		if err != nil {
+			return err
+		}
+		for _, name := range roles {
+			current, _ = without(current, name)
+		}
+		if err := rm.saveUserRoles(loginID, current); err != nil {
+			return err
+		}
+		for _, name := range roles {
+			if err := adapter.HDel(rm.storage, rm.getRoleMemberKey(name), loginID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// GetUserRoles Gets roles directly assigned to account | 获取账号直接分配的角色
+func (rm *RoleManager) GetUserRoles(loginID string) ([]string, error) {
+	return rm.loadUserRoles(loginID)
+}
+
+// ============ Effective Grants | 有效授权 ============
+
+// GetEffectiveRoles Gets assigned roles plus every inherited role | 获取账号分配的角色及其全部继承角色
+func (rm *RoleManager) GetEffectiveRoles(loginID string) ([]string, error) {
+	roles, err := rm.loadUserRoles(loginID)
+	if err != nil {
+		return nil, err
+	}
+	return rm.expandRoles(roles), nil
+}
+
+// GetEffectivePermissions Gets permissions granted through assigned roles | 获取通过所分配角色获得的权限
+func (rm *RoleManager) GetEffectivePermissions(loginID string) ([]string, error) {
+	roles, err := rm.loadUserRoles(loginID)
+	if err != nil {
+		return nil, err
+	}
+	return rm.PermissionsOfRoles(roles), nil
+}
+
+// ExpandRoles Expands role names with every inherited role, unknown names are kept | 展开角色及其继承角色，未定义的角色名原样保留
+func (rm *RoleManager) ExpandRoles(roles []string) []string {
+	return rm.expandRoles(roles)
+}
+
+// PermissionsOfRoles Gets permissions granted by roles along the hierarchy | 获取角色（含继承链）授予的权限
+// Results are cached per role set, accounts without roles never touch storage | 结果按角色集合缓存，无角色的账号不访问存储
+func (rm *RoleManager) PermissionsOfRoles(roles []string) []string {
+	if len(roles) == 0 {
+		return []string{}
+	}
+	if rm.cacheTTL <= 0 {
+		return rm.collectPermissions(roles)
+	}
+
+	cacheKey := rm.getPermissionCacheKey(roles)
+	if data, err := rm.storage.Get(cacheKey); err == nil && data != nil {
+		if dataBytes, err := utils.ToBytes(data); err == nil {
+			var perms []string
+			if err := json.Unmarshal(dataBytes, &perms); err == nil {
+				return perms
+			}
+		}
+	}
+
+	perms := rm.collectPermissions(roles)
+	if data, err := json.Marshal(perms); err == nil {
+		_ = rm.storage.Set(cacheKey, string(data), rm.cacheTTL)
+	}
+	return perms
+}
+
+// ValidateRoleName Checks role name is usable as a key segment | 检查角色名可作为键的一部分
+func ValidateRoleName(name string) error {
+	if name == "" || strings.ContainsAny(name, invalidRoleChars) {
+		return ErrInvalidRoleName
+	}
+	return nil
+}
+
+// ============ Internal Methods | 内部方法 ============
+
+// collectPermissions Unions permissions along the hierarchy of roles | 合并角色继承链上的权限
+func (rm *RoleManager) collectPermissions(roles []string) []string {
+	perms := []string{}
+	for _, name := range rm.expandRoles(roles) {
+		role, err := rm.loadRole(name)
+		if err != nil {
+			continue
+		}
+		perms = union(perms, role.Permissions)
+	}
+	return perms
+}
+
+// expandRoles Walks role hierarchy breadth-first, tolerating cycles | 广度优先遍历角色继承链（容忍环）
+func (rm *RoleManager) expandRoles(roles []string) []string {
+	visited := make(map[string]bool, len(roles))
+	result := make([]string, 0, len(roles))
+	queue := append([]string{}, roles...)
+
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		if name == "" || visited[name] {
+			continue
+		}
+		visited[name] = true
+		result = append(result, name)
+
+		if role, err := rm.loadRole(name); err == nil {
+			queue = append(queue, role.Parents...)
+		}
+	}
+	return result
+}
+
+// updateRole Loads, mutates and saves role under lock | 加锁加载、修改并保存角色
+func (rm *RoleManager) updateRole(name string, fn func(role *Role) error) error {
+	return rm.withLock(func() error {
+		role, err := rm.loadRole(name)
+		if err != nil {
+			return err
+		}
+		if err := fn(role); err != nil {
+			return err
+		}
+		if err := rm.saveRole(role); err != nil {
+			return err
+		}
+		rm.bumpGeneration()
+		return nil
+	})
+}
+
+// withLock Runs fn while holding the storage-wide write lock | 持有存储级写锁时执行fn
+// The lease bounds a crashed holder, fn must finish well within LockTTL | 租期用于兜底崩溃的持有者，fn须远早于LockTTL完成
+func (rm *RoleManager) withLock(fn func() error) error {
+	key := rm.keyPrefix + LockKeySuffix
+	owner := utils.RandomString(16)
+	deadline := time.Now().Add(LockWait)
+	for {
+		acquired, err := adapter.SetNX(rm.storage, key, owner, LockTTL)
+		if err != nil {
+			return err
+		}
+		if acquired {
+			break
+		}
+		if time.Now().After(deadline) {
+			return ErrLockTimeout
+		}
+		time.Sleep(lockRetry)
+	}
+	defer func() {
+		// Only release our own lease | 只释放自己持有的租约
+		if data, err := rm.storage.Get(key); err == nil && utils.ToString(data) == owner {
+			_ = rm.storage.Delete(key)
+		}
+	}()
+	return fn()
+}
+
+// bumpGeneration Invalidates every cached expansion | 使全部展开缓存失效
+func (rm *RoleManager) bumpGeneration() {
+	_ = rm.storage.Set(rm.keyPrefix+GenerationKeySuffix, utils.RandomString(16), 0)
+}
+
+// listRoleNames Lists role names from the role index, sorted | 从角色索引列出角色名（排序）
+func (rm *RoleManager) listRoleNames() ([]string, error) {
+	index, err := adapter.HGetAll(rm.storage, rm.getRoleIndexKey())
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(index))
+	for name := range index {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// loadRole Loads role from storage | 从存储加载角色
+func (rm *RoleManager) loadRole(name string) (*Role, error) {
+	if name == "" {
+		return nil, ErrInvalidRoleName
+	}
+
+	data, err := rm.storage.Get(rm.getRoleKey(name))
+	if err != nil || data == nil {
+		return nil, ErrRoleNotFound
+	}
+
+	dataBytes, err := utils.ToBytes(data)
+	if err != nil {
+		return nil, ErrInvalidRoleData
+	}
+
+	role := &Role{}
+	if err := role.UnmarshalBinary(dataBytes); err != nil {
+		return nil, ErrInvalidRoleData
+	}
+	return role, nil
+}
+
+// saveRole Saves role to storage without expiration | 保存角色（永不过期）
+func (rm *RoleManager) saveRole(role *Role) error {
+	data, err := role.MarshalBinary()
+	if err != nil {
+		return fmt.Errorf("failed to marshal role: %w", err)
+	}
+	return rm.storage.Set(rm.getRoleKey(role.Name), string(data), 0)
+}
+
+// loadUserRoles Loads roles assigned to account | 加载账号分配的角色
+func (rm *RoleManager) loadUserRoles(loginID string) ([]string, error) {
+	data, err := rm.storage.Get(rm.getUserRoleKey(loginID))
+	if err != nil || data == nil {
+		return []string{}, nil
+	}
+
+	dataBytes, err := utils.ToBytes(data)
+	if err != nil {
+		return nil, ErrInvalidGrantData
+	}
+
+	var roles []string
+	if err := json.Unmarshal(dataBytes, &roles); err != nil {
+		return nil, ErrInvalidGrantData
+	}
+	return roles, nil
+}
+
+// saveUserRoles Saves roles assigned to account, deleting key when empty | 保存账号分配的角色（为空时删除）
+func (rm *RoleManager) saveUserRoles(loginID string, roles []string) error {
+	key := rm.getUserRoleKey(loginID)
+	if len(roles) == 0 {
+		return rm.storage.Delete(key)
+	}
+
+	data, err := json.Marshal(roles)
+	if err != nil {
+		return fmt.Errorf("failed to marshal role assignment: %w", err)
+	}
+	return rm.storage.Set(key, string(data), 0)
+}
+
+// getRoleKey Gets storage key for role | 获取角色的存储键
+func (rm *RoleManager) getRoleKey(name string) string {
+	return rm.keyPrefix + RoleKeySuffix + name
+}
+
+// getUserRoleKey Gets storage key for account roles | 获取账号角色的存储键
+func (rm *RoleManager) getUserRoleKey(loginID string) string {
+	return rm.keyPrefix + UserRoleKeySuffix + loginID
+}
+
+// getRoleIndexKey Gets storage key of the role index | 获取角色索引的存储键
+func (rm *RoleManager) getRoleIndexKey() string {
+	return rm.keyPrefix + RoleIndexKeySuffix
+}
+
+// getRoleMemberKey Gets storage key of accounts assigned role | 获取分配了角色的账号集合的存储键
+func (rm *RoleManager) getRoleMemberKey(name string) string {
+	return rm.keyPrefix + RoleMemberKeySuffix + name
+}
+
+// getPermissionCacheKey Gets cache key of role set in the current generation | 获取当前代次下角色集合的缓存键
+func (rm *RoleManager) getPermissionCacheKey(roles []string) string {
+	generation := "0"
+	if data, err := rm.storage.Get(rm.keyPrefix + GenerationKeySuffix); err == nil && data != nil {
+		generation = utils.ToString(data)
+	}
+
+	names := utils.UniqueStrings(roles)
+	sort.Strings(names)
+	sum := sha256.Sum256([]byte(strings.Join(names, "\n")))
+	return rm.keyPrefix + PermissionCacheKeySuffix + generation + ":" + hex.EncodeToString(sum[:])
+}
+
+// union Appends items not yet in list, keeping order | 追加列表中尚不存在的元素（保持顺序）
+func union(list, items []string) []string {
+	seen := make(map[string]bool, len(list))
+	for _, item := range list {
+		seen[item] = true
+	}
+	for _, item := range items {
+		if item != "" && !seen[item] {
+			seen[item] = true
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+// without Removes item from list, reporting whether it was present | 从列表中移除元素，并返回是否存在
+func without(list []string, item string) ([]string, bool) {
+	result := make([]string, 0, len(list))
+	changed := false
+	for _, v := range list {
+		if v == item {
+			changed = true
+			continue
+		}
+		result = append(result, v)
+	}
+	return result, changed
+}
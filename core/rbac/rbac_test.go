@@ -0,0 +1,238 @@
+package rbac
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/click33/sa-token-go/core/adapter/adaptertest"
+)
+
+// countingStorage Counts reads to observe caching | 统计读取次数以观察缓存
+type countingStorage struct {
+	*adaptertest.MapStorage
+	mu   sync.Mutex
+	gets int
+}
+
+func (s *countingStorage) Get(key string) (any, error) {
+	s.mu.Lock()
+	s.gets++
+	s.mu.Unlock()
+	return s.MapStorage.Get(key)
+}
+
+func (s *countingStorage) reads() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.gets
+}
+
+func newTestRoleManager(t *testing.T, roles ...string) (*RoleManager, *countingStorage) {
+	t.Helper()
+
+	storage := &countingStorage{MapStorage: adaptertest.NewMapStorage()}
+	rm := NewRoleManager(storage, "satoken:")
+	for _, name := range roles {
+		if _, err := rm.CreateRole(name, ""); err != nil {
+			t.Fatalf("CreateRole(%s) error = %v", name, err)
+		}
+	}
+	return rm, storage
+}
+
+func sorted(list []string) []string {
+	list = append([]string{}, list...)
+	sort.Strings(list)
+	return list
+}
+
+func TestRoleInheritance(t *testing.T) {
+	rm, _ := newTestRoleManager(t, "viewer", "editor", "admin")
+	mustDo(t, rm.GrantPermission("viewer", "article:read"))
+	mustDo(t, rm.GrantPermission("editor", "article:write"))
+	mustDo(t, rm.GrantPermission("admin", "user:*"))
+	mustDo(t, rm.InheritRole("editor", "viewer"))
+	mustDo(t, rm.InheritRole("admin", "editor"))
+	mustDo(t, rm.AssignRole("1001", "admin"))
+
+	roles, err := rm.GetEffectiveRoles("1001")
+	if err != nil || !reflect.DeepEqual(roles, []string{"admin", "editor", "viewer"}) {
+		t.Fatalf("GetEffectiveRoles() = %v, %v", roles, err)
+	}
+	perms, err := rm.GetEffectivePermissions("1001")
+	want := []string{"article:read", "article:write", "user:*"}
+	if err != nil || !reflect.DeepEqual(sorted(perms), want) {
+		t.Fatalf("GetEffectivePermissions() = %v, %v, want %v", perms, err, want)
+	}
+
+	// Unknown names pass through without grants | 未定义的角色名原样保留且无权限
+	if got := rm.ExpandRoles([]string{"guest", "editor"}); !reflect.DeepEqual(got, []string{"guest", "editor", "viewer"}) {
+		t.Fatalf("ExpandRoles() = %v", got)
+	}
+}
+
+func TestRoleCycle(t *testing.T) {
+	rm, _ := newTestRoleManager(t, "a", "b", "c")
+	mustDo(t, rm.InheritRole("b", "a"))
+	mustDo(t, rm.InheritRole("c", "b"))
+
+	for _, tt := range []struct{ name, parent string }{{"a", "a"}, {"a", "b"}, {"a", "c"}} {
+		if err := rm.InheritRole(tt.name, tt.parent); !errors.Is(err, ErrRoleCycle) {
+			t.Fatalf("InheritRole(%s, %s) = %v, want ErrRoleCycle", tt.name, tt.parent, err)
+		}
+	}
+	if err := rm.InheritRole("a", "missing"); !errors.Is(err, ErrRoleNotFound) {
+		t.Fatalf("InheritRole(missing parent) = %v, want ErrRoleNotFound", err)
+	}
+
+	// A cycle written behind the manager's back must not hang expansion | 绕过管理器写入的环不能导致展开死循环
+	role, _ := rm.GetRole("a")
+	role.Parents = []string{"c"}
+	mustDo(t, rm.saveRole(role))
+	if got := rm.ExpandRoles([]string{"a"}); !reflect.DeepEqual(got, []string{"a", "c", "b"}) {
+		t.Fatalf("ExpandRoles(cycle) = %v", got)
+	}
+}
+
+func TestRoleRevocation(t *testing.T) {
+	rm, _ := newTestRoleManager(t, "viewer", "editor")
+	mustDo(t, rm.GrantPermission("viewer", "article:read", "article:list"))
+	mustDo(t, rm.InheritRole("editor", "viewer"))
+	mustDo(t, rm.AssignRole("1001", "editor"))
+	other := NewRoleManager(rm.storage, "satoken:")
+
+	assertPerms := func(step string, want ...string) {
+		t.Helper()
+		for _, m := range []*RoleManager{rm, other} {
+			perms, _ := m.GetEffectivePermissions("1001")
+			if !reflect.DeepEqual(sorted(perms), sorted(want)) {
+				t.Fatalf("%s: GetEffectivePermissions() = %v, want %v", step, perms, want)
+			}
+		}
+	}
+	assertPerms("granted", "article:read", "article:list")
+
+	mustDo(t, rm.RevokePermission("viewer", "article:list"))
+	assertPerms("revoked on parent", "article:read")
+
+	mustDo(t, other.DisinheritRole("editor", "viewer"))
+	assertPerms("disinherited")
+
+	mustDo(t, rm.InheritRole("editor", "viewer"))
+	assertPerms("re-inherited", "article:read")
+
+	mustDo(t, rm.UnassignRole("1001", "editor"))
+	assertPerms("unassigned")
+}
+
+func TestDeleteRole(t *testing.T) {
+	rm, storage := newTestRoleManager(t, "viewer", "editor")
+	mustDo(t, rm.GrantPermission("viewer", "article:read"))
+	mustDo(t, rm.InheritRole("editor", "viewer"))
+	mustDo(t, rm.AssignRole("1001", "viewer", "editor"))
+	mustDo(t, rm.AssignRole("1002", "viewer"))
+
+	mustDo(t, rm.DeleteRole("viewer"))
+	if err := rm.DeleteRole("viewer"); !errors.Is(err, ErrRoleNotFound) {
+		t.Fatalf("DeleteRole(deleted) = %v, want ErrRoleNotFound", err)
+	}
+
+	if editor, _ := rm.GetRole("editor"); len(editor.Parents) != 0 {
+		t.Fatalf("editor parents = %v, want detached", editor.Parents)
+	}
+	if roles, _ := rm.GetUserRoles("1001"); !reflect.DeepEqual(roles, []string{"editor"}) {
+		t.Fatalf("GetUserRoles(1001) = %v, want [editor]", roles)
+	}
+	if storage.Exists(rm.getUserRoleKey("1002")) || storage.Exists(rm.getRoleMemberKey("viewer")) {
+		t.Fatal("assignments of deleted role kept")
+	}
+	if perms, _ := rm.GetEffectivePermissions("1001"); len(perms) != 0 {
+		t.Fatalf("GetEffectivePermissions() = %v, want none", perms)
+	}
+
+	roles, err := rm.ListRoles()
+	if err != nil || len(roles) != 1 || roles[0].Name != "editor" {
+		t.Fatalf("ListRoles() = %v, %v, want [editor]", roles, err)
+	}
+}
+
+func TestRoleName(t *testing.T) {
+	rm, _ := newTestRoleManager(t, "admin")
+
+	for _, name := range []string{"", "a:b", "admin*", "*"} {
+		if _, err := rm.CreateRole(name, ""); !errors.Is(err, ErrInvalidRoleName) {
+			t.Fatalf("CreateRole(%q) = %v, want ErrInvalidRoleName", name, err)
+		}
+	}
+	if _, err := rm.CreateRole("admin", ""); !errors.Is(err, ErrRoleExists) {
+		t.Fatalf("CreateRole(duplicate) = %v, want ErrRoleExists", err)
+	}
+	if err := rm.AssignRole("1001", "missing"); !errors.Is(err, ErrRoleNotFound) {
+		t.Fatalf("AssignRole(missing) = %v, want ErrRoleNotFound", err)
+	}
+}
+
+func TestPermissionCache(t *testing.T) {
+	rm, storage := newTestRoleManager(t, "r0", "r1", "r2", "r3")
+	for i := 1; i < 4; i++ {
+		mustDo(t, rm.InheritRole(fmt.Sprintf("r%d", i), fmt.Sprintf("r%d", i-1)))
+		mustDo(t, rm.GrantPermission(fmt.Sprintf("r%d", i), fmt.Sprintf("p%d", i)))
+	}
+
+	before := storage.reads()
+	if perms := rm.PermissionsOfRoles(nil); len(perms) != 0 || storage.reads() != before {
+		t.Fatalf("PermissionsOfRoles(nil) = %v after %d reads, want no storage access", perms, storage.reads()-before)
+	}
+
+	rm.PermissionsOfRoles([]string{"r3"})
+	before = storage.reads()
+	if perms := rm.PermissionsOfRoles([]string{"r3", "r3"}); len(perms) != 3 {
+		t.Fatalf("PermissionsOfRoles() = %v, want 3 permissions", perms)
+	}
+	if reads := storage.reads() - before; reads != 2 {
+		t.Fatalf("cached PermissionsOfRoles() did %d reads, want 2", reads)
+	}
+
+	uncached := NewRoleManager(storage, "satoken:").SetCacheTTL(0)
+	before = storage.reads()
+	uncached.PermissionsOfRoles([]string{"r3"})
+	if reads := storage.reads() - before; reads <= 2 {
+		t.Fatalf("uncached PermissionsOfRoles() did %d reads, want one per role", reads)
+	}
+}
+
+func TestConcurrentGrants(t *testing.T) {
+	rm, _ := newTestRoleManager(t, "editor")
+	other := NewRoleManager(rm.storage, "satoken:")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			m := rm
+			if i%2 == 1 {
+				m = other
+			}
+			if err := m.GrantPermission("editor", fmt.Sprintf("perm:%d", i)); err != nil {
+				t.Errorf("GrantPermission() error = %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if role, _ := rm.GetRole("editor"); len(role.Permissions) != 16 {
+		t.Fatalf("permissions = %d, want 16 (lost update)", len(role.Permissions))
+	}
+}
+
+func mustDo(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -1,6 +1,8 @@
 package core
 
 import (
+	stdcontext "context"
+	"database/sql"
 	"time"
 
 	"github.com/click33/sa-token-go/core/adapter"
@@ -10,8 +12,10 @@ import (
 	"github.com/click33/sa-token-go/core/listener"
 	"github.com/click33/sa-token-go/core/manager"
 	"github.com/click33/sa-token-go/core/oauth2"
+	"github.com/click33/sa-token-go/core/rbac"
 	"github.com/click33/sa-token-go/core/security"
 	"github.com/click33/sa-token-go/core/session"
+	"github.com/click33/sa-token-go/core/sso"
 	"github.com/click33/sa-token-go/core/token"
 	"github.com/click33/sa-token-go/core/utils"
 )
@@ -24,11 +28,33 @@ const Version = "0.1.3"
 
 // Configuration related types | 配置相关类型
 type (
-	Config       = config.Config
-	CookieConfig = config.CookieConfig
-	TokenStyle   = config.TokenStyle
+	Config         = config.Config
+	CookieConfig   = config.CookieConfig
+	TokenStyle     = config.TokenStyle
+	OverflowPolicy = config.OverflowPolicy
+	JwtMode        = config.JwtMode
+	TokenSource    = config.TokenSource
 )
 
+// JWT claims types | JWT声明类型
+type (
+	JwtClaimsConfig = config.JwtClaimsConfig
+	JwtClaimsHook   = manager.JwtClaimsHook
+	TokenClaims     = token.Claims
+)
+
+// Custom token generator types | 自定义Token生成器类型
+type (
+	CustomTokenGenerator = token.TokenGenerator
+	TokenGeneratorFunc   = token.TokenGeneratorFunc
+)
+
+// DefaultLoginType Default account realm name | 默认账号体系标识
+const DefaultLoginType = config.DefaultLoginType
+
+// DefaultSafeService Default second-level authentication service | 默认二级认证业务标识
+const DefaultSafeService = manager.DefaultSafeService
+
 // Token style constants | Token风格常量
 const (
 	TokenStyleUUID      = config.TokenStyleUUID
@@ -40,23 +66,104 @@ const (
 	TokenStyleHash      = config.TokenStyleHash
 	TokenStyleTimestamp = config.TokenStyleTimestamp
 	TokenStyleTik       = config.TokenStyleTik
+	TokenStyleEncrypted = config.TokenStyleEncrypted
+)
+
+// Login overflow policy constants | 登录数量溢出策略常量
+const (
+	OverflowReject          = config.OverflowReject
+	OverflowKickOldest      = config.OverflowKickOldest
+	OverflowKickLeastActive = config.OverflowKickLeastActive
+	OverflowKickSameDevice  = config.OverflowKickSameDevice
+)
+
+// JWT mode constants | JWT模式常量
+const (
+	JwtModeDefault   = config.JwtModeDefault
+	JwtModeSimple    = config.JwtModeSimple
+	JwtModeMixed     = config.JwtModeMixed
+	JwtModeStateless = config.JwtModeStateless
+)
+
+// Token source constants | Token来源常量
+const (
+	TokenSourceHeader        = config.TokenSourceHeader
+	TokenSourceAuthorization = config.TokenSourceAuthorization
+	TokenSourceCookie        = config.TokenSourceCookie
+	TokenSourceQuery         = config.TokenSourceQuery
+	TokenSourceBody          = config.TokenSourceBody
+	TokenSourceForm          = config.TokenSourceForm
+	TokenSourceMetadata      = config.TokenSourceMetadata
+	TokenSourceWebSocket     = config.TokenSourceWebSocket
 )
 
 // Core types | 核心类型
 type (
 	Manager             = manager.Manager
 	TokenInfo           = manager.TokenInfo
+	TokenSign           = manager.TokenSign
+	LoginOptions        = manager.LoginOptions
 	Session             = session.Session
 	TokenGenerator      = token.Generator
+	JwtKey              = token.JwtKey
+	JwtKeySet           = token.JwtKeySet
+	JWKS                = token.JWKS
+	EncryptKey          = token.EncryptKey
+	EncryptKeySet       = token.EncryptKeySet
 	SaTokenContext      = context.SaTokenContext
 	Builder             = builder.Builder
 	NonceManager        = security.NonceManager
 	RefreshTokenInfo    = security.RefreshTokenInfo
 	RefreshTokenManager = security.RefreshTokenManager
+	TempTokenInfo       = security.TempTokenInfo
+	TempTokenManager    = security.TempTokenManager
+	SignManager         = security.SignManager
+	RoleManager         = rbac.RoleManager
+	RBACRole            = rbac.Role
+	SignConfig          = security.SignConfig
+	SignAlgorithm       = security.SignAlgorithm
 	OAuth2Server        = oauth2.OAuth2Server
 	OAuth2Client        = oauth2.Client
 	OAuth2AccessToken   = oauth2.AccessToken
 	OAuth2GrantType     = oauth2.GrantType
+	SSOServer           = sso.SSOServer
+	SSOClient           = sso.SSOClient
+	SSOClientConfig     = sso.Client
+	SSOTicket           = sso.Ticket
+)
+
+// OAuth2 password grant types | OAuth2密码模式类型
+type (
+	OAuth2UserAuthenticator     = oauth2.UserAuthenticator
+	OAuth2UserAuthenticatorFunc = oauth2.UserAuthenticatorFunc
+)
+
+// OAuth2 HTTP endpoint types | OAuth2 HTTP端点类型
+type (
+	OAuth2Handler          = oauth2.Handler
+	OAuth2Response         = oauth2.Response
+	OAuth2AuthorizeRequest = oauth2.AuthorizeRequest
+	OAuth2ConsentResult    = oauth2.ConsentResult
+	OAuth2ConsentHook      = oauth2.ConsentHook
+)
+
+// OAuth2 client registry types | OAuth2客户端注册表类型
+type (
+	OAuth2ClientStore            = oauth2.ClientStore
+	OAuth2MemoryClientStore      = oauth2.MemoryClientStore
+	OAuth2StorageClientStore     = oauth2.StorageClientStore
+	OAuth2SQLClientStore         = oauth2.SQLClientStore
+	OAuth2SecretHasher           = oauth2.SecretHasher
+	OAuth2ClientMetadata         = oauth2.ClientMetadata
+	OAuth2ClientInformation      = oauth2.ClientInformation
+	OAuth2RegistrationPolicy     = oauth2.RegistrationPolicy
+	OAuth2RegistrationAuthorizer = oauth2.RegistrationAuthorizer
+)
+
+// Permission provider types | 权限数据源类型
+type (
+	PermissionProvider     = manager.PermissionProvider
+	PermissionProviderFunc = manager.PermissionProviderFunc
 )
 
 // Adapter interfaces | 适配器接口
@@ -65,6 +172,13 @@ type (
 	RequestContext = adapter.RequestContext
 )
 
+// Context-aware storage types | 上下文感知存储类型
+type (
+	StorageV2         = adapter.StorageV2
+	StorageV2Provider = adapter.StorageV2Provider
+	ContextCarrier    = adapter.ContextCarrier
+)
+
 // Event related types | 事件相关类型
 type (
 	EventListener  = listener.Listener
@@ -80,6 +194,7 @@ const (
 	EventLogin           = listener.EventLogin
 	EventLogout          = listener.EventLogout
 	EventKickout         = listener.EventKickout
+	EventReplaced        = listener.EventReplaced
 	EventDisable         = listener.EventDisable
 	EventUntie           = listener.EventUntie
 	EventRenew           = listener.EventRenew
@@ -97,6 +212,27 @@ const (
 	GrantTypePassword          = oauth2.GrantTypePassword
 )
 
+// OAuth2 PKCE code challenge methods | OAuth2 PKCE挑战值计算方式
+const (
+	CodeChallengeMethodS256  = oauth2.CodeChallengeMethodS256
+	CodeChallengeMethodPlain = oauth2.CodeChallengeMethodPlain
+)
+
+// OAuth2 endpoint paths | OAuth2端点路径
+const (
+	OAuth2AuthorizePath  = oauth2.AuthorizePath
+	OAuth2TokenPath      = oauth2.TokenPath
+	OAuth2RevokePath     = oauth2.RevokePath
+	OAuth2IntrospectPath = oauth2.IntrospectPath
+	OAuth2RegisterPath   = oauth2.RegisterPath
+)
+
+const (
+	SignAlgMD5        = security.SignAlgMD5
+	SignAlgSHA256     = security.SignAlgSHA256
+	SignAlgHMACSHA256 = security.SignAlgHMACSHA256
+)
+
 // ============ Utility Functions | 工具函数 ============
 
 var (
@@ -141,6 +277,26 @@ func NewManager(storage Storage, cfg *Config) *Manager {
 	return manager.NewManager(storage, cfg)
 }
 
+// AsStorageV2 Adapts Storage to context-aware StorageV2 | 将Storage适配为上下文感知的StorageV2
+func AsStorageV2(storage Storage) StorageV2 {
+	return adapter.AsStorageV2(storage)
+}
+
+// AsStorage Adapts StorageV2 to Storage | 将StorageV2适配为Storage
+func AsStorage(storage StorageV2) Storage {
+	return adapter.AsStorage(storage)
+}
+
+// BindStorageContext Binds StorageV2 to ctx as Storage | 将StorageV2绑定到ctx并作为Storage使用
+func BindStorageContext(storage StorageV2, ctx stdcontext.Context) Storage {
+	return adapter.BindContext(storage, ctx)
+}
+
+// NewLoginOptions Creates login options with remember-me enabled | 创建登录参数（默认记住我）
+func NewLoginOptions() *LoginOptions {
+	return manager.NewLoginOptions()
+}
+
 // NewContext Creates a new Sa-Token context | 创建新的Sa-Token上下文
 func NewContext(ctx RequestContext, mgr *Manager) *SaTokenContext {
 	return context.NewContext(ctx, mgr)
@@ -161,6 +317,26 @@ func NewTokenGenerator(cfg *Config) *TokenGenerator {
 	return token.NewGenerator(cfg)
 }
 
+// NewJwtKeyFromPEM Creates JWT key from PEM encoded private or public key | 从PEM编码的私钥或公钥创建JWT密钥
+func NewJwtKeyFromPEM(kid string, pemData []byte) (*JwtKey, error) {
+	return token.NewJwtKeyFromPEM(kid, pemData)
+}
+
+// RegisterTokenStyle Registers named custom token style | 注册命名的自定义Token风格
+func RegisterTokenStyle(style TokenStyle, gen CustomTokenGenerator) error {
+	return token.RegisterStyle(style, gen)
+}
+
+// NewEncryptKey Creates AES-GCM token encryption key from 16, 24 or 32 raw bytes | 由16/24/32字节原始密钥创建AES-GCM Token加密密钥
+func NewEncryptKey(kid string, key []byte) (*EncryptKey, error) {
+	return token.NewEncryptKey(kid, key)
+}
+
+// NewEncryptKeyFromSecret Creates AES-256-GCM token encryption key derived from secret | 由密钥字符串派生AES-256-GCM Token加密密钥
+func NewEncryptKeyFromSecret(kid, secret string) (*EncryptKey, error) {
+	return token.NewEncryptKeyFromSecret(kid, secret)
+}
+
 // NewEventManager Creates a new event manager | 创建新的事件管理器
 func NewEventManager() *EventManager {
 	return listener.NewManager()
@@ -185,7 +361,52 @@ func NewRefreshTokenManager(storage Storage, prefix string, cfg *Config) *Refres
 	return security.NewRefreshTokenManager(storage, prefix, manager.TokenKeyPrefix, cfg)
 }
 
+// NewTempTokenManager Creates a new temp token manager | 创建新的临时Token管理器
+func NewTempTokenManager(storage Storage, prefix string) *TempTokenManager {
+	return security.NewTempTokenManager(storage, prefix)
+}
+
+// NewSignManager Creates a new API sign manager | 创建新的API签名管理器
+func NewSignManager(storage Storage, prefix string, cfg *SignConfig) *SignManager {
+	return security.NewSignManager(storage, prefix, cfg)
+}
+
 // NewOAuth2Server Creates a new OAuth2 server | 创建新的OAuth2服务器
 func NewOAuth2Server(storage Storage, prefix string) *OAuth2Server {
 	return oauth2.NewOAuth2Server(storage, prefix)
 }
+
+// S256Challenge Computes PKCE S256 code_challenge of verifier | 计算PKCE校验码的S256挑战值
+func S256Challenge(verifier string) string {
+	return oauth2.S256Challenge(verifier)
+}
+
+// NewOAuth2Handler Creates framework-neutral OAuth2 HTTP endpoints | 创建框架无关的OAuth2 HTTP端点
+func NewOAuth2Handler(server *OAuth2Server, consent OAuth2ConsentHook) *OAuth2Handler {
+	return oauth2.NewHandler(server, consent)
+}
+
+// NewOAuth2MemoryClientStore Creates per-process OAuth2 client store | 创建进程内OAuth2客户端存储
+func NewOAuth2MemoryClientStore() *OAuth2MemoryClientStore {
+	return oauth2.NewMemoryClientStore()
+}
+
+// NewOAuth2StorageClientStore Creates OAuth2 client store on storage | 创建基于存储的OAuth2客户端存储
+func NewOAuth2StorageClientStore(storage Storage, prefix string) *OAuth2StorageClientStore {
+	return oauth2.NewStorageClientStore(storage, prefix)
+}
+
+// NewOAuth2SQLClientStore Creates OAuth2 client store on a database/sql table | 创建基于database/sql表的OAuth2客户端存储
+func NewOAuth2SQLClientStore(db *sql.DB, table string) *OAuth2SQLClientStore {
+	return oauth2.NewSQLClientStore(db, table)
+}
+
+// NewSSOServer Creates a new SSO server | 创建新的SSO认证中心
+func NewSSOServer(mgr *Manager) *SSOServer {
+	return sso.NewSSOServer(mgr)
+}
+
+// NewSSOClient Creates a new SSO client | 创建新的SSO子系统客户端
+func NewSSOClient(mgr *Manager, clientID, secret string, checker sso.TicketChecker) *SSOClient {
+	return sso.NewSSOClient(mgr, clientID, secret, checker)
+}
@@ -4,7 +4,6 @@ import (
 	"crypto/rand"
 	"encoding/hex"
 	"fmt"
-	"sync"
 	"time"
 
 	"github.com/click33/sa-token-go/core/adapter"
@@ -32,7 +31,8 @@ const (
 
 // Error variables | 错误变量
 var (
-	ErrInvalidNonce = fmt.Errorf("invalid or expired nonce")
+	ErrInvalidNonce  = fmt.Errorf("invalid or expired nonce")
+	ErrNonceReplayed = fmt.Errorf("nonce has already been used")
 )
 
 // NonceManager Nonce manager for anti-replay attacks | Nonce管理器，用于防重放攻击
@@ -40,7 +40,6 @@ type NonceManager struct {
 	storage   adapter.Storage
 	keyPrefix string // Configurable prefix | 可配置的前缀
 	ttl       time.Duration
-	mu        sync.RWMutex
 }
 
 // NewNonceManager Creates a new nonce manager | 创建新的Nonce管理器
@@ -57,6 +56,13 @@ func NewNonceManager(storage adapter.Storage, prefix string, ttl time.Duration)
 	}
 }
 
+// WithStorage Returns a copy using storage, e.g. one bound to a request context | 返回使用storage的副本，如绑定请求上下文的存储
+func (nm *NonceManager) WithStorage(storage adapter.Storage) *NonceManager {
+	copied := *nm
+	copied.storage = storage
+	return &copied
+}
+
 // Generate Generates a new nonce and stores it | 生成新的nonce并存储
 // Returns 64-char hex string | 返回64字符的十六进制字符串
 func (nm *NonceManager) Generate() (string, error) {
@@ -81,17 +87,8 @@ func (nm *NonceManager) Verify(nonce string) bool {
 		return false
 	}
 
-	key := nm.getNonceKey(nonce)
-
-	nm.mu.Lock()
-	defer nm.mu.Unlock()
-
-	if !nm.storage.Exists(key) {
-		return false
-	}
-
-	nm.storage.Delete(key)
-	return true
+	_, err := adapter.GetDel(nm.storage, nm.getNonceKey(nonce))
+	return err == nil
 }
 
 // VerifyAndConsume Verifies and consumes nonce, returns error if invalid | 验证并消费nonce，无效时返回错误
@@ -102,18 +99,33 @@ func (nm *NonceManager) VerifyAndConsume(nonce string) error {
 	return nil
 }
 
+// Record Records a client-supplied nonce, fails if already seen within TTL | 记录客户端提供的nonce，TTL内重复出现则失败
+// ttl: 0 uses manager TTL | 0使用管理器默认TTL
+func (nm *NonceManager) Record(nonce string, ttl time.Duration) error {
+	if nonce == "" {
+		return ErrInvalidNonce
+	}
+	if ttl == 0 {
+		ttl = nm.ttl
+	}
+
+	// Set-if-absent so only the first of concurrent requests wins | 仅在不存在时写入，并发请求中只有第一个成功
+	recorded, err := adapter.SetNX(nm.storage, nm.getNonceKey(nonce), time.Now().Unix(), ttl)
+	if err != nil {
+		return fmt.Errorf("failed to store nonce: %w", err)
+	}
+	if !recorded {
+		return ErrNonceReplayed
+	}
+	return nil
+}
+
 // IsValid Checks if nonce is valid without consuming it | 检查nonce是否有效（不消费）
 func (nm *NonceManager) IsValid(nonce string) bool {
 	if nonce == "" {
 		return false
 	}
-
-	key := nm.getNonceKey(nonce)
-
-	nm.mu.RLock()
-	defer nm.mu.RUnlock()
-
-	return nm.storage.Exists(key)
+	return nm.storage.Exists(nm.getNonceKey(nonce))
 }
 
 // getNonceKey Gets storage key for nonce | 获取nonce的存储键
@@ -0,0 +1,111 @@
+package security
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/click33/sa-token-go/core/adapter/adaptertest"
+)
+
+func TestNonceGenerateVerify(t *testing.T) {
+	nm := NewNonceManager(adaptertest.NewMapStorage(), "satoken:", 0)
+
+	nonce, err := nm.Generate()
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if len(nonce) != NonceLength*2 {
+		t.Fatalf("nonce length = %d, want %d", len(nonce), NonceLength*2)
+	}
+	if !nm.IsValid(nonce) {
+		t.Fatal("IsValid() = false for fresh nonce")
+	}
+	if !nm.Verify(nonce) {
+		t.Fatal("Verify() = false for fresh nonce")
+	}
+	if nm.Verify(nonce) || nm.IsValid(nonce) {
+		t.Fatal("nonce accepted after it was consumed")
+	}
+	if err := nm.VerifyAndConsume(nonce); !errors.Is(err, ErrInvalidNonce) {
+		t.Fatalf("VerifyAndConsume(used) = %v, want ErrInvalidNonce", err)
+	}
+	if nm.Verify("") || nm.IsValid("") {
+		t.Fatal("empty nonce accepted")
+	}
+}
+
+func TestNonceExpiry(t *testing.T) {
+	nm := NewNonceManager(adaptertest.NewMapStorage(), "satoken:", 10*time.Millisecond)
+
+	nonce, err := nm.Generate()
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	time.Sleep(20 * time.Millisecond)
+	if nm.Verify(nonce) {
+		t.Fatal("Verify() accepted an expired nonce")
+	}
+
+	if err := nm.Record("client-nonce", 0); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+	time.Sleep(20 * time.Millisecond)
+	if err := nm.Record("client-nonce", 0); err != nil {
+		t.Fatalf("Record() after TTL error = %v", err)
+	}
+}
+
+func TestNonceRecord(t *testing.T) {
+	nm := NewNonceManager(adaptertest.NewMapStorage(), "satoken:", time.Minute)
+
+	if err := nm.Record("", 0); !errors.Is(err, ErrInvalidNonce) {
+		t.Fatalf("Record(empty) = %v, want ErrInvalidNonce", err)
+	}
+	if err := nm.Record("client-nonce", 0); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+	if err := nm.Record("client-nonce", 0); !errors.Is(err, ErrNonceReplayed) {
+		t.Fatalf("Record(replay) = %v, want ErrNonceReplayed", err)
+	}
+}
+
+func TestNonceRace(t *testing.T) {
+	nm := NewNonceManager(adaptertest.NewMapStorage(), "satoken:", time.Minute)
+	nonce, err := nm.Generate()
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		verified int
+		recorded int
+	)
+	for i := 0; i < 16; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			if nm.Verify(nonce) {
+				mu.Lock()
+				verified++
+				mu.Unlock()
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			if nm.Record("client-nonce", 0) == nil {
+				mu.Lock()
+				recorded++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if verified != 1 || recorded != 1 {
+		t.Fatalf("verified = %d, recorded = %d, want one winner each", verified, recorded)
+	}
+}
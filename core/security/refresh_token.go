@@ -92,6 +92,13 @@ func NewRefreshTokenManager(storage adapter.Storage, prefix, keyPrefix string, c
 	}
 }
 
+// WithStorage Returns a copy using storage, e.g. one bound to a request context | 返回使用storage的副本，如绑定请求上下文的存储
+func (rtm *RefreshTokenManager) WithStorage(storage adapter.Storage) *RefreshTokenManager {
+	copied := *rtm
+	copied.storage = storage
+	return &copied
+}
+
 // GenerateTokenPair Generates access token and refresh token pair | 生成访问令牌和刷新令牌对
 func (rtm *RefreshTokenManager) GenerateTokenPair(loginID, device string, accessTokenOverride ...string) (*RefreshTokenInfo, error) {
 	if loginID == "" {
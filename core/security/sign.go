@@ -0,0 +1,340 @@
+package security
+
+import (
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/click33/sa-token-go/core/adapter"
+)
+
+// Signed API Request Implementation
+// API请求签名实现
+//
+// Flow | 流程:
+// 1. Caller adds appId, timestamp, nonce and sign to its params | 调用方在参数中附加appId、timestamp、nonce与sign
+// 2. Canonical string = params sorted by key, URL-escaped "k=v" joined by "&", sign excluded | 规范串 = 按键排序并URL转义的"k=v"以"&"连接（排除sign）
+// 3. VerifyRequest() - Check secret signature, timestamp window and nonce replay | 校验签名、时间戳窗口与nonce防重放
+//
+// Usage | 用法:
+//   sm := manager.GetSignManager()
+//   sm.AddApp("partner-a", "secret")
+//   params, _ := sm.CreateSignParams("partner-a", map[string]string{"orderId": "1"}) // caller side | 调用方
+//   err := sm.VerifyRequest(ctx)                                                      // server side | 服务端
+
+// SignAlgorithm signature algorithm | 签名算法
+type SignAlgorithm string
+
+const (
+	SignAlgMD5        SignAlgorithm = "md5"         // md5(canonical&key=secret) | md5(规范串&key=密钥)
+	SignAlgSHA256     SignAlgorithm = "sha256"      // sha256(canonical&key=secret) | sha256(规范串&key=密钥)
+	SignAlgHMACSHA256 SignAlgorithm = "hmac-sha256" // hmac-sha256(secret, canonical) | 以密钥对规范串做HMAC-SHA256
+)
+
+// Constants for sign | 签名常量
+const (
+	DefaultSignTimestampDisparity = 15 * time.Minute // Allowed timestamp skew | 允许的时间戳偏差
+	SignKeySuffix                 = "sign:"          // Key suffix after prefix | 前缀后的键后缀
+
+	SignParamAppID     = "appId"      // App ID parameter | 应用ID参数
+	SignParamTimestamp = "timestamp"  // Timestamp parameter (unix seconds) | 时间戳参数（秒）
+	SignParamNonce     = "nonce"      // Nonce parameter | 随机数参数
+	SignParamSign      = "sign"       // Signature parameter | 签名参数
+	SignParamBody      = "bodyDigest" // Digest of non-form request body | 非表单请求体的摘要参数
+
+	signNonceLength = 16 // Generated nonce byte length | 生成的nonce字节长度
+)
+
+// Error variables | 错误变量
+var (
+	ErrSignMissingParam = fmt.Errorf("missing sign parameter")
+	ErrSignUnknownApp   = fmt.Errorf("unknown sign app")
+	ErrSignTimestamp    = fmt.Errorf("sign timestamp out of allowed window")
+	ErrSignMismatch     = fmt.Errorf("sign mismatch")
+	ErrSignAlgorithm    = fmt.Errorf("unsupported sign algorithm")
+	ErrSignDuplicate    = fmt.Errorf("duplicate sign parameter")
+)
+
+// SignConfig sign configuration | 签名配置
+type SignConfig struct {
+	Algorithm          SignAlgorithm // Signature algorithm, default hmac-sha256 | 签名算法，默认hmac-sha256
+	TimestampDisparity time.Duration // Allowed timestamp skew, default 15min | 允许的时间戳偏差，默认15分钟
+	CheckNonce         bool          // Reject replayed nonces | 是否拒绝重复的nonce
+}
+
+// DefaultSignConfig Returns default sign configuration | 返回默认签名配置
+func DefaultSignConfig() *SignConfig {
+	return &SignConfig{
+		Algorithm:          SignAlgHMACSHA256,
+		TimestampDisparity: DefaultSignTimestampDisparity,
+		CheckNonce:         true,
+	}
+}
+
+// SignManager API request signature manager | API请求签名管理器
+type SignManager struct {
+	config         *SignConfig
+	nonces         *NonceManager
+	secrets        map[string]string
+	secretsMu      *sync.RWMutex // Shared with WithStorage copies | 与WithStorage副本共享
+	secretProvider func(appID string) (string, error)
+}
+
+// NewSignManager Creates a new sign manager | 创建新的签名管理器
+// prefix: key prefix (e.g., "satoken:" or "" for Java compatibility) | 键前缀（如："satoken:" 或 "" 兼容Java）
+func NewSignManager(storage adapter.Storage, prefix string, cfg *SignConfig) *SignManager {
+	if cfg == nil {
+		cfg = DefaultSignConfig()
+	}
+	if cfg.Algorithm == "" {
+		cfg.Algorithm = SignAlgHMACSHA256
+	}
+	if cfg.TimestampDisparity <= 0 {
+		cfg.TimestampDisparity = DefaultSignTimestampDisparity
+	}
+
+	return &SignManager{
+		config: cfg,
+		// Nonces must outlive the whole timestamp window on both sides | nonce需覆盖时间戳前后两个窗口
+		nonces:    NewNonceManager(storage, prefix+SignKeySuffix, 2*cfg.TimestampDisparity),
+		secrets:   make(map[string]string),
+		secretsMu: &sync.RWMutex{},
+	}
+}
+
+// WithStorage Returns a copy recording nonces in storage, registered apps stay shared | 返回在storage中记录nonce的副本，已注册的应用保持共享
+func (sm *SignManager) WithStorage(storage adapter.Storage) *SignManager {
+	copied := *sm
+	copied.nonces = sm.nonces.WithStorage(storage)
+	return &copied
+}
+
+// GetConfig Gets sign configuration | 获取签名配置
+func (sm *SignManager) GetConfig() *SignConfig {
+	return sm.config
+}
+
+// AddApp Registers app secret | 注册应用密钥
+func (sm *SignManager) AddApp(appID, secret string) *SignManager {
+	sm.secretsMu.Lock()
+	defer sm.secretsMu.Unlock()
+
+	sm.secrets[appID] = secret
+	return sm
+}
+
+// RemoveApp Removes app secret | 移除应用密钥
+func (sm *SignManager) RemoveApp(appID string) {
+	sm.secretsMu.Lock()
+	defer sm.secretsMu.Unlock()
+
+	delete(sm.secrets, appID)
+}
+
+// SetSecretProvider Sets fallback secret lookup for apps not registered via AddApp | 设置未注册应用的密钥查询函数
+func (sm *SignManager) SetSecretProvider(provider func(appID string) (string, error)) *SignManager {
+	sm.secretProvider = provider
+	return sm
+}
+
+// GetSecret Gets secret of app | 获取应用密钥
+func (sm *SignManager) GetSecret(appID string) (string, error) {
+	sm.secretsMu.RLock()
+	secret, ok := sm.secrets[appID]
+	sm.secretsMu.RUnlock()
+	if ok {
+		return secret, nil
+	}
+
+	if sm.secretProvider != nil {
+		secret, err := sm.secretProvider(appID)
+		if err != nil {
+			return "", err
+		}
+		if secret != "" {
+			return secret, nil
+		}
+	}
+	return "", ErrSignUnknownApp
+}
+
+// Sign Computes signature of params with secret | 使用密钥计算参数签名
+func (sm *SignManager) Sign(secret string, params map[string]string) (string, error) {
+	canonical := CanonicalSignString(params)
+
+	switch sm.config.Algorithm {
+	case SignAlgMD5:
+		sum := md5.Sum([]byte(canonical + "&key=" + secret))
+		return hex.EncodeToString(sum[:]), nil
+	case SignAlgSHA256:
+		sum := sha256.Sum256([]byte(canonical + "&key=" + secret))
+		return hex.EncodeToString(sum[:]), nil
+	case SignAlgHMACSHA256:
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write([]byte(canonical))
+		return hex.EncodeToString(mac.Sum(nil)), nil
+	default:
+		return "", ErrSignAlgorithm
+	}
+}
+
+// CreateSignParams Adds appId, timestamp, nonce and sign to params (caller side) | 为参数附加appId、timestamp、nonce与sign（调用方使用）
+func (sm *SignManager) CreateSignParams(appID string, params map[string]string) (map[string]string, error) {
+	secret, err := sm.GetSecret(appID)
+	if err != nil {
+		return nil, err
+	}
+
+	nonceBytes := make([]byte, signNonceLength)
+	if _, err := rand.Read(nonceBytes); err != nil {
+		return nil, fmt.Errorf("failed to generate random bytes: %w", err)
+	}
+
+	signed := make(map[string]string, len(params)+4)
+	for k, v := range params {
+		signed[k] = v
+	}
+	signed[SignParamAppID] = appID
+	signed[SignParamTimestamp] = strconv.FormatInt(time.Now().Unix(), 10)
+	signed[SignParamNonce] = hex.EncodeToString(nonceBytes)
+
+	sign, err := sm.Sign(secret, signed)
+	if err != nil {
+		return nil, err
+	}
+	signed[SignParamSign] = sign
+	return signed, nil
+}
+
+// Verify Verifies signed params | 校验签名参数
+func (sm *SignManager) Verify(params map[string]string) error {
+	appID := params[SignParamAppID]
+	timestamp := params[SignParamTimestamp]
+	nonce := params[SignParamNonce]
+	sign := params[SignParamSign]
+	if appID == "" || timestamp == "" || nonce == "" || sign == "" {
+		return ErrSignMissingParam
+	}
+
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrSignTimestamp
+	}
+	skew := time.Since(time.Unix(ts, 0))
+	if skew > sm.config.TimestampDisparity || skew < -sm.config.TimestampDisparity {
+		return ErrSignTimestamp
+	}
+
+	secret, err := sm.GetSecret(appID)
+	if err != nil {
+		return err
+	}
+	expected, err := sm.Sign(secret, params)
+	if err != nil {
+		return err
+	}
+	if subtle.ConstantTimeCompare([]byte(expected), []byte(strings.ToLower(sign))) != 1 {
+		return ErrSignMismatch
+	}
+
+	// Record nonce only after signature passes, so forged requests cannot burn nonces | 签名通过后才记录nonce，防止伪造请求占用nonce
+	if sm.config.CheckNonce {
+		return sm.nonces.Record(appID+":"+nonce, 0)
+	}
+	return nil
+}
+
+// VerifyRequest Collects params from request and verifies them | 从请求收集参数并校验
+func (sm *SignManager) VerifyRequest(ctx adapter.RequestContext) error {
+	params, err := CollectSignParams(ctx)
+	if err != nil {
+		return err
+	}
+	return sm.Verify(params)
+}
+
+// ============ Canonicalization | 规范化 ============
+
+// CanonicalSignString Builds canonical string from params, excluding sign | 由参数构建规范串（排除sign）
+// Keys and values are escaped with url.QueryEscape, so "&" or "=" inside a value cannot forge another param | 键与值经url.QueryEscape转义，值中的"&"或"="无法伪造其他参数
+func CanonicalSignString(params map[string]string) string {
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		if k == SignParamSign {
+			continue
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var sb strings.Builder
+	for i, k := range keys {
+		if i > 0 {
+			sb.WriteByte('&')
+		}
+		sb.WriteString(url.QueryEscape(k))
+		sb.WriteByte('=')
+		sb.WriteString(url.QueryEscape(params[k]))
+	}
+	return sb.String()
+}
+
+// CollectSignParams Collects query, form and body params from request | 从请求中收集查询、表单与请求体参数
+// Form-encoded bodies are merged as params, any other non-empty body is signed via its SHA256 digest under "bodyDigest"
+// 表单请求体按参数合并，其他非空请求体以SHA256摘要作为"bodyDigest"参与签名
+// A key repeated within the query or body, or present in both, is rejected so the signed values are unambiguous
+// 在查询或请求体中重复出现、或同时出现在两者中的键会被拒绝，保证签名值无歧义
+func CollectSignParams(ctx adapter.RequestContext) (map[string]string, error) {
+	params := make(map[string]string)
+	if err := addSignParams(params, ctx.GetQueryAll()); err != nil {
+		return nil, err
+	}
+
+	body, err := ctx.GetBody()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request body: %w", err)
+	}
+	if len(body) == 0 {
+		return params, nil
+	}
+
+	if strings.HasPrefix(strings.ToLower(ctx.GetHeader("Content-Type")), "application/x-www-form-urlencoded") {
+		form, err := url.ParseQuery(string(body))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse form body: %w", err)
+		}
+		if err := addSignParams(params, form); err != nil {
+			return nil, err
+		}
+		return params, nil
+	}
+
+	sum := sha256.Sum256(body)
+	if err := addSignParams(params, map[string][]string{SignParamBody: {hex.EncodeToString(sum[:])}}); err != nil {
+		return nil, err
+	}
+	return params, nil
+}
+
+// addSignParams 将单值参数合并到params，重复或已存在的键返回ErrSignDuplicate
+func addSignParams(params map[string]string, values map[string][]string) error {
+	for k, v := range values {
+		if _, exists := params[k]; exists || len(v) > 1 {
+			return fmt.Errorf("%w: %s", ErrSignDuplicate, k)
+		}
+		if len(v) == 1 {
+			params[k] = v[0]
+		}
+	}
+	return nil
+}
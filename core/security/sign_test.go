@@ -0,0 +1,253 @@
+package security
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/click33/sa-token-go/core/adapter/adaptertest"
+)
+
+func newTestSignManager(cfg *SignConfig) *SignManager {
+	return NewSignManager(adaptertest.NewMapStorage(), "satoken:", cfg).AddApp("partner-a", "secret-a")
+}
+
+func TestCanonicalSignString(t *testing.T) {
+	got := CanonicalSignString(map[string]string{"b": "2", "a": "1", SignParamSign: "ignored"})
+	if got != "a=1&b=2" {
+		t.Fatalf("CanonicalSignString() = %q, want a=1&b=2", got)
+	}
+
+	// Separators inside values must not collide with separate params | 值中的分隔符不能与独立参数冲突
+	joined := CanonicalSignString(map[string]string{"a": "1&b=2"})
+	split := CanonicalSignString(map[string]string{"a": "1", "b": "2"})
+	if joined == split {
+		t.Fatalf("CanonicalSignString() collides: %q", joined)
+	}
+	if joined != "a=1%26b%3D2" {
+		t.Fatalf("CanonicalSignString() = %q, want escaped value", joined)
+	}
+	if key := CanonicalSignString(map[string]string{"a=1&b": "2"}); key != "a%3D1%26b=2" {
+		t.Fatalf("CanonicalSignString() = %q, want escaped key", key)
+	}
+}
+
+func TestSignAlgorithms(t *testing.T) {
+	for _, alg := range []SignAlgorithm{SignAlgMD5, SignAlgSHA256, SignAlgHMACSHA256} {
+		t.Run(string(alg), func(t *testing.T) {
+			sm := newTestSignManager(&SignConfig{Algorithm: alg, CheckNonce: true})
+
+			params, err := sm.CreateSignParams("partner-a", map[string]string{"orderId": "1"})
+			if err != nil {
+				t.Fatalf("CreateSignParams() error = %v", err)
+			}
+			if err := sm.Verify(params); err != nil {
+				t.Fatalf("Verify() error = %v", err)
+			}
+
+			// Upper-case signatures are accepted | 接受大写签名
+			params[SignParamNonce] = "other-nonce"
+			params[SignParamSign], _ = sm.Sign("secret-a", params)
+			params[SignParamSign] = strings.ToUpper(params[SignParamSign])
+			if err := sm.Verify(params); err != nil {
+				t.Fatalf("Verify(upper-case) error = %v", err)
+			}
+		})
+	}
+
+	sm := newTestSignManager(&SignConfig{Algorithm: "rsa"})
+	if _, err := sm.Sign("secret-a", nil); !errors.Is(err, ErrSignAlgorithm) {
+		t.Fatalf("Sign(rsa) = %v, want ErrSignAlgorithm", err)
+	}
+}
+
+func TestSignVerifyRejects(t *testing.T) {
+	sm := newTestSignManager(nil)
+	signed := func(mutate func(params map[string]string)) map[string]string {
+		params, err := sm.CreateSignParams("partner-a", map[string]string{"orderId": "1"})
+		if err != nil {
+			t.Fatalf("CreateSignParams() error = %v", err)
+		}
+		if mutate != nil {
+			mutate(params)
+		}
+		return params
+	}
+	resign := func(params map[string]string) {
+		params[SignParamSign], _ = sm.Sign("secret-a", params)
+	}
+
+	tests := []struct {
+		name   string
+		params map[string]string
+		want   error
+	}{
+		{"tampered value", signed(func(p map[string]string) { p["orderId"] = "2" }), ErrSignMismatch},
+		{"injected param", signed(func(p map[string]string) { p["orderId"] = "1&admin=1" }), ErrSignMismatch},
+		{"missing nonce", signed(func(p map[string]string) { delete(p, SignParamNonce) }), ErrSignMissingParam},
+		{"unknown app", signed(func(p map[string]string) { p[SignParamAppID] = "partner-b" }), ErrSignUnknownApp},
+		{"bad timestamp", signed(func(p map[string]string) { p[SignParamTimestamp] = "now" }), ErrSignTimestamp},
+		{"old timestamp", signed(func(p map[string]string) {
+			p[SignParamTimestamp] = strconv.FormatInt(time.Now().Add(-time.Hour).Unix(), 10)
+			resign(p)
+		}), ErrSignTimestamp},
+		{"future timestamp", signed(func(p map[string]string) {
+			p[SignParamTimestamp] = strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10)
+			resign(p)
+		}), ErrSignTimestamp},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := sm.Verify(tt.params); !errors.Is(err, tt.want) {
+				t.Fatalf("Verify() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestSignNonceReplay(t *testing.T) {
+	sm := newTestSignManager(nil)
+	params, err := sm.CreateSignParams("partner-a", nil)
+	if err != nil {
+		t.Fatalf("CreateSignParams() error = %v", err)
+	}
+
+	// A forged request must not burn the nonce | 伪造请求不能占用nonce
+	forged := make(map[string]string, len(params))
+	for k, v := range params {
+		forged[k] = v
+	}
+	forged[SignParamSign] = "forged"
+	if err := sm.Verify(forged); !errors.Is(err, ErrSignMismatch) {
+		t.Fatalf("Verify(forged) = %v, want ErrSignMismatch", err)
+	}
+
+	if err := sm.Verify(params); err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if err := sm.Verify(params); !errors.Is(err, ErrNonceReplayed) {
+		t.Fatalf("Verify(replay) = %v, want ErrNonceReplayed", err)
+	}
+
+	noNonce := newTestSignManager(&SignConfig{CheckNonce: false})
+	params, _ = noNonce.CreateSignParams("partner-a", nil)
+	if err := noNonce.Verify(params); err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if err := noNonce.Verify(params); err != nil {
+		t.Fatalf("Verify(replay without nonce check) error = %v", err)
+	}
+}
+
+func TestSignSecretProvider(t *testing.T) {
+	sm := newTestSignManager(nil).SetSecretProvider(func(appID string) (string, error) {
+		if appID == "partner-b" {
+			return "secret-b", nil
+		}
+		return "", nil
+	})
+
+	if secret, err := sm.GetSecret("partner-b"); err != nil || secret != "secret-b" {
+		t.Fatalf("GetSecret(partner-b) = %q, %v", secret, err)
+	}
+	if _, err := sm.GetSecret("partner-c"); !errors.Is(err, ErrSignUnknownApp) {
+		t.Fatalf("GetSecret(partner-c) = %v, want ErrSignUnknownApp", err)
+	}
+
+	sm.RemoveApp("partner-a")
+	if _, err := sm.GetSecret("partner-a"); !errors.Is(err, ErrSignUnknownApp) {
+		t.Fatalf("GetSecret(removed) = %v, want ErrSignUnknownApp", err)
+	}
+}
+
+func TestSignVerifyRequest(t *testing.T) {
+	sm := newTestSignManager(nil)
+
+	t.Run("query and form", func(t *testing.T) {
+		params, _ := sm.CreateSignParams("partner-a", map[string]string{"orderId": "1", "note": "a&b=c"})
+		query := url.Values{}
+		form := url.Values{}
+		for k, v := range params {
+			if k == "note" {
+				form.Set(k, v)
+			} else {
+				query.Set(k, v)
+			}
+		}
+		r := httptest.NewRequest(http.MethodPost, "/pay?"+query.Encode(), strings.NewReader(form.Encode()))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+		if err := sm.VerifyRequest(adaptertest.NewRequestContext(r)); err != nil {
+			t.Fatalf("VerifyRequest() error = %v", err)
+		}
+	})
+
+	t.Run("duplicate params", func(t *testing.T) {
+		tests := []struct {
+			name  string
+			query string
+			form  string
+			body  string
+		}{
+			{"repeated in query", "a=1&a=2", "", ""},
+			{"repeated in form", "", "a=1&a=2", ""},
+			{"query and form", "a=1", "a=1", ""},
+			{"bodyDigest in query", SignParamBody + "=x", "", `{"a":1}`},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				r := httptest.NewRequest(http.MethodPost, "/pay?"+tt.query, strings.NewReader(tt.form+tt.body))
+				if tt.form != "" {
+					r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+				} else {
+					r.Header.Set("Content-Type", "application/json")
+				}
+				if _, err := CollectSignParams(adaptertest.NewRequestContext(r)); !errors.Is(err, ErrSignDuplicate) {
+					t.Fatalf("CollectSignParams() error = %v, want ErrSignDuplicate", err)
+				}
+			})
+		}
+	})
+
+	t.Run("json body digest", func(t *testing.T) {
+		body := `{"orderId":1}`
+		collect := func(body string) map[string]string {
+			r := httptest.NewRequest(http.MethodPost, "/pay", strings.NewReader(body))
+			r.Header.Set("Content-Type", "application/json")
+			params, err := CollectSignParams(adaptertest.NewRequestContext(r))
+			if err != nil {
+				t.Fatalf("CollectSignParams() error = %v", err)
+			}
+			return params
+		}
+		digest := collect(body)[SignParamBody]
+		if digest == "" || digest == collect(`{"orderId":2}`)[SignParamBody] {
+			t.Fatalf("bodyDigest = %q, want a digest bound to the body", digest)
+		}
+
+		params, _ := sm.CreateSignParams("partner-a", map[string]string{SignParamBody: digest})
+		query := url.Values{}
+		for k, v := range params {
+			if k != SignParamBody {
+				query.Set(k, v)
+			}
+		}
+		r := httptest.NewRequest(http.MethodPost, "/pay?"+query.Encode(), strings.NewReader(body))
+		r.Header.Set("Content-Type", "application/json")
+		if err := sm.VerifyRequest(adaptertest.NewRequestContext(r)); err != nil {
+			t.Fatalf("VerifyRequest() error = %v", err)
+		}
+
+		r = httptest.NewRequest(http.MethodPost, "/pay?"+query.Encode(), strings.NewReader(`{"orderId":2}`))
+		r.Header.Set("Content-Type", "application/json")
+		if err := sm.VerifyRequest(adaptertest.NewRequestContext(r)); !errors.Is(err, ErrSignMismatch) {
+			t.Fatalf("VerifyRequest(tampered body) = %v, want ErrSignMismatch", err)
+		}
+	})
+}
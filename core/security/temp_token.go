@@ -0,0 +1,215 @@
+package security
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/click33/sa-token-go/core/adapter"
+	"github.com/click33/sa-token-go/core/utils"
+)
+
+// Temporary Token Implementation
+// 临时Token实现
+//
+// Flow | 流程:
+// 1. Create() - Issue a purpose-bound token carrying a value | 签发绑定业务的临时Token（携带任意值）
+// 2. Parse() - Read value without consuming | 读取值（不消费）
+// 3. Consume() - Read value and delete (one-time use) | 读取值并删除（一次性使用）
+// 4. Auto-expire after TTL (default 10min) | TTL后自动过期（默认10分钟）
+//
+// Usage | 用法:
+//   token, _ := manager.CreateTempToken("reset-password", userID, 600)
+//   info, _ := manager.ConsumeTempToken("reset-password", token)  // ok
+//   _, err := manager.ConsumeTempToken("reset-password", token)   // ErrInvalidTempToken
+
+// Constants for temp token | 临时Token常量
+const (
+	DefaultTempTokenTTL = 10 * time.Minute // Default temp token expiration | 默认临时Token过期时间
+	TempTokenLength     = 32               // Temp token byte length | 临时Token字节长度
+	TempTokenKeySuffix  = "temp-token:"    // Key suffix after prefix | 前缀后的键后缀
+)
+
+// Error variables | 错误变量
+var (
+	ErrInvalidTempToken     = fmt.Errorf("invalid or expired temp token")
+	ErrInvalidTempTokenData = fmt.Errorf("invalid temp token data")
+	ErrTempTokenService     = fmt.Errorf("temp token service mismatch")
+)
+
+// TempTokenInfo temp token information | 临时Token信息
+type TempTokenInfo struct {
+	Token      string `json:"token"`      // Temp token | 临时Token
+	Service    string `json:"service"`    // Service name the token is bound to | 绑定的业务标识
+	Value      any    `json:"value"`      // Carried value, round-trips through JSON | 携带的值（经JSON序列化）
+	CreateTime int64  `json:"createTime"` // Creation timestamp | 创建时间戳
+	ExpireTime int64  `json:"expireTime"` // Expiration timestamp, 0 means never | 过期时间戳，0表示永不过期
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler for Redis storage | 实现encoding.BinaryMarshaler接口用于Redis存储
+func (t *TempTokenInfo) MarshalBinary() ([]byte, error) {
+	return json.Marshal(t)
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler for Redis storage | 实现encoding.BinaryUnmarshaler接口用于Redis存储
+func (t *TempTokenInfo) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, t)
+}
+
+// DecodeValue Decodes carried value into out | 将携带的值解码到out
+func (t *TempTokenInfo) DecodeValue(out any) error {
+	data, err := json.Marshal(t.Value)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, out)
+}
+
+// TempTokenManager Temporary token manager | 临时Token管理器
+type TempTokenManager struct {
+	storage   adapter.Storage
+	keyPrefix string // Configurable prefix | 可配置的前缀
+}
+
+// NewTempTokenManager Creates a new temp token manager | 创建新的临时Token管理器
+// prefix: key prefix (e.g., "satoken:" or "" for Java compatibility) | 键前缀（如："satoken:" 或 "" 兼容Java）
+func NewTempTokenManager(storage adapter.Storage, prefix string) *TempTokenManager {
+	return &TempTokenManager{
+		storage:   storage,
+		keyPrefix: prefix,
+	}
+}
+
+// WithStorage Returns a copy using storage, e.g. one bound to a request context | 返回使用storage的副本，如绑定请求上下文的存储
+func (tm *TempTokenManager) WithStorage(storage adapter.Storage) *TempTokenManager {
+	copied := *tm
+	copied.storage = storage
+	return &copied
+}
+
+// Create Issues a temp token bound to service | 签发绑定业务的临时Token
+// ttl: 0 uses default 10 minutes, negative means never expire | 0使用默认10分钟，负数表示永不过期
+func (tm *TempTokenManager) Create(service string, value any, ttl time.Duration) (string, error) {
+	if ttl == 0 {
+		ttl = DefaultTempTokenTTL
+	}
+
+	bytes := make([]byte, TempTokenLength)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", fmt.Errorf("failed to generate random bytes: %w", err)
+	}
+	tempToken := hex.EncodeToString(bytes)
+
+	now := time.Now()
+	info := &TempTokenInfo{
+		Token:      tempToken,
+		Service:    service,
+		Value:      value,
+		CreateTime: now.Unix(),
+	}
+
+	var expiration time.Duration
+	if ttl > 0 {
+		expiration = ttl
+		info.ExpireTime = now.Add(ttl).Unix()
+	}
+
+	// Store serialized form so that every storage backend round-trips it | 存储序列化结果，保证各存储后端均可还原
+	data, err := info.MarshalBinary()
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal temp token: %w", err)
+	}
+	if err := tm.storage.Set(tm.getTempTokenKey(tempToken), string(data), expiration); err != nil {
+		return "", fmt.Errorf("failed to store temp token: %w", err)
+	}
+
+	return tempToken, nil
+}
+
+// Parse Gets temp token info without consuming it | 获取临时Token信息（不消费）
+func (tm *TempTokenManager) Parse(service, tempToken string) (*TempTokenInfo, error) {
+	info, err := tm.load(tempToken)
+	if err != nil {
+		return nil, err
+	}
+	if info.Service != service {
+		return nil, ErrTempTokenService
+	}
+	return info, nil
+}
+
+// Consume Gets temp token info and deletes it (one-time use) | 获取临时Token信息并删除（一次性使用）
+// A service mismatch leaves the token in place, concurrent consumers get one winner | 业务不匹配时不消费，并发消费仅一个成功
+func (tm *TempTokenManager) Consume(service, tempToken string) (*TempTokenInfo, error) {
+	if _, err := tm.Parse(service, tempToken); err != nil {
+		return nil, err
+	}
+
+	data, err := adapter.GetDel(tm.storage, tm.getTempTokenKey(tempToken))
+	if err != nil || data == nil {
+		return nil, ErrInvalidTempToken
+	}
+	return decodeTempToken(data)
+}
+
+// GetTimeout Gets remaining TTL in seconds (-1 never expire, -2 not exist) | 获取剩余有效时间（秒）（-1永不过期，-2不存在）
+func (tm *TempTokenManager) GetTimeout(tempToken string) int64 {
+	if tempToken == "" {
+		return -2
+	}
+	ttl, err := tm.storage.TTL(tm.getTempTokenKey(tempToken))
+	if err != nil {
+		return -2
+	}
+
+	// Storages report -1/-2 in different units, e.g. go-redis returns -1ns/-2ns | 各存储返回-1/-2的单位不同，如go-redis返回-1ns/-2ns
+	switch {
+	case ttl == -1 || ttl == -1*time.Second:
+		return -1
+	case ttl < 0:
+		return -2
+	}
+	return int64(ttl.Seconds())
+}
+
+// Delete Deletes a temp token | 删除临时Token
+func (tm *TempTokenManager) Delete(tempToken string) error {
+	if tempToken == "" {
+		return nil
+	}
+	return tm.storage.Delete(tm.getTempTokenKey(tempToken))
+}
+
+// load Loads temp token info from storage | 从存储加载临时Token信息
+func (tm *TempTokenManager) load(tempToken string) (*TempTokenInfo, error) {
+	if tempToken == "" {
+		return nil, ErrInvalidTempToken
+	}
+
+	data, err := tm.storage.Get(tm.getTempTokenKey(tempToken))
+	if err != nil || data == nil {
+		return nil, ErrInvalidTempToken
+	}
+	return decodeTempToken(data)
+}
+
+// decodeTempToken Decodes stored temp token info | 解码存储的临时Token信息
+func decodeTempToken(data any) (*TempTokenInfo, error) {
+	dataBytes, err := utils.ToBytes(data)
+	if err != nil {
+		return nil, ErrInvalidTempTokenData
+	}
+
+	info := &TempTokenInfo{}
+	if err := info.UnmarshalBinary(dataBytes); err != nil {
+		return nil, ErrInvalidTempTokenData
+	}
+	return info, nil
+}
+
+// getTempTokenKey Gets storage key for temp token | 获取临时Token的存储键
+func (tm *TempTokenManager) getTempTokenKey(tempToken string) string {
+	return tm.keyPrefix + TempTokenKeySuffix + tempToken
+}
@@ -0,0 +1,163 @@
+package security
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/click33/sa-token-go/core/adapter/adaptertest"
+)
+
+func newTestTempTokens() *TempTokenManager {
+	return NewTempTokenManager(adaptertest.NewMapStorage(), "satoken:")
+}
+
+func TestTempTokenCreateParse(t *testing.T) {
+	tm := newTestTempTokens()
+
+	token, err := tm.Create("reset-password", map[string]any{"userId": 1001}, time.Minute)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if len(token) != TempTokenLength*2 {
+		t.Fatalf("token length = %d, want %d", len(token), TempTokenLength*2)
+	}
+
+	info, err := tm.Parse("reset-password", token)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	var value struct {
+		UserID int `json:"userId"`
+	}
+	if err := info.DecodeValue(&value); err != nil || value.UserID != 1001 {
+		t.Fatalf("DecodeValue() = %+v, %v", value, err)
+	}
+	if info.ExpireTime-info.CreateTime != 60 {
+		t.Fatalf("ExpireTime - CreateTime = %d, want 60", info.ExpireTime-info.CreateTime)
+	}
+	if timeout := tm.GetTimeout(token); timeout <= 0 || timeout > 60 {
+		t.Fatalf("GetTimeout() = %d, want (0, 60]", timeout)
+	}
+
+	// Parse does not consume | Parse不消费
+	if _, err := tm.Parse("reset-password", token); err != nil {
+		t.Fatalf("second Parse() error = %v", err)
+	}
+}
+
+func TestTempTokenConsume(t *testing.T) {
+	tm := newTestTempTokens()
+	token, err := tm.Create("reset-password", "1001", 0)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	info, err := tm.Consume("reset-password", token)
+	if err != nil || info.Value != "1001" {
+		t.Fatalf("Consume() = %+v, %v", info, err)
+	}
+	if _, err := tm.Consume("reset-password", token); !errors.Is(err, ErrInvalidTempToken) {
+		t.Fatalf("second Consume() = %v, want ErrInvalidTempToken", err)
+	}
+	if _, err := tm.Parse("reset-password", token); !errors.Is(err, ErrInvalidTempToken) {
+		t.Fatalf("Parse() after Consume = %v, want ErrInvalidTempToken", err)
+	}
+	if timeout := tm.GetTimeout(token); timeout != -2 {
+		t.Fatalf("GetTimeout() after Consume = %d, want -2", timeout)
+	}
+}
+
+func TestTempTokenServiceMismatch(t *testing.T) {
+	tm := newTestTempTokens()
+	token, err := tm.Create("reset-password", "1001", time.Minute)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	if _, err := tm.Parse("bind-email", token); !errors.Is(err, ErrTempTokenService) {
+		t.Fatalf("Parse(other service) = %v, want ErrTempTokenService", err)
+	}
+	if _, err := tm.Consume("bind-email", token); !errors.Is(err, ErrTempTokenService) {
+		t.Fatalf("Consume(other service) = %v, want ErrTempTokenService", err)
+	}
+	// A mismatch must not burn the token | 业务不匹配不能消耗Token
+	if _, err := tm.Consume("reset-password", token); err != nil {
+		t.Fatalf("Consume() after mismatch error = %v", err)
+	}
+}
+
+func TestTempTokenExpiry(t *testing.T) {
+	tm := newTestTempTokens()
+
+	token, err := tm.Create("reset-password", "1001", 10*time.Millisecond)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	time.Sleep(20 * time.Millisecond)
+	if _, err := tm.Parse("reset-password", token); !errors.Is(err, ErrInvalidTempToken) {
+		t.Fatalf("Parse(expired) = %v, want ErrInvalidTempToken", err)
+	}
+	if _, err := tm.Consume("reset-password", token); !errors.Is(err, ErrInvalidTempToken) {
+		t.Fatalf("Consume(expired) = %v, want ErrInvalidTempToken", err)
+	}
+
+	forever, err := tm.Create("invite", "1001", -1)
+	if err != nil {
+		t.Fatalf("Create(never expire) error = %v", err)
+	}
+	if timeout := tm.GetTimeout(forever); timeout != -1 {
+		t.Fatalf("GetTimeout(never expire) = %d, want -1", timeout)
+	}
+	if info, _ := tm.Parse("invite", forever); info == nil || info.ExpireTime != 0 {
+		t.Fatalf("Parse(never expire) = %+v, want ExpireTime 0", info)
+	}
+}
+
+func TestTempTokenConsumeOnce(t *testing.T) {
+	tm := newTestTempTokens()
+	token, err := tm.Create("reset-password", "1001", time.Minute)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		wins int
+	)
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := tm.Consume("reset-password", token); err == nil {
+				mu.Lock()
+				wins++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if wins != 1 {
+		t.Fatalf("temp token consumed %d times, want 1", wins)
+	}
+}
+
+func TestTempTokenInvalid(t *testing.T) {
+	tm := newTestTempTokens()
+
+	if _, err := tm.Parse("reset-password", ""); !errors.Is(err, ErrInvalidTempToken) {
+		t.Fatalf("Parse(empty) = %v, want ErrInvalidTempToken", err)
+	}
+	if _, err := tm.Consume("reset-password", "missing"); !errors.Is(err, ErrInvalidTempToken) {
+		t.Fatalf("Consume(missing) = %v, want ErrInvalidTempToken", err)
+	}
+	if err := tm.storage.Set(tm.getTempTokenKey("broken"), "{", time.Minute); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if _, err := tm.Parse("reset-password", "broken"); !errors.Is(err, ErrInvalidTempTokenData) {
+		t.Fatalf("Parse(broken) = %v, want ErrInvalidTempTokenData", err)
+	}
+}
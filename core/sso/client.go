@@ -0,0 +1,168 @@
+package sso
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/click33/sa-token-go/core/manager"
+)
+
+// TicketChecker exchanges a ticket for the login ID at SSO server | 向认证中心用Ticket换取登录ID
+// *SSOServer implements it for same-process deployments | 同进程部署时可直接使用*SSOServer
+type TicketChecker interface {
+	CheckTicket(ticket, clientID string) (string, error)
+}
+
+// SSOClient SSO client (subsystem) side | SSO子系统端
+type SSOClient struct {
+	manager  *manager.Manager
+	clientID string
+	secret   string
+	device   string
+	checker  TicketChecker
+}
+
+// NewSSOClient Creates a new SSO client on top of the subsystem's manager | 基于子系统Manager创建SSO客户端
+func NewSSOClient(mgr *manager.Manager, clientID, secret string, checker TicketChecker) *SSOClient {
+	return &SSOClient{
+		manager:  mgr,
+		clientID: clientID,
+		secret:   secret,
+		checker:  checker,
+	}
+}
+
+// SetDevice Sets device used for local login | 设置本地登录使用的设备类型
+func (c *SSOClient) SetDevice(device string) *SSOClient {
+	c.device = device
+	return c
+}
+
+// GetClientID Gets client ID | 获取子系统ID
+func (c *SSOClient) GetClientID() string {
+	return c.clientID
+}
+
+// BuildAuthURL Builds the SSO server login URL to redirect user to | 构建跳转到认证中心的登录地址
+func (c *SSOClient) BuildAuthURL(serverAuthURL, redirect string) (string, error) {
+	u, err := url.Parse(serverAuthURL)
+	if err != nil {
+		return "", err
+	}
+	query := u.Query()
+	query.Set(ParamClientID, c.clientID)
+	query.Set(ParamRedirect, redirect)
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
+// CheckTicket Exchanges ticket for login ID without local login | 用Ticket换取登录ID（不进行本地登录）
+func (c *SSOClient) CheckTicket(ticket string) (string, error) {
+	if c.checker == nil {
+		return "", fmt.Errorf("sso ticket checker is not configured")
+	}
+	return c.checker.CheckTicket(ticket, c.clientID)
+}
+
+// Login Exchanges ticket and logs in locally | 用Ticket换取登录ID并在本地登录
+func (c *SSOClient) Login(ticket string) (string, error) {
+	loginID, err := c.CheckTicket(ticket)
+	if err != nil {
+		return "", err
+	}
+	return c.manager.Login(loginID, c.getDevice()...)
+}
+
+// HandleLogout Verifies a single logout notification and logs out locally | 校验单点注销通知并在本地登出
+// Unsigned notifications are always rejected, a client without secret cannot accept any | 始终拒绝未签名的通知，未配置密钥的客户端不接受任何通知
+func (c *SSOClient) HandleLogout(loginID, timestamp, sign string) error {
+	if c.secret == "" {
+		return ErrSecretRequired
+	}
+	if err := VerifySign(c.secret, timestamp, sign, loginID, c.clientID); err != nil {
+		return err
+	}
+	return c.manager.Logout(loginID, c.getDevice()...)
+}
+
+// SloHandler HTTP endpoint receiving single logout notifications | 接收单点注销通知的HTTP接口
+func (c *SSOClient) SloHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		err := c.HandleLogout(r.FormValue(ParamLoginID), r.FormValue(ParamTimestamp), r.FormValue(ParamSign))
+		if err != nil {
+			writeJSON(w, http.StatusUnauthorized, err.Error(), nil)
+			return
+		}
+		writeJSON(w, http.StatusOK, "success", nil)
+	}
+}
+
+// getDevice Gets device parameter for manager calls | 获取Manager调用使用的设备参数
+func (c *SSOClient) getDevice() []string {
+	if c.device == "" {
+		return nil
+	}
+	return []string{c.device}
+}
+
+// ============ Remote Ticket Checker | 远程Ticket校验 ============
+
+// HTTPTicketChecker Checks tickets against SSOServer.CheckTicketHandler over HTTP | 通过HTTP调用认证中心校验Ticket
+type HTTPTicketChecker struct {
+	checkURL string
+	secret   string
+	client   *http.Client
+}
+
+// NewHTTPTicketChecker Creates HTTP ticket checker, nil client uses a 5s-timeout client | 创建HTTP Ticket校验器，nil使用5秒超时的客户端
+func NewHTTPTicketChecker(checkURL, secret string, client *http.Client) *HTTPTicketChecker {
+	if client == nil {
+		client = &http.Client{Timeout: 5 * time.Second}
+	}
+	return &HTTPTicketChecker{
+		checkURL: checkURL,
+		secret:   secret,
+		client:   client,
+	}
+}
+
+// CheckTicket Implements TicketChecker | 实现TicketChecker
+func (h *HTTPTicketChecker) CheckTicket(ticket, clientID string) (string, error) {
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	form := url.Values{
+		ParamTicket:    {ticket},
+		ParamClientID:  {clientID},
+		ParamTimestamp: {timestamp},
+	}
+	if h.secret != "" {
+		form.Set(ParamSign, Sign(h.secret, timestamp, ticket, clientID))
+	}
+
+	resp, err := h.client.PostForm(h.checkURL, form)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		Code    int               `json:"code"`
+		Message string            `json:"message"`
+		Data    map[string]string `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("%w: %s", ErrInvalidTicket, body.Message)
+	}
+
+	loginID := body.Data[ParamLoginID]
+	if loginID == "" {
+		return "", ErrInvalidTicket
+	}
+	return loginID, nil
+}
@@ -0,0 +1,514 @@
+package sso
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/click33/sa-token-go/core/adapter"
+	"github.com/click33/sa-token-go/core/listener"
+	"github.com/click33/sa-token-go/core/manager"
+	"github.com/click33/sa-token-go/core/utils"
+)
+
+// SSO Ticket-based Single Sign-On Implementation
+// 基于Ticket的单点登录实现
+//
+// Flow | 流程:
+// 1. Client redirects unauthenticated user to SSO server with redirect URL | 子系统将未登录用户重定向到认证中心（携带回跳地址）
+// 2. Server logs user in, BuildRedirectURL() issues one-time ticket | 认证中心登录后签发一次性Ticket并回跳
+// 3. SSOClient.Login() exchanges ticket for a local token | 子系统用Ticket换取本地Token
+// 4. When an account's last token logs out at the server, every client's SLO URL is notified | 账号在认证中心的最后一个Token登出时通知所有子系统单点注销
+//
+// Usage | 用法:
+//   server := sso.NewSSOServer(centerManager)
+//   server.RegisterClient(&sso.Client{ClientID: "shop", AllowURLs: []string{"https://shop.example.com/*"}, SloURL: "..."})
+//   redirect, _ := server.BuildRedirectURL(loginID, "shop", "https://shop.example.com/sso/callback")
+//
+//   client := sso.NewSSOClient(shopManager, "shop", secret, server) // or sso.NewHTTPTicketChecker(checkURL)
+//   token, _ := client.Login(ticket)
+
+// Constants for SSO | SSO常量
+const (
+	DefaultTicketExpiration = 5 * time.Minute // Ticket expiration | Ticket过期时间
+	DefaultSignTolerance    = 5 * time.Minute // Allowed timestamp skew of signed requests | 签名请求允许的时间偏差
+
+	TicketLength = 32 // Ticket byte length | Ticket字节长度
+
+	TicketKeySuffix = "sso:ticket:" // Ticket key suffix after prefix | Ticket键后缀
+
+	ParamTicket    = "ticket"    // Ticket parameter name | Ticket参数名
+	ParamRedirect  = "redirect"  // Redirect parameter name | 回跳地址参数名
+	ParamClientID  = "clientId"  // Client ID parameter name | 客户端ID参数名
+	ParamLoginID   = "loginId"   // Login ID parameter name | 登录ID参数名
+	ParamTimestamp = "timestamp" // Timestamp parameter name | 时间戳参数名
+	ParamSign      = "sign"      // Signature parameter name | 签名参数名
+)
+
+// Error variables | 错误变量
+var (
+	ErrClientNotFound     = fmt.Errorf("sso client not found")
+	ErrInvalidRedirect    = fmt.Errorf("invalid sso redirect url")
+	ErrRedirectNotAllowed = fmt.Errorf("sso redirect url not allowed")
+	ErrInvalidTicket      = fmt.Errorf("invalid or expired sso ticket")
+	ErrTicketClientMatch  = fmt.Errorf("sso ticket does not belong to client")
+	ErrInvalidSign        = fmt.Errorf("invalid sso signature")
+	ErrSignExpired        = fmt.Errorf("sso signature timestamp expired")
+	ErrInvalidTicketData  = fmt.Errorf("invalid sso ticket data")
+	ErrSecretRequired     = fmt.Errorf("sso client secret is required to verify logout notifications")
+)
+
+// Client SSO client (subsystem) registration | SSO子系统注册信息
+type Client struct {
+	ClientID  string   // Client ID | 子系统ID
+	Secret    string   // Shared secret used to sign server-client calls, required for single logout | 用于签名中心与子系统通信的共享密钥，单点注销必须配置
+	AllowURLs []string // Allowed redirect URLs, trailing "*" means prefix match | 允许的回跳地址，末尾"*"表示前缀匹配
+	SloURL    string   // Single logout callback URL, empty to skip | 单点注销回调地址，为空则不通知
+}
+
+// Ticket SSO ticket information | SSO Ticket信息
+type Ticket struct {
+	Ticket      string `json:"ticket"`      // Ticket value | Ticket值
+	LoginID     string `json:"loginId"`     // Login ID at SSO server | 认证中心登录ID
+	ClientID    string `json:"clientId"`    // Client the ticket was issued to | Ticket签发目标子系统
+	RedirectURL string `json:"redirectUrl"` // Redirect URL bound to ticket | Ticket绑定的回跳地址
+	CreateTime  int64  `json:"createTime"`  // Creation time | 创建时间
+}
+
+// LogoutNotifier delivers single logout notifications to a client | 向子系统发送单点注销通知
+type LogoutNotifier interface {
+	NotifyLogout(client *Client, loginID string) error
+}
+
+// SSOServer SSO authentication center | SSO认证中心
+type SSOServer struct {
+	manager          *manager.Manager
+	storage          adapter.Storage
+	keyPrefix        string
+	clients          map[string]*Client
+	clientsMu        sync.RWMutex  // Clients map lock | 客户端映射锁
+	ticketExpiration time.Duration // Ticket expiration (5min) | Ticket过期时间（5分钟）
+	notifier         LogoutNotifier
+	notifyErrHandler func(loginID string, err error) // Handles failed logout notifications | 处理失败的注销通知
+}
+
+// NewSSOServer Creates a new SSO server on top of manager | 基于Manager创建SSO认证中心
+// Once logout or kickout leaves an account without tokens, every registered client is notified asynchronously
+// 登出或踢人使账号不再有任何Token时，异步通知所有已注册子系统
+func NewSSOServer(mgr *manager.Manager) *SSOServer {
+	s := &SSOServer{
+		manager:          mgr,
+		storage:          mgr.GetStorage(),
+		keyPrefix:        mgr.GetKeyPrefix(),
+		clients:          make(map[string]*Client),
+		ticketExpiration: DefaultTicketExpiration,
+		notifier:         NewHTTPLogoutNotifier(nil),
+		notifyErrHandler: func(loginID string, err error) {
+			// Default handler: log and go on | 默认处理器：记录日志后继续
+			fmt.Printf("sa-token: sso logout notification failed: loginId=%s, err=%v\n", loginID, err)
+		},
+	}
+
+	// Runs on the event manager's goroutines, so logout never waits for client SLO URLs | 在事件管理器的协程中运行，登出不会等待子系统SLO地址
+	onLogout := listener.ListenerFunc(func(data *listener.EventData) {
+		// Other devices still signed in keep their client sessions | 其他设备仍在线时保留子系统会话
+		if tokens, err := mgr.GetTokenValueListByLoginID(data.LoginID); err == nil && len(tokens) > 0 {
+			return
+		}
+		if err := s.NotifyLogout(data.LoginID); err != nil {
+			s.notifyErrHandler(data.LoginID, err)
+		}
+	})
+	for _, event := range []listener.Event{listener.EventLogout, listener.EventKickout} {
+		mgr.RegisterWithConfig(event, onLogout, listener.ListenerConfig{Async: true})
+	}
+
+	return s
+}
+
+// SetTicketExpiration Sets ticket expiration | 设置Ticket过期时间
+func (s *SSOServer) SetTicketExpiration(expiration time.Duration) *SSOServer {
+	s.ticketExpiration = expiration
+	return s
+}
+
+// SetNotifier Sets single logout notifier | 设置单点注销通知器
+func (s *SSOServer) SetNotifier(notifier LogoutNotifier) *SSOServer {
+	s.notifier = notifier
+	return s
+}
+
+// SetNotifyErrorHandler Sets handler of failed logout notifications, default logs them | 设置注销通知失败的处理器，默认记录日志
+func (s *SSOServer) SetNotifyErrorHandler(handler func(loginID string, err error)) *SSOServer {
+	if handler != nil {
+		s.notifyErrHandler = handler
+	}
+	return s
+}
+
+// GetManager Gets underlying manager | 获取底层Manager
+func (s *SSOServer) GetManager() *manager.Manager {
+	return s.manager
+}
+
+// ============ Client Registry | 子系统注册 ============
+
+// RegisterClient Registers an SSO client | 注册SSO子系统
+func (s *SSOServer) RegisterClient(client *Client) error {
+	if client == nil || client.ClientID == "" {
+		return fmt.Errorf("invalid client: clientID is required")
+	}
+	if client.SloURL != "" && client.Secret == "" {
+		return fmt.Errorf("invalid client: secret is required to sign single logout notifications")
+	}
+
+	s.clientsMu.Lock()
+	defer s.clientsMu.Unlock()
+
+	s.clients[client.ClientID] = client
+	return nil
+}
+
+// UnregisterClient Unregisters an SSO client | 注销SSO子系统
+func (s *SSOServer) UnregisterClient(clientID string) {
+	s.clientsMu.Lock()
+	defer s.clientsMu.Unlock()
+
+	delete(s.clients, clientID)
+}
+
+// GetClient Gets client by ID | 获取子系统信息
+func (s *SSOServer) GetClient(clientID string) (*Client, error) {
+	s.clientsMu.RLock()
+	defer s.clientsMu.RUnlock()
+
+	client, exists := s.clients[clientID]
+	if !exists {
+		return nil, ErrClientNotFound
+	}
+	return client, nil
+}
+
+// ============ Redirect Validation | 回跳地址校验 ============
+
+// CheckRedirect Validates redirect URL against client's allow list | 校验回跳地址是否在子系统白名单内
+func (s *SSOServer) CheckRedirect(clientID, redirect string) error {
+	client, err := s.GetClient(clientID)
+	if err != nil {
+		return err
+	}
+
+	u, err := url.Parse(redirect)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ErrInvalidRedirect
+	}
+	// Reject userinfo tricks such as https://trusted.com@evil.com | 拒绝 https://trusted.com@evil.com 之类的伪装地址
+	if u.User != nil {
+		return ErrInvalidRedirect
+	}
+
+	for _, allow := range client.AllowURLs {
+		if matchAllowURL(allow, redirect, u) {
+			return nil
+		}
+	}
+	return ErrRedirectNotAllowed
+}
+
+// matchAllowURL Matches redirect against an allow-list entry | 匹配回跳地址与白名单条目
+func matchAllowURL(allow, redirect string, u *url.URL) bool {
+	if allow == "*" {
+		return true
+	}
+	if strings.HasSuffix(allow, "*") {
+		prefix := strings.TrimSuffix(allow, "*")
+		if !strings.HasPrefix(redirect, prefix) {
+			return false
+		}
+		// Prefix must pin the host, otherwise https://a.com* would match https://a.com.evil.com | 前缀必须锁定主机，避免匹配到伪装域名
+		p, err := url.Parse(prefix)
+		if err != nil || p.Host == "" {
+			return false
+		}
+		return p.Host == u.Host
+	}
+
+	// Exact match ignores query and fragment | 精确匹配时忽略查询参数与锚点
+	base := *u
+	base.RawQuery = ""
+	base.Fragment = ""
+	return allow == redirect || allow == base.String()
+}
+
+// ============ Ticket | Ticket管理 ============
+
+// CreateTicket Issues a one-time ticket bound to client and redirect URL | 签发绑定子系统与回跳地址的一次性Ticket
+func (s *SSOServer) CreateTicket(loginID, clientID, redirect string) (string, error) {
+	if loginID == "" {
+		return "", fmt.Errorf("invalid loginID: loginID is required")
+	}
+	if err := s.CheckRedirect(clientID, redirect); err != nil {
+		return "", err
+	}
+
+	value, err := generateRandomString(TicketLength)
+	if err != nil {
+		return "", err
+	}
+
+	ticket := &Ticket{
+		Ticket:      value,
+		LoginID:     loginID,
+		ClientID:    clientID,
+		RedirectURL: redirect,
+		CreateTime:  time.Now().Unix(),
+	}
+	data, err := json.Marshal(ticket)
+	if err != nil {
+		return "", err
+	}
+
+	if err := s.storage.Set(s.getTicketKey(value), string(data), s.ticketExpiration); err != nil {
+		return "", err
+	}
+	return value, nil
+}
+
+// BuildRedirectURL Issues a ticket and appends it to redirect URL | 签发Ticket并拼接到回跳地址
+func (s *SSOServer) BuildRedirectURL(loginID, clientID, redirect string) (string, error) {
+	ticket, err := s.CreateTicket(loginID, clientID, redirect)
+	if err != nil {
+		return "", err
+	}
+
+	u, _ := url.Parse(redirect) // Already validated by CreateTicket | 已在CreateTicket中校验
+	query := u.Query()
+	query.Set(ParamTicket, ticket)
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
+// ValidateTicket Consumes a ticket issued to client | 校验并消费指定子系统的Ticket
+func (s *SSOServer) ValidateTicket(ticket, clientID string) (*Ticket, error) {
+	if ticket == "" {
+		return nil, ErrInvalidTicket
+	}
+
+	key := s.getTicketKey(ticket)
+	info, err := s.loadTicket(key)
+	if err != nil {
+		return nil, err
+	}
+	// Check the owner first so a wrong client cannot burn the ticket | 先校验归属，避免错误的子系统消耗Ticket
+	if info.ClientID != clientID {
+		return nil, ErrTicketClientMatch
+	}
+
+	// One-time use: only the caller that deletes the ticket wins | 一次性使用：只有成功删除Ticket的调用者有效
+	if _, err := adapter.GetDel(s.storage, key); err != nil {
+		return nil, ErrInvalidTicket
+	}
+	return info, nil
+}
+
+// loadTicket Reads ticket without consuming it | 读取Ticket但不消费
+func (s *SSOServer) loadTicket(key string) (*Ticket, error) {
+	data, err := s.storage.Get(key)
+	if err != nil || data == nil {
+		return nil, ErrInvalidTicket
+	}
+
+	raw, err := utils.ToBytes(data)
+	if err != nil {
+		return nil, ErrInvalidTicketData
+	}
+	var info Ticket
+	if err := json.Unmarshal(raw, &info); err != nil {
+		return nil, ErrInvalidTicketData
+	}
+	return &info, nil
+}
+
+// CheckTicket Consumes a ticket and returns its login ID, implements TicketChecker | 消费Ticket并返回登录ID（实现TicketChecker）
+func (s *SSOServer) CheckTicket(ticket, clientID string) (string, error) {
+	info, err := s.ValidateTicket(ticket, clientID)
+	if err != nil {
+		return "", err
+	}
+	return info.LoginID, nil
+}
+
+// CheckTicketHandler HTTP endpoint for remote clients to exchange tickets | 供远程子系统校验Ticket的HTTP接口
+// Requests from clients with a secret must carry timestamp and sign | 配置了密钥的子系统必须携带时间戳与签名
+func (s *SSOServer) CheckTicketHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ticket := r.FormValue(ParamTicket)
+		clientID := r.FormValue(ParamClientID)
+
+		client, err := s.GetClient(clientID)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, err.Error(), nil)
+			return
+		}
+		if client.Secret != "" {
+			if err := VerifySign(client.Secret, r.FormValue(ParamTimestamp), r.FormValue(ParamSign), ticket, clientID); err != nil {
+				writeJSON(w, http.StatusUnauthorized, err.Error(), nil)
+				return
+			}
+		}
+
+		loginID, err := s.CheckTicket(ticket, clientID)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, err.Error(), nil)
+			return
+		}
+		writeJSON(w, http.StatusOK, "success", map[string]string{ParamLoginID: loginID})
+	}
+}
+
+// getTicketKey Gets storage key for ticket | 获取Ticket存储键
+func (s *SSOServer) getTicketKey(ticket string) string {
+	return s.keyPrefix + TicketKeySuffix + ticket
+}
+
+// ============ Single Logout | 单点注销 ============
+
+// SignOut Logs user out at the center, which fans out to every client | 在认证中心登出（会通知所有子系统）
+func (s *SSOServer) SignOut(loginID string, device ...string) error {
+	return s.manager.Logout(loginID, device...)
+}
+
+// NotifyLogout Fans out logout notification to every registered client | 向所有已注册子系统广播注销通知
+func (s *SSOServer) NotifyLogout(loginID string) error {
+	if loginID == "" || s.notifier == nil {
+		return nil
+	}
+
+	s.clientsMu.RLock()
+	clients := make([]*Client, 0, len(s.clients))
+	for _, client := range s.clients {
+		if client.SloURL != "" {
+			clients = append(clients, client)
+		}
+	}
+	s.clientsMu.RUnlock()
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+	)
+	for _, client := range clients {
+		wg.Add(1)
+		go func(c *Client) {
+			defer wg.Done()
+			if err := s.notifier.NotifyLogout(c, loginID); err != nil {
+				mu.Lock()
+				errs = append(errs, fmt.Errorf("notify client %s: %w", c.ClientID, err))
+				mu.Unlock()
+			}
+		}(client)
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// HTTPLogoutNotifier Posts signed logout form to client's SLO URL | 向子系统SLO地址POST带签名的注销表单
+type HTTPLogoutNotifier struct {
+	client *http.Client
+}
+
+// NewHTTPLogoutNotifier Creates HTTP logout notifier, nil uses a 5s-timeout client | 创建HTTP注销通知器，nil使用5秒超时的客户端
+func NewHTTPLogoutNotifier(client *http.Client) *HTTPLogoutNotifier {
+	if client == nil {
+		client = &http.Client{Timeout: 5 * time.Second}
+	}
+	return &HTTPLogoutNotifier{client: client}
+}
+
+// NotifyLogout Implements LogoutNotifier | 实现LogoutNotifier
+func (n *HTTPLogoutNotifier) NotifyLogout(client *Client, loginID string) error {
+	if client.Secret == "" {
+		return ErrSecretRequired
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	form := url.Values{
+		ParamLoginID:   {loginID},
+		ParamClientID:  {client.ClientID},
+		ParamTimestamp: {timestamp},
+		ParamSign:      {Sign(client.Secret, timestamp, loginID, client.ClientID)},
+	}
+
+	resp, err := n.client.PostForm(client.SloURL, form)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("unexpected status: %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// ============ Signature | 签名 ============
+
+// Sign Computes HMAC-SHA256 over timestamp and params | 对时间戳与参数计算HMAC-SHA256签名
+func Sign(secret, timestamp string, params ...string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	for _, p := range params {
+		mac.Write([]byte("&" + p))
+	}
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySign Verifies signature and timestamp skew | 校验签名与时间戳偏差
+func VerifySign(secret, timestamp, sign string, params ...string) error {
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrInvalidSign
+	}
+	skew := time.Since(time.Unix(ts, 0))
+	if skew > DefaultSignTolerance || skew < -DefaultSignTolerance {
+		return ErrSignExpired
+	}
+	if !hmac.Equal([]byte(sign), []byte(Sign(secret, timestamp, params...))) {
+		return ErrInvalidSign
+	}
+	return nil
+}
+
+// ============ Helpers | 辅助函数 ============
+
+// generateRandomString Generates a random hex string | 生成随机十六进制字符串
+func generateRandomString(length int) (string, error) {
+	bytes := make([]byte, length)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(bytes), nil
+}
+
+// writeJSON Writes a JSON response in the integrations' envelope | 以集成层统一格式写入JSON响应
+func writeJSON(w http.ResponseWriter, status int, message string, data any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"code":    status,
+		"message": message,
+		"data":    data,
+	})
+}
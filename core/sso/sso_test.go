@@ -0,0 +1,412 @@
+package sso
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/click33/sa-token-go/core/adapter/adaptertest"
+	"github.com/click33/sa-token-go/core/config"
+	"github.com/click33/sa-token-go/core/manager"
+)
+
+const (
+	testClientID = "shop"
+	testSecret   = "shop-secret"
+	testRedirect = "https://shop.example.com/sso/callback"
+)
+
+func newTestManager(t *testing.T) *manager.Manager {
+	t.Helper()
+
+	cfg := config.DefaultConfig()
+	cfg.IsPrintBanner = false
+	m := manager.NewManager(adaptertest.NewMapStorage(), cfg)
+	t.Cleanup(m.CloseManager)
+	return m
+}
+
+// isLoggedIn Reports whether loginID has a live token | 判断loginID是否存在有效Token
+func isLoggedIn(mgr *manager.Manager, loginID string) bool {
+	tokens, err := mgr.GetTokenValueListByLoginID(loginID)
+	return err == nil && len(tokens) > 0
+}
+
+func newTestServer(t *testing.T, allowURLs ...string) *SSOServer {
+	t.Helper()
+
+	if len(allowURLs) == 0 {
+		allowURLs = []string{"https://shop.example.com/*"}
+	}
+	server := NewSSOServer(newTestManager(t)).SetNotifier(nil)
+	if err := server.RegisterClient(&Client{ClientID: testClientID, Secret: testSecret, AllowURLs: allowURLs}); err != nil {
+		t.Fatalf("RegisterClient() error = %v", err)
+	}
+	if err := server.RegisterClient(&Client{ClientID: "blog", AllowURLs: []string{"https://blog.example.com/*"}}); err != nil {
+		t.Fatalf("RegisterClient() error = %v", err)
+	}
+	return server
+}
+
+func TestCheckRedirect(t *testing.T) {
+	tests := []struct {
+		name     string
+		allow    string
+		redirect string
+		want     error
+	}{
+		{"prefix match", "https://shop.example.com/*", "https://shop.example.com/sso/callback?x=1", nil},
+		{"prefix other path", "https://shop.example.com/sso/*", "https://shop.example.com/admin", ErrRedirectNotAllowed},
+		{"prefix host suffix", "https://shop.example.com*", "https://shop.example.com.evil.com/", ErrRedirectNotAllowed},
+		{"prefix host pinned", "https://shop.example.com*", "https://shop.example.com/callback", nil},
+		{"exact match", testRedirect, testRedirect, nil},
+		{"exact ignores query", testRedirect, testRedirect + "?state=1#top", nil},
+		{"exact other path", testRedirect, "https://shop.example.com/sso/callback2", ErrRedirectNotAllowed},
+		{"wildcard", "*", "https://anything.example.org/", nil},
+		{"userinfo", "https://shop.example.com/*", "https://shop.example.com@evil.com/", ErrInvalidRedirect},
+		{"userinfo prefix", "https://shop.example.com*", "https://shop.example.com@evil.com/", ErrInvalidRedirect},
+		{"javascript scheme", "*", "javascript:alert(1)", ErrInvalidRedirect},
+		{"relative", "*", "/sso/callback", ErrInvalidRedirect},
+		{"missing host", "*", "https:///callback", ErrInvalidRedirect},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t, tt.allow)
+			if err := server.CheckRedirect(testClientID, tt.redirect); !errors.Is(err, tt.want) {
+				t.Fatalf("CheckRedirect(%q) = %v, want %v", tt.redirect, err, tt.want)
+			}
+		})
+	}
+
+	server := newTestServer(t)
+	if err := server.CheckRedirect("unknown", testRedirect); !errors.Is(err, ErrClientNotFound) {
+		t.Fatalf("CheckRedirect(unknown client) = %v, want ErrClientNotFound", err)
+	}
+}
+
+func TestMatchAllowURL(t *testing.T) {
+	tests := []struct {
+		allow, redirect string
+		want            bool
+	}{
+		{"https://a.com/*", "https://a.com/x", true},
+		{"https://a.com/*", "https://a.com.evil.com/x", false},
+		{"https://a.com*", "https://a.com:8443/x", false},
+		{"*", "https://b.com/", true},
+		{"no-host*", "no-host/x", false},
+		{"https://a.com/cb", "https://a.com/cb?code=1", true},
+		{"https://a.com/cb", "https://a.com/cb/", false},
+	}
+
+	for _, tt := range tests {
+		u, err := url.Parse(tt.redirect)
+		if err != nil {
+			t.Fatalf("url.Parse(%q) error = %v", tt.redirect, err)
+		}
+		if got := matchAllowURL(tt.allow, tt.redirect, u); got != tt.want {
+			t.Errorf("matchAllowURL(%q, %q) = %v, want %v", tt.allow, tt.redirect, got, tt.want)
+		}
+	}
+}
+
+func TestValidateTicket(t *testing.T) {
+	server := newTestServer(t)
+
+	redirect, err := server.BuildRedirectURL("1001", testClientID, testRedirect+"?from=home")
+	if err != nil {
+		t.Fatalf("BuildRedirectURL() error = %v", err)
+	}
+	u, _ := url.Parse(redirect)
+	ticket := u.Query().Get(ParamTicket)
+	if ticket == "" || u.Query().Get("from") != "home" {
+		t.Fatalf("BuildRedirectURL() = %s, want ticket and original query", redirect)
+	}
+
+	// A wrong client must not consume the ticket | 错误的子系统不能消耗Ticket
+	if _, err := server.ValidateTicket(ticket, "blog"); !errors.Is(err, ErrTicketClientMatch) {
+		t.Fatalf("ValidateTicket(blog) = %v, want ErrTicketClientMatch", err)
+	}
+
+	info, err := server.ValidateTicket(ticket, testClientID)
+	if err != nil {
+		t.Fatalf("ValidateTicket() error = %v", err)
+	}
+	if info.LoginID != "1001" || info.RedirectURL != testRedirect+"?from=home" {
+		t.Fatalf("ValidateTicket() = %+v", info)
+	}
+
+	if _, err := server.ValidateTicket(ticket, testClientID); !errors.Is(err, ErrInvalidTicket) {
+		t.Fatalf("second ValidateTicket() = %v, want ErrInvalidTicket", err)
+	}
+	if _, err := server.ValidateTicket("", testClientID); !errors.Is(err, ErrInvalidTicket) {
+		t.Fatalf("ValidateTicket(empty) = %v, want ErrInvalidTicket", err)
+	}
+	if _, err := server.CreateTicket("1001", testClientID, "https://evil.com/"); !errors.Is(err, ErrRedirectNotAllowed) {
+		t.Fatalf("CreateTicket(evil redirect) = %v, want ErrRedirectNotAllowed", err)
+	}
+}
+
+func TestValidateTicketOnce(t *testing.T) {
+	server := newTestServer(t)
+	ticket, err := server.CreateTicket("1001", testClientID, testRedirect)
+	if err != nil {
+		t.Fatalf("CreateTicket() error = %v", err)
+	}
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		wins int
+	)
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := server.ValidateTicket(ticket, testClientID); err == nil {
+				mu.Lock()
+				wins++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if wins != 1 {
+		t.Fatalf("ticket redeemed %d times, want 1", wins)
+	}
+}
+
+func TestTicketExpiration(t *testing.T) {
+	server := newTestServer(t).SetTicketExpiration(time.Millisecond)
+	ticket, err := server.CreateTicket("1001", testClientID, testRedirect)
+	if err != nil {
+		t.Fatalf("CreateTicket() error = %v", err)
+	}
+	time.Sleep(5 * time.Millisecond)
+
+	if _, err := server.ValidateTicket(ticket, testClientID); !errors.Is(err, ErrInvalidTicket) {
+		t.Fatalf("ValidateTicket(expired) = %v, want ErrInvalidTicket", err)
+	}
+}
+
+func TestVerifySign(t *testing.T) {
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	sign := Sign(testSecret, now, "1001", testClientID)
+
+	if err := VerifySign(testSecret, now, sign, "1001", testClientID); err != nil {
+		t.Fatalf("VerifySign() error = %v", err)
+	}
+	if err := VerifySign("other", now, sign, "1001", testClientID); !errors.Is(err, ErrInvalidSign) {
+		t.Fatalf("VerifySign(wrong secret) = %v, want ErrInvalidSign", err)
+	}
+	if err := VerifySign(testSecret, now, sign, "1002", testClientID); !errors.Is(err, ErrInvalidSign) {
+		t.Fatalf("VerifySign(tampered) = %v, want ErrInvalidSign", err)
+	}
+	if err := VerifySign(testSecret, "not-a-number", sign, "1001", testClientID); !errors.Is(err, ErrInvalidSign) {
+		t.Fatalf("VerifySign(bad timestamp) = %v, want ErrInvalidSign", err)
+	}
+
+	old := strconv.FormatInt(time.Now().Add(-2*DefaultSignTolerance).Unix(), 10)
+	if err := VerifySign(testSecret, old, Sign(testSecret, old, "1001"), "1001"); !errors.Is(err, ErrSignExpired) {
+		t.Fatalf("VerifySign(old) = %v, want ErrSignExpired", err)
+	}
+}
+
+func TestHandleLogout(t *testing.T) {
+	mgr := newTestManager(t)
+	client := NewSSOClient(mgr, testClientID, testSecret, nil)
+	if _, err := mgr.Login("1001"); err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	if err := client.HandleLogout("1001", now, ""); !errors.Is(err, ErrInvalidSign) {
+		t.Fatalf("HandleLogout(unsigned) = %v, want ErrInvalidSign", err)
+	}
+	if !isLoggedIn(mgr, "1001") {
+		t.Fatal("unsigned notification logged the user out")
+	}
+
+	if err := client.HandleLogout("1001", now, Sign(testSecret, now, "1001", testClientID)); err != nil {
+		t.Fatalf("HandleLogout() error = %v", err)
+	}
+	if isLoggedIn(mgr, "1001") {
+		t.Fatal("signed notification did not log the user out")
+	}
+
+	noSecret := NewSSOClient(mgr, testClientID, "", nil)
+	if err := noSecret.HandleLogout("1001", now, ""); !errors.Is(err, ErrSecretRequired) {
+		t.Fatalf("HandleLogout(no secret) = %v, want ErrSecretRequired", err)
+	}
+}
+
+func TestSingleLogoutOverHTTP(t *testing.T) {
+	shop := newTestManager(t)
+	client := NewSSOClient(shop, testClientID, testSecret, nil)
+	slo := httptest.NewServer(client.SloHandler())
+	defer slo.Close()
+
+	center := newTestManager(t)
+	server := NewSSOServer(center)
+	if err := server.RegisterClient(&Client{ClientID: testClientID, AllowURLs: []string{"*"}, SloURL: slo.URL}); err == nil {
+		t.Fatal("RegisterClient() accepted an SLO URL without secret")
+	}
+	if err := server.RegisterClient(&Client{ClientID: testClientID, Secret: testSecret, AllowURLs: []string{"*"}, SloURL: slo.URL}); err != nil {
+		t.Fatalf("RegisterClient() error = %v", err)
+	}
+
+	for _, mgr := range []*manager.Manager{center, shop} {
+		if _, err := mgr.Login("1001"); err != nil {
+			t.Fatalf("Login() error = %v", err)
+		}
+	}
+	if err := server.SignOut("1001"); err != nil {
+		t.Fatalf("SignOut() error = %v", err)
+	}
+	center.WaitEvents()
+	if isLoggedIn(shop, "1001") {
+		t.Fatal("single logout did not reach the client")
+	}
+
+	resp, err := http.PostForm(slo.URL, url.Values{ParamLoginID: {"1001"}})
+	if err != nil {
+		t.Fatalf("PostForm() error = %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("unsigned SLO status = %d, want 401", resp.StatusCode)
+	}
+}
+
+// recordingNotifier Records notified login IDs, failing with err | 记录被通知的登录ID，并返回err
+type recordingNotifier struct {
+	mu       sync.Mutex
+	loginIDs []string
+	err      error
+}
+
+func (n *recordingNotifier) NotifyLogout(client *Client, loginID string) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.loginIDs = append(n.loginIDs, loginID)
+	return n.err
+}
+
+func (n *recordingNotifier) notified() []string {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return append([]string(nil), n.loginIDs...)
+}
+
+func TestSingleLogoutScope(t *testing.T) {
+	center := newTestManager(t)
+	notifier := &recordingNotifier{err: errors.New("slo down")}
+	var failed []string
+	server := NewSSOServer(center).SetNotifier(notifier).SetNotifyErrorHandler(func(loginID string, err error) {
+		failed = append(failed, loginID)
+	})
+	if err := server.RegisterClient(&Client{ClientID: testClientID, Secret: testSecret, AllowURLs: []string{"*"}, SloURL: "https://shop.example.com/sso/logout"}); err != nil {
+		t.Fatalf("RegisterClient() error = %v", err)
+	}
+
+	pc, err := center.Login("1001", "pc")
+	if err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+	mobile, err := center.Login("1001", "mobile")
+	if err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+
+	// Logging out one browser keeps the other device and every client signed in | 登出一个浏览器时，其他设备与子系统保持登录
+	if err := center.LogoutByToken(pc); err != nil {
+		t.Fatalf("LogoutByToken() error = %v", err)
+	}
+	if err := center.KickoutByToken("unknown"); err == nil {
+		t.Fatal("KickoutByToken(unknown) succeeded")
+	}
+	center.WaitEvents()
+	if got := notifier.notified(); len(got) != 0 {
+		t.Fatalf("notified %v while another device is signed in", got)
+	}
+
+	if err := center.KickoutByToken(mobile); err != nil {
+		t.Fatalf("KickoutByToken() error = %v", err)
+	}
+	center.WaitEvents()
+	if got := notifier.notified(); len(got) != 1 || got[0] != "1001" {
+		t.Fatalf("notified %v, want [1001] once the last token is gone", got)
+	}
+	if len(failed) != 1 || failed[0] != "1001" {
+		t.Fatalf("failed notifications = %v, want [1001]", failed)
+	}
+}
+
+func TestCheckTicketHandler(t *testing.T) {
+	server := newTestServer(t)
+	endpoint := httptest.NewServer(server.CheckTicketHandler())
+	defer endpoint.Close()
+
+	ticket, err := server.CreateTicket("1001", testClientID, testRedirect)
+	if err != nil {
+		t.Fatalf("CreateTicket() error = %v", err)
+	}
+
+	// Unsigned request of a client with secret is rejected without consuming | 未签名请求被拒绝且不消耗Ticket
+	resp, err := http.PostForm(endpoint.URL, url.Values{ParamTicket: {ticket}, ParamClientID: {testClientID}})
+	if err != nil {
+		t.Fatalf("PostForm() error = %v", err)
+	}
+	var body map[string]any
+	_ = json.NewDecoder(resp.Body).Decode(&body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("unsigned status = %d (%v), want 401", resp.StatusCode, body)
+	}
+
+	checker := NewHTTPTicketChecker(endpoint.URL, testSecret, nil)
+	loginID, err := checker.CheckTicket(ticket, testClientID)
+	if err != nil || loginID != "1001" {
+		t.Fatalf("CheckTicket() = %q, %v, want 1001", loginID, err)
+	}
+	if _, err := checker.CheckTicket(ticket, testClientID); !errors.Is(err, ErrInvalidTicket) {
+		t.Fatalf("second CheckTicket() = %v, want ErrInvalidTicket", err)
+	}
+}
+
+func TestSSOClientLogin(t *testing.T) {
+	server := newTestServer(t)
+	shop := newTestManager(t)
+	client := NewSSOClient(shop, testClientID, testSecret, server).SetDevice("web")
+
+	ticket, err := server.CreateTicket("1001", testClientID, testRedirect)
+	if err != nil {
+		t.Fatalf("CreateTicket() error = %v", err)
+	}
+	token, err := client.Login(ticket)
+	if err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+	if loginID, err := shop.GetLoginID(token); err != nil || loginID != "1001" {
+		t.Fatalf("GetLoginID() = %q, %v, want 1001", loginID, err)
+	}
+	if _, err := client.Login(ticket); !errors.Is(err, ErrInvalidTicket) {
+		t.Fatalf("Login(reused ticket) = %v, want ErrInvalidTicket", err)
+	}
+
+	authURL, err := client.BuildAuthURL("https://sso.example.com/auth", testRedirect)
+	if err != nil {
+		t.Fatalf("BuildAuthURL() error = %v", err)
+	}
+	u, _ := url.Parse(authURL)
+	if u.Query().Get(ParamClientID) != testClientID || u.Query().Get(ParamRedirect) != testRedirect {
+		t.Fatalf("BuildAuthURL() = %s", authURL)
+	}
+}
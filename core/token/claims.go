@@ -0,0 +1,116 @@
+package token
+
+import (
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Claim keys | 声明键
+const (
+	ClaimKeyLoginID     = "loginId"
+	ClaimKeyDevice      = "device"
+	ClaimKeyDeviceID    = "deviceId"
+	ClaimKeyIssuer      = "iss"
+	ClaimKeySubject     = "sub"
+	ClaimKeyAudience    = "aud"
+	ClaimKeyExpiresAt   = "exp"
+	ClaimKeyNotBefore   = "nbf"
+	ClaimKeyIssuedAt    = "iat"
+	ClaimKeyJwtID       = "jti"
+	ClaimKeyIssuedAtUs  = "iatUs" // Private issue time in microseconds, written with JwtRevocation | 私有签发时间（微秒），启用JwtRevocation时写入
+	ClaimKeyPermissions = "permissions"
+	ClaimKeyRoles       = "roles"
+)
+
+// registeredClaims Registered claim names, only ever written by the generator | 注册声明名，仅由生成器写入
+var registeredClaims = []string{
+	ClaimKeyIssuer, ClaimKeySubject, ClaimKeyAudience, ClaimKeyExpiresAt,
+	ClaimKeyNotBefore, ClaimKeyIssuedAt, ClaimKeyJwtID, ClaimKeyIssuedAtUs,
+}
+
+// Claims Typed claims of a verified JWT or decrypted token | 已验签JWT或已解密Token的类型化声明
+type Claims struct {
+	LoginID     string         `json:"loginId"`
+	Device      string         `json:"device"`
+	DeviceID    string         `json:"deviceId,omitempty"`
+	Issuer      string         `json:"iss,omitempty"`
+	Subject     string         `json:"sub,omitempty"`
+	Audience    []string       `json:"aud,omitempty"`
+	ExpiresAt   int64          `json:"exp,omitempty"` // Unix seconds, 0 means never expire | Unix秒，0表示永不过期
+	NotBefore   int64          `json:"nbf,omitempty"`
+	IssuedAt    int64          `json:"iat,omitempty"`
+	IssuedAtUs  int64          `json:"iatUs,omitempty"` // Unix microseconds, 0 when absent | Unix微秒，不存在时为0
+	ID          string         `json:"jti,omitempty"`
+	Permissions []string       `json:"permissions,omitempty"` // nil when the claim is absent | 声明不存在时为nil
+	Roles       []string       `json:"roles,omitempty"`       // nil when the claim is absent | 声明不存在时为nil
+	Extra       map[string]any `json:"-"`                     // Custom claims | 自定义声明
+}
+
+// Get Gets custom claim | 获取自定义声明
+func (c *Claims) Get(key string) (any, bool) {
+	if c.Extra == nil {
+		return nil, false
+	}
+	v, ok := c.Extra[key]
+	return v, ok
+}
+
+// newClaims Converts raw claims to typed claims | 将原始声明转换为类型化声明
+func newClaims(mc jwt.MapClaims) *Claims {
+	c := &Claims{}
+	c.LoginID, _ = mc[ClaimKeyLoginID].(string)
+	c.Device, _ = mc[ClaimKeyDevice].(string)
+	c.DeviceID, _ = mc[ClaimKeyDeviceID].(string)
+	c.Issuer, _ = mc.GetIssuer()
+	c.Subject, _ = mc.GetSubject()
+	c.Audience, _ = mc.GetAudience()
+	c.ID, _ = mc[ClaimKeyJwtID].(string)
+	if exp, _ := mc.GetExpirationTime(); exp != nil {
+		c.ExpiresAt = exp.Unix()
+	}
+	if nbf, _ := mc.GetNotBefore(); nbf != nil {
+		c.NotBefore = nbf.Unix()
+	}
+	if iat, _ := mc.GetIssuedAt(); iat != nil {
+		c.IssuedAt = iat.Unix()
+	}
+	if iatUs, ok := mc[ClaimKeyIssuedAtUs].(float64); ok {
+		c.IssuedAtUs = int64(iatUs)
+	}
+	if v, ok := mc[ClaimKeyPermissions]; ok {
+		c.Permissions = toStrings(v)
+	}
+	if v, ok := mc[ClaimKeyRoles]; ok {
+		c.Roles = toStrings(v)
+	}
+
+	for k, v := range mc {
+		switch k {
+		case ClaimKeyLoginID, ClaimKeyDevice, ClaimKeyDeviceID, ClaimKeyIssuer, ClaimKeySubject, ClaimKeyAudience,
+			ClaimKeyExpiresAt, ClaimKeyNotBefore, ClaimKeyIssuedAt, ClaimKeyJwtID, ClaimKeyIssuedAtUs, ClaimKeyPermissions, ClaimKeyRoles:
+			continue
+		}
+		if c.Extra == nil {
+			c.Extra = make(map[string]any)
+		}
+		c.Extra[k] = v
+	}
+	return c
+}
+
+// toStrings Converts decoded JSON array to []string | 将解码后的JSON数组转换为[]string
+func toStrings(v any) []string {
+	switch val := v.(type) {
+	case []string:
+		return val
+	case []any:
+		result := make([]string, 0, len(val))
+		for _, item := range val {
+			if str, ok := item.(string); ok {
+				result = append(result, str)
+			}
+		}
+		return result
+	default:
+		return []string{}
+	}
+}
@@ -0,0 +1,264 @@
+package token
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Encrypted Token Implementation
+// 加密Token实现
+//
+// Format | 格式:
+//   [kid.]base64url(nonce || AES-GCM(claims JSON))
+//   The kid is authenticated as additional data, claims use the same keys as JWT | kid作为附加数据参与认证，声明键与JWT一致
+//
+// Rotation flow | 轮换流程:
+// 1. AddKey(newKey) - Let every service decrypt with the new key first | 先让所有服务可用新密钥解密
+// 2. SetActiveKey(newKid) - Start encrypting with it, old keys keep decrypting | 开始用新密钥加密，旧密钥继续解密
+// 3. RemoveKey(oldKid) - Retire old key after its tokens expired | 旧Token过期后移除旧密钥
+//
+// Usage | 用法:
+//   key, _ := token.NewEncryptKey("2024-01", secret32) // AES-256-GCM
+//   manager.GetEncryptKeySet().Rotate(key)
+//   claims, err := generator.DecryptToken(tokenValue) // edge services, no storage | 边缘服务，无需存储
+
+// Error variables | 错误变量
+var (
+	ErrEncryptKeyNotFound     = fmt.Errorf("encrypt key not found")
+	ErrEncryptKeyInvalid      = fmt.Errorf("encrypt key must be 16, 24 or 32 bytes")
+	ErrEncryptKeyKidDuplicate = fmt.Errorf("encrypt key id already exists")
+	ErrDecryptFailed          = fmt.Errorf("failed to decrypt token")
+)
+
+// EncryptKey AES-GCM token encryption key | AES-GCM Token加密密钥
+type EncryptKey struct {
+	Kid  string // Key ID prefixed to the token, empty only for the legacy secret | Token前缀中的密钥ID，仅旧版密钥可为空
+	aead cipher.AEAD
+}
+
+// NewEncryptKey Creates AES-128/192/256-GCM key from 16, 24 or 32 raw bytes | 由16/24/32字节原始密钥创建AES-GCM密钥
+func NewEncryptKey(kid string, key []byte) (*EncryptKey, error) {
+	if strings.Contains(kid, ".") {
+		return nil, fmt.Errorf("encrypt key id cannot contain '.': %s", kid)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrEncryptKeyInvalid, err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &EncryptKey{Kid: kid, aead: aead}, nil
+}
+
+// NewEncryptKeyFromSecret Creates AES-256-GCM key derived from secret by SHA-256 | 由密钥字符串经SHA-256派生AES-256-GCM密钥
+func NewEncryptKeyFromSecret(kid, secret string) (*EncryptKey, error) {
+	sum := sha256.Sum256([]byte(secret))
+	return NewEncryptKey(kid, sum[:])
+}
+
+// ============ Key Set | 密钥集 ============
+
+// EncryptKeySet Active encryption key plus decryption keys indexed by kid | 当前加密密钥及按kid索引的解密密钥
+type EncryptKeySet struct {
+	mu        sync.RWMutex
+	keys      map[string]*EncryptKey
+	activeKid string
+	hasActive bool
+}
+
+// NewEncryptKeySet Creates key set, the first key becomes active | 创建密钥集，第一个密钥为当前加密密钥
+func NewEncryptKeySet(keys ...*EncryptKey) (*EncryptKeySet, error) {
+	ks := &EncryptKeySet{keys: make(map[string]*EncryptKey)}
+	for i, key := range keys {
+		if err := ks.AddKey(key); err != nil {
+			return nil, err
+		}
+		if i == 0 {
+			if err := ks.SetActiveKey(key.Kid); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return ks, nil
+}
+
+// AddKey Adds decryption key | 添加解密密钥
+func (ks *EncryptKeySet) AddKey(key *EncryptKey) error {
+	if key == nil {
+		return ErrEncryptKeyNotFound
+	}
+
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	if _, exists := ks.keys[key.Kid]; exists {
+		return fmt.Errorf("%w: %s", ErrEncryptKeyKidDuplicate, key.Kid)
+	}
+	ks.keys[key.Kid] = key
+	return nil
+}
+
+// SetActiveKey Switches encryption key | 切换加密密钥
+func (ks *EncryptKeySet) SetActiveKey(kid string) error {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	if _, ok := ks.keys[kid]; !ok {
+		return fmt.Errorf("%w: %s", ErrEncryptKeyNotFound, kid)
+	}
+	ks.activeKid = kid
+	ks.hasActive = true
+	return nil
+}
+
+// Rotate Adds key and makes it active, previous keys keep decrypting | 添加密钥并设为加密密钥，旧密钥继续用于解密
+func (ks *EncryptKeySet) Rotate(key *EncryptKey) error {
+	if err := ks.AddKey(key); err != nil {
+		return err
+	}
+	return ks.SetActiveKey(key.Kid)
+}
+
+// RemoveKey Removes key, the active key cannot be removed | 移除密钥（不能移除当前加密密钥）
+func (ks *EncryptKeySet) RemoveKey(kid string) error {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	if ks.hasActive && kid == ks.activeKid {
+		return fmt.Errorf("cannot remove active encrypt key: %s", kid)
+	}
+	if _, ok := ks.keys[kid]; !ok {
+		return fmt.Errorf("%w: %s", ErrEncryptKeyNotFound, kid)
+	}
+	delete(ks.keys, kid)
+	return nil
+}
+
+// GetKey Gets key by kid | 按kid获取密钥
+func (ks *EncryptKeySet) GetKey(kid string) (*EncryptKey, bool) {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	key, ok := ks.keys[kid]
+	return key, ok
+}
+
+// ActiveKey Gets current encryption key | 获取当前加密密钥
+func (ks *EncryptKeySet) ActiveKey() (*EncryptKey, error) {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	key, ok := ks.keys[ks.activeKid]
+	if !ks.hasActive || !ok {
+		return nil, ErrEncryptKeyNotFound
+	}
+	return key, nil
+}
+
+// ============ Encrypt / Decrypt | 加密与解密 ============
+
+// seal Encrypts payload with key | 使用密钥加密载荷
+func (k *EncryptKey) seal(payload []byte) (string, error) {
+	nonce := make([]byte, k.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate random bytes: %w", err)
+	}
+
+	sealed := k.aead.Seal(nonce, nonce, payload, []byte(k.Kid))
+	encoded := base64.RawURLEncoding.EncodeToString(sealed)
+	if k.Kid == "" {
+		return encoded, nil
+	}
+	return k.Kid + "." + encoded, nil
+}
+
+// open Decrypts token with key set | 使用密钥集解密Token
+func (ks *EncryptKeySet) open(tokenStr string) ([]byte, error) {
+	kid, encoded := "", tokenStr
+	if i := strings.LastIndexByte(tokenStr, '.'); i >= 0 {
+		kid, encoded = tokenStr[:i], tokenStr[i+1:]
+	}
+
+	key, ok := ks.GetKey(kid)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrEncryptKeyNotFound, kid)
+	}
+
+	sealed, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil || len(sealed) < key.aead.NonceSize() {
+		return nil, ErrDecryptFailed
+	}
+	nonceSize := key.aead.NonceSize()
+	payload, err := key.aead.Open(nil, sealed[:nonceSize], sealed[nonceSize:], []byte(kid))
+	if err != nil {
+		return nil, ErrDecryptFailed
+	}
+	return payload, nil
+}
+
+// generateEncrypted Generates AES-GCM encrypted token | 生成AES-GCM加密Token
+func (g *Generator) generateEncrypted(loginID string, device string, timeout int64, extra map[string]any) (string, error) {
+	now := time.Now()
+	claims := make(map[string]any, len(extra)+5)
+	for k, v := range extra {
+		claims[k] = v
+	}
+	// Extra must not forge registered claims | extra不能伪造注册声明
+	for _, k := range registeredClaims {
+		delete(claims, k)
+	}
+	claims[ClaimKeyLoginID] = loginID
+	claims[ClaimKeyDevice] = device
+	claims[ClaimKeyIssuedAt] = now.Unix()
+
+	if timeout > 0 {
+		claims[ClaimKeyExpiresAt] = now.Add(time.Duration(timeout) * time.Second).Unix()
+	}
+
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal token claims: %w", err)
+	}
+
+	key, err := g.encryptKeys.ActiveKey()
+	if err != nil {
+		return "", err
+	}
+	return key.seal(payload)
+}
+
+// DecryptToken Decrypts encrypted token and checks its expiry | 解密加密Token并校验过期时间
+func (g *Generator) DecryptToken(tokenStr string) (*Claims, error) {
+	if tokenStr == "" {
+		return nil, fmt.Errorf("token string cannot be empty")
+	}
+
+	payload, err := g.encryptKeys.open(tokenStr)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw map[string]any
+	if err := json.Unmarshal(payload, &raw); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+	claims := newClaims(raw)
+	if claims.LoginID == "" {
+		return nil, ErrInvalidToken
+	}
+	if claims.ExpiresAt > 0 && time.Now().Unix() >= claims.ExpiresAt {
+		return nil, ErrTokenExpired
+	}
+	return claims, nil
+}
@@ -0,0 +1,404 @@
+package token
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// JWT Key Set Implementation
+// JWT密钥集实现
+//
+// Rotation flow | 轮换流程:
+// 1. AddKey(newKey) - Publish new key for verification first | 先发布新密钥用于验签
+// 2. SetActiveKey(newKid) - Start signing with it, old keys keep verifying | 开始用新密钥签名，旧密钥继续验签
+// 3. RemoveKey(oldKid) - Retire old key after its tokens expired | 旧Token过期后移除旧密钥
+//
+// Usage | 用法:
+//   priv, _ := rsa.GenerateKey(rand.Reader, 2048)
+//   key, _ := token.NewRSAKey("2024-01", priv)
+//   keys := manager.GetJwtKeySet()
+//   keys.Rotate(key)                  // add + activate | 添加并启用
+//   http.Handle("/.well-known/jwks.json", keys.JWKSHandler())
+
+// JWT signing algorithms | JWT签名算法
+const (
+	JwtAlgHS256 = "HS256"
+	JwtAlgRS256 = "RS256"
+	JwtAlgRS384 = "RS384"
+	JwtAlgRS512 = "RS512"
+	JwtAlgPS256 = "PS256"
+	JwtAlgES256 = "ES256"
+	JwtAlgES384 = "ES384"
+	JwtAlgES512 = "ES512"
+	JwtAlgEdDSA = "EdDSA"
+)
+
+// Error variables | 错误变量
+var (
+	ErrJwtKeyNotFound     = fmt.Errorf("jwt key not found")
+	ErrJwtKeyUnsupported  = fmt.Errorf("unsupported jwt key type")
+	ErrJwtKeyVerifyOnly   = fmt.Errorf("jwt key has no private part and cannot sign")
+	ErrJwtKeyInvalidPEM   = fmt.Errorf("invalid PEM encoded jwt key")
+	ErrJwtKeyAlgMismatch  = fmt.Errorf("jwt algorithm does not match key")
+	ErrJwtKeyKidDuplicate = fmt.Errorf("jwt key id already exists")
+)
+
+// JwtKey JWT signing and verification key | JWT签名与验签密钥
+type JwtKey struct {
+	Kid       string // Key ID written to "kid" header, empty only for the legacy secret | 写入kid头的密钥ID，仅旧版密钥可为空
+	Algorithm string // Signing algorithm, e.g. RS256 | 签名算法，如RS256
+	SignKey   any    // Private key or HMAC secret, nil means verify-only | 私钥或HMAC密钥，nil表示仅验签
+	VerifyKey any    // Public key or HMAC secret | 公钥或HMAC密钥
+}
+
+// NewHMACKey Creates HS256 key from shared secret | 使用共享密钥创建HS256密钥
+func NewHMACKey(kid, secret string) *JwtKey {
+	return &JwtKey{
+		Kid:       kid,
+		Algorithm: JwtAlgHS256,
+		SignKey:   []byte(secret),
+		VerifyKey: []byte(secret),
+	}
+}
+
+// NewRSAKey Creates RSA signing key, alg defaults to RS256 | 创建RSA签名密钥，算法默认RS256
+func NewRSAKey(kid string, privateKey *rsa.PrivateKey, alg ...string) (*JwtKey, error) {
+	if privateKey == nil {
+		return nil, ErrJwtKeyUnsupported
+	}
+	algorithm := JwtAlgRS256
+	if len(alg) > 0 && alg[0] != "" {
+		algorithm = alg[0]
+	}
+	return newJwtKey(kid, algorithm, privateKey, &privateKey.PublicKey)
+}
+
+// NewECDSAKey Creates ECDSA signing key, alg follows the curve | 创建ECDSA签名密钥，算法由曲线决定
+func NewECDSAKey(kid string, privateKey *ecdsa.PrivateKey) (*JwtKey, error) {
+	if privateKey == nil {
+		return nil, ErrJwtKeyUnsupported
+	}
+	alg, err := ecdsaAlgorithm(privateKey.Curve)
+	if err != nil {
+		return nil, err
+	}
+	return newJwtKey(kid, alg, privateKey, &privateKey.PublicKey)
+}
+
+// NewEd25519Key Creates EdDSA signing key | 创建EdDSA签名密钥
+func NewEd25519Key(kid string, privateKey ed25519.PrivateKey) (*JwtKey, error) {
+	if len(privateKey) != ed25519.PrivateKeySize {
+		return nil, ErrJwtKeyUnsupported
+	}
+	return newJwtKey(kid, JwtAlgEdDSA, privateKey, privateKey.Public())
+}
+
+// NewVerifyKey Creates verify-only key from public key, empty alg is inferred | 使用公钥创建仅验签密钥，alg为空时自动推断
+func NewVerifyKey(kid, alg string, publicKey crypto.PublicKey) (*JwtKey, error) {
+	if alg == "" {
+		switch pub := publicKey.(type) {
+		case *rsa.PublicKey:
+			alg = JwtAlgRS256
+		case *ecdsa.PublicKey:
+			a, err := ecdsaAlgorithm(pub.Curve)
+			if err != nil {
+				return nil, err
+			}
+			alg = a
+		case ed25519.PublicKey:
+			alg = JwtAlgEdDSA
+		default:
+			return nil, ErrJwtKeyUnsupported
+		}
+	}
+	return newJwtKey(kid, alg, nil, publicKey)
+}
+
+// NewJwtKeyFromPEM Creates key from PEM private key (PKCS#1, PKCS#8, SEC1) or public key (PKIX) | 从PEM私钥（PKCS#1、PKCS#8、SEC1）或公钥（PKIX）创建密钥
+func NewJwtKeyFromPEM(kid string, pemData []byte) (*JwtKey, error) {
+	block, _ := pem.Decode(pemData)
+	if block == nil {
+		return nil, ErrJwtKeyInvalidPEM
+	}
+
+	var parsed any
+	var err error
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		parsed, err = x509.ParseECPrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrJwtKeyInvalidPEM, block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrJwtKeyInvalidPEM, err)
+	}
+
+	switch k := parsed.(type) {
+	case *rsa.PrivateKey:
+		return NewRSAKey(kid, k)
+	case *ecdsa.PrivateKey:
+		return NewECDSAKey(kid, k)
+	case ed25519.PrivateKey:
+		return NewEd25519Key(kid, k)
+	default:
+		return NewVerifyKey(kid, "", k)
+	}
+}
+
+// CanSign Reports whether key has a private part | 是否可用于签名
+func (k *JwtKey) CanSign() bool {
+	return k.SignKey != nil
+}
+
+// IsSymmetric Reports whether key is a shared HMAC secret | 是否为对称HMAC密钥
+func (k *JwtKey) IsSymmetric() bool {
+	_, ok := jwt.GetSigningMethod(k.Algorithm).(*jwt.SigningMethodHMAC)
+	return ok
+}
+
+// newJwtKey Validates algorithm against key types | 校验算法与密钥类型是否匹配
+func newJwtKey(kid, alg string, signKey, verifyKey any) (*JwtKey, error) {
+	method := jwt.GetSigningMethod(alg)
+	if method == nil {
+		return nil, fmt.Errorf("%w: %s", ErrJwtKeyUnsupported, alg)
+	}
+
+	var ok bool
+	switch method.(type) {
+	case *jwt.SigningMethodRSA, *jwt.SigningMethodRSAPSS:
+		_, ok = verifyKey.(*rsa.PublicKey)
+	case *jwt.SigningMethodECDSA:
+		var pub *ecdsa.PublicKey
+		if pub, ok = verifyKey.(*ecdsa.PublicKey); ok {
+			curveAlg, err := ecdsaAlgorithm(pub.Curve)
+			ok = err == nil && curveAlg == alg
+		}
+	case *jwt.SigningMethodEd25519:
+		_, ok = verifyKey.(ed25519.PublicKey)
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrJwtKeyAlgMismatch, alg)
+	}
+
+	return &JwtKey{
+		Kid:       kid,
+		Algorithm: alg,
+		SignKey:   signKey,
+		VerifyKey: verifyKey,
+	}, nil
+}
+
+// ecdsaAlgorithm Maps curve to ES algorithm | 将曲线映射为ES算法
+func ecdsaAlgorithm(curve elliptic.Curve) (string, error) {
+	switch curve {
+	case elliptic.P256():
+		return JwtAlgES256, nil
+	case elliptic.P384():
+		return JwtAlgES384, nil
+	case elliptic.P521():
+		return JwtAlgES512, nil
+	default:
+		return "", ErrJwtKeyUnsupported
+	}
+}
+
+// ============ Key Set | 密钥集 ============
+
+// JwtKeySet Active signing key plus verification keys indexed by kid | 当前签名密钥及按kid索引的验签密钥
+type JwtKeySet struct {
+	mu        sync.RWMutex
+	keys      map[string]*JwtKey
+	order     []string // Insertion order for stable JWKS output | 插入顺序，保证JWKS输出稳定
+	activeKid string
+}
+
+// NewJwtKeySet Creates key set, the first key becomes active | 创建密钥集，第一个密钥为当前签名密钥
+func NewJwtKeySet(keys ...*JwtKey) (*JwtKeySet, error) {
+	ks := &JwtKeySet{keys: make(map[string]*JwtKey)}
+	for i, key := range keys {
+		if err := ks.AddKey(key); err != nil {
+			return nil, err
+		}
+		if i == 0 {
+			if err := ks.SetActiveKey(key.Kid); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return ks, nil
+}
+
+// AddKey Adds verification key | 添加验签密钥
+func (ks *JwtKeySet) AddKey(key *JwtKey) error {
+	if key == nil {
+		return ErrJwtKeyNotFound
+	}
+
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	if _, exists := ks.keys[key.Kid]; exists {
+		return fmt.Errorf("%w: %s", ErrJwtKeyKidDuplicate, key.Kid)
+	}
+	ks.keys[key.Kid] = key
+	ks.order = append(ks.order, key.Kid)
+	return nil
+}
+
+// SetActiveKey Switches signing key | 切换签名密钥
+func (ks *JwtKeySet) SetActiveKey(kid string) error {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	key, ok := ks.keys[kid]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrJwtKeyNotFound, kid)
+	}
+	if !key.CanSign() {
+		return ErrJwtKeyVerifyOnly
+	}
+	ks.activeKid = kid
+	return nil
+}
+
+// Rotate Adds key and makes it active, previous keys keep verifying | 添加密钥并设为签名密钥，旧密钥继续用于验签
+func (ks *JwtKeySet) Rotate(key *JwtKey) error {
+	if err := ks.AddKey(key); err != nil {
+		return err
+	}
+	return ks.SetActiveKey(key.Kid)
+}
+
+// RemoveKey Removes key, the active key cannot be removed | 移除密钥（不能移除当前签名密钥）
+func (ks *JwtKeySet) RemoveKey(kid string) error {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	if kid == ks.activeKid {
+		return fmt.Errorf("cannot remove active jwt key: %s", kid)
+	}
+	if _, ok := ks.keys[kid]; !ok {
+		return fmt.Errorf("%w: %s", ErrJwtKeyNotFound, kid)
+	}
+	delete(ks.keys, kid)
+	for i, k := range ks.order {
+		if k == kid {
+			ks.order = append(ks.order[:i], ks.order[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+// GetKey Gets key by kid | 按kid获取密钥
+func (ks *JwtKeySet) GetKey(kid string) (*JwtKey, bool) {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	key, ok := ks.keys[kid]
+	return key, ok
+}
+
+// ActiveKey Gets current signing key | 获取当前签名密钥
+func (ks *JwtKeySet) ActiveKey() (*JwtKey, error) {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	key, ok := ks.keys[ks.activeKid]
+	if !ok {
+		return nil, ErrJwtKeyNotFound
+	}
+	return key, nil
+}
+
+// ============ JWKS | JWKS ============
+
+// JWK JSON Web Key (public part only) | JSON Web Key（仅公钥部分）
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid,omitempty"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`   // RSA modulus | RSA模数
+	E   string `json:"e,omitempty"`   // RSA exponent | RSA指数
+	Crv string `json:"crv,omitempty"` // Curve name | 曲线名
+	X   string `json:"x,omitempty"`   // EC/OKP x coordinate | EC/OKP的x坐标
+	Y   string `json:"y,omitempty"`   // EC y coordinate | EC的y坐标
+}
+
+// JWKS JSON Web Key Set document | JSON Web Key Set文档
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS Builds JWKS of asymmetric keys, HMAC secrets are never published | 构建非对称密钥的JWKS（HMAC密钥永不发布）
+func (ks *JwtKeySet) JWKS() *JWKS {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	doc := &JWKS{Keys: []JWK{}}
+	for _, kid := range ks.order {
+		if jwk, ok := toJWK(ks.keys[kid]); ok {
+			doc.Keys = append(doc.Keys, jwk)
+		}
+	}
+	return doc
+}
+
+// JWKSHandler HTTP endpoint serving the JWKS document | 提供JWKS文档的HTTP接口
+func (ks *JwtKeySet) JWKSHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "public, max-age=300")
+		_ = json.NewEncoder(w).Encode(ks.JWKS())
+	}
+}
+
+// toJWK Converts public key to JWK | 将公钥转换为JWK
+func toJWK(key *JwtKey) (JWK, bool) {
+	jwk := JWK{Kid: key.Kid, Use: "sig", Alg: key.Algorithm}
+
+	switch pub := key.VerifyKey.(type) {
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = base64URL(pub.N.Bytes())
+		jwk.E = base64URL(big.NewInt(int64(pub.E)).Bytes())
+	case *ecdsa.PublicKey:
+		size := (pub.Curve.Params().BitSize + 7) / 8
+		jwk.Kty = "EC"
+		jwk.Crv = pub.Curve.Params().Name
+		jwk.X = base64URL(pub.X.FillBytes(make([]byte, size)))
+		jwk.Y = base64URL(pub.Y.FillBytes(make([]byte, size)))
+	case ed25519.PublicKey:
+		jwk.Kty = "OKP"
+		jwk.Crv = "Ed25519"
+		jwk.X = base64URL(pub)
+	default:
+		return JWK{}, false
+	}
+	return jwk, true
+}
+
+// base64URL Encodes bytes as unpadded base64url | 编码为无填充的base64url
+func base64URL(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
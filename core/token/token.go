@@ -4,6 +4,7 @@ import (
 	"crypto/rand"
 	"crypto/sha256"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"math/big"
 	"time"
@@ -28,11 +29,18 @@ const (
 var (
 	ErrInvalidToken            = fmt.Errorf("invalid token")
 	ErrUnexpectedSigningMethod = fmt.Errorf("unexpected signing method")
+	ErrTokenExpired            = fmt.Errorf("token has expired")
+	ErrInvalidIssuer           = fmt.Errorf("token issuer is not accepted")
+	ErrInvalidAudience         = fmt.Errorf("token audience is not accepted")
 )
 
 // Generator Token generator | Token生成器
 type Generator struct {
 	config *config.Config
+	keys   *JwtKeySet // JWT signing and verification keys | JWT签名与验签密钥
+
+	encryptKeys *EncryptKeySet // Encrypted token keys | 加密Token密钥
+	custom      TokenGenerator // Overrides TokenStyle when set | 设置后覆盖TokenStyle
 }
 
 // NewGenerator Creates a new token generator | 创建新的Token生成器
@@ -40,18 +48,50 @@ func NewGenerator(cfg *config.Config) *Generator {
 	if cfg == nil {
 		cfg = config.DefaultConfig()
 	}
-	return &Generator{
+	g := &Generator{
 		config: cfg,
 	}
+
+	// Configured secret is the legacy key without kid | 配置的密钥作为无kid的旧版密钥
+	g.keys, _ = NewJwtKeySet(NewHMACKey("", g.getJWTSecret()))
+
+	// Configured encrypt secret is the legacy key without kid | 配置的加密密钥作为无kid的旧版密钥
+	g.encryptKeys, _ = NewEncryptKeySet()
+	if cfg.TokenEncryptKey != "" {
+		key, _ := NewEncryptKeyFromSecret("", cfg.TokenEncryptKey)
+		_ = g.encryptKeys.Rotate(key)
+	}
+	return g
+}
+
+// GetKeySet Gets JWT key set for rotation and JWKS | 获取JWT密钥集（用于轮换与JWKS）
+func (g *Generator) GetKeySet() *JwtKeySet {
+	return g.keys
+}
+
+// GetEncryptKeySet Gets encrypted token key set for rotation | 获取加密Token密钥集（用于轮换）
+func (g *Generator) GetEncryptKeySet() *EncryptKeySet {
+	return g.encryptKeys
 }
 
 // ============ Public Methods | 公共方法 ============
 
 // Generate Generates token based on configured style | 根据配置的风格生成Token
 func (g *Generator) Generate(loginID string, device string) (string, error) {
+	return g.GenerateWithExtra(loginID, device, g.config.Timeout, nil)
+}
+
+// GenerateWithExtra Generates token with timeout override and extra data | 使用超时覆盖与扩展数据生成Token
+// timeout only affects JWT and encrypted "exp", extra is merged into their claims without overriding reserved ones,
+// registered claims (iss, sub, aud, exp, nbf, iat, jti) in extra are dropped
+// timeout仅影响JWT与加密Token的exp，extra合并进其声明（不覆盖保留字段），extra中的注册声明（iss、sub、aud、exp、nbf、iat、jti）会被丢弃
+func (g *Generator) GenerateWithExtra(loginID string, device string, timeout int64, extra map[string]any) (string, error) {
 	if loginID == "" {
 		return "", fmt.Errorf("loginID cannot be empty")
 	}
+	if g.custom != nil {
+		return generateCustom(g.custom, loginID, device)
+	}
 
 	switch g.config.TokenStyle {
 	case config.TokenStyleUUID:
@@ -65,7 +105,9 @@ func (g *Generator) Generate(loginID string, device string) (string, error) {
 	case config.TokenStyleRandom128:
 		return g.generateSimple(128)
 	case config.TokenStyleJWT:
-		return g.generateJWT(loginID, device)
+		return g.generateJWT(loginID, device, timeout, extra)
+	case config.TokenStyleEncrypted:
+		return g.generateEncrypted(loginID, device, timeout, extra)
 	case config.TokenStyleHash:
 		return g.generateHash(loginID, device)
 	case config.TokenStyleTimestamp:
@@ -73,6 +115,9 @@ func (g *Generator) Generate(loginID string, device string) (string, error) {
 	case config.TokenStyleTik:
 		return g.generateTik()
 	default:
+		if gen, ok := GetStyleGenerator(g.config.TokenStyle); ok {
+			return generateCustom(gen, loginID, device)
+		}
 		return g.generateUUID()
 	}
 }
@@ -102,23 +147,66 @@ func (g *Generator) generateSimple(length int) (string, error) {
 }
 
 // generateJWT Generates JWT token | 生成JWT Token
-func (g *Generator) generateJWT(loginID string, device string) (string, error) {
+func (g *Generator) generateJWT(loginID string, device string, timeout int64, extra map[string]any) (string, error) {
 	now := time.Now()
-	claims := jwt.MapClaims{
-		"loginId": loginID,
-		"device":  device,
-		"iat":     now.Unix(),
+	claims := jwt.MapClaims{}
+	for k, v := range extra {
+		claims[k] = v
+	}
+	// Extra must not forge registered claims | extra不能伪造注册声明
+	for _, k := range registeredClaims {
+		delete(claims, k)
 	}
+	claims[ClaimKeyLoginID] = loginID
+	claims[ClaimKeyDevice] = device
+	claims[ClaimKeyIssuedAt] = now.Unix()
 
 	// Add expiration if timeout is configured | 如果配置了超时时间则添加过期时间
-	if g.config.Timeout > 0 {
-		claims["exp"] = now.Add(time.Duration(g.config.Timeout) * time.Second).Unix()
+	if timeout > 0 {
+		claims[ClaimKeyExpiresAt] = now.Add(time.Duration(timeout) * time.Second).Unix()
+	}
+
+	// Add configured registered claims | 添加配置的注册声明
+	if cfg := g.config.JwtClaims; cfg != nil {
+		if cfg.Issuer != "" {
+			claims[ClaimKeyIssuer] = cfg.Issuer
+		}
+		if len(cfg.Audience) > 0 {
+			claims[ClaimKeyAudience] = cfg.Audience
+		}
+		if cfg.Subject {
+			claims[ClaimKeySubject] = loginID
+		}
+		if cfg.NotBefore {
+			claims[ClaimKeyNotBefore] = now.Unix()
+		}
+	}
+
+	// Revocation watermarks need sub-second issue time | 吊销水位需要亚秒级签发时间
+	if g.config.JwtRevocation {
+		claims[ClaimKeyIssuedAtUs] = now.UnixMicro()
+	}
+
+	// Unique ID for revocation, also keeps same-second logins apart | 用于吊销的唯一ID，同时区分同一秒内的登录
+	if g.needsJwtID() {
+		jti := make([]byte, HashRandomBytesLen)
+		if _, err := rand.Read(jti); err != nil {
+			return "", fmt.Errorf("failed to generate random bytes: %w", err)
+		}
+		claims[ClaimKeyJwtID] = hex.EncodeToString(jti)
+	}
+
+	key, err := g.keys.ActiveKey()
+	if err != nil {
+		return "", err
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	secretKey := g.getJWTSecret()
+	token := jwt.NewWithClaims(jwt.GetSigningMethod(key.Algorithm), claims)
+	if key.Kid != "" {
+		token.Header["kid"] = key.Kid
+	}
 
-	signedToken, err := token.SignedString([]byte(secretKey))
+	signedToken, err := token.SignedString(key.SignKey)
 	if err != nil {
 		return "", fmt.Errorf("failed to sign JWT token: %w", err)
 	}
@@ -126,6 +214,14 @@ func (g *Generator) generateJWT(loginID string, device string) (string, error) {
 	return signedToken, nil
 }
 
+// needsJwtID Reports whether JWT carries a "jti" | JWT是否需要携带jti
+func (g *Generator) needsJwtID() bool {
+	if g.config.JwtMode != config.JwtModeDefault || g.config.JwtRevocation {
+		return true
+	}
+	return g.config.JwtClaims != nil && g.config.JwtClaims.JwtID
+}
+
 // getJWTSecret Gets JWT secret key with fallback | 获取JWT密钥（带默认值）
 func (g *Generator) getJWTSecret() string {
 	if g.config.JwtSecretKey != "" {
@@ -142,25 +238,87 @@ func (g *Generator) ParseJWT(tokenStr string) (jwt.MapClaims, error) {
 		return nil, fmt.Errorf("token string cannot be empty")
 	}
 
-	secretKey := g.getJWTSecret()
+	var opts []jwt.ParserOption
+	if cfg := g.config.JwtClaims; cfg != nil && cfg.Leeway > 0 {
+		opts = append(opts, jwt.WithLeeway(time.Duration(cfg.Leeway)*time.Second))
+	}
 
 	token, err := jwt.Parse(tokenStr, func(token *jwt.Token) (any, error) {
-		// Verify signing method | 验证签名方法
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+		// Select key by kid, tokens without kid use the legacy secret | 按kid选择密钥，无kid的Token使用旧版密钥
+		kid, _ := token.Header["kid"].(string)
+		key, ok := g.keys.GetKey(kid)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrJwtKeyNotFound, kid)
+		}
+
+		// Algorithm must match the key to prevent alg confusion | 算法必须与密钥一致，防止算法混淆攻击
+		if token.Method.Alg() != key.Algorithm {
 			return nil, fmt.Errorf("%w: %v", ErrUnexpectedSigningMethod, token.Header["alg"])
 		}
-		return []byte(secretKey), nil
-	})
+		return key.VerifyKey, nil
+	}, opts...)
 
 	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return nil, fmt.Errorf("%w: %v", ErrTokenExpired, err)
+		}
+		return nil, fmt.Errorf("failed to parse JWT: %w", err)
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !token.Valid {
+		return nil, ErrInvalidToken
+	}
+	if err := g.validateClaims(claims); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+// ParseClaimsUnverified Reads claims without verifying signature or expiry | 读取声明但不校验签名与过期
+// Only for lookups that can reject a token, never to accept one | 仅用于只会拒绝Token的查询，不可用于认可Token
+func (g *Generator) ParseClaimsUnverified(tokenStr string) (*Claims, error) {
+	claims := jwt.MapClaims{}
+	if _, _, err := jwt.NewParser().ParseUnverified(tokenStr, claims); err != nil {
 		return nil, fmt.Errorf("failed to parse JWT: %w", err)
 	}
+	return newClaims(claims), nil
+}
+
+// ParseClaims Parses JWT token and returns typed claims | 解析JWT Token并返回类型化声明
+func (g *Generator) ParseClaims(tokenStr string) (*Claims, error) {
+	claims, err := g.ParseJWT(tokenStr)
+	if err != nil {
+		return nil, err
+	}
+	return newClaims(claims), nil
+}
+
+// validateClaims Checks configured issuer and audience | 校验配置的签发者与受众
+func (g *Generator) validateClaims(claims jwt.MapClaims) error {
+	cfg := g.config.JwtClaims
+	if cfg == nil {
+		return nil
+	}
 
-	if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-		return claims, nil
+	if cfg.Issuer != "" {
+		if iss, _ := claims.GetIssuer(); iss != cfg.Issuer {
+			return fmt.Errorf("%w: %s", ErrInvalidIssuer, iss)
+		}
 	}
 
-	return nil, ErrInvalidToken
+	if len(cfg.Audience) > 0 {
+		aud, _ := claims.GetAudience()
+		for _, want := range cfg.Audience {
+			for _, got := range aud {
+				if got == want {
+					return nil
+				}
+			}
+		}
+		return fmt.Errorf("%w: %v", ErrInvalidAudience, aud)
+	}
+	return nil
 }
 
 // ValidateJWT Validates JWT token | 验证JWT Token
@@ -169,19 +327,18 @@ func (g *Generator) ValidateJWT(tokenStr string) error {
 	return err
 }
 
-// GetLoginIDFromJWT Extracts login ID from JWT token | 从JWT Token中提取登录ID
+// GetLoginIDFromJWT Extracts login ID from JWT token, use ParseClaims for the other claims | 从JWT Token中提取登录ID，其他声明请使用ParseClaims
 func (g *Generator) GetLoginIDFromJWT(tokenStr string) (string, error) {
-	claims, err := g.ParseJWT(tokenStr)
+	claims, err := g.ParseClaims(tokenStr)
 	if err != nil {
 		return "", err
 	}
 
-	loginID, ok := claims["loginId"].(string)
-	if !ok {
+	if claims.LoginID == "" {
 		return "", fmt.Errorf("loginId not found in token claims")
 	}
 
-	return loginID, nil
+	return claims.LoginID, nil
 }
 
 // generateHash Generates SHA256 hash-based token | 生成SHA256哈希风格Token
@@ -1,7 +1,14 @@
 package token
 
 import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/click33/sa-token-go/core/config"
 )
@@ -93,14 +100,16 @@ func TestAllTokenStyles(t *testing.T) {
 		config.TokenStyleHash,
 		config.TokenStyleTimestamp,
 		config.TokenStyleTik,
+		config.TokenStyleEncrypted,
 	}
 
 	for _, style := range styles {
 		t.Run(string(style), func(t *testing.T) {
 			cfg := &config.Config{
-				TokenStyle:   style,
-				Timeout:      3600,
-				JwtSecretKey: "test-secret-key",
+				TokenStyle:      style,
+				Timeout:         3600,
+				JwtSecretKey:    "test-secret-key",
+				TokenEncryptKey: "test-encrypt-key",
 			}
 			gen := NewGenerator(cfg)
 
@@ -117,3 +126,282 @@ func TestAllTokenStyles(t *testing.T) {
 		})
 	}
 }
+
+func TestJWTAsymmetricKeys(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	_, edKey, _ := ed25519.GenerateKey(rand.Reader)
+
+	rsaJwk, err := NewRSAKey("rsa-1", rsaKey)
+	if err != nil {
+		t.Fatalf("Failed to create RSA key: %v", err)
+	}
+	ecJwk, err := NewECDSAKey("ec-1", ecKey)
+	if err != nil {
+		t.Fatalf("Failed to create ECDSA key: %v", err)
+	}
+	edJwk, err := NewEd25519Key("ed-1", edKey)
+	if err != nil {
+		t.Fatalf("Failed to create Ed25519 key: %v", err)
+	}
+
+	gen := NewGenerator(&config.Config{
+		TokenStyle:   config.TokenStyleJWT,
+		Timeout:      3600,
+		JwtSecretKey: "test-secret-key",
+	})
+	legacy, _ := gen.Generate("user1000", "default")
+
+	var issued []string
+	for _, key := range []*JwtKey{rsaJwk, ecJwk, edJwk} {
+		if err := gen.GetKeySet().Rotate(key); err != nil {
+			t.Fatalf("Failed to rotate to %s: %v", key.Kid, err)
+		}
+		token, err := gen.Generate("user1000", "default")
+		if err != nil {
+			t.Fatalf("Failed to sign with %s: %v", key.Algorithm, err)
+		}
+		issued = append(issued, token)
+	}
+
+	// Every rotated key keeps verifying its own tokens | 轮换后旧密钥仍可验签
+	for _, token := range append(issued, legacy) {
+		if loginID, err := gen.GetLoginIDFromJWT(token); err != nil || loginID != "user1000" {
+			t.Errorf("Failed to verify token: %v", err)
+		}
+	}
+
+	// Retired key stops verifying | 移除的密钥不再验签
+	if err := gen.GetKeySet().RemoveKey("rsa-1"); err != nil {
+		t.Fatalf("Failed to remove key: %v", err)
+	}
+	if err := gen.ValidateJWT(issued[0]); err == nil {
+		t.Error("Token signed by removed key should be rejected")
+	}
+
+	jwks := gen.GetKeySet().JWKS()
+	if len(jwks.Keys) != 2 {
+		t.Fatalf("JWKS should publish 2 asymmetric keys, got %d", len(jwks.Keys))
+	}
+	if jwks.Keys[0].Kty != "EC" || jwks.Keys[0].Crv != "P-256" || jwks.Keys[1].Kty != "OKP" {
+		t.Errorf("Unexpected JWKS: %+v", jwks.Keys)
+	}
+}
+
+func TestJWTRejectsAlgorithmConfusion(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	verifyOnly, err := NewVerifyKey("rsa-1", "", &rsaKey.PublicKey)
+	if err != nil {
+		t.Fatalf("Failed to create verify key: %v", err)
+	}
+
+	gen := NewGenerator(&config.Config{TokenStyle: config.TokenStyleJWT, JwtSecretKey: "test-secret-key"})
+	if err := gen.GetKeySet().AddKey(verifyOnly); err != nil {
+		t.Fatalf("Failed to add key: %v", err)
+	}
+	if err := gen.GetKeySet().SetActiveKey("rsa-1"); err == nil {
+		t.Error("Verify-only key should not become the signing key")
+	}
+
+	// HS256 token claiming an RSA kid must fail | 声称RSA kid的HS256 Token必须被拒绝
+	forger := NewGenerator(&config.Config{TokenStyle: config.TokenStyleJWT, JwtSecretKey: "test-secret-key"})
+	_ = forger.GetKeySet().Rotate(NewHMACKey("rsa-1", "test-secret-key"))
+	forged, _ := forger.Generate("admin", "default")
+	if err := gen.ValidateJWT(forged); err == nil {
+		t.Error("Token with mismatched algorithm should be rejected")
+	}
+}
+
+func TestJWTRegisteredClaims(t *testing.T) {
+	cfg := &config.Config{
+		TokenStyle:   config.TokenStyleJWT,
+		JwtSecretKey: "test-secret-key",
+		Timeout:      3600,
+		JwtClaims: &config.JwtClaimsConfig{
+			Issuer:    "auth.example.com",
+			Audience:  []string{"api", "admin"},
+			Subject:   true,
+			NotBefore: true,
+			JwtID:     true,
+			Leeway:    5,
+		},
+	}
+	gen := NewGenerator(cfg)
+
+	token, err := gen.GenerateWithExtra("user1000", "pc", 0, map[string]any{"tenant": "t1", "roles": []string{"admin"}})
+	if err != nil {
+		t.Fatalf("Failed to generate JWT: %v", err)
+	}
+
+	claims, err := gen.ParseClaims(token)
+	if err != nil {
+		t.Fatalf("Failed to parse claims: %v", err)
+	}
+	if claims.LoginID != "user1000" || claims.Subject != "user1000" || claims.Issuer != "auth.example.com" {
+		t.Errorf("Unexpected claims: %+v", claims)
+	}
+	if len(claims.Audience) != 2 || claims.ID == "" || claims.NotBefore != claims.IssuedAt || claims.ExpiresAt != 0 {
+		t.Errorf("Unexpected registered claims: %+v", claims)
+	}
+	if tenant, _ := claims.Get("tenant"); tenant != "t1" || len(claims.Roles) != 1 || claims.Permissions != nil {
+		t.Errorf("Unexpected custom claims: %+v", claims)
+	}
+
+	// Tokens from another issuer or audience must be rejected | 其他签发者或受众的Token必须被拒绝
+	other := NewGenerator(&config.Config{TokenStyle: config.TokenStyleJWT, JwtSecretKey: "test-secret-key",
+		JwtClaims: &config.JwtClaimsConfig{Issuer: "other", Audience: []string{"api"}}})
+	foreign, _ := other.Generate("user1000", "pc")
+	if err := gen.ValidateJWT(foreign); !errors.Is(err, ErrInvalidIssuer) {
+		t.Errorf("Expected ErrInvalidIssuer, got %v", err)
+	}
+
+	other = NewGenerator(&config.Config{TokenStyle: config.TokenStyleJWT, JwtSecretKey: "test-secret-key",
+		JwtClaims: &config.JwtClaimsConfig{Issuer: "auth.example.com", Audience: []string{"billing"}}})
+	foreign, _ = other.Generate("user1000", "pc")
+	if err := gen.ValidateJWT(foreign); !errors.Is(err, ErrInvalidAudience) {
+		t.Errorf("Expected ErrInvalidAudience, got %v", err)
+	}
+}
+
+func TestJWTExtraCannotForgeRegisteredClaims(t *testing.T) {
+	future := time.Now().Add(time.Hour).Unix()
+	forged := map[string]any{
+		"iss": "attacker", "sub": "admin", "aud": []string{"billing"},
+		"exp": future, "nbf": future, "iat": int64(1), "jti": "fixed", "tenant": "t1",
+	}
+
+	for _, style := range []config.TokenStyle{config.TokenStyleJWT, config.TokenStyleEncrypted} {
+		t.Run(string(style), func(t *testing.T) {
+			gen := NewGenerator(&config.Config{
+				TokenStyle:      style,
+				JwtSecretKey:    "test-secret-key",
+				TokenEncryptKey: "test-encrypt-key",
+				JwtClaims:       &config.JwtClaimsConfig{JwtID: true},
+			})
+			token, err := gen.GenerateWithExtra("user1000", "pc", 0, forged)
+			if err != nil {
+				t.Fatalf("GenerateWithExtra() error = %v", err)
+			}
+			parse := gen.ParseClaims
+			if style == config.TokenStyleEncrypted {
+				parse = gen.DecryptToken
+			}
+			claims, err := parse(token)
+			if err != nil {
+				t.Fatalf("parse error = %v", err)
+			}
+			if claims.Issuer != "" || claims.Subject != "" || claims.Audience != nil || claims.ExpiresAt != 0 || claims.NotBefore != 0 {
+				t.Fatalf("registered claims taken from extra: %+v", claims)
+			}
+			if claims.IssuedAt == 1 || claims.ID == "fixed" {
+				t.Fatalf("iat/jti taken from extra: %+v", claims)
+			}
+			if tenant, _ := claims.Get("tenant"); tenant != "t1" {
+				t.Fatalf("custom claim dropped: %+v", claims)
+			}
+		})
+	}
+}
+
+func TestEncryptedToken(t *testing.T) {
+	cfg := &config.Config{
+		TokenStyle:      config.TokenStyleEncrypted,
+		Timeout:         3600,
+		TokenEncryptKey: "test-encrypt-key",
+	}
+	gen := NewGenerator(cfg)
+
+	token, err := gen.GenerateWithExtra("user1000", "pc", 3600, map[string]any{"tenant": "t1"})
+	if err != nil {
+		t.Fatalf("Failed to generate encrypted token: %v", err)
+	}
+
+	claims, err := gen.DecryptToken(token)
+	if err != nil {
+		t.Fatalf("Failed to decrypt token: %v", err)
+	}
+	if claims.LoginID != "user1000" || claims.Device != "pc" || claims.ExpiresAt-claims.IssuedAt != 3600 {
+		t.Errorf("Unexpected claims: %+v", claims)
+	}
+	if tenant, _ := claims.Get("tenant"); tenant != "t1" {
+		t.Errorf("Expected tenant claim, got %+v", claims.Extra)
+	}
+
+	// Tampered tokens and other secrets must be rejected | 被篡改的Token与其他密钥必须被拒绝
+	tampered := []byte(token)
+	tampered[len(tampered)-2] ^= 1
+	if _, err := gen.DecryptToken(string(tampered)); err == nil {
+		t.Error("Tampered token should fail to decrypt")
+	}
+	other := NewGenerator(&config.Config{TokenStyle: config.TokenStyleEncrypted, TokenEncryptKey: "other-key"})
+	if _, err := other.DecryptToken(token); !errors.Is(err, ErrDecryptFailed) {
+		t.Errorf("Expected ErrDecryptFailed, got %v", err)
+	}
+
+	// Rotation: new tokens carry kid, old ones keep decrypting until the key is removed | 轮换：新Token携带kid，旧Token在密钥移除前仍可解密
+	key, err := NewEncryptKey("k2", make([]byte, 32))
+	if err != nil {
+		t.Fatalf("Failed to create encrypt key: %v", err)
+	}
+	if err := gen.GetEncryptKeySet().Rotate(key); err != nil {
+		t.Fatalf("Failed to rotate key: %v", err)
+	}
+	rotated, _ := gen.Generate("user1000", "pc")
+	if rotated[:3] != "k2." {
+		t.Errorf("Rotated token should start with kid, got %s", rotated)
+	}
+	if _, err := gen.DecryptToken(rotated); err != nil {
+		t.Errorf("Rotated token should decrypt: %v", err)
+	}
+	if _, err := gen.DecryptToken(token); err != nil {
+		t.Errorf("Old token should still decrypt: %v", err)
+	}
+	if err := gen.GetEncryptKeySet().RemoveKey(""); err != nil {
+		t.Fatalf("Failed to remove old key: %v", err)
+	}
+	if _, err := gen.DecryptToken(token); !errors.Is(err, ErrEncryptKeyNotFound) {
+		t.Errorf("Expected ErrEncryptKeyNotFound, got %v", err)
+	}
+
+	if _, err := NewEncryptKey("bad", []byte("short")); !errors.Is(err, ErrEncryptKeyInvalid) {
+		t.Errorf("Expected ErrEncryptKeyInvalid, got %v", err)
+	}
+}
+
+func TestCustomTokenGenerator(t *testing.T) {
+	style := config.TokenStyle("test-prefixed")
+	if style.IsValid() {
+		t.Fatal("Unregistered style should be invalid")
+	}
+
+	err := RegisterStyle(style, TokenGeneratorFunc(func(loginID, device string) (string, error) {
+		return "sk_live_" + loginID + "_" + device, nil
+	}))
+	if err != nil {
+		t.Fatalf("Failed to register style: %v", err)
+	}
+	if !style.IsValid() {
+		t.Error("Registered style should be valid")
+	}
+	if err := RegisterStyle(config.TokenStyleUUID, TokenGeneratorFunc(nil)); err == nil {
+		t.Error("Built-in style should not be replaceable")
+	}
+
+	gen := NewGenerator(&config.Config{TokenStyle: style})
+	token, err := gen.Generate("user1000", "pc")
+	if err != nil || token != "sk_live_user1000_pc" {
+		t.Errorf("Unexpected custom token %q: %v", token, err)
+	}
+
+	// Generator override wins over TokenStyle | 生成器覆盖优先于TokenStyle
+	gen.SetTokenGenerator(TokenGeneratorFunc(func(loginID, device string) (string, error) {
+		return "", nil
+	}))
+	if _, err := gen.Generate("user1000", "pc"); err == nil {
+		t.Error("Empty custom token should be rejected")
+	}
+	gen.SetTokenGenerator(nil)
+	if token, _ := gen.Generate("user1000", "pc"); token != "sk_live_user1000_pc" {
+		t.Errorf("Nil generator should restore style, got %q", token)
+	}
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/click33/sa-token-go/core"
@@ -14,7 +15,14 @@ var oauth2Server *core.OAuth2Server
 
 func main() {
 	storage := memory.NewStorage()
-	oauth2Server = core.NewOAuth2Server(storage)
+	oauth2Server = core.NewOAuth2Server(storage, "satoken:")
+	oauth2Server.SetUserAuthenticator(core.OAuth2UserAuthenticatorFunc(func(username, password string) (string, error) {
+		// Check credentials with your user service | 使用你的用户服务校验凭证
+		if username == "alice" && password == "alice-pwd" {
+			return "user123", nil
+		}
+		return "", fmt.Errorf("bad credentials")
+	}))
 
 	registerClients()
 
@@ -65,9 +73,22 @@ func registerClients() {
 	}
 	oauth2Server.RegisterClient(mobileClient)
 
+	serviceClient := &core.OAuth2Client{
+		ClientID:     "report-service",
+		ClientSecret: "service-secret-789",
+		GrantTypes: []core.OAuth2GrantType{
+			core.GrantTypeClientCredentials,
+			core.GrantTypePassword,
+			core.GrantTypeRefreshToken,
+		},
+		Scopes: []string{"read"},
+	}
+	oauth2Server.RegisterClient(serviceClient)
+
 	fmt.Println("✅ OAuth2 Clients registered:")
 	fmt.Println("  - webapp (client_id: webapp, secret: secret123)")
 	fmt.Println("  - mobile-app (client_id: mobile-app, secret: mobile-secret-456)")
+	fmt.Println("  - report-service (client_id: report-service, secret: service-secret-789)")
 }
 
 func authorizeHandler(c *gin.Context) {
@@ -76,6 +97,8 @@ func authorizeHandler(c *gin.Context) {
 	responseType := c.Query("response_type")
 	state := c.Query("state")
 	scope := c.Query("scope")
+	codeChallenge := c.Query("code_challenge")
+	codeChallengeMethod := c.Query("code_challenge_method")
 
 	if responseType != "code" {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "unsupported_response_type"})
@@ -84,16 +107,18 @@ func authorizeHandler(c *gin.Context) {
 
 	scopes := []string{"read", "write"}
 	if scope != "" {
-		scopes = []string{scope}
+		scopes = strings.Fields(scope)
 	}
 
 	userID := "user123"
 
-	authCode, err := oauth2Server.GenerateAuthorizationCode(
+	authCode, err := oauth2Server.GenerateAuthorizationCodeWithPKCE(
 		clientID,
 		redirectURI,
 		userID,
 		scopes,
+		codeChallenge,
+		codeChallengeMethod,
 	)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
@@ -118,6 +143,10 @@ func tokenHandler(c *gin.Context) {
 		handleAuthorizationCodeGrant(c)
 	case "refresh_token":
 		handleRefreshTokenGrant(c)
+	case "client_credentials":
+		handleClientCredentialsGrant(c)
+	case "password":
+		handlePasswordGrant(c)
 	default:
 		c.JSON(http.StatusBadRequest, gin.H{"error": "unsupported_grant_type"})
 	}
@@ -128,12 +157,14 @@ func handleAuthorizationCodeGrant(c *gin.Context) {
 	clientID := c.PostForm("client_id")
 	clientSecret := c.PostForm("client_secret")
 	redirectURI := c.PostForm("redirect_uri")
+	codeVerifier := c.PostForm("code_verifier")
 
-	accessToken, err := oauth2Server.ExchangeCodeForToken(
+	accessToken, err := oauth2Server.ExchangeCodeForTokenWithPKCE(
 		code,
 		clientID,
 		clientSecret,
 		redirectURI,
+		codeVerifier,
 	)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
@@ -173,6 +204,47 @@ func handleRefreshTokenGrant(c *gin.Context) {
 	})
 }
 
+func handleClientCredentialsGrant(c *gin.Context) {
+	accessToken, err := oauth2Server.ClientCredentialsToken(
+		c.PostForm("client_id"),
+		c.PostForm("client_secret"),
+		strings.Fields(c.PostForm("scope")),
+	)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"access_token": accessToken.Token,
+		"token_type":   accessToken.TokenType,
+		"expires_in":   accessToken.ExpiresIn,
+		"scope":        accessToken.Scopes,
+	})
+}
+
+func handlePasswordGrant(c *gin.Context) {
+	accessToken, err := oauth2Server.PasswordToken(
+		c.PostForm("username"),
+		c.PostForm("password"),
+		c.PostForm("client_id"),
+		c.PostForm("client_secret"),
+		strings.Fields(c.PostForm("scope")),
+	)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"access_token":  accessToken.Token,
+		"token_type":    accessToken.TokenType,
+		"expires_in":    accessToken.ExpiresIn,
+		"refresh_token": accessToken.RefreshToken,
+		"scope":         accessToken.Scopes,
+	})
+}
+
 func userinfoHandler(c *gin.Context) {
 	authHeader := c.GetHeader("Authorization")
 	if authHeader == "" {
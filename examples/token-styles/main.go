@@ -24,6 +24,7 @@ func main() {
 	demoTokenStyle(core.TokenStyleHash, "Hash Style (SHA256)")
 	demoTokenStyle(core.TokenStyleTimestamp, "Timestamp Style")
 	demoTokenStyle(core.TokenStyleTik, "Tik Style (Short ID)")
+	demoTokenStyle(core.TokenStyleEncrypted, "Encrypted Style (AES-GCM)")
 
 	fmt.Println("\n========================================")
 	fmt.Println("✅ All token styles demonstrated!")
@@ -39,7 +40,8 @@ func demoTokenStyle(style core.TokenStyle, name string) {
 		Storage(memory.NewStorage()).
 		TokenStyle(style).
 		Timeout(3600).
-		JwtSecretKey("my-secret-key-123"). // For JWT style | 用于JWT风格
+		JwtSecretKey("my-secret-key-123").     // For JWT style | 用于JWT风格
+		TokenEncryptKey("my-encrypt-key-123"). // For encrypted style | 用于加密风格
 		IsPrintBanner(false).
 		Build()
 
@@ -2,7 +2,6 @@ package chi
 
 import (
 	"context"
-	"io"
 	"net/http"
 
 	"github.com/click33/sa-token-go/core/adapter"
@@ -122,12 +121,17 @@ func (c *ChiContext) GetQueryAll() map[string][]string {
 
 // GetPostForm implements adapter.RequestContext.
 func (c *ChiContext) GetPostForm(key string) string {
-	return c.r.FormValue(key)
+	// Only urlencoded bodies are buffered, the body stays readable | 仅缓冲urlencoded请求体，请求体保持可读
+	if !adapter.IsFormURLEncoded(c.r.Header.Get("Content-Type")) {
+		return c.r.FormValue(key)
+	}
+	return adapter.PostFormValue(c.r, key)
 }
 
 // GetBody implements adapter.RequestContext.
+// Reads at most adapter.MaxBodySize bytes and restores the body for downstream handlers | 至多读取adapter.MaxBodySize字节，并恢复请求体供后续处理器读取
 func (c *ChiContext) GetBody() ([]byte, error) {
-	return io.ReadAll(c.r.Body)
+	return adapter.ReadBody(c.r)
 }
 
 // GetURL implements adapter.RequestContext.
@@ -196,3 +200,8 @@ func (c *ChiContext) Abort() {
 func (c *ChiContext) IsAborted() bool {
 	return c.aborted
 }
+
+// Context implements adapter.ContextCarrier, storage calls honor its cancellation and deadline | 实现adapter.ContextCarrier，存储调用遵循其取消与截止时间
+func (c *ChiContext) Context() context.Context {
+	return c.ctx
+}
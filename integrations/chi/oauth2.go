@@ -0,0 +1,32 @@
+package chi
+
+import (
+	"net/http"
+
+	"github.com/click33/sa-token-go/core"
+)
+
+// OAuth2Router Route registration subset of chi.Router | chi.Router的路由注册子集
+type OAuth2Router interface {
+	Get(pattern string, handlerFn http.HandlerFunc)
+	Post(pattern string, handlerFn http.HandlerFunc)
+}
+
+// RegisterOAuth2Routes Mounts OAuth2 endpoints at their default paths | 在默认路径挂载OAuth2端点
+// Usage | 用法: RegisterOAuth2Routes(r, NewOAuth2Handler(manager.GetOAuth2Server(), consent))
+func RegisterOAuth2Routes(r OAuth2Router, handler *OAuth2Handler) {
+	authorize := oauth2Endpoint(handler.Authorize)
+	r.Get(core.OAuth2AuthorizePath, authorize)
+	r.Post(core.OAuth2AuthorizePath, authorize)
+	r.Post(core.OAuth2TokenPath, oauth2Endpoint(handler.Token))
+	r.Post(core.OAuth2RevokePath, oauth2Endpoint(handler.Revoke))
+	r.Post(core.OAuth2IntrospectPath, oauth2Endpoint(handler.Introspect))
+	r.Post(core.OAuth2RegisterPath, oauth2Endpoint(handler.Register))
+}
+
+// oauth2Endpoint Adapts a framework-neutral OAuth2 endpoint to net/http | 将框架无关的OAuth2端点适配为net/http处理函数
+func oauth2Endpoint(endpoint func(core.RequestContext) *core.OAuth2Response) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		endpoint(NewChiContext(w, r)).Write(w)
+	}
+}
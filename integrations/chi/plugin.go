@@ -85,12 +85,55 @@ func (p *Plugin) RoleRequired(role string) func(http.Handler) http.Handler {
 	}
 }
 
+// SafeRequired second-level authentication middleware | 二级认证中间件
+func (p *Plugin) SafeRequired(service ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := NewChiContext(w, r)
+			saCtx := core.NewContext(ctx, p.manager)
+
+			if err := saCtx.CheckLogin(); err != nil {
+				writeErrorResponse(w, err)
+				return
+			}
+
+			if !saCtx.IsSafe(service...) {
+				writeErrorResponse(w, core.NewNotSafeError(safeServices(service)[0]))
+				return
+			}
+
+			ctx.Set("satoken", saCtx)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// SignRequired API request signature middleware | API请求签名校验中间件
+func (p *Plugin) SignRequired() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := NewChiContext(w, r)
+
+			if err := p.manager.GetSignManager().VerifyRequest(ctx); err != nil {
+				writeErrorResponse(w, core.NewSignError(err))
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
 // LoginHandler 登录处理器
 func (p *Plugin) LoginHandler(w http.ResponseWriter, r *http.Request) {
 	var req struct {
-		Username string `json:"username"`
-		Password string `json:"password"`
-		Device   string `json:"device"`
+		Username      string `json:"username"`
+		Password      string `json:"password"`
+		Device        string `json:"device"`
+		DeviceID      string `json:"deviceId"`
+		Timeout       int64  `json:"timeout"`
+		ActiveTimeout int64  `json:"activeTimeout"`
+		Remember      *bool  `json:"remember"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -103,7 +146,21 @@ func (p *Plugin) LoginHandler(w http.ResponseWriter, r *http.Request) {
 		device = "default"
 	}
 
-	token, err := p.manager.Login(req.Username, device)
+	// Client input may only shorten configured timeouts | 客户端传入的超时只能缩短全局配置
+	cfg := p.manager.GetConfig()
+	opts := core.NewLoginOptions().
+		SetDevice(device).
+		SetDeviceID(req.DeviceID).
+		SetTimeout(req.Timeout).
+		SetActiveTimeout(req.ActiveTimeout).
+		LimitTimeouts(cfg.Timeout, cfg.ActiveTimeout)
+	if req.Remember != nil {
+		opts.SetRemember(*req.Remember)
+	}
+
+	// Writes token cookie and response header as configured | 按配置写入Token Cookie与响应头
+	saCtx := core.NewContext(NewChiContext(w, r), p.manager)
+	token, err := saCtx.LoginAndSetCookie(req.Username, opts)
 	if err != nil {
 		writeErrorResponse(w, core.NewError(core.CodeServerError, "login failed", err))
 		return
@@ -114,6 +171,26 @@ func (p *Plugin) LoginHandler(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// LogoutHandler 登出处理器
+func (p *Plugin) LogoutHandler(w http.ResponseWriter, r *http.Request) {
+	saCtx := core.NewContext(NewChiContext(w, r), p.manager)
+	loginID, err := saCtx.GetLoginID()
+	if err != nil {
+		writeErrorResponse(w, err)
+		return
+	}
+
+	if err := saCtx.Logout(loginID); err != nil {
+		writeErrorResponse(w, core.NewError(core.CodeServerError, "logout failed", err))
+		return
+	}
+	saCtx.ClearTokenCookie()
+
+	writeSuccessResponse(w, map[string]interface{}{
+		"message": "logout successful",
+	})
+}
+
 // GetSaToken 从请求上下文获取Sa-Token上下文
 func GetSaToken(r *http.Request) (*core.SaTokenContext, bool) {
 	satoken := r.Context().Value("satoken")
@@ -167,9 +244,9 @@ func writeSuccessResponse(w http.ResponseWriter, data interface{}) {
 // getHTTPStatusFromCode converts Sa-Token error code to HTTP status | 将Sa-Token错误码转换为HTTP状态码
 func getHTTPStatusFromCode(code int) int {
 	switch code {
-	case core.CodeNotLogin:
+	case core.CodeNotLogin, core.CodeSignInvalid:
 		return http.StatusUnauthorized
-	case core.CodePermissionDenied:
+	case core.CodePermissionDenied, core.CodeNotSafe:
 		return http.StatusForbidden
 	case core.CodeBadRequest:
 		return http.StatusBadRequest
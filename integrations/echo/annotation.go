@@ -14,7 +14,9 @@ type Annotation struct {
 	CheckRole       []string `json:"checkRole"`
 	CheckPermission []string `json:"checkPermission"`
 	CheckDisable    bool     `json:"checkDisable"`
+	CheckSafe       []string `json:"checkSafe"` // Required safe services, any one passes | 需要的二级认证业务，满足其一即可
 	Ignore          bool     `json:"ignore"`
+	LoginType       string   `json:"loginType"` // Target account realm, empty means global | 目标账号体系，为空表示全局
 }
 
 // GetHandler gets handler with annotations | 获取带注解的处理器
@@ -30,35 +32,50 @@ func GetHandler(handler echo.HandlerFunc, annotations ...*Annotation) echo.Handl
 
 		// Get token from context using configured TokenName | 从上下文获取Token（使用配置的TokenName）
 		ctx := NewEchoContext(c)
-		saCtx := core.NewContext(ctx, stputil.GetManager())
+		mgr := annotationManager(annotations)
+		saCtx := core.NewContext(ctx, mgr)
 		token := saCtx.GetTokenValue()
 		if token == "" {
 			return writeErrorResponse(c, core.NewNotLoginError())
 		}
 
 		// Check login | 检查登录
-		if !stputil.IsLogin(token) {
+		if !mgr.IsLogin(token) {
 			return writeErrorResponse(c, core.NewNotLoginError())
 		}
 
 		// Get login ID | 获取登录ID
-		loginID, err := stputil.GetLoginID(token)
+		loginID, err := mgr.GetLoginID(token)
 		if err != nil {
 			return writeErrorResponse(c, err)
 		}
 
 		// Check if account is disabled | 检查是否被封禁
 		if len(annotations) > 0 && annotations[0].CheckDisable {
-			if stputil.IsDisable(loginID) {
+			if mgr.IsDisable(loginID) {
 				return writeErrorResponse(c, core.NewAccountDisabledError(loginID))
 			}
 		}
 
+		// Check safe authentication | 检查二级认证
+		if len(annotations) > 0 && len(annotations[0].CheckSafe) > 0 {
+			isSafe := false
+			for _, service := range annotations[0].CheckSafe {
+				if mgr.IsSafe(token, strings.TrimSpace(service)) {
+					isSafe = true
+					break
+				}
+			}
+			if !isSafe {
+				return writeErrorResponse(c, core.NewNotSafeError(strings.Join(annotations[0].CheckSafe, ",")))
+			}
+		}
+
 		// Check permission | 检查权限
 		if len(annotations) > 0 && len(annotations[0].CheckPermission) > 0 {
 			hasPermission := false
 			for _, perm := range annotations[0].CheckPermission {
-				if stputil.HasPermission(loginID, strings.TrimSpace(perm)) {
+				if mgr.HasPermissionByToken(token, strings.TrimSpace(perm)) {
 					hasPermission = true
 					break
 				}
@@ -72,7 +89,7 @@ func GetHandler(handler echo.HandlerFunc, annotations ...*Annotation) echo.Handl
 		if len(annotations) > 0 && len(annotations[0].CheckRole) > 0 {
 			hasRole := false
 			for _, role := range annotations[0].CheckRole {
-				if stputil.HasRole(loginID, strings.TrimSpace(role)) {
+				if mgr.HasRoleByToken(token, strings.TrimSpace(role)) {
 					hasRole = true
 					break
 				}
@@ -118,9 +135,32 @@ func CheckDisableMiddleware() echo.MiddlewareFunc {
 	}
 }
 
+// CheckSafeMiddleware decorator for second-level authentication checking | 检查二级认证装饰器
+func CheckSafeMiddleware(services ...string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return GetHandler(next, &Annotation{CheckSafe: safeServices(services)})
+	}
+}
+
 // IgnoreMiddleware decorator to ignore authentication | 忽略认证装饰器
 func IgnoreMiddleware() echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return GetHandler(next, &Annotation{Ignore: true})
 	}
 }
+
+// annotationManager resolves the Manager targeted by annotations | 获取注解指定账号体系的Manager
+func annotationManager(annotations []*Annotation) *core.Manager {
+	if len(annotations) > 0 && annotations[0].LoginType != "" {
+		return stputil.GetManagerByLoginType(annotations[0].LoginType)
+	}
+	return stputil.GetManager()
+}
+
+// safeServices returns the default safe service when none given | 未指定时返回默认二级认证业务
+func safeServices(services []string) []string {
+	if len(services) == 0 {
+		return []string{core.DefaultSafeService}
+	}
+	return services
+}
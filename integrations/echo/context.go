@@ -1,7 +1,7 @@
 package echo
 
 import (
-	"io"
+	"context"
 	"net/http"
 
 	"github.com/click33/sa-token-go/core/adapter"
@@ -106,17 +106,17 @@ func (e *EchoContext) GetQueryAll() map[string][]string {
 
 // GetPostForm implements adapter.RequestContext.
 func (e *EchoContext) GetPostForm(key string) string {
-	return e.c.FormValue(key)
+	// Only urlencoded bodies are buffered, the body stays readable | 仅缓冲urlencoded请求体，请求体保持可读
+	if !adapter.IsFormURLEncoded(e.c.Request().Header.Get("Content-Type")) {
+		return e.c.FormValue(key)
+	}
+	return adapter.PostFormValue(e.c.Request(), key)
 }
 
 // GetBody implements adapter.RequestContext.
+// Reads at most adapter.MaxBodySize bytes and restores the body for downstream handlers | 至多读取adapter.MaxBodySize字节，并恢复请求体供后续处理器读取
 func (e *EchoContext) GetBody() ([]byte, error) {
-	body := e.c.Request().Body
-	if body == nil {
-		return nil, nil
-	}
-	defer body.Close()
-	return io.ReadAll(body)
+	return adapter.ReadBody(e.c.Request())
 }
 
 // GetURL implements adapter.RequestContext.
@@ -185,3 +185,8 @@ func (e *EchoContext) Abort() {
 func (e *EchoContext) IsAborted() bool {
 	return e.aborted
 }
+
+// Context implements adapter.ContextCarrier, storage calls honor its cancellation and deadline | 实现adapter.ContextCarrier，存储调用遵循其取消与截止时间
+func (e *EchoContext) Context() context.Context {
+	return e.c.Request().Context()
+}
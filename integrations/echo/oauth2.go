@@ -0,0 +1,32 @@
+package echo
+
+import (
+	"github.com/click33/sa-token-go/core"
+	"github.com/labstack/echo/v4"
+)
+
+// OAuth2Router Route registration shared by *echo.Echo and *echo.Group | *echo.Echo与*echo.Group共有的路由注册方法
+type OAuth2Router interface {
+	GET(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
+	POST(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
+}
+
+// RegisterOAuth2Routes Mounts OAuth2 endpoints at their default paths | 在默认路径挂载OAuth2端点
+// Usage | 用法: RegisterOAuth2Routes(e, NewOAuth2Handler(manager.GetOAuth2Server(), consent))
+func RegisterOAuth2Routes(r OAuth2Router, handler *OAuth2Handler) {
+	authorize := oauth2Endpoint(handler.Authorize)
+	r.GET(core.OAuth2AuthorizePath, authorize)
+	r.POST(core.OAuth2AuthorizePath, authorize)
+	r.POST(core.OAuth2TokenPath, oauth2Endpoint(handler.Token))
+	r.POST(core.OAuth2RevokePath, oauth2Endpoint(handler.Revoke))
+	r.POST(core.OAuth2IntrospectPath, oauth2Endpoint(handler.Introspect))
+	r.POST(core.OAuth2RegisterPath, oauth2Endpoint(handler.Register))
+}
+
+// oauth2Endpoint Adapts a framework-neutral OAuth2 endpoint to echo | 将框架无关的OAuth2端点适配为echo处理函数
+func oauth2Endpoint(endpoint func(core.RequestContext) *core.OAuth2Response) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		endpoint(NewEchoContext(c)).Write(c.Response())
+		return nil
+	}
+}
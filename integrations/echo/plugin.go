@@ -79,12 +79,52 @@ func (p *Plugin) RoleRequired(role string) echo.MiddlewareFunc {
 	}
 }
 
+// SafeRequired second-level authentication middleware | 二级认证中间件
+func (p *Plugin) SafeRequired(service ...string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			ctx := NewEchoContext(c)
+			saCtx := core.NewContext(ctx, p.manager)
+
+			if err := saCtx.CheckLogin(); err != nil {
+				return writeErrorResponse(c, err)
+			}
+
+			if !saCtx.IsSafe(service...) {
+				return writeErrorResponse(c, core.NewNotSafeError(safeServices(service)[0]))
+			}
+
+			c.Set("satoken", saCtx)
+			return next(c)
+		}
+	}
+}
+
+// SignRequired API request signature middleware | API请求签名校验中间件
+func (p *Plugin) SignRequired() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			ctx := NewEchoContext(c)
+
+			if err := p.manager.GetSignManager().VerifyRequest(ctx); err != nil {
+				return writeErrorResponse(c, core.NewSignError(err))
+			}
+
+			return next(c)
+		}
+	}
+}
+
 // LoginHandler 登录处理器
 func (p *Plugin) LoginHandler(c echo.Context) error {
 	var req struct {
-		Username string `json:"username"`
-		Password string `json:"password"`
-		Device   string `json:"device"`
+		Username      string `json:"username"`
+		Password      string `json:"password"`
+		Device        string `json:"device"`
+		DeviceID      string `json:"deviceId"`
+		Timeout       int64  `json:"timeout"`
+		ActiveTimeout int64  `json:"activeTimeout"`
+		Remember      *bool  `json:"remember"`
 	}
 
 	if err := c.Bind(&req); err != nil {
@@ -96,7 +136,21 @@ func (p *Plugin) LoginHandler(c echo.Context) error {
 		device = "default"
 	}
 
-	token, err := p.manager.Login(req.Username, device)
+	// Client input may only shorten configured timeouts | 客户端传入的超时只能缩短全局配置
+	cfg := p.manager.GetConfig()
+	opts := core.NewLoginOptions().
+		SetDevice(device).
+		SetDeviceID(req.DeviceID).
+		SetTimeout(req.Timeout).
+		SetActiveTimeout(req.ActiveTimeout).
+		LimitTimeouts(cfg.Timeout, cfg.ActiveTimeout)
+	if req.Remember != nil {
+		opts.SetRemember(*req.Remember)
+	}
+
+	// Writes token cookie and response header as configured | 按配置写入Token Cookie与响应头
+	saCtx := core.NewContext(NewEchoContext(c), p.manager)
+	token, err := saCtx.LoginAndSetCookie(req.Username, opts)
 	if err != nil {
 		return writeErrorResponse(c, core.NewError(core.CodeServerError, "login failed", err))
 	}
@@ -106,6 +160,24 @@ func (p *Plugin) LoginHandler(c echo.Context) error {
 	})
 }
 
+// LogoutHandler 登出处理器
+func (p *Plugin) LogoutHandler(c echo.Context) error {
+	saCtx := core.NewContext(NewEchoContext(c), p.manager)
+	loginID, err := saCtx.GetLoginID()
+	if err != nil {
+		return writeErrorResponse(c, err)
+	}
+
+	if err := saCtx.Logout(loginID); err != nil {
+		return writeErrorResponse(c, core.NewError(core.CodeServerError, "logout failed", err))
+	}
+	saCtx.ClearTokenCookie()
+
+	return writeSuccessResponse(c, map[string]interface{}{
+		"message": "logout successful",
+	})
+}
+
 // GetSaToken 从Echo上下文获取Sa-Token上下文
 func GetSaToken(c echo.Context) (*core.SaTokenContext, bool) {
 	satoken := c.Get("satoken")
@@ -156,9 +228,9 @@ func writeSuccessResponse(c echo.Context, data interface{}) error {
 // getHTTPStatusFromCode converts Sa-Token error code to HTTP status | 将Sa-Token错误码转换为HTTP状态码
 func getHTTPStatusFromCode(code int) int {
 	switch code {
-	case core.CodeNotLogin:
+	case core.CodeNotLogin, core.CodeSignInvalid:
 		return http.StatusUnauthorized
-	case core.CodePermissionDenied:
+	case core.CodePermissionDenied, core.CodeNotSafe:
 		return http.StatusForbidden
 	case core.CodeBadRequest:
 		return http.StatusBadRequest
@@ -1,6 +1,7 @@
 package fiber
 
 import (
+	"context"
 	"github.com/click33/sa-token-go/core/adapter"
 	"github.com/gofiber/fiber/v2"
 	"time"
@@ -147,6 +148,9 @@ func (f *FiberContext) SetCookieWithOptions(options *adapter.CookieOptions) {
 	
 	if options.MaxAge > 0 {
 		cookie.Expires = time.Now().Add(time.Duration(options.MaxAge) * time.Second)
+	} else if options.MaxAge < 0 {
+		// fasthttp ignores negative max-age, expire in the past to delete
+		cookie.Expires = time.Unix(0, 0)
 	}
 	
 	f.c.Cookie(cookie)
@@ -182,3 +186,8 @@ func (f *FiberContext) Abort() {
 func (f *FiberContext) IsAborted() bool {
 	return f.aborted
 }
+
+// Context implements adapter.ContextCarrier, storage calls honor its cancellation and deadline | 实现adapter.ContextCarrier，存储调用遵循其取消与截止时间
+func (f *FiberContext) Context() context.Context {
+	return f.c.UserContext()
+}
@@ -1,6 +1,8 @@
 package fiber
 
 import (
+	"context"
+	"database/sql"
 	"time"
 
 	"github.com/click33/sa-token-go/core"
@@ -14,6 +16,8 @@ type (
 	Config       = core.Config
 	CookieConfig = core.CookieConfig
 	TokenStyle   = core.TokenStyle
+	JwtMode      = core.JwtMode
+	TokenSource  = core.TokenSource
 )
 
 // Token style constants | Token风格常量
@@ -27,18 +31,50 @@ const (
 	TokenStyleHash      = core.TokenStyleHash
 	TokenStyleTimestamp = core.TokenStyleTimestamp
 	TokenStyleTik       = core.TokenStyleTik
+	TokenStyleEncrypted = core.TokenStyleEncrypted
+)
+
+// JWT mode constants | JWT模式常量
+const (
+	JwtModeDefault   = core.JwtModeDefault
+	JwtModeSimple    = core.JwtModeSimple
+	JwtModeMixed     = core.JwtModeMixed
+	JwtModeStateless = core.JwtModeStateless
+)
+
+// Token source constants | Token来源常量
+const (
+	TokenSourceHeader        = core.TokenSourceHeader
+	TokenSourceAuthorization = core.TokenSourceAuthorization
+	TokenSourceCookie        = core.TokenSourceCookie
+	TokenSourceQuery         = core.TokenSourceQuery
+	TokenSourceBody          = core.TokenSourceBody
+	TokenSourceForm          = core.TokenSourceForm
+	TokenSourceMetadata      = core.TokenSourceMetadata
+	TokenSourceWebSocket     = core.TokenSourceWebSocket
 )
 
 // Core types | 核心类型
 type (
 	Manager             = core.Manager
 	TokenInfo           = core.TokenInfo
+	TokenSign           = core.TokenSign
+	LoginOptions        = core.LoginOptions
 	Session             = core.Session
 	TokenGenerator      = core.TokenGenerator
+	JwtKey              = core.JwtKey
+	JwtKeySet           = core.JwtKeySet
+	JWKSDocument        = core.JWKS
+	EncryptKey          = core.EncryptKey
+	EncryptKeySet       = core.EncryptKeySet
 	SaTokenContext      = core.SaTokenContext
 	Builder             = core.Builder
 	NonceManager        = core.NonceManager
 	RefreshTokenInfo    = core.RefreshTokenInfo
+	TempTokenInfo       = core.TempTokenInfo
+	SignManager         = core.SignManager
+	RoleManager         = core.RoleManager
+	RBACRole            = core.RBACRole
 	RefreshTokenManager = core.RefreshTokenManager
 	OAuth2Server        = core.OAuth2Server
 	OAuth2Client        = core.OAuth2Client
@@ -46,12 +82,66 @@ type (
 	OAuth2GrantType     = core.OAuth2GrantType
 )
 
+// JWT claims types | JWT声明类型
+type (
+	JwtClaimsConfig = core.JwtClaimsConfig
+	JwtClaimsHook   = core.JwtClaimsHook
+	TokenClaims     = core.TokenClaims
+)
+
+// Custom token generator types | 自定义Token生成器类型
+type (
+	CustomTokenGenerator = core.CustomTokenGenerator
+	TokenGeneratorFunc   = core.TokenGeneratorFunc
+)
+
+// OAuth2 password grant types | OAuth2密码模式类型
+type (
+	OAuth2UserAuthenticator     = core.OAuth2UserAuthenticator
+	OAuth2UserAuthenticatorFunc = core.OAuth2UserAuthenticatorFunc
+)
+
+// OAuth2 HTTP endpoint types | OAuth2 HTTP端点类型
+type (
+	OAuth2Handler          = core.OAuth2Handler
+	OAuth2Response         = core.OAuth2Response
+	OAuth2AuthorizeRequest = core.OAuth2AuthorizeRequest
+	OAuth2ConsentResult    = core.OAuth2ConsentResult
+	OAuth2ConsentHook      = core.OAuth2ConsentHook
+)
+
+// OAuth2 client registry types | OAuth2客户端注册表类型
+type (
+	OAuth2ClientStore            = core.OAuth2ClientStore
+	OAuth2MemoryClientStore      = core.OAuth2MemoryClientStore
+	OAuth2StorageClientStore     = core.OAuth2StorageClientStore
+	OAuth2SQLClientStore         = core.OAuth2SQLClientStore
+	OAuth2SecretHasher           = core.OAuth2SecretHasher
+	OAuth2ClientMetadata         = core.OAuth2ClientMetadata
+	OAuth2ClientInformation      = core.OAuth2ClientInformation
+	OAuth2RegistrationPolicy     = core.OAuth2RegistrationPolicy
+	OAuth2RegistrationAuthorizer = core.OAuth2RegistrationAuthorizer
+)
+
+// Permission provider types | 权限数据源类型
+type (
+	PermissionProvider     = core.PermissionProvider
+	PermissionProviderFunc = core.PermissionProviderFunc
+)
+
 // Adapter interfaces | 适配器接口
 type (
 	Storage        = core.Storage
 	RequestContext = core.RequestContext
 )
 
+// Context-aware storage types | 上下文感知存储类型
+type (
+	StorageV2         = core.StorageV2
+	StorageV2Provider = core.StorageV2Provider
+	ContextCarrier    = core.ContextCarrier
+)
+
 // Event related types | 事件相关类型
 type (
 	EventListener  = core.EventListener
@@ -67,6 +157,7 @@ const (
 	EventLogin           = core.EventLogin
 	EventLogout          = core.EventLogout
 	EventKickout         = core.EventKickout
+	EventReplaced        = core.EventReplaced
 	EventDisable         = core.EventDisable
 	EventUntie           = core.EventUntie
 	EventRenew           = core.EventRenew
@@ -85,6 +176,21 @@ const (
 	GrantTypePassword          = core.GrantTypePassword
 )
 
+// OAuth2 PKCE code challenge methods | OAuth2 PKCE挑战值计算方式
+const (
+	CodeChallengeMethodS256  = core.CodeChallengeMethodS256
+	CodeChallengeMethodPlain = core.CodeChallengeMethodPlain
+)
+
+// OAuth2 endpoint paths | OAuth2端点路径
+const (
+	OAuth2AuthorizePath  = core.OAuth2AuthorizePath
+	OAuth2TokenPath      = core.OAuth2TokenPath
+	OAuth2RevokePath     = core.OAuth2RevokePath
+	OAuth2IntrospectPath = core.OAuth2IntrospectPath
+	OAuth2RegisterPath   = core.OAuth2RegisterPath
+)
+
 // Utility functions | 工具函数
 var (
 	RandomString   = core.RandomString
@@ -110,6 +216,26 @@ func NewManager(storage Storage, cfg *Config) *Manager {
 	return core.NewManager(storage, cfg)
 }
 
+// AsStorageV2 adapts Storage to context-aware StorageV2 | 将Storage适配为上下文感知的StorageV2
+func AsStorageV2(storage Storage) StorageV2 {
+	return core.AsStorageV2(storage)
+}
+
+// AsStorage adapts StorageV2 to Storage | 将StorageV2适配为Storage
+func AsStorage(storage StorageV2) Storage {
+	return core.AsStorage(storage)
+}
+
+// BindStorageContext binds StorageV2 to ctx as Storage | 将StorageV2绑定到ctx并作为Storage使用
+func BindStorageContext(storage StorageV2, ctx context.Context) Storage {
+	return core.BindStorageContext(storage, ctx)
+}
+
+// NewLoginOptions creates login options with remember-me enabled | 创建登录参数（默认记住我）
+func NewLoginOptions() *LoginOptions {
+	return core.NewLoginOptions()
+}
+
 // NewContext creates a new Sa-Token context | 创建新的Sa-Token上下文
 func NewContext(ctx RequestContext, mgr *Manager) *SaTokenContext {
 	return core.NewContext(ctx, mgr)
@@ -130,6 +256,26 @@ func NewTokenGenerator(cfg *Config) *TokenGenerator {
 	return core.NewTokenGenerator(cfg)
 }
 
+// NewJwtKeyFromPEM creates JWT key from PEM encoded private or public key | 从PEM编码的私钥或公钥创建JWT密钥
+func NewJwtKeyFromPEM(kid string, pemData []byte) (*JwtKey, error) {
+	return core.NewJwtKeyFromPEM(kid, pemData)
+}
+
+// RegisterTokenStyle registers named custom token style | 注册命名的自定义Token风格
+func RegisterTokenStyle(style TokenStyle, gen CustomTokenGenerator) error {
+	return core.RegisterTokenStyle(style, gen)
+}
+
+// NewEncryptKey creates AES-GCM token encryption key from 16, 24 or 32 raw bytes | 由16/24/32字节原始密钥创建AES-GCM Token加密密钥
+func NewEncryptKey(kid string, key []byte) (*EncryptKey, error) {
+	return core.NewEncryptKey(kid, key)
+}
+
+// NewEncryptKeyFromSecret creates AES-256-GCM token encryption key derived from secret | 由密钥字符串派生AES-256-GCM Token加密密钥
+func NewEncryptKeyFromSecret(kid, secret string) (*EncryptKey, error) {
+	return core.NewEncryptKeyFromSecret(kid, secret)
+}
+
 // NewEventManager creates a new event manager | 创建新的事件管理器
 func NewEventManager() *EventManager {
 	return core.NewEventManager()
@@ -155,6 +301,31 @@ func NewOAuth2Server(storage Storage, prefix string) *OAuth2Server {
 	return core.NewOAuth2Server(storage, prefix)
 }
 
+// S256Challenge computes PKCE S256 code_challenge of verifier | 计算PKCE校验码的S256挑战值
+func S256Challenge(verifier string) string {
+	return core.S256Challenge(verifier)
+}
+
+// NewOAuth2Handler creates framework-neutral OAuth2 HTTP endpoints | 创建框架无关的OAuth2 HTTP端点
+func NewOAuth2Handler(server *OAuth2Server, consent OAuth2ConsentHook) *OAuth2Handler {
+	return core.NewOAuth2Handler(server, consent)
+}
+
+// NewOAuth2MemoryClientStore creates per-process OAuth2 client store | 创建进程内OAuth2客户端存储
+func NewOAuth2MemoryClientStore() *OAuth2MemoryClientStore {
+	return core.NewOAuth2MemoryClientStore()
+}
+
+// NewOAuth2StorageClientStore creates OAuth2 client store on storage | 创建基于存储的OAuth2客户端存储
+func NewOAuth2StorageClientStore(storage Storage, prefix string) *OAuth2StorageClientStore {
+	return core.NewOAuth2StorageClientStore(storage, prefix)
+}
+
+// NewOAuth2SQLClientStore creates OAuth2 client store on a database/sql table | 创建基于database/sql表的OAuth2客户端存储
+func NewOAuth2SQLClientStore(db *sql.DB, table string) *OAuth2SQLClientStore {
+	return core.NewOAuth2SQLClientStore(db, table)
+}
+
 // ============ Global StpUtil functions | 全局StpUtil函数 ============
 
 // SetManager sets the global Manager (must be called first) | 设置全局Manager（必须先调用此方法）
@@ -167,6 +338,21 @@ func GetManager() *Manager {
 	return stputil.GetManager()
 }
 
+// PutManager registers a Manager for its login type | 按账号体系注册Manager
+func PutManager(mgr *Manager) {
+	stputil.PutManager(mgr)
+}
+
+// GetManagerByLoginType gets the Manager of specified login type | 获取指定账号体系的Manager
+func GetManagerByLoginType(loginType string) *Manager {
+	return stputil.GetManagerByLoginType(loginType)
+}
+
+// LookupManager gets the Manager of specified login type without panicking | 获取指定账号体系的Manager（不会panic）
+func LookupManager(loginType string) (*Manager, bool) {
+	return stputil.LookupManager(loginType)
+}
+
 // ============ Authentication | 登录认证 ============
 
 // Login performs user login | 用户登录
@@ -174,6 +360,11 @@ func Login(loginID interface{}, device ...string) (string, error) {
 	return stputil.Login(loginID, device...)
 }
 
+// LoginWithOptions performs user login with parameters | 使用登录参数登录
+func LoginWithOptions(loginID interface{}, opts *LoginOptions) (string, error) {
+	return stputil.LoginWithOptions(loginID, opts)
+}
+
 // LoginByToken performs login with specified token | 使用指定Token登录
 func LoginByToken(loginID interface{}, tokenValue string, device ...string) error {
 	return stputil.LoginByToken(loginID, tokenValue, device...)
@@ -219,6 +410,11 @@ func GetTokenInfo(tokenValue string) (*TokenInfo, error) {
 	return stputil.GetTokenInfo(tokenValue)
 }
 
+// GetTokenExtra gets extra login data of token | 获取Token的登录扩展数据
+func GetTokenExtra(tokenValue, key string) (any, bool) {
+	return stputil.GetTokenExtra(tokenValue, key)
+}
+
 // ============ Kickout | 踢人下线 ============
 
 // Kickout kicks out a user session | 踢人下线
@@ -253,6 +449,33 @@ func Untie(loginID interface{}) error {
 	return stputil.Untie(loginID)
 }
 
+// ============ Safe Authentication | 二级认证 ============
+
+// OpenSafe opens a time-boxed safe window for token | 为Token开启限时二级认证
+func OpenSafe(tokenValue string, safeTime int64, service ...string) error {
+	return stputil.OpenSafe(tokenValue, safeTime, service...)
+}
+
+// IsSafe checks if token is within safe window | 检查Token是否处于二级认证有效期内
+func IsSafe(tokenValue string, service ...string) bool {
+	return stputil.IsSafe(tokenValue, service...)
+}
+
+// CheckSafeByToken checks safe window (returns error if not opened) | 检查Token的二级认证（未通过返回错误）
+func CheckSafeByToken(tokenValue string, service ...string) error {
+	return stputil.CheckSafe(tokenValue, service...)
+}
+
+// GetSafeTime gets remaining safe time in seconds | 获取二级认证剩余有效时间（秒）
+func GetSafeTime(tokenValue string, service ...string) int64 {
+	return stputil.GetSafeTime(tokenValue, service...)
+}
+
+// CloseSafe closes safe window of token | 关闭Token的二级认证
+func CloseSafe(tokenValue string, service ...string) error {
+	return stputil.CloseSafe(tokenValue, service...)
+}
+
 // ============ Permission Check | 权限验证 ============
 
 // CheckPermission checks if the account has specified permission | 检查账号是否拥有指定权限
@@ -307,6 +530,11 @@ func GetRoleListByToken(tokenValue string) ([]string, error) {
 	return stputil.GetRoleList(tokenValue)
 }
 
+// ClearPermissionCache invalidates cached permissions and roles of a login ID | 使账号的权限与角色缓存失效
+func ClearPermissionCache(loginID interface{}) error {
+	return stputil.ClearPermissionCache(loginID)
+}
+
 // ============ Session Management | Session管理 ============
 
 // GetSession gets the session for a login ID | 获取登录ID的Session
@@ -355,6 +583,86 @@ func RevokeRefreshToken(refreshToken string) error {
 	return stputil.RevokeRefreshToken(refreshToken)
 }
 
+// CreateTempToken creates a one-shot temp token bound to service | 创建绑定业务的临时Token
+func CreateTempToken(service string, value any, timeout int64) (string, error) {
+	return stputil.CreateTempToken(service, value, timeout)
+}
+
+// ParseTempToken parses temp token without consuming it | 解析临时Token（不消费）
+func ParseTempToken(service, tempToken string) (*TempTokenInfo, error) {
+	return stputil.ParseTempToken(service, tempToken)
+}
+
+// ConsumeTempToken parses and deletes temp token (one-time use) | 解析并删除临时Token（一次性使用）
+func ConsumeTempToken(service, tempToken string) (*TempTokenInfo, error) {
+	return stputil.ConsumeTempToken(service, tempToken)
+}
+
+// GetTempTokenTimeout gets remaining temp token TTL in seconds | 获取临时Token剩余有效时间（秒）
+func GetTempTokenTimeout(tempToken string) int64 {
+	return stputil.GetTempTokenTimeout(tempToken)
+}
+
+// DeleteTempToken deletes temp token | 删除临时Token
+func DeleteTempToken(tempToken string) error {
+	return stputil.DeleteTempToken(tempToken)
+}
+
+// GetSignManager gets the API sign manager | 获取API签名管理器
+func GetSignManager() *SignManager {
+	return stputil.GetSignManager()
+}
+
+// GetTokenClaims gets typed claims of a logged-in JWT token | 获取已登录JWT Token的类型化声明
+func GetTokenClaims(tokenValue string) (*TokenClaims, error) {
+	return stputil.GetTokenClaims(tokenValue)
+}
+
+// RevokeToken adds a JWT to the revocation list until it expires | 将JWT加入吊销列表直至其过期
+func RevokeToken(tokenValue string) error {
+	return stputil.RevokeToken(tokenValue)
+}
+
+// RevokeTokensBefore revokes tokens of an account issued up to t | 吊销账号在t及之前签发的Token
+func RevokeTokensBefore(loginID interface{}, t time.Time) error {
+	return stputil.RevokeTokensBefore(loginID, t)
+}
+
+// RevokeAllTokens revokes every token issued to an account so far | 吊销账号目前为止签发的所有Token
+func RevokeAllTokens(loginID interface{}) error {
+	return stputil.RevokeAllTokens(loginID)
+}
+
+// IsTokenRevoked checks if a JWT has been revoked | 检查JWT是否已吊销
+func IsTokenRevoked(tokenValue string) bool {
+	return stputil.IsTokenRevoked(tokenValue)
+}
+
+// GetJwtKeySet gets the JWT signing and verification keys | 获取JWT签名与验签密钥集
+func GetJwtKeySet() *JwtKeySet {
+	return stputil.GetJwtKeySet()
+}
+
+// GetEncryptKeySet gets the encrypted token keys | 获取加密Token密钥集
+func GetEncryptKeySet() *EncryptKeySet {
+	return stputil.GetEncryptKeySet()
+}
+
+// DecryptToken reads token info from an encrypted token without storage lookup | 不查询存储，直接从加密Token读取Token信息
+func DecryptToken(tokenValue string) (*TokenInfo, error) {
+	return stputil.DecryptToken(tokenValue)
+}
+
+// JWKS gets the JWKS document of asymmetric JWT keys | 获取非对称JWT密钥的JWKS文档
+func JWKS() *JWKSDocument {
+	return stputil.JWKS()
+}
+
+// GetRoleManager gets the RBAC role manager | 获取RBAC角色管理器
+func GetRoleManager() *RoleManager {
+	return stputil.GetRoleManager()
+}
+
 // GetOAuth2Server gets the OAuth2 server instance | 获取OAuth2服务器实例
 func GetOAuth2Server() *OAuth2Server {
 	return stputil.GetOAuth2Server()
@@ -0,0 +1,29 @@
+package fiber
+
+import (
+	"github.com/click33/sa-token-go/core"
+	"github.com/gofiber/fiber/v2"
+)
+
+// RegisterOAuth2Routes Mounts OAuth2 endpoints at their default paths | 在默认路径挂载OAuth2端点
+// Usage | 用法: RegisterOAuth2Routes(app, NewOAuth2Handler(manager.GetOAuth2Server(), consent))
+func RegisterOAuth2Routes(r fiber.Router, handler *OAuth2Handler) {
+	authorize := oauth2Endpoint(handler.Authorize)
+	r.Get(core.OAuth2AuthorizePath, authorize)
+	r.Post(core.OAuth2AuthorizePath, authorize)
+	r.Post(core.OAuth2TokenPath, oauth2Endpoint(handler.Token))
+	r.Post(core.OAuth2RevokePath, oauth2Endpoint(handler.Revoke))
+	r.Post(core.OAuth2IntrospectPath, oauth2Endpoint(handler.Introspect))
+	r.Post(core.OAuth2RegisterPath, oauth2Endpoint(handler.Register))
+}
+
+// oauth2Endpoint Adapts a framework-neutral OAuth2 endpoint to fiber | 将框架无关的OAuth2端点适配为fiber处理函数
+func oauth2Endpoint(endpoint func(core.RequestContext) *core.OAuth2Response) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		resp := endpoint(NewFiberContext(c))
+		for key, value := range resp.Header {
+			c.Set(key, value)
+		}
+		return c.Status(resp.Status).Send(resp.Body)
+	}
+}
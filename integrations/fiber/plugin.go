@@ -72,12 +72,48 @@ func (p *Plugin) RoleRequired(role string) fiber.Handler {
 	}
 }
 
+// SafeRequired second-level authentication middleware | 二级认证中间件
+func (p *Plugin) SafeRequired(service ...string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ctx := NewFiberContext(c)
+		saCtx := core.NewContext(ctx, p.manager)
+
+		if err := saCtx.CheckLogin(); err != nil {
+			return writeErrorResponse(c, err)
+		}
+
+		if !saCtx.IsSafe(service...) {
+			return writeErrorResponse(c, core.NewNotSafeError(safeServices(service)[0]))
+		}
+
+		c.Locals("satoken", saCtx)
+		return c.Next()
+	}
+}
+
+// SignRequired API request signature middleware | API请求签名校验中间件
+func (p *Plugin) SignRequired() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ctx := NewFiberContext(c)
+
+		if err := p.manager.GetSignManager().VerifyRequest(ctx); err != nil {
+			return writeErrorResponse(c, core.NewSignError(err))
+		}
+
+		return c.Next()
+	}
+}
+
 // LoginHandler 登录处理器
 func (p *Plugin) LoginHandler(c *fiber.Ctx) error {
 	var req struct {
-		Username string `json:"username"`
-		Password string `json:"password"`
-		Device   string `json:"device"`
+		Username      string `json:"username"`
+		Password      string `json:"password"`
+		Device        string `json:"device"`
+		DeviceID      string `json:"deviceId"`
+		Timeout       int64  `json:"timeout"`
+		ActiveTimeout int64  `json:"activeTimeout"`
+		Remember      *bool  `json:"remember"`
 	}
 
 	if err := c.BodyParser(&req); err != nil {
@@ -89,7 +125,21 @@ func (p *Plugin) LoginHandler(c *fiber.Ctx) error {
 		device = "default"
 	}
 
-	token, err := p.manager.Login(req.Username, device)
+	// Client input may only shorten configured timeouts | 客户端传入的超时只能缩短全局配置
+	cfg := p.manager.GetConfig()
+	opts := core.NewLoginOptions().
+		SetDevice(device).
+		SetDeviceID(req.DeviceID).
+		SetTimeout(req.Timeout).
+		SetActiveTimeout(req.ActiveTimeout).
+		LimitTimeouts(cfg.Timeout, cfg.ActiveTimeout)
+	if req.Remember != nil {
+		opts.SetRemember(*req.Remember)
+	}
+
+	// Writes token cookie and response header as configured | 按配置写入Token Cookie与响应头
+	saCtx := core.NewContext(NewFiberContext(c), p.manager)
+	token, err := saCtx.LoginAndSetCookie(req.Username, opts)
 	if err != nil {
 		return writeErrorResponse(c, core.NewError(core.CodeServerError, "login failed", err))
 	}
@@ -99,6 +149,24 @@ func (p *Plugin) LoginHandler(c *fiber.Ctx) error {
 	})
 }
 
+// LogoutHandler 登出处理器
+func (p *Plugin) LogoutHandler(c *fiber.Ctx) error {
+	saCtx := core.NewContext(NewFiberContext(c), p.manager)
+	loginID, err := saCtx.GetLoginID()
+	if err != nil {
+		return writeErrorResponse(c, err)
+	}
+
+	if err := saCtx.Logout(loginID); err != nil {
+		return writeErrorResponse(c, core.NewError(core.CodeServerError, "logout failed", err))
+	}
+	saCtx.ClearTokenCookie()
+
+	return writeSuccessResponse(c, fiber.Map{
+		"message": "logout successful",
+	})
+}
+
 // GetSaToken 从Fiber上下文获取Sa-Token上下文
 func GetSaToken(c *fiber.Ctx) (*core.SaTokenContext, bool) {
 	satoken := c.Locals("satoken")
@@ -149,9 +217,9 @@ func writeSuccessResponse(c *fiber.Ctx, data interface{}) error {
 // getHTTPStatusFromCode converts Sa-Token error code to HTTP status | 将Sa-Token错误码转换为HTTP状态码
 func getHTTPStatusFromCode(code int) int {
 	switch code {
-	case core.CodeNotLogin:
+	case core.CodeNotLogin, core.CodeSignInvalid:
 		return fiber.StatusUnauthorized
-	case core.CodePermissionDenied:
+	case core.CodePermissionDenied, core.CodeNotSafe:
 		return fiber.StatusForbidden
 	case core.CodeBadRequest:
 		return fiber.StatusBadRequest
@@ -14,7 +14,9 @@ type Annotation struct {
 	CheckRole       []string `json:"checkRole"`
 	CheckPermission []string `json:"checkPermission"`
 	CheckDisable    bool     `json:"checkDisable"`
+	CheckSafe       []string `json:"checkSafe"` // Required safe services, any one passes | 需要的二级认证业务，满足其一即可
 	Ignore          bool     `json:"ignore"`
+	LoginType       string   `json:"loginType"` // Target account realm, empty means global | 目标账号体系，为空表示全局
 }
 
 // GetHandler gets handler with annotations | 获取带注解的处理器
@@ -32,7 +34,8 @@ func GetHandler(handler ghttp.HandlerFunc, annotations ...*Annotation) ghttp.Han
 
 		// Get token from context using configured TokenName | 从上下文获取Token（使用配置的TokenName）
 		ctx := NewGFContext(r)
-		saCtx := core.NewContext(ctx, stputil.GetManager())
+		mgr := annotationManager(annotations)
+		saCtx := core.NewContext(ctx, mgr)
 		token := saCtx.GetTokenValue()
 		if token == "" {
 			writeErrorResponse(r, core.NewNotLoginError())
@@ -40,13 +43,13 @@ func GetHandler(handler ghttp.HandlerFunc, annotations ...*Annotation) ghttp.Han
 		}
 
 		// Check login | 检查登录
-		if !stputil.IsLogin(token) {
+		if !mgr.IsLogin(token) {
 			writeErrorResponse(r, core.NewNotLoginError())
 			return
 		}
 
 		// Get login ID | 获取登录ID
-		loginID, err := stputil.GetLoginID(token)
+		loginID, err := mgr.GetLoginID(token)
 		if err != nil {
 			writeErrorResponse(r, err)
 			return
@@ -54,17 +57,32 @@ func GetHandler(handler ghttp.HandlerFunc, annotations ...*Annotation) ghttp.Han
 
 		// Check if account is disabled | 检查是否被封禁
 		if len(annotations) > 0 && annotations[0].CheckDisable {
-			if stputil.IsDisable(loginID) {
+			if mgr.IsDisable(loginID) {
 				writeErrorResponse(r, core.NewAccountDisabledError(loginID))
 				return
 			}
 		}
 
+		// Check safe authentication | 检查二级认证
+		if len(annotations) > 0 && len(annotations[0].CheckSafe) > 0 {
+			isSafe := false
+			for _, service := range annotations[0].CheckSafe {
+				if mgr.IsSafe(token, strings.TrimSpace(service)) {
+					isSafe = true
+					break
+				}
+			}
+			if !isSafe {
+				writeErrorResponse(r, core.NewNotSafeError(strings.Join(annotations[0].CheckSafe, ",")))
+				return
+			}
+		}
+
 		// Check permission | 检查权限
 		if len(annotations) > 0 && len(annotations[0].CheckPermission) > 0 {
 			hasPermission := false
 			for _, perm := range annotations[0].CheckPermission {
-				if stputil.HasPermission(loginID, strings.TrimSpace(perm)) {
+				if mgr.HasPermissionByToken(token, strings.TrimSpace(perm)) {
 					hasPermission = true
 					break
 				}
@@ -79,7 +97,7 @@ func GetHandler(handler ghttp.HandlerFunc, annotations ...*Annotation) ghttp.Han
 		if len(annotations) > 0 && len(annotations[0].CheckRole) > 0 {
 			hasRole := false
 			for _, role := range annotations[0].CheckRole {
-				if stputil.HasRole(loginID, strings.TrimSpace(role)) {
+				if mgr.HasRoleByToken(token, strings.TrimSpace(role)) {
 					hasRole = true
 					break
 				}
@@ -119,7 +137,28 @@ func CheckDisableMiddleware() ghttp.HandlerFunc {
 	return GetHandler(nil, &Annotation{CheckDisable: true})
 }
 
+// CheckSafeMiddleware decorator for second-level authentication checking | 检查二级认证装饰器
+func CheckSafeMiddleware(services ...string) ghttp.HandlerFunc {
+	return GetHandler(nil, &Annotation{CheckSafe: safeServices(services)})
+}
+
 // IgnoreMiddleware decorator to ignore authentication | 忽略认证装饰器
 func IgnoreMiddleware() ghttp.HandlerFunc {
 	return GetHandler(nil, &Annotation{Ignore: true})
 }
+
+// annotationManager resolves the Manager targeted by annotations | 获取注解指定账号体系的Manager
+func annotationManager(annotations []*Annotation) *core.Manager {
+	if len(annotations) > 0 && annotations[0].LoginType != "" {
+		return stputil.GetManagerByLoginType(annotations[0].LoginType)
+	}
+	return stputil.GetManager()
+}
+
+// safeServices returns the default safe service when none given | 未指定时返回默认二级认证业务
+func safeServices(services []string) []string {
+	if len(services) == 0 {
+		return []string{core.DefaultSafeService}
+	}
+	return services
+}
@@ -1,6 +1,7 @@
 package gf
 
 import (
+	"context"
 	"net/http"
 
 	"github.com/click33/sa-token-go/core/adapter"
@@ -161,3 +162,8 @@ func (g *GFContext) Abort() {
 func (g *GFContext) IsAborted() bool {
 	return g.aborted
 }
+
+// Context implements adapter.ContextCarrier, storage calls honor its cancellation and deadline | 实现adapter.ContextCarrier，存储调用遵循其取消与截止时间
+func (g *GFContext) Context() context.Context {
+	return g.c.Context()
+}
@@ -0,0 +1,25 @@
+package gf
+
+import (
+	"github.com/click33/sa-token-go/core"
+	"github.com/gogf/gf/v2/net/ghttp"
+)
+
+// RegisterOAuth2Routes Mounts OAuth2 endpoints at their default paths | 在默认路径挂载OAuth2端点
+// Usage | 用法: s.Group("/", func(group *ghttp.RouterGroup) { RegisterOAuth2Routes(group, handler) })
+func RegisterOAuth2Routes(group *ghttp.RouterGroup, handler *OAuth2Handler) {
+	authorize := oauth2Endpoint(handler.Authorize)
+	group.GET(core.OAuth2AuthorizePath, authorize)
+	group.POST(core.OAuth2AuthorizePath, authorize)
+	group.POST(core.OAuth2TokenPath, oauth2Endpoint(handler.Token))
+	group.POST(core.OAuth2RevokePath, oauth2Endpoint(handler.Revoke))
+	group.POST(core.OAuth2IntrospectPath, oauth2Endpoint(handler.Introspect))
+	group.POST(core.OAuth2RegisterPath, oauth2Endpoint(handler.Register))
+}
+
+// oauth2Endpoint Adapts a framework-neutral OAuth2 endpoint to gf | 将框架无关的OAuth2端点适配为gf处理函数
+func oauth2Endpoint(endpoint func(core.RequestContext) *core.OAuth2Response) ghttp.HandlerFunc {
+	return func(r *ghttp.Request) {
+		endpoint(NewGFContext(r)).Write(r.Response.BufferWriter)
+	}
+}
@@ -88,6 +88,41 @@ func (p *Plugin) RoleRequired(role string) ghttp.HandlerFunc {
 	}
 }
 
+// SafeRequired second-level authentication middleware | 二级认证中间件
+func (p *Plugin) SafeRequired(service ...string) ghttp.HandlerFunc {
+	return func(r *ghttp.Request) {
+		ctx := NewGFContext(r)
+		saCtx := core.NewContext(ctx, p.manager)
+
+		if err := saCtx.CheckLogin(); err != nil {
+			writeErrorResponse(r, err)
+			return
+		}
+
+		if !saCtx.IsSafe(service...) {
+			writeErrorResponse(r, core.NewNotSafeError(safeServices(service)[0]))
+			return
+		}
+
+		r.SetCtxVar("satoken", saCtx)
+		r.Middleware.Next()
+	}
+}
+
+// SignRequired API request signature middleware | API请求签名校验中间件
+func (p *Plugin) SignRequired() ghttp.HandlerFunc {
+	return func(r *ghttp.Request) {
+		ctx := NewGFContext(r)
+
+		if err := p.manager.GetSignManager().VerifyRequest(ctx); err != nil {
+			writeErrorResponse(r, core.NewSignError(err))
+			return
+		}
+
+		r.Middleware.Next()
+	}
+}
+
 // HandlerAuthMiddleware — Authentication check middleware | 认证校验中间件
 func (p *Plugin) HandlerAuthMiddleware(authFailedFunc ...func(r *ghttp.Request)) ghttp.HandlerFunc {
 	return func(r *ghttp.Request) {
@@ -199,9 +234,13 @@ func (p *Plugin) HandlerRoleRequiredMiddleware(middlewareType MiddlewareType, ro
 // LoginHandler 登录处理器
 func (p *Plugin) LoginHandler(r *ghttp.Request) {
 	var req struct {
-		Username string `json:"username"`
-		Password string `json:"password"`
-		Device   string `json:"device"`
+		Username      string `json:"username"`
+		Password      string `json:"password"`
+		Device        string `json:"device"`
+		DeviceID      string `json:"deviceId"`
+		Timeout       int64  `json:"timeout"`
+		ActiveTimeout int64  `json:"activeTimeout"`
+		Remember      *bool  `json:"remember"`
 	}
 
 	if err := r.Parse(&req); err != nil {
@@ -214,7 +253,21 @@ func (p *Plugin) LoginHandler(r *ghttp.Request) {
 		device = "default"
 	}
 
-	token, err := p.manager.Login(req.Username, device)
+	// Client input may only shorten configured timeouts | 客户端传入的超时只能缩短全局配置
+	cfg := p.manager.GetConfig()
+	opts := core.NewLoginOptions().
+		SetDevice(device).
+		SetDeviceID(req.DeviceID).
+		SetTimeout(req.Timeout).
+		SetActiveTimeout(req.ActiveTimeout).
+		LimitTimeouts(cfg.Timeout, cfg.ActiveTimeout)
+	if req.Remember != nil {
+		opts.SetRemember(*req.Remember)
+	}
+
+	// Writes token cookie and response header as configured | 按配置写入Token Cookie与响应头
+	saCtx := core.NewContext(NewGFContext(r), p.manager)
+	token, err := saCtx.LoginAndSetCookie(req.Username, opts)
 	if err != nil {
 		writeErrorResponse(r, core.NewError(core.CodeServerError, "login failed", err))
 		return
@@ -225,6 +278,26 @@ func (p *Plugin) LoginHandler(r *ghttp.Request) {
 	})
 }
 
+// LogoutHandler logout handler | 登出处理器
+func (p *Plugin) LogoutHandler(r *ghttp.Request) {
+	saCtx := core.NewContext(NewGFContext(r), p.manager)
+	loginID, err := saCtx.GetLoginID()
+	if err != nil {
+		writeErrorResponse(r, err)
+		return
+	}
+
+	if err := saCtx.Logout(loginID); err != nil {
+		writeErrorResponse(r, core.NewError(core.CodeServerError, "logout failed", err))
+		return
+	}
+	saCtx.ClearTokenCookie()
+
+	writeSuccessResponse(r, g.Map{
+		"message": "logout successful",
+	})
+}
+
 // UserInfoHandler user info handler example | 获取用户信息处理器示例
 func (p *Plugin) UserInfoHandler(r *ghttp.Request) {
 	ctx := NewGFContext(r)
@@ -297,9 +370,9 @@ func writeSuccessResponse(r *ghttp.Request, data interface{}) {
 // getHTTPStatusFromCode converts Sa-Token error code to HTTP status | 将Sa-Token错误码转换为HTTP状态码
 func getHTTPStatusFromCode(code int) int {
 	switch code {
-	case core.CodeNotLogin:
+	case core.CodeNotLogin, core.CodeSignInvalid:
 		return http.StatusUnauthorized
-	case core.CodePermissionDenied:
+	case core.CodePermissionDenied, core.CodeNotSafe:
 		return http.StatusForbidden
 	case core.CodeBadRequest:
 		return http.StatusBadRequest
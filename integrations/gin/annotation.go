@@ -15,7 +15,9 @@ const (
 	TagSaCheckRole       = "sa_check_role"
 	TagSaCheckPermission = "sa_check_permission"
 	TagSaCheckDisable    = "sa_check_disable"
+	TagSaCheckSafe       = "sa_check_safe"
 	TagSaIgnore          = "sa_ignore"
+	TagSaLoginType       = "sa_login_type"
 )
 
 // Annotation annotation structure | 注解结构体
@@ -24,7 +26,9 @@ type Annotation struct {
 	CheckRole       []string `json:"checkRole"`
 	CheckPermission []string `json:"checkPermission"`
 	CheckDisable    bool     `json:"checkDisable"`
+	CheckSafe       []string `json:"checkSafe"` // Required safe services, any one passes | 需要的二级认证业务，满足其一即可
 	Ignore          bool     `json:"ignore"`
+	LoginType       string   `json:"loginType"` // Target account realm, empty means global | 目标账号体系，为空表示全局
 }
 
 // ParseTag parses struct tags | 解析结构体标签
@@ -55,8 +59,19 @@ func ParseTag(tag string) *Annotation {
 			}
 		case part == TagSaCheckDisable || part == "disable":
 			ann.CheckDisable = true
+		case part == TagSaCheckSafe || part == "safe":
+			ann.CheckSafe = []string{core.DefaultSafeService}
+		case strings.HasPrefix(part, TagSaCheckSafe+"=") || strings.HasPrefix(part, "safe="):
+			services := strings.TrimPrefix(part, TagSaCheckSafe+"=")
+			services = strings.TrimPrefix(services, "safe=")
+			if services != "" {
+				ann.CheckSafe = strings.Split(services, "|")
+			}
 		case part == TagSaIgnore || part == "ignore":
 			ann.Ignore = true
+		case strings.HasPrefix(part, TagSaLoginType+"=") || strings.HasPrefix(part, "type="):
+			loginType := strings.TrimPrefix(part, TagSaLoginType+"=")
+			ann.LoginType = strings.TrimPrefix(loginType, "type=")
 		}
 	}
 
@@ -82,6 +97,9 @@ func (a *Annotation) Validate() bool {
 	if a.CheckDisable {
 		count++
 	}
+	if len(a.CheckSafe) > 0 {
+		count++
+	}
 
 	// At most one check type allowed | 最多只能有一个检查类型
 	return count <= 1
@@ -101,7 +119,8 @@ func GetHandler(handler interface{}, annotations ...*Annotation) ginfw.HandlerFu
 
 		// Get token from context using configured TokenName | 从上下文获取Token（使用配置的TokenName）
 		ctx := NewGinContext(c)
-		saCtx := core.NewContext(ctx, stputil.GetManager())
+		mgr := annotationManager(annotations)
+		saCtx := core.NewContext(ctx, mgr)
 		token := saCtx.GetTokenValue()
 		if token == "" {
 			writeErrorResponse(c, core.NewNotLoginError())
@@ -110,14 +129,14 @@ func GetHandler(handler interface{}, annotations ...*Annotation) ginfw.HandlerFu
 		}
 
 		// Check login | 检查登录
-		if !stputil.IsLogin(token) {
+		if !mgr.IsLogin(token) {
 			writeErrorResponse(c, core.NewNotLoginError())
 			c.Abort()
 			return
 		}
 
 		// Get login ID | 获取登录ID
-		loginID, err := stputil.GetLoginID(token)
+		loginID, err := mgr.GetLoginID(token)
 		if err != nil {
 			writeErrorResponse(c, err)
 			c.Abort()
@@ -126,18 +145,34 @@ func GetHandler(handler interface{}, annotations ...*Annotation) ginfw.HandlerFu
 
 		// Check if account is disabled | 检查是否被封禁
 		if len(annotations) > 0 && annotations[0].CheckDisable {
-			if stputil.IsDisable(loginID) {
+			if mgr.IsDisable(loginID) {
 				writeErrorResponse(c, core.NewAccountDisabledError(loginID))
 				c.Abort()
 				return
 			}
 		}
 
+		// Check safe authentication | 检查二级认证
+		if len(annotations) > 0 && len(annotations[0].CheckSafe) > 0 {
+			isSafe := false
+			for _, service := range annotations[0].CheckSafe {
+				if mgr.IsSafe(token, strings.TrimSpace(service)) {
+					isSafe = true
+					break
+				}
+			}
+			if !isSafe {
+				writeErrorResponse(c, core.NewNotSafeError(strings.Join(annotations[0].CheckSafe, ",")))
+				c.Abort()
+				return
+			}
+		}
+
 		// Check permission | 检查权限
 		if len(annotations) > 0 && len(annotations[0].CheckPermission) > 0 {
 			hasPermission := false
 			for _, perm := range annotations[0].CheckPermission {
-				if stputil.HasPermission(loginID, strings.TrimSpace(perm)) {
+				if mgr.HasPermissionByToken(token, strings.TrimSpace(perm)) {
 					hasPermission = true
 					break
 				}
@@ -153,7 +188,7 @@ func GetHandler(handler interface{}, annotations ...*Annotation) ginfw.HandlerFu
 		if len(annotations) > 0 && len(annotations[0].CheckRole) > 0 {
 			hasRole := false
 			for _, role := range annotations[0].CheckRole {
-				if stputil.HasRole(loginID, strings.TrimSpace(role)) {
+				if mgr.HasRoleByToken(token, strings.TrimSpace(role)) {
 					hasRole = true
 					break
 				}
@@ -229,6 +264,11 @@ func CheckDisable() ginfw.HandlerFunc {
 	return GetHandler(nil, &Annotation{CheckDisable: true})
 }
 
+// CheckSafe decorator for second-level authentication checking | 检查二级认证装饰器
+func CheckSafe(services ...string) ginfw.HandlerFunc {
+	return GetHandler(nil, &Annotation{CheckSafe: safeServices(services)})
+}
+
 // Ignore decorator to ignore authentication | 忽略认证装饰器
 func Ignore() ginfw.HandlerFunc {
 	return GetHandler(nil, &Annotation{Ignore: true})
@@ -298,7 +338,8 @@ func Middleware(annotations ...*Annotation) ginfw.HandlerFunc {
 
 		// 获取Token（使用配置的TokenName）
 		ctx := NewGinContext(c)
-		saCtx := core.NewContext(ctx, stputil.GetManager())
+		mgr := annotationManager(annotations)
+		saCtx := core.NewContext(ctx, mgr)
 		token := saCtx.GetTokenValue()
 		if token == "" {
 			writeErrorResponse(c, core.NewNotLoginError())
@@ -307,14 +348,14 @@ func Middleware(annotations ...*Annotation) ginfw.HandlerFunc {
 		}
 
 		// 检查登录
-		if !stputil.IsLogin(token) {
+		if !mgr.IsLogin(token) {
 			writeErrorResponse(c, core.NewNotLoginError())
 			c.Abort()
 			return
 		}
 
 		// 获取登录ID
-		loginID, err := stputil.GetLoginID(token)
+		loginID, err := mgr.GetLoginID(token)
 		if err != nil {
 			writeErrorResponse(c, err)
 			c.Abort()
@@ -323,18 +364,34 @@ func Middleware(annotations ...*Annotation) ginfw.HandlerFunc {
 
 		// 检查是否被封禁
 		if len(annotations) > 0 && annotations[0].CheckDisable {
-			if stputil.IsDisable(loginID) {
+			if mgr.IsDisable(loginID) {
 				writeErrorResponse(c, core.NewAccountDisabledError(loginID))
 				c.Abort()
 				return
 			}
 		}
 
+		// 检查二级认证
+		if len(annotations) > 0 && len(annotations[0].CheckSafe) > 0 {
+			isSafe := false
+			for _, service := range annotations[0].CheckSafe {
+				if mgr.IsSafe(token, strings.TrimSpace(service)) {
+					isSafe = true
+					break
+				}
+			}
+			if !isSafe {
+				writeErrorResponse(c, core.NewNotSafeError(strings.Join(annotations[0].CheckSafe, ",")))
+				c.Abort()
+				return
+			}
+		}
+
 		// 检查权限
 		if len(annotations) > 0 && len(annotations[0].CheckPermission) > 0 {
 			hasPermission := false
 			for _, perm := range annotations[0].CheckPermission {
-				if stputil.HasPermission(loginID, strings.TrimSpace(perm)) {
+				if mgr.HasPermissionByToken(token, strings.TrimSpace(perm)) {
 					hasPermission = true
 					break
 				}
@@ -350,7 +407,7 @@ func Middleware(annotations ...*Annotation) ginfw.HandlerFunc {
 		if len(annotations) > 0 && len(annotations[0].CheckRole) > 0 {
 			hasRole := false
 			for _, role := range annotations[0].CheckRole {
-				if stputil.HasRole(loginID, strings.TrimSpace(role)) {
+				if mgr.HasRoleByToken(token, strings.TrimSpace(role)) {
 					hasRole = true
 					break
 				}
@@ -366,3 +423,19 @@ func Middleware(annotations ...*Annotation) ginfw.HandlerFunc {
 		c.Next()
 	}
 }
+
+// annotationManager resolves the Manager targeted by annotations | 获取注解指定账号体系的Manager
+func annotationManager(annotations []*Annotation) *core.Manager {
+	if len(annotations) > 0 && annotations[0].LoginType != "" {
+		return stputil.GetManagerByLoginType(annotations[0].LoginType)
+	}
+	return stputil.GetManager()
+}
+
+// safeServices returns the default safe service when none given | 未指定时返回默认二级认证业务
+func safeServices(services []string) []string {
+	if len(services) == 0 {
+		return []string{core.DefaultSafeService}
+	}
+	return services
+}
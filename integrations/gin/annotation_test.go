@@ -421,6 +421,28 @@ func TestParseTag(t *testing.T) {
 				CheckDisable: true,
 			},
 		},
+		{
+			name: "解析账号体系标签",
+			tag:  "sa_check_login,sa_login_type=admin",
+			expected: &Annotation{
+				CheckLogin: true,
+				LoginType:  "admin",
+			},
+		},
+		{
+			name: "解析二级认证标签",
+			tag:  "sa_check_safe=payment|delete-account",
+			expected: &Annotation{
+				CheckSafe: []string{"payment", "delete-account"},
+			},
+		},
+		{
+			name: "解析默认二级认证标签",
+			tag:  "sa_check_safe",
+			expected: &Annotation{
+				CheckSafe: []string{"important"},
+			},
+		},
 		{
 			name:     "空标签",
 			tag:      "",
@@ -436,6 +458,8 @@ func TestParseTag(t *testing.T) {
 			assert.Equal(t, tt.expected.CheckPermission, result.CheckPermission)
 			assert.Equal(t, tt.expected.CheckDisable, result.CheckDisable)
 			assert.Equal(t, tt.expected.Ignore, result.Ignore)
+			assert.Equal(t, tt.expected.LoginType, result.LoginType)
+			assert.Equal(t, tt.expected.CheckSafe, result.CheckSafe)
 		})
 	}
 }
@@ -1,6 +1,7 @@
 package gin
 
 import (
+	"context"
 	"net/http"
 
 	"github.com/click33/sa-token-go/core/adapter"
@@ -84,12 +85,17 @@ func (g *GinContext) GetQueryAll() map[string][]string {
 
 // GetPostForm implements adapter.RequestContext.
 func (g *GinContext) GetPostForm(key string) string {
-	return g.c.PostForm(key)
+	// Only urlencoded bodies are buffered, the body stays readable | 仅缓冲urlencoded请求体，请求体保持可读
+	if !adapter.IsFormURLEncoded(g.c.GetHeader("Content-Type")) {
+		return g.c.PostForm(key)
+	}
+	return adapter.PostFormValue(g.c.Request, key)
 }
 
 // GetBody implements adapter.RequestContext.
+// Reads at most adapter.MaxBodySize bytes and restores the body for downstream handlers | 至多读取adapter.MaxBodySize字节，并恢复请求体供后续处理器读取
 func (g *GinContext) GetBody() ([]byte, error) {
-	return g.c.GetRawData()
+	return adapter.ReadBody(g.c.Request)
 }
 
 // GetURL implements adapter.RequestContext.
@@ -104,6 +110,16 @@ func (g *GinContext) GetUserAgent() string {
 
 // SetCookieWithOptions implements adapter.RequestContext.
 func (g *GinContext) SetCookieWithOptions(options *adapter.CookieOptions) {
+	// Set SameSite attribute, gin applies it on the next SetCookie
+	switch options.SameSite {
+	case "Strict":
+		g.c.SetSameSite(http.SameSiteStrictMode)
+	case "Lax":
+		g.c.SetSameSite(http.SameSiteLaxMode)
+	case "None":
+		g.c.SetSameSite(http.SameSiteNoneMode)
+	}
+	
 	g.c.SetCookie(
 		options.Name,
 		options.Value,
@@ -113,16 +129,6 @@ func (g *GinContext) SetCookieWithOptions(options *adapter.CookieOptions) {
 		options.Secure,
 		options.HttpOnly,
 	)
-	
-	// Set SameSite attribute
-	switch options.SameSite {
-	case "Strict":
-		g.c.SetSameSite(http.SameSiteStrictMode)
-	case "Lax":
-		g.c.SetSameSite(http.SameSiteLaxMode)
-	case "None":
-		g.c.SetSameSite(http.SameSiteNoneMode)
-	}
 }
 
 // GetString implements adapter.RequestContext.
@@ -156,3 +162,8 @@ func (g *GinContext) Abort() {
 func (g *GinContext) IsAborted() bool {
 	return g.aborted
 }
+
+// Context implements adapter.ContextCarrier, storage calls honor its cancellation and deadline | 实现adapter.ContextCarrier，存储调用遵循其取消与截止时间
+func (g *GinContext) Context() context.Context {
+	return g.c.Request.Context()
+}
@@ -0,0 +1,67 @@
+package gin
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/click33/sa-token-go/core/adapter"
+	ginfw "github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// newBodyContext 创建携带请求体的测试上下文
+func newBodyContext(contentType string, body *bytes.Buffer) *ginfw.Context {
+	ginfw.SetMode(ginfw.TestMode)
+	c, _ := ginfw.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodPost, "/", body)
+	c.Request.Header.Set("Content-Type", contentType)
+	return c
+}
+
+func TestGinContext_GetPostFormKeepsBody(t *testing.T) {
+	c := newBodyContext("application/x-www-form-urlencoded", bytes.NewBufferString("satoken=abc&name=sa"))
+	ctx := NewGinContext(c)
+
+	assert.Equal(t, "abc", ctx.GetPostForm("satoken"))
+	assert.Equal(t, "sa", c.PostForm("name"), "gin must still parse the form after the token was read")
+}
+
+func TestGinContext_GetBodyKeepsBody(t *testing.T) {
+	c := newBodyContext("application/json", bytes.NewBufferString(`{"name":"sa"}`))
+	ctx := NewGinContext(c)
+
+	body, err := ctx.GetBody()
+	assert.NoError(t, err)
+	assert.Equal(t, `{"name":"sa"}`, string(body))
+
+	var req struct {
+		Name string `json:"name"`
+	}
+	assert.NoError(t, c.ShouldBindJSON(&req))
+	assert.Equal(t, "sa", req.Name)
+}
+
+func TestGinContext_GetBodyLimit(t *testing.T) {
+	large := strings.Repeat("a", int(adapter.MaxBodySize)+1)
+	c := newBodyContext("application/json", bytes.NewBufferString(large))
+
+	_, err := NewGinContext(c).GetBody()
+	assert.Error(t, err)
+	raw, _ := c.GetRawData()
+	assert.Len(t, raw, len(large), "oversized body must stay whole for the handler")
+}
+
+func TestGinContext_GetPostFormMultipart(t *testing.T) {
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	_ = w.WriteField("satoken", "abc")
+	_ = w.Close()
+	c := newBodyContext(w.FormDataContentType(), &body)
+
+	assert.Equal(t, "abc", NewGinContext(c).GetPostForm("satoken"))
+	assert.Equal(t, "abc", c.PostForm("satoken"), "multipart form stays available through gin")
+}
@@ -0,0 +1,25 @@
+package gin
+
+import (
+	"github.com/click33/sa-token-go/core"
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterOAuth2Routes Mounts OAuth2 endpoints at their default paths | 在默认路径挂载OAuth2端点
+// Usage | 用法: RegisterOAuth2Routes(router, NewOAuth2Handler(manager.GetOAuth2Server(), consent))
+func RegisterOAuth2Routes(r gin.IRoutes, handler *OAuth2Handler) {
+	authorize := oauth2Endpoint(handler.Authorize)
+	r.GET(core.OAuth2AuthorizePath, authorize)
+	r.POST(core.OAuth2AuthorizePath, authorize)
+	r.POST(core.OAuth2TokenPath, oauth2Endpoint(handler.Token))
+	r.POST(core.OAuth2RevokePath, oauth2Endpoint(handler.Revoke))
+	r.POST(core.OAuth2IntrospectPath, oauth2Endpoint(handler.Introspect))
+	r.POST(core.OAuth2RegisterPath, oauth2Endpoint(handler.Register))
+}
+
+// oauth2Endpoint Adapts a framework-neutral OAuth2 endpoint to gin | 将框架无关的OAuth2端点适配为gin处理函数
+func oauth2Endpoint(endpoint func(core.RequestContext) *core.OAuth2Response) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		endpoint(NewGinContext(c)).Write(c.Writer)
+	}
+}
@@ -89,12 +89,57 @@ func (p *Plugin) RoleRequired(role string) gin.HandlerFunc {
 	}
 }
 
+// SafeRequired second-level authentication middleware | 二级认证中间件
+func (p *Plugin) SafeRequired(service ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := NewGinContext(c)
+		saCtx := core.NewContext(ctx, p.manager)
+
+		// Check login | 检查登录
+		if err := saCtx.CheckLogin(); err != nil {
+			writeErrorResponse(c, err)
+			c.Abort()
+			return
+		}
+
+		// Check safe authentication | 检查二级认证
+		if !saCtx.IsSafe(service...) {
+			writeErrorResponse(c, core.NewNotSafeError(safeServices(service)[0]))
+			c.Abort()
+			return
+		}
+
+		c.Set("satoken", saCtx)
+		c.Next()
+	}
+}
+
+// SignRequired API request signature middleware | API请求签名校验中间件
+func (p *Plugin) SignRequired() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := NewGinContext(c)
+
+		// Verify signature, timestamp and nonce | 校验签名、时间戳与nonce
+		if err := p.manager.GetSignManager().VerifyRequest(ctx); err != nil {
+			writeErrorResponse(c, core.NewSignError(err))
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
 // LoginHandler login handler example | 登录处理器示例
 func (p *Plugin) LoginHandler(c *gin.Context) {
 	var req struct {
-		Username string `json:"username" binding:"required"`
-		Password string `json:"password" binding:"required"`
-		Device   string `json:"device"`
+		Username      string `json:"username" binding:"required"`
+		Password      string `json:"password" binding:"required"`
+		Device        string `json:"device"`
+		DeviceID      string `json:"deviceId"`
+		Timeout       int64  `json:"timeout"`
+		ActiveTimeout int64  `json:"activeTimeout"`
+		Remember      *bool  `json:"remember"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -111,30 +156,26 @@ func (p *Plugin) LoginHandler(c *gin.Context) {
 		device = "default"
 	}
 
-	token, err := p.manager.Login(req.Username, device)
+	// Client input may only shorten configured timeouts | 客户端传入的超时只能缩短全局配置
+	cfg := p.manager.GetConfig()
+	opts := core.NewLoginOptions().
+		SetDevice(device).
+		SetDeviceID(req.DeviceID).
+		SetTimeout(req.Timeout).
+		SetActiveTimeout(req.ActiveTimeout).
+		LimitTimeouts(cfg.Timeout, cfg.ActiveTimeout)
+	if req.Remember != nil {
+		opts.SetRemember(*req.Remember)
+	}
+
+	// Writes token cookie and response header as configured | 按配置写入Token Cookie与响应头
+	saCtx := core.NewContext(NewGinContext(c), p.manager)
+	token, err := saCtx.LoginAndSetCookie(req.Username, opts)
 	if err != nil {
 		writeErrorResponse(c, core.NewError(core.CodeServerError, "login failed", err))
 		return
 	}
 
-	// Set cookie (optional) | 设置Cookie（可选）
-	cfg := p.manager.GetConfig()
-	if cfg.IsReadCookie {
-		maxAge := int(cfg.Timeout)
-		if maxAge < 0 {
-			maxAge = 0
-		}
-		c.SetCookie(
-			cfg.TokenName,
-			token,
-			maxAge,
-			cfg.CookieConfig.Path,
-			cfg.CookieConfig.Domain,
-			cfg.CookieConfig.Secure,
-			cfg.CookieConfig.HttpOnly,
-		)
-	}
-
 	writeSuccessResponse(c, gin.H{
 		"token": token,
 	})
@@ -151,10 +192,11 @@ func (p *Plugin) LogoutHandler(c *gin.Context) {
 		return
 	}
 
-	if err := p.manager.Logout(loginID); err != nil {
+	if err := saCtx.Logout(loginID); err != nil {
 		writeErrorResponse(c, core.NewError(core.CodeServerError, "logout failed", err))
 		return
 	}
+	saCtx.ClearTokenCookie()
 
 	writeSuccessResponse(c, gin.H{
 		"message": "logout successful",
@@ -233,9 +275,9 @@ func writeSuccessResponse(c *gin.Context, data interface{}) {
 // getHTTPStatusFromCode converts Sa-Token error code to HTTP status | 将Sa-Token错误码转换为HTTP状态码
 func getHTTPStatusFromCode(code int) int {
 	switch code {
-	case core.CodeNotLogin:
+	case core.CodeNotLogin, core.CodeSignInvalid:
 		return http.StatusUnauthorized
-	case core.CodePermissionDenied:
+	case core.CodePermissionDenied, core.CodeNotSafe:
 		return http.StatusForbidden
 	case core.CodeBadRequest:
 		return http.StatusBadRequest
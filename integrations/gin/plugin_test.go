@@ -0,0 +1,114 @@
+package gin
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/click33/sa-token-go/core/config"
+	"github.com/click33/sa-token-go/core/manager"
+	"github.com/click33/sa-token-go/storage/memory"
+	ginfw "github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// newLogoutRouter 创建挂载登出处理器的测试路由
+func newLogoutRouter(configure func(cfg *config.Config)) (*ginfw.Engine, *manager.Manager) {
+	ginfw.SetMode(ginfw.TestMode)
+
+	cfg := config.DefaultConfig()
+	cfg.IsReadCookie = true
+	cfg.IsPrintBanner = false
+	if configure != nil {
+		configure(cfg)
+	}
+	mgr := manager.NewManager(memory.NewStorage(), cfg)
+
+	router := ginfw.New()
+	router.POST("/logout", NewPlugin(mgr).LogoutHandler)
+	return router, mgr
+}
+
+// doLogout 携带Token请求登出接口
+func doLogout(router *ginfw.Engine, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/logout", nil)
+	if token != "" {
+		req.Header.Set("satoken", token)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestLogoutHandler_Anonymous(t *testing.T) {
+	router, _ := newLogoutRouter(nil)
+
+	w := doLogout(router, "")
+	assert.NotEqual(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "not login")
+	assert.Empty(t, w.Header().Get("Set-Cookie"), "anonymous logout must keep the cookie")
+}
+
+func TestLogoutHandler_LogsOut(t *testing.T) {
+	router, mgr := newLogoutRouter(nil)
+	token, err := mgr.Login("1001")
+	assert.NoError(t, err)
+
+	w := doLogout(router, token)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.True(t, strings.HasPrefix(w.Header().Get("Set-Cookie"), "satoken=;"), "cookie must be cleared")
+	assert.False(t, mgr.IsLogin(token))
+}
+
+func TestLogoutHandler_Device(t *testing.T) {
+	router, mgr := newLogoutRouter(nil)
+	mobile, err := mgr.Login("1001", "mobile")
+	assert.NoError(t, err)
+	pc, err := mgr.Login("1001", "pc")
+	assert.NoError(t, err)
+
+	w := doLogout(router, mobile)
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.False(t, mgr.IsLogin(mobile), "token of the logged out device must be invalid")
+	assert.True(t, mgr.IsLogin(pc), "other devices stay logged in")
+}
+
+func TestLogoutHandler_JwtModes(t *testing.T) {
+	modes := []struct {
+		mode       config.JwtMode
+		revocation bool
+		loggedOut  bool
+	}{
+		{config.JwtModeDefault, false, true},
+		{config.JwtModeSimple, false, true},
+		{config.JwtModeMixed, false, true},
+		{config.JwtModeStateless, false, false},
+		{config.JwtModeStateless, true, true},
+	}
+
+	for _, tt := range modes {
+		name := string(tt.mode)
+		if name == "" {
+			name = "default"
+		}
+		if tt.revocation {
+			name += "+revocation"
+		}
+		t.Run(name, func(t *testing.T) {
+			router, mgr := newLogoutRouter(func(cfg *config.Config) {
+				cfg.TokenStyle = config.TokenStyleJWT
+				cfg.JwtSecretKey = "logout-test-secret"
+				cfg.JwtMode = tt.mode
+				cfg.JwtRevocation = tt.revocation
+			})
+			token, err := mgr.Login("1001", "mobile")
+			assert.NoError(t, err)
+
+			w := doLogout(router, token)
+			assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+			assert.True(t, strings.HasPrefix(w.Header().Get("Set-Cookie"), "satoken=;"), "cookie must be cleared")
+			assert.Equal(t, !tt.loggedOut, mgr.IsLogin(token))
+		})
+	}
+}
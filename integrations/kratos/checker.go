@@ -2,6 +2,8 @@ package kratos
 
 import (
 	"context"
+	"strings"
+
 	"github.com/click33/sa-token-go/core"
 )
 
@@ -129,6 +131,29 @@ func (c *DisableChecker) Check(ctx context.Context, manager *core.Manager, login
 	return nil
 }
 
+// ========== 二级认证检查 ==========
+
+// SafeChecker 二级认证检查器（任一业务标识通过即可）
+type SafeChecker struct {
+	services []string
+}
+
+func (c *SafeChecker) Check(ctx context.Context, manager *core.Manager, loginID string) error {
+	token := core.NewContext(NewKratosContext(ctx), manager).GetTokenValue()
+
+	services := c.services
+	if len(services) == 0 {
+		services = []string{core.DefaultSafeService}
+	}
+	for _, service := range services {
+		if manager.IsSafe(token, service) {
+			return nil
+		}
+	}
+
+	return core.NewNotSafeError(strings.Join(services, ","))
+}
+
 // ========== 自定义检查 ==========
 
 // CustomChecker 自定义检查器
@@ -201,6 +226,11 @@ func NewDisableChecker() Checker {
 	return &DisableChecker{}
 }
 
+// NewSafeChecker 创建二级认证检查器
+func NewSafeChecker(services ...string) Checker {
+	return &SafeChecker{services: services}
+}
+
 // NewCustomChecker 创建自定义检查器
 func NewCustomChecker(name string, fn func(ctx context.Context, manager *core.Manager, loginID string) error) Checker {
 	return &CustomChecker{name: name, fn: fn}
@@ -2,7 +2,6 @@ package kratos
 
 import (
 	"context"
-	"io"
 	"net/http"
 	"sync"
 
@@ -185,10 +184,11 @@ func (k *KratosContext) GetPostForm(key string) string {
 	if tr, ok := transport.FromServerContext(k.ctx); ok {
 		if htr, ok := tr.(*khttp.Transport); ok {
 			request := htr.Request()
-			if err := request.ParseForm(); err != nil {
-				return ""
+			// Only urlencoded bodies are buffered, the body stays readable for downstream decoders | 仅缓冲urlencoded请求体，请求体保持可读，供后续解码器读取
+			if !adapter.IsFormURLEncoded(request.Header.Get("Content-Type")) {
+				return request.PostFormValue(key)
 			}
-			return request.PostFormValue(key)
+			return adapter.PostFormValue(request, key)
 		}
 	}
 	return ""
@@ -197,12 +197,8 @@ func (k *KratosContext) GetPostForm(key string) string {
 func (k *KratosContext) GetBody() ([]byte, error) {
 	if tr, ok := transport.FromServerContext(k.ctx); ok {
 		if htr, ok := tr.(*khttp.Transport); ok {
-			request := htr.Request()
-			if request.Body == nil {
-				return nil, nil
-			}
-			defer request.Body.Close()
-			return io.ReadAll(request.Body)
+			// At most adapter.MaxBodySize bytes, body is restored for downstream handlers | 至多读取adapter.MaxBodySize字节，并恢复请求体供后续处理器读取
+			return adapter.ReadBody(htr.Request())
 		}
 	}
 	return nil, nil
@@ -287,3 +283,8 @@ func (k *KratosContext) IsAborted() bool {
 	defer k.mu.RUnlock()
 	return k.aborted
 }
+
+// Context implements adapter.ContextCarrier, storage calls honor its cancellation and deadline | 实现adapter.ContextCarrier，存储调用遵循其取消与截止时间
+func (k *KratosContext) Context() context.Context {
+	return k.ctx
+}
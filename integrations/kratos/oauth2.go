@@ -0,0 +1,26 @@
+package kratos
+
+import (
+	"github.com/click33/sa-token-go/core"
+	khttp "github.com/go-kratos/kratos/v2/transport/http"
+)
+
+// RegisterOAuth2Routes Mounts OAuth2 endpoints at their default paths | 在默认路径挂载OAuth2端点
+// Usage | 用法: RegisterOAuth2Routes(httpSrv.Route("/"), NewOAuth2Handler(manager.GetOAuth2Server(), consent))
+func RegisterOAuth2Routes(r *khttp.Router, handler *OAuth2Handler) {
+	authorize := oauth2Endpoint(handler.Authorize)
+	r.GET(core.OAuth2AuthorizePath, authorize)
+	r.POST(core.OAuth2AuthorizePath, authorize)
+	r.POST(core.OAuth2TokenPath, oauth2Endpoint(handler.Token))
+	r.POST(core.OAuth2RevokePath, oauth2Endpoint(handler.Revoke))
+	r.POST(core.OAuth2IntrospectPath, oauth2Endpoint(handler.Introspect))
+	r.POST(core.OAuth2RegisterPath, oauth2Endpoint(handler.Register))
+}
+
+// oauth2Endpoint Adapts a framework-neutral OAuth2 endpoint to a kratos HTTP route | 将框架无关的OAuth2端点适配为kratos HTTP路由
+func oauth2Endpoint(endpoint func(core.RequestContext) *core.OAuth2Response) khttp.HandlerFunc {
+	return func(ctx khttp.Context) error {
+		endpoint(NewKratosContext(ctx)).Write(ctx.Response())
+		return nil
+	}
+}
@@ -82,9 +82,9 @@ func WithErrorHandler(handler func(ctx context.Context, err error) error) Option
 // getHTTPStatusFromCode converts Sa-Token error code to HTTP status | 将Sa-Token错误码转换为HTTP状态码
 func getHTTPStatusFromCode(code int) int {
 	switch code {
-	case core.CodeNotLogin:
+	case core.CodeNotLogin, core.CodeSignInvalid:
 		return http.StatusUnauthorized
-	case core.CodePermissionDenied:
+	case core.CodePermissionDenied, core.CodeNotSafe:
 		return http.StatusForbidden
 	case core.CodeBadRequest:
 		return http.StatusBadRequest
@@ -103,6 +103,10 @@ func getReasonFromCode(code int) string {
 		return "UNAUTHORIZED"
 	case core.CodePermissionDenied:
 		return "FORBIDDEN"
+	case core.CodeNotSafe:
+		return "NOT_SAFE"
+	case core.CodeSignInvalid:
+		return "SIGN_INVALID"
 	case core.CodeBadRequest:
 		return "BAD_REQUEST"
 	case core.CodeNotFound:
@@ -78,8 +78,9 @@ func (e *Plugin) Server() middleware.Middleware {
 				return nil, e.options.ErrorHandler(ctx, core.ErrNotLogin)
 			}
 
+			// The context-bound manager carries ctx into storage | 绑定上下文的管理器将ctx传递到存储
 			for _, checker := range rule.Checkers {
-				if err := checker.Check(ctx, e.manager, loginID); err != nil {
+				if err := checker.Check(ctx, saCtx.GetManager(), loginID); err != nil {
 					return nil, e.options.ErrorHandler(ctx, err)
 				}
 			}
@@ -90,6 +91,18 @@ func (e *Plugin) Server() middleware.Middleware {
 	}
 }
 
+// SignServer 返回API请求签名校验中间件（可配合 selector 中间件限定作用范围）
+func (e *Plugin) SignServer() middleware.Middleware {
+	return func(handler middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req interface{}) (reply interface{}, err error) {
+			if err := e.manager.GetSignManager().VerifyRequest(NewKratosContext(ctx)); err != nil {
+				return nil, e.options.ErrorHandler(ctx, core.NewSignError(err))
+			}
+			return handler(ctx, req)
+		}
+	}
+}
+
 // ========== 规则构建器 ==========
 
 // RuleBuilder 规则构建器（链式API）
@@ -232,6 +245,12 @@ func (rb *RuleBuilder) CheckNotDisabled() *RuleBuilder {
 	return rb
 }
 
+// RequireSafe 需要二级认证（任一业务标识通过即可，未指定时使用默认业务）
+func (rb *RuleBuilder) RequireSafe(services ...string) *RuleBuilder {
+	rb.checkers = append(rb.checkers, &SafeChecker{services: services})
+	return rb
+}
+
 // CustomCheck 自定义检查
 func (rb *RuleBuilder) CustomCheck(name string, fn func(ctx context.Context, manager *core.Manager, loginID string) error) *RuleBuilder {
 	rb.checkers = append(rb.checkers, &CustomChecker{name: name, fn: fn})
@@ -15,6 +15,8 @@ var (
 	ErrKeyNotFound = errors.New("key not found")
 	// ErrKeyExpired 键已过期错误
 	ErrKeyExpired = errors.New("key expired")
+	// ErrWrongType 键不是哈希
+	ErrWrongType = errors.New("key does not hold a hash")
 )
 
 // item 存储项
@@ -28,6 +30,11 @@ func (i *item) isExpired(now int64) bool {
 	return i.expiration > 0 && now > i.expiration
 }
 
+var (
+	_ adapter.AtomicStorage = (*Storage)(nil)
+	_ adapter.HashStorage   = (*Storage)(nil)
+)
+
 // Storage 内存存储实现
 type Storage struct {
 	data       map[string]*item
@@ -232,6 +239,106 @@ func (s *Storage) Ping() error {
 	return nil
 }
 
+// SetNX Sets key only when absent or expired | 仅当键不存在或已过期时设置
+func (s *Storage) SetNX(key string, value any, expiration time.Duration) (bool, error) {
+	now := time.Now().Unix()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if item, exists := s.data[key]; exists && !item.isExpired(now) {
+		return false, nil
+	}
+
+	var exp int64
+	if expiration > 0 {
+		exp = time.Now().Add(expiration).Unix()
+	}
+	s.data[key] = &item{value: value, expiration: exp}
+	return true, nil
+}
+
+// GetDel Gets and deletes key in one step | 一步获取并删除键
+func (s *Storage) GetDel(key string) (any, error) {
+	now := time.Now().Unix()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	item, exists := s.data[key]
+	if !exists {
+		return nil, ErrKeyNotFound
+	}
+	delete(s.data, key)
+	if item.isExpired(now) {
+		return nil, ErrKeyExpired
+	}
+	return item.value, nil
+}
+
+// HSet Sets one field of hash, keeping the key's expiration | 设置哈希的一个字段，保持键的过期时间
+func (s *Storage) HSet(key, field, value string) error {
+	now := time.Now().Unix()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if it, exists := s.data[key]; exists && !it.isExpired(now) {
+		hash, ok := it.value.(map[string]string)
+		if !ok {
+			return ErrWrongType
+		}
+		hash[field] = value
+		return nil
+	}
+	s.data[key] = &item{value: map[string]string{field: value}}
+	return nil
+}
+
+// HGetAll Gets a copy of every hash field, empty map when missing | 获取哈希全部字段的副本，不存在时返回空map
+func (s *Storage) HGetAll(key string) (map[string]string, error) {
+	now := time.Now().Unix()
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make(map[string]string)
+	it, exists := s.data[key]
+	if !exists || it.isExpired(now) {
+		return result, nil
+	}
+	hash, ok := it.value.(map[string]string)
+	if !ok {
+		return nil, ErrWrongType
+	}
+	for field, value := range hash {
+		result[field] = value
+	}
+	return result, nil
+}
+
+// HDel Deletes hash fields, removing the key with its last field | 删除哈希字段，最后一个字段删除时键随之删除
+func (s *Storage) HDel(key string, fields ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	it, exists := s.data[key]
+	if !exists {
+		return nil
+	}
+	hash, ok := it.value.(map[string]string)
+	if !ok {
+		return ErrWrongType
+	}
+	for _, field := range fields {
+		delete(hash, field)
+	}
+	if len(hash) == 0 {
+		delete(s.data, key)
+	}
+	return nil
+}
+
 // Close 关闭存储，停止清理协程
 func (s *Storage) Close() error {
 	s.mu.Lock()
@@ -3,6 +3,10 @@ package memory
 import (
 	"testing"
 	"time"
+
+	"github.com/click33/sa-token-go/core/adapter"
+	"github.com/click33/sa-token-go/core/adapter/adaptertest"
+	"github.com/click33/sa-token-go/core/oauth2/oauth2test"
 )
 
 func TestSetKeepTTL(t *testing.T) {
@@ -65,3 +69,11 @@ func TestSetKeepTTL(t *testing.T) {
 	// 注意：Memory实现中，过期检查是在访问时进行的，而不是通过后台任务
 	// 因此我们无法可靠地测试已过期键的情况，这里只测试键不存在的情况
 }
+
+func TestOAuth2StorageSuite(t *testing.T) {
+	oauth2test.RunStorageSuite(t, func() adapter.Storage { return NewStorage() })
+}
+
+func TestAtomicSuite(t *testing.T) {
+	adaptertest.RunAtomicSuite(t, func() adapter.Storage { return NewStorage() })
+}
@@ -3,6 +3,7 @@ module github.com/click33/sa-token-go/storage/redis
 go 1.23.0
 
 require (
+	github.com/alicebob/miniredis/v2 v2.37.0
 	github.com/click33/sa-token-go/core v0.1.4
 	github.com/redis/go-redis/v9 v9.5.1
 )
@@ -10,6 +11,11 @@ require (
 require (
 	github.com/cespare/xxhash/v2 v2.3.0 // indirect
 	github.com/dgryski/go-rendezvous v0.0.0-20200823014737-9f7001d12a5f // indirect
+	github.com/golang-jwt/jwt/v5 v5.2.2 // indirect
+	github.com/google/uuid v1.6.0 // indirect
+	github.com/panjf2000/ants/v2 v2.11.3 // indirect
+	github.com/yuin/gopher-lua v1.1.1 // indirect
+	golang.org/x/sync v0.16.0 // indirect
 )
 
 replace github.com/click33/sa-token-go/core => ../../core
@@ -9,11 +9,14 @@ import (
 	"github.com/redis/go-redis/v9"
 )
 
+// defaultOperationTimeout 单次存储操作的默认超时
+const defaultOperationTimeout = 3 * time.Second
+
 // Storage Redis存储实现
 type Storage struct {
-	client    *redis.Client
-	ctx       context.Context
-	opTimeout time.Duration
+	client *redis.Client
+	ctx    context.Context
+	v2     *ContextStorage
 }
 
 // Config Redis配置
@@ -48,9 +51,9 @@ func NewStorage(url string) (adapter.Storage, error) {
 	}
 
 	return &Storage{
-		client:    client,
-		ctx:       ctx,
-		opTimeout: 3 * time.Second,
+		client: client,
+		ctx:    ctx,
+		v2:     NewContextStorage(client, defaultOperationTimeout),
 	}, nil
 }
 
@@ -75,168 +78,99 @@ func NewStorageFromConfig(cfg *Config) (adapter.Storage, error) {
 
 	opTimeout := cfg.OperationTimeout
 	if opTimeout <= 0 {
-		opTimeout = 3 * time.Second
+		opTimeout = defaultOperationTimeout
 	}
 
 	return &Storage{
-		client:    client,
-		ctx:       ctx,
-		opTimeout: opTimeout,
+		client: client,
+		ctx:    ctx,
+		v2:     NewContextStorage(client, opTimeout),
 	}, nil
 }
 
 // NewStorageFromClient 从已有的Redis客户端创建存储
 func NewStorageFromClient(client *redis.Client) adapter.Storage {
 	return &Storage{
-		client:    client,
-		ctx:       context.Background(),
-		opTimeout: 3 * time.Second,
+		client: client,
+		ctx:    context.Background(),
+		v2:     NewContextStorage(client, defaultOperationTimeout),
 	}
 }
 
-// getKey 获取完整的键名（Storage 层不处理前缀，前缀由 Manager 层统一管理）
-func (s *Storage) getKey(key string) string {
-	return key
-}
-
 // Set 设置键值对
 func (s *Storage) Set(key string, value any, expiration time.Duration) error {
-	ctx, cancel := s.withTimeout()
-	defer cancel()
-	return s.client.Set(ctx, s.getKey(key), value, expiration).Err()
+	return s.v2.Set(s.ctx, key, value, expiration)
 }
 
 // SetKeepTTL Sets value without modifying TTL | 设置键值但保持原有TTL不变
 func (s *Storage) SetKeepTTL(key string, value any) error {
-	ctx, cancel := s.withTimeout()
-	defer cancel()
-
-	// 先检查键是否存在，不存在则返回错误（与Memory实现保持一致）
-	exists, err := s.client.Exists(ctx, s.getKey(key)).Result()
-	if err != nil {
-		return err
-	}
-	if exists == 0 {
-		return fmt.Errorf("key not found: %s", key)
-	}
-
-	// Use SET key value KeepTTL | 使用 SET key value KeepTTL
-	return s.client.SetArgs(ctx, s.getKey(key), value, redis.SetArgs{
-		KeepTTL: true, // Keep original TTL | 保留原有TTL
-	}).Err()
+	return s.v2.SetKeepTTL(s.ctx, key, value)
 }
 
 // Get 获取值
 func (s *Storage) Get(key string) (any, error) {
-	ctx, cancel := s.withTimeout()
-	defer cancel()
-	val, err := s.client.Get(ctx, s.getKey(key)).Result()
-	if err == redis.Nil {
-		return nil, fmt.Errorf("key not found: %s", key)
-	}
-	if err != nil {
-		return nil, err
-	}
-	return val, nil
+	return s.v2.Get(s.ctx, key)
 }
 
 // Delete 删除键
 func (s *Storage) Delete(keys ...string) error {
-	if len(keys) == 0 {
-		return nil
-	}
-
-	ctx, cancel := s.withTimeout()
-	defer cancel()
-
-	fullKeys := make([]string, len(keys))
-	for i, key := range keys {
-		fullKeys[i] = s.getKey(key)
-	}
-	return s.client.Del(ctx, fullKeys...).Err()
+	return s.v2.Delete(s.ctx, keys...)
 }
 
-// Exists 检查键是否存在
+// Exists 检查键是否存在（出错时返回false，需要错误请使用StorageV2）
 func (s *Storage) Exists(key string) bool {
-	ctx, cancel := s.withTimeout()
-	defer cancel()
-	result, err := s.client.Exists(ctx, s.getKey(key)).Result()
-	if err != nil {
-		return false
-	}
-	return result > 0
+	exists, err := s.v2.Exists(s.ctx, key)
+	return err == nil && exists
 }
 
 // Keys 获取匹配模式的所有键
 func (s *Storage) Keys(pattern string) ([]string, error) {
-	ctx, cancel := s.withTimeout()
-	defer cancel()
-
-	var (
-		cursor uint64
-		result []string
-	)
-
-	for {
-		keys, next, err := s.client.Scan(ctx, cursor, pattern, 1000).Result()
-		if err != nil {
-			return nil, err
-		}
-		if len(keys) > 0 {
-			result = append(result, keys...)
-		}
-		cursor = next
-		if cursor == 0 {
-			break
-		}
-	}
-	return result, nil
+	return s.v2.Keys(s.ctx, pattern)
 }
 
 // Expire 设置键的过期时间
 func (s *Storage) Expire(key string, expiration time.Duration) error {
-	ctx, cancel := s.withTimeout()
-	defer cancel()
-	return s.client.Expire(ctx, s.getKey(key), expiration).Err()
+	return s.v2.Expire(s.ctx, key, expiration)
 }
 
 // TTL 获取键的剩余生存时间
 func (s *Storage) TTL(key string) (time.Duration, error) {
-	ctx, cancel := s.withTimeout()
-	defer cancel()
-	return s.client.TTL(ctx, s.getKey(key)).Result()
+	return s.v2.TTL(s.ctx, key)
 }
 
 // Clear 清空所有数据（警告：会清空整个 Redis，谨慎使用！应由 Manager 层控制）
 func (s *Storage) Clear() error {
-	ctx, cancel := s.withTimeout()
-	defer cancel()
-
-	var cursor uint64
-	for {
-		keys, next, err := s.client.Scan(ctx, cursor, "*", 1000).Result()
-		if err != nil {
-			return err
-		}
-		if len(keys) > 0 {
-			// Use UNLINK for async non-blocking deletion
-			if err := s.client.Unlink(ctx, keys...).Err(); err != nil {
-				return err
-			}
-		}
-		cursor = next
-		if cursor == 0 {
-			break
-		}
-	}
-	return nil
+	return s.v2.Clear(s.ctx)
 }
 
 // Ping 检查连接
 func (s *Storage) Ping() error {
-	ctx, cancel := s.withTimeout()
-	defer cancel()
-	return s.client.Ping(ctx).Err()
+	return s.v2.Ping(s.ctx)
+}
+
+// SetNX 仅当键不存在时设置
+func (s *Storage) SetNX(key string, value any, expiration time.Duration) (bool, error) {
+	return s.v2.SetNX(s.ctx, key, value, expiration)
+}
+
+// GetDel 一步获取并删除键
+func (s *Storage) GetDel(key string) (any, error) {
+	return s.v2.GetDel(s.ctx, key)
+}
+
+// HSet 设置哈希字段
+func (s *Storage) HSet(key, field, value string) error {
+	return s.v2.HSet(s.ctx, key, field, value)
+}
+
+// HGetAll 获取哈希全部字段
+func (s *Storage) HGetAll(key string) (map[string]string, error) {
+	return s.v2.HGetAll(s.ctx, key)
+}
+
+// HDel 删除哈希字段
+func (s *Storage) HDel(key string, fields ...string) error {
+	return s.v2.HDel(s.ctx, key, fields...)
 }
 
 // Close 关闭连接
@@ -249,12 +183,9 @@ func (s *Storage) GetClient() *redis.Client {
 	return s.client
 }
 
-// withTimeout returns a context with the configured per-operation timeout.
-func (s *Storage) withTimeout() (context.Context, context.CancelFunc) {
-	if s.opTimeout > 0 {
-		return context.WithTimeout(s.ctx, s.opTimeout)
-	}
-	return context.WithCancel(s.ctx)
+// StorageV2 获取原生StorageV2实现，Manager据此把请求ctx传递给Redis
+func (s *Storage) StorageV2() adapter.StorageV2 {
+	return s.v2
 }
 
 // Builder Redis存储构建器
@@ -1,7 +1,18 @@
 package redis
 
 import (
+	"context"
+	"errors"
+	"os"
 	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/click33/sa-token-go/core/adapter"
+	"github.com/click33/sa-token-go/core/adapter/adaptertest"
+	"github.com/click33/sa-token-go/core/oauth2/oauth2test"
+	"github.com/click33/sa-token-go/core/security"
+	"github.com/redis/go-redis/v9"
 )
 
 // 如果需要在本地运行测试，请取消下面注释并配置Redis连接信息
@@ -84,3 +95,151 @@ func TestSetKeepTTL(t *testing.T) {
 func TestDummy(t *testing.T) {
 	// 这是一个空测试，仅用于确保测试文件能够编译通过
 }
+
+// TestOAuth2StorageSuite 需要设置 SATOKEN_REDIS_URL（如 redis://localhost:6379/15），否则跳过
+func TestOAuth2StorageSuite(t *testing.T) {
+	url := os.Getenv("SATOKEN_REDIS_URL")
+	if url == "" {
+		t.Skip("SATOKEN_REDIS_URL not set")
+	}
+
+	storage, err := NewStorage(url)
+	if err != nil {
+		t.Fatalf("NewStorage() error = %v", err)
+	}
+	oauth2test.RunStorageSuite(t, func() adapter.Storage { return storage })
+}
+
+// TestAtomicSuite 需要设置 SATOKEN_REDIS_URL，否则跳过
+func TestAtomicSuite(t *testing.T) {
+	url := os.Getenv("SATOKEN_REDIS_URL")
+	if url == "" {
+		t.Skip("SATOKEN_REDIS_URL not set")
+	}
+
+	storage, err := NewStorage(url)
+	if err != nil {
+		t.Fatalf("NewStorage() error = %v", err)
+	}
+	adaptertest.RunAtomicSuite(t, func() adapter.Storage { return storage })
+}
+
+// TestContextStorage 需要设置 SATOKEN_REDIS_URL，否则跳过
+func TestContextStorage(t *testing.T) {
+	url := os.Getenv("SATOKEN_REDIS_URL")
+	if url == "" {
+		t.Skip("SATOKEN_REDIS_URL not set")
+	}
+
+	opts, err := redis.ParseURL(url)
+	if err != nil {
+		t.Fatalf("ParseURL() error = %v", err)
+	}
+	client := redis.NewClient(opts)
+	t.Cleanup(func() { _ = client.Close() })
+	storage := NewContextStorage(client, time.Second)
+
+	ctx := context.Background()
+	key := "satoken-test:ctx:key"
+	t.Cleanup(func() { _ = storage.Delete(ctx, key) })
+
+	if err := storage.Set(ctx, key, "v", time.Minute); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if value, err := storage.Get(ctx, key); err != nil || value != "v" {
+		t.Fatalf("Get() = %v, %v, want v", value, err)
+	}
+	if exists, err := storage.Exists(ctx, key); err != nil || !exists {
+		t.Fatalf("Exists() = %v, %v, want true", exists, err)
+	}
+	if exists, err := storage.Exists(ctx, "satoken-test:ctx:missing"); err != nil || exists {
+		t.Fatalf("Exists(missing) = %v, %v, want false", exists, err)
+	}
+
+	// 已取消的ctx必须以错误返回，而不是被当作键不存在
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := storage.Get(cancelled, key); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Get(cancelled) error = %v, want context.Canceled", err)
+	}
+	if _, err := storage.Exists(cancelled, key); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Exists(cancelled) error = %v, want context.Canceled", err)
+	}
+	if _, err := adapter.CheckExists(adapter.BindContext(storage, cancelled), key); !errors.Is(err, context.Canceled) {
+		t.Fatalf("CheckExists(cancelled) error = %v, want context.Canceled", err)
+	}
+
+	adaptertest.RunAtomicSuite(t, func() adapter.Storage { return adapter.BindContext(storage, ctx) })
+}
+
+// TestStorageProvidesContextStorage 需要设置 SATOKEN_REDIS_URL，否则跳过
+func TestStorageProvidesContextStorage(t *testing.T) {
+	url := os.Getenv("SATOKEN_REDIS_URL")
+	if url == "" {
+		t.Skip("SATOKEN_REDIS_URL not set")
+	}
+
+	storage, err := NewStorage(url)
+	if err != nil {
+		t.Fatalf("NewStorage() error = %v", err)
+	}
+	if _, ok := adapter.AsStorageV2(storage).(*ContextStorage); !ok {
+		t.Fatal("AsStorageV2() must return the native ContextStorage")
+	}
+}
+
+// newMiniredisStorage 创建基于miniredis的存储，无需外部Redis
+func newMiniredisStorage(t *testing.T) adapter.Storage {
+	t.Helper()
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	return NewStorageFromClient(client)
+}
+
+// TestTTL go-redis以-1ns/-2ns表示永不过期/不存在，需统一为与Memory一致的-1s/-2s
+func TestTTL(t *testing.T) {
+	storage := newMiniredisStorage(t)
+
+	if err := storage.Set("forever", "v", 0); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if ttl, err := storage.TTL("forever"); err != nil || ttl != -1*time.Second {
+		t.Fatalf("TTL(never expire) = %v, %v, want -1s", ttl, err)
+	}
+	if ttl, err := storage.TTL("missing"); err == nil || ttl != -2*time.Second {
+		t.Fatalf("TTL(missing) = %v, %v, want -2s and an error", ttl, err)
+	}
+	if err := storage.Set("minute", "v", time.Minute); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if ttl, err := storage.TTL("minute"); err != nil || ttl <= 0 || ttl > time.Minute {
+		t.Fatalf("TTL(minute) = %v, %v, want (0, 1m]", ttl, err)
+	}
+}
+
+func TestTempTokenTimeout(t *testing.T) {
+	tm := security.NewTempTokenManager(newMiniredisStorage(t), "satoken:")
+
+	forever, err := tm.Create("invite", "1001", -1)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if timeout := tm.GetTimeout(forever); timeout != -1 {
+		t.Fatalf("GetTimeout(never expire) = %d, want -1", timeout)
+	}
+
+	token, err := tm.Create("reset-password", "1001", time.Minute)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if timeout := tm.GetTimeout(token); timeout <= 0 || timeout > 60 {
+		t.Fatalf("GetTimeout() = %d, want (0, 60]", timeout)
+	}
+	if _, err := tm.Consume("reset-password", token); err != nil {
+		t.Fatalf("Consume() error = %v", err)
+	}
+	if timeout := tm.GetTimeout(token); timeout != -2 {
+		t.Fatalf("GetTimeout() after Consume = %d, want -2", timeout)
+	}
+}
@@ -0,0 +1,238 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/click33/sa-token-go/core/adapter"
+	"github.com/redis/go-redis/v9"
+)
+
+// ContextStorage Redis存储的StorageV2实现，调用方的ctx直接传递给Redis命令
+// OperationTimeout is applied on top of the caller's deadline | OperationTimeout叠加在调用方截止时间之上
+type ContextStorage struct {
+	client    *redis.Client
+	opTimeout time.Duration
+}
+
+// NewContextStorage 从已有的Redis客户端创建StorageV2，opTimeout<=0表示仅使用调用方的截止时间
+func NewContextStorage(client *redis.Client, opTimeout time.Duration) *ContextStorage {
+	return &ContextStorage{
+		client:    client,
+		opTimeout: opTimeout,
+	}
+}
+
+var (
+	_ adapter.StorageV2         = (*ContextStorage)(nil)
+	_ adapter.AtomicStorageV2   = (*ContextStorage)(nil)
+	_ adapter.HashStorageV2     = (*ContextStorage)(nil)
+	_ adapter.StorageV2Provider = (*Storage)(nil)
+	_ adapter.AtomicStorage     = (*Storage)(nil)
+	_ adapter.HashStorage       = (*Storage)(nil)
+)
+
+// Set 设置键值对
+func (s *ContextStorage) Set(ctx context.Context, key string, value any, expiration time.Duration) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+	return s.client.Set(ctx, key, value, expiration).Err()
+}
+
+// SetKeepTTL Sets value without modifying TTL | 设置键值但保持原有TTL不变
+func (s *ContextStorage) SetKeepTTL(ctx context.Context, key string, value any) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	// 先检查键是否存在，不存在则返回错误（与Memory实现保持一致）
+	exists, err := s.client.Exists(ctx, key).Result()
+	if err != nil {
+		return err
+	}
+	if exists == 0 {
+		return fmt.Errorf("key not found: %s", key)
+	}
+
+	// Use SET key value KeepTTL | 使用 SET key value KeepTTL
+	return s.client.SetArgs(ctx, key, value, redis.SetArgs{
+		KeepTTL: true, // Keep original TTL | 保留原有TTL
+	}).Err()
+}
+
+// Get 获取值
+func (s *ContextStorage) Get(ctx context.Context, key string) (any, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+	val, err := s.client.Get(ctx, key).Result()
+	if err == redis.Nil {
+		return nil, fmt.Errorf("key not found: %s", key)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return val, nil
+}
+
+// Delete 删除键
+func (s *ContextStorage) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+	return s.client.Del(ctx, keys...).Err()
+}
+
+// Exists 检查键是否存在
+func (s *ContextStorage) Exists(ctx context.Context, key string) (bool, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+	result, err := s.client.Exists(ctx, key).Result()
+	if err != nil {
+		return false, err
+	}
+	return result > 0, nil
+}
+
+// Keys 获取匹配模式的所有键
+func (s *ContextStorage) Keys(ctx context.Context, pattern string) ([]string, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	var (
+		cursor uint64
+		result []string
+	)
+
+	for {
+		keys, next, err := s.client.Scan(ctx, cursor, pattern, 1000).Result()
+		if err != nil {
+			return nil, err
+		}
+		if len(keys) > 0 {
+			result = append(result, keys...)
+		}
+		cursor = next
+		if cursor == 0 {
+			break
+		}
+	}
+	return result, nil
+}
+
+// Expire 设置键的过期时间，expiration<=0时移除过期时间（与Memory实现保持一致）
+func (s *ContextStorage) Expire(ctx context.Context, key string, expiration time.Duration) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+	if expiration <= 0 {
+		return s.client.Persist(ctx, key).Err()
+	}
+	return s.client.Expire(ctx, key, expiration).Err()
+}
+
+// TTL 获取键的剩余生存时间
+func (s *ContextStorage) TTL(ctx context.Context, key string) (time.Duration, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+	ttl, err := s.client.TTL(ctx, key).Result()
+	if err != nil {
+		return ttl, err
+	}
+
+	// go-redis 以 -1ns/-2ns 返回“永不过期”/“不存在”，按Memory实现统一为 -1s/-2s
+	switch {
+	case ttl == -1 || ttl == -1*time.Second:
+		return -1 * time.Second, nil
+	case ttl < 0:
+		return -2 * time.Second, fmt.Errorf("key not found: %s", key)
+	}
+	return ttl, nil
+}
+
+// Clear 清空所有数据（警告：会清空整个 Redis，谨慎使用！应由 Manager 层控制）
+func (s *ContextStorage) Clear(ctx context.Context) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	var cursor uint64
+	for {
+		keys, next, err := s.client.Scan(ctx, cursor, "*", 1000).Result()
+		if err != nil {
+			return err
+		}
+		if len(keys) > 0 {
+			// Use UNLINK for async non-blocking deletion
+			if err := s.client.Unlink(ctx, keys...).Err(); err != nil {
+				return err
+			}
+		}
+		cursor = next
+		if cursor == 0 {
+			break
+		}
+	}
+	return nil
+}
+
+// Ping 检查连接
+func (s *ContextStorage) Ping(ctx context.Context) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+	return s.client.Ping(ctx).Err()
+}
+
+// SetNX Sets key only when absent (SET NX) | 仅当键不存在时设置（SET NX）
+func (s *ContextStorage) SetNX(ctx context.Context, key string, value any, expiration time.Duration) (bool, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+	return s.client.SetNX(ctx, key, value, expiration).Result()
+}
+
+// GetDel Gets and deletes key in one step (GETDEL, Redis 6.2+) | 一步获取并删除键（GETDEL，需Redis 6.2+）
+func (s *ContextStorage) GetDel(ctx context.Context, key string) (any, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+	val, err := s.client.GetDel(ctx, key).Result()
+	if err == redis.Nil {
+		return nil, fmt.Errorf("key not found: %s", key)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return val, nil
+}
+
+// HSet 设置哈希字段
+func (s *ContextStorage) HSet(ctx context.Context, key, field, value string) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+	return s.client.HSet(ctx, key, field, value).Err()
+}
+
+// HGetAll 获取哈希全部字段，不存在时返回空map
+func (s *ContextStorage) HGetAll(ctx context.Context, key string) (map[string]string, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+	return s.client.HGetAll(ctx, key).Result()
+}
+
+// HDel 删除哈希字段，Redis在最后一个字段删除时移除键
+func (s *ContextStorage) HDel(ctx context.Context, key string, fields ...string) error {
+	if len(fields) == 0 {
+		return nil
+	}
+
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+	return s.client.HDel(ctx, key, fields...).Err()
+}
+
+// withTimeout returns ctx limited by the configured per-operation timeout.
+func (s *ContextStorage) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if s.opTimeout > 0 {
+		return context.WithTimeout(ctx, s.opTimeout)
+	}
+	return context.WithCancel(ctx)
+}
@@ -7,22 +7,29 @@ import (
 
 	"github.com/click33/sa-token-go/core/manager"
 	"github.com/click33/sa-token-go/core/oauth2"
+	"github.com/click33/sa-token-go/core/rbac"
 	"github.com/click33/sa-token-go/core/security"
 	"github.com/click33/sa-token-go/core/session"
+	"github.com/click33/sa-token-go/core/token"
 )
 
 // Global Manager instance | 全局Manager实例
 var (
 	globalManager *manager.Manager
+	managers      = make(map[string]*manager.Manager) // Managers by login type | 按账号体系索引的Manager
 	once          sync.Once
 	mu            sync.RWMutex
 )
 
 // SetManager sets the global Manager (must be called first) | 设置全局Manager（必须先调用此方法）
+// The manager is also registered under its login type | 同时按其账号体系注册
 func SetManager(mgr *manager.Manager) {
 	mu.Lock()
 	defer mu.Unlock()
 	globalManager = mgr
+	if mgr != nil {
+		managers[mgr.GetLoginType()] = mgr
+	}
 }
 
 // GetManager gets the global Manager | 获取全局Manager
@@ -35,7 +42,45 @@ func GetManager() *manager.Manager {
 	return globalManager
 }
 
-// CloseManager closes global Manager and releases resources | 关闭全局 Manager 并释放资源
+// PutManager registers a Manager for its login type without changing the global one | 按账号体系注册Manager（不改变全局Manager）
+func PutManager(mgr *manager.Manager) {
+	if mgr == nil {
+		return
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	managers[mgr.GetLoginType()] = mgr
+	if globalManager == nil {
+		globalManager = mgr
+	}
+}
+
+// GetManagerByLoginType gets the Manager of specified login type, empty means global | 获取指定账号体系的Manager，为空时返回全局Manager
+func GetManagerByLoginType(loginType string) *manager.Manager {
+	if loginType == "" {
+		return GetManager()
+	}
+	mu.RLock()
+	defer mu.RUnlock()
+	mgr, ok := managers[loginType]
+	if !ok {
+		panic("StpUtil manager not found for login type: " + loginType + ", please call PutManager() first")
+	}
+	return mgr
+}
+
+// LookupManager gets the Manager of specified login type without panicking | 获取指定账号体系的Manager（不会panic）
+func LookupManager(loginType string) (*manager.Manager, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	if loginType == "" {
+		return globalManager, globalManager != nil
+	}
+	mgr, ok := managers[loginType]
+	return mgr, ok
+}
+
+// CloseManager closes all registered Managers and releases resources | 关闭所有已注册的 Manager 并释放资源
 func CloseManager() {
 	mu.Lock()
 	defer mu.Unlock()
@@ -43,6 +88,10 @@ func CloseManager() {
 		globalManager.CloseManager()
 		globalManager = nil // 置 nil 避免后续误用
 	}
+	for loginType, mgr := range managers {
+		mgr.CloseManager()
+		delete(managers, loginType)
+	}
 }
 
 // ============ Authentication | 登录认证 ============
@@ -52,6 +101,11 @@ func Login(loginID interface{}, device ...string) (string, error) {
 	return GetManager().Login(toString(loginID), device...)
 }
 
+// LoginWithOptions performs user login with parameters | 使用登录参数登录
+func LoginWithOptions(loginID interface{}, opts *manager.LoginOptions) (string, error) {
+	return GetManager().LoginWithOptions(toString(loginID), opts)
+}
+
 // LoginByToken performs login with specified token | 使用指定Token登录
 func LoginByToken(loginID interface{}, tokenValue string, device ...string) error {
 	return GetManager().LoginByToken(toString(loginID), tokenValue, device...)
@@ -97,6 +151,11 @@ func GetTokenInfo(tokenValue string) (*manager.TokenInfo, error) {
 	return GetManager().GetTokenInfo(tokenValue)
 }
 
+// GetTokenExtra gets extra login data of token | 获取Token的登录扩展数据
+func GetTokenExtra(tokenValue, key string) (any, bool) {
+	return GetManager().GetTokenExtra(tokenValue, key)
+}
+
 // ============ Kickout | 踢人下线 ============
 
 // Kickout kicks out a user session | 踢人下线
@@ -126,6 +185,33 @@ func GetDisableTime(loginID interface{}) (int64, error) {
 	return GetManager().GetDisableTime(toString(loginID))
 }
 
+// ============ Safe Authentication | 二级认证 ============
+
+// OpenSafe opens a time-boxed safe window for token | 为Token开启限时二级认证
+func OpenSafe(tokenValue string, safeTime int64, service ...string) error {
+	return GetManager().OpenSafe(tokenValue, safeTime, service...)
+}
+
+// IsSafe checks if token is within safe window | 检查Token是否处于二级认证有效期内
+func IsSafe(tokenValue string, service ...string) bool {
+	return GetManager().IsSafe(tokenValue, service...)
+}
+
+// CheckSafe checks safe window (returns error if not opened) | 检查二级认证（未通过返回错误）
+func CheckSafe(tokenValue string, service ...string) error {
+	return GetManager().CheckSafe(tokenValue, service...)
+}
+
+// GetSafeTime gets remaining safe time in seconds | 获取二级认证剩余有效时间（秒）
+func GetSafeTime(tokenValue string, service ...string) int64 {
+	return GetManager().GetSafeTime(tokenValue, service...)
+}
+
+// CloseSafe closes safe window of token | 关闭Token的二级认证
+func CloseSafe(tokenValue string, service ...string) error {
+	return GetManager().CloseSafe(tokenValue, service...)
+}
+
 // ============ Session Management | Session管理 ============
 
 // GetSession gets session by login ID | 根据登录ID获取Session
@@ -182,6 +268,11 @@ func GetRoles(loginID interface{}) ([]string, error) {
 	return GetManager().GetRoles(toString(loginID))
 }
 
+// ClearPermissionCache invalidates cached permissions and roles of a login ID | 使账号的权限与角色缓存失效
+func ClearPermissionCache(loginID interface{}) error {
+	return GetManager().ClearPermissionCache(toString(loginID))
+}
+
 // HasRole checks if has specified role | 检查是否拥有指定角色
 func HasRole(loginID interface{}, role string) bool {
 	return GetManager().HasRole(toString(loginID), role)
@@ -216,6 +307,16 @@ func GetTokenValueList(loginID interface{}) ([]string, error) {
 	return GetManager().GetTokenValueListByLoginID(toString(loginID))
 }
 
+// GetTokenValueListByDevice 获取指定账号在某设备上的所有Token
+func GetTokenValueListByDevice(loginID interface{}, device ...string) []string {
+	return GetManager().GetTokenValueListByDevice(toString(loginID), device...)
+}
+
+// GetTokenSignList 获取指定账号的Token索引（含设备与登录时间）
+func GetTokenSignList(loginID interface{}) ([]manager.TokenSign, error) {
+	return GetManager().GetTokenSignList(toString(loginID))
+}
+
 // GetSessionCount 获取指定账号的Session数量
 func GetSessionCount(loginID interface{}) (int, error) {
 	return GetManager().GetSessionCountByLoginID(toString(loginID))
@@ -326,6 +427,86 @@ func RevokeRefreshToken(refreshToken string) error {
 	return globalManager.RevokeRefreshToken(refreshToken)
 }
 
+// CreateTempToken creates a one-shot temp token bound to service | 创建绑定业务的临时Token
+func CreateTempToken(service string, value any, timeout int64) (string, error) {
+	return GetManager().CreateTempToken(service, value, timeout)
+}
+
+// ParseTempToken parses temp token without consuming it | 解析临时Token（不消费）
+func ParseTempToken(service, tempToken string) (*security.TempTokenInfo, error) {
+	return GetManager().ParseTempToken(service, tempToken)
+}
+
+// ConsumeTempToken parses and deletes temp token (one-time use) | 解析并删除临时Token（一次性使用）
+func ConsumeTempToken(service, tempToken string) (*security.TempTokenInfo, error) {
+	return GetManager().ConsumeTempToken(service, tempToken)
+}
+
+// GetTempTokenTimeout gets remaining temp token TTL in seconds | 获取临时Token剩余有效时间（秒）
+func GetTempTokenTimeout(tempToken string) int64 {
+	return GetManager().GetTempTokenTimeout(tempToken)
+}
+
+// DeleteTempToken deletes temp token | 删除临时Token
+func DeleteTempToken(tempToken string) error {
+	return GetManager().DeleteTempToken(tempToken)
+}
+
+// GetSignManager gets the API sign manager | 获取API签名管理器
+func GetSignManager() *security.SignManager {
+	return GetManager().GetSignManager()
+}
+
+// GetTokenClaims gets typed claims of a logged-in JWT token | 获取已登录JWT Token的类型化声明
+func GetTokenClaims(tokenValue string) (*token.Claims, error) {
+	return GetManager().GetTokenClaims(tokenValue)
+}
+
+// RevokeToken adds a JWT to the revocation list until it expires | 将JWT加入吊销列表直至其过期
+func RevokeToken(tokenValue string) error {
+	return GetManager().RevokeToken(tokenValue)
+}
+
+// RevokeTokensBefore revokes tokens of an account issued up to t | 吊销账号在t及之前签发的Token
+func RevokeTokensBefore(loginID interface{}, t time.Time) error {
+	return GetManager().RevokeTokensBefore(toString(loginID), t)
+}
+
+// RevokeAllTokens revokes every token issued to an account so far | 吊销账号目前为止签发的所有Token
+func RevokeAllTokens(loginID interface{}) error {
+	return GetManager().RevokeAllTokens(toString(loginID))
+}
+
+// IsTokenRevoked checks if a JWT has been revoked | 检查JWT是否已吊销
+func IsTokenRevoked(tokenValue string) bool {
+	return GetManager().IsTokenRevoked(tokenValue)
+}
+
+// GetJwtKeySet gets the JWT signing and verification keys | 获取JWT签名与验签密钥集
+func GetJwtKeySet() *token.JwtKeySet {
+	return GetManager().GetJwtKeySet()
+}
+
+// GetEncryptKeySet gets the encrypted token keys | 获取加密Token密钥集
+func GetEncryptKeySet() *token.EncryptKeySet {
+	return GetManager().GetEncryptKeySet()
+}
+
+// DecryptToken reads token info from an encrypted token without storage lookup | 不查询存储，直接从加密Token读取Token信息
+func DecryptToken(tokenValue string) (*manager.TokenInfo, error) {
+	return GetManager().DecryptToken(tokenValue)
+}
+
+// JWKS gets the JWKS document of asymmetric JWT keys | 获取非对称JWT密钥的JWKS文档
+func JWKS() *token.JWKS {
+	return GetManager().JWKS()
+}
+
+// GetRoleManager gets the RBAC role manager | 获取RBAC角色管理器
+func GetRoleManager() *rbac.RoleManager {
+	return GetManager().GetRoleManager()
+}
+
 func GetOAuth2Server() *oauth2.OAuth2Server {
 	if globalManager == nil {
 		panic("Manager not initialized. Call stputil.SetManager() first")
@@ -349,11 +530,10 @@ func CheckDisable(tokenValue string) error {
 
 // CheckPermission checks if the token has the specified permission | 检查Token是否拥有指定权限
 func CheckPermission(tokenValue string, permission string) error {
-	loginID, err := GetLoginID(tokenValue)
-	if err != nil {
+	if _, err := GetLoginID(tokenValue); err != nil {
 		return err
 	}
-	if !HasPermission(loginID, permission) {
+	if !GetManager().HasPermissionByToken(tokenValue, permission) {
 		return fmt.Errorf("permission denied: %s", permission)
 	}
 	return nil
@@ -361,44 +541,41 @@ func CheckPermission(tokenValue string, permission string) error {
 
 // CheckPermissionAnd checks if the token has all specified permissions | 检查Token是否拥有所有指定权限
 func CheckPermissionAnd(tokenValue string, permissions []string) error {
-	loginID, err := GetLoginID(tokenValue)
-	if err != nil {
+	if _, err := GetLoginID(tokenValue); err != nil {
 		return err
 	}
-	if !HasPermissionsAnd(loginID, permissions) {
-		return fmt.Errorf("permission denied: %v", permissions)
+	for _, permission := range permissions {
+		if !GetManager().HasPermissionByToken(tokenValue, permission) {
+			return fmt.Errorf("permission denied: %v", permissions)
+		}
 	}
 	return nil
 }
 
 // CheckPermissionOr checks if the token has any of the specified permissions | 检查Token是否拥有任一指定权限
 func CheckPermissionOr(tokenValue string, permissions []string) error {
-	loginID, err := GetLoginID(tokenValue)
-	if err != nil {
+	if _, err := GetLoginID(tokenValue); err != nil {
 		return err
 	}
-	if !HasPermissionsOr(loginID, permissions) {
-		return fmt.Errorf("permission denied: %v", permissions)
+	for _, permission := range permissions {
+		if GetManager().HasPermissionByToken(tokenValue, permission) {
+			return nil
+		}
 	}
-	return nil
+	return fmt.Errorf("permission denied: %v", permissions)
 }
 
 // GetPermissionList gets permission list for the token | 获取Token对应的权限列表
 func GetPermissionList(tokenValue string) ([]string, error) {
-	loginID, err := GetLoginID(tokenValue)
-	if err != nil {
-		return nil, err
-	}
-	return GetPermissions(loginID)
+	return GetManager().GetPermissionsByToken(tokenValue)
 }
 
 // CheckRole checks if the token has the specified role | 检查Token是否拥有指定角色
 func CheckRole(tokenValue string, role string) error {
-	loginID, err := GetLoginID(tokenValue)
-	if err != nil {
+	if _, err := GetLoginID(tokenValue); err != nil {
 		return err
 	}
-	if !HasRole(loginID, role) {
+	if !GetManager().HasRoleByToken(tokenValue, role) {
 		return fmt.Errorf("role denied: %s", role)
 	}
 	return nil
@@ -406,35 +583,33 @@ func CheckRole(tokenValue string, role string) error {
 
 // CheckRoleAnd checks if the token has all specified roles | 检查Token是否拥有所有指定角色
 func CheckRoleAnd(tokenValue string, roles []string) error {
-	loginID, err := GetLoginID(tokenValue)
-	if err != nil {
+	if _, err := GetLoginID(tokenValue); err != nil {
 		return err
 	}
-	if !HasRolesAnd(loginID, roles) {
-		return fmt.Errorf("role denied: %v", roles)
+	for _, role := range roles {
+		if !GetManager().HasRoleByToken(tokenValue, role) {
+			return fmt.Errorf("role denied: %v", roles)
+		}
 	}
 	return nil
 }
 
 // CheckRoleOr checks if the token has any of the specified roles | 检查Token是否拥有任一指定角色
 func CheckRoleOr(tokenValue string, roles []string) error {
-	loginID, err := GetLoginID(tokenValue)
-	if err != nil {
+	if _, err := GetLoginID(tokenValue); err != nil {
 		return err
 	}
-	if !HasRolesOr(loginID, roles) {
-		return fmt.Errorf("role denied: %v", roles)
+	for _, role := range roles {
+		if GetManager().HasRoleByToken(tokenValue, role) {
+			return nil
+		}
 	}
-	return nil
+	return fmt.Errorf("role denied: %v", roles)
 }
 
 // GetRoleList gets role list for the token | 获取Token对应的角色列表
 func GetRoleList(tokenValue string) ([]string, error) {
-	loginID, err := GetLoginID(tokenValue)
-	if err != nil {
-		return nil, err
-	}
-	return GetRoles(loginID)
+	return GetManager().GetRolesByToken(tokenValue)
 }
 
 // GetTokenSession gets session for the token | 获取Token对应的Session